@@ -67,16 +67,13 @@ func (g *G1) String() string {
 
 
 // ScalarBaseMult sets e to g*k where g is the generator of the group and then
-// returns out.
+// returns e.
 func (e *GT) ScalarBaseMult(k *big.Int) *GT {
 	if e.p == nil {
 		e.p = &gfP12{}
 	}
-	g1:=&G1{curveGen}
-	g1.p.Mul(curveGen, k)
-	e2 := Pair(g1, &G2{twistGen})
-	// e.p.Exp(e2, k)
-	return e2
+	e.p.Set(Pair(new(G1).ScalarBaseMult(k), &G2{twistGen}).p)
+	return e
 }
 
 
@@ -597,6 +594,26 @@ func PairingCheck(a []*G1, b []*G2) bool {
 	return finalExponentiation(acc).IsOne()
 }
 
+// MultiPair returns the product of the pairings e(a[i], b[i]). It runs a
+// Miller loop per pair but only one final exponentiation, so it is cheaper
+// than multiplying the results of Pair. It panics if a and b differ in
+// length.
+func MultiPair(a []*G1, b []*G2) *GT {
+	if len(a) != len(b) {
+		panic("bn256: MultiPair of different numbers of points")
+	}
+	acc := new(gfP12)
+	acc.SetOne()
+
+	for i := range a {
+		if a[i].p.IsInfinity() || b[i].p.IsInfinity() {
+			continue
+		}
+		acc.Mul(acc, miller(b[i].p, a[i].p))
+	}
+	return &GT{finalExponentiation(acc)}
+}
+
 // Miller applies Miller's algorithm, which is a bilinear function from the
 // source groups to F_p^12. Miller(g1, g2).Finalize() is equivalent to Pair(g1,
 // g2).
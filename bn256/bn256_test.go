@@ -3,6 +3,7 @@ package bn256
 import (
 	"bytes"
 	"crypto/rand"
+	"math/big"
 	"testing"
 )
 
@@ -105,6 +106,111 @@ func TestG2SelfAddition(t *testing.T) {
 	}
 }
 
+func TestEqual(t *testing.T) {
+	a, p, _ := RandomG1(rand.Reader)
+	_, q, _ := RandomG2(rand.Reader)
+
+	// The same points in projective and in affine form.
+	p2 := new(G1).Add(p, p)
+	q2 := new(G2).Add(q, q)
+	if !p2.Equal(new(G1).ScalarMult(p, big.NewInt(2))) || !q2.Equal(new(G2).ScalarMult(q, big.NewInt(2))) {
+		t.Fatal("equal points compare unequal")
+	}
+	if p2.Equal(p) || q2.Equal(q) {
+		t.Fatal("different points compare equal")
+	}
+
+	e := Pair(p, q)
+	if !e.Equal(new(GT).ScalarMult(Pair(&G1{curveGen}, q), a)) {
+		t.Fatal("equal GT elements compare unequal")
+	}
+	if e.Equal(Pair(p2, q)) {
+		t.Fatal("different GT elements compare equal")
+	}
+}
+
+func TestIsIdentity(t *testing.T) {
+	_, p, _ := RandomG1(rand.Reader)
+	_, q, _ := RandomG2(rand.Reader)
+
+	if p.IsIdentity() || q.IsIdentity() || Pair(p, q).IsIdentity() {
+		t.Fatal("random element is the identity")
+	}
+	if !new(G1).Add(p, new(G1).Neg(p)).IsIdentity() || !new(G2).Add(q, new(G2).Neg(q)).IsIdentity() {
+		t.Fatal("p - p is not the identity")
+	}
+	if !new(G1).IsIdentity() || !new(G2).IsIdentity() || !new(GT).IsIdentity() {
+		t.Fatal("zero value is not the identity")
+	}
+	if !new(GT).ScalarBaseMult(big.NewInt(0)).IsIdentity() {
+		t.Fatal("gT^0 is not the identity")
+	}
+}
+
+func TestCompareKeepsOperands(t *testing.T) {
+	_, p, _ := RandomG1(rand.Reader)
+	_, q, _ := RandomG2(rand.Reader)
+
+	// Comparing zero values must leave them zero values.
+	e, a := new(GT), new(GT)
+	if !e.Equal(a) || !e.IsIdentity() || e.Equal(Pair(p, q)) {
+		t.Fatal("zero GT values compare wrongly")
+	}
+	if e.p != nil || a.p != nil {
+		t.Fatal("comparing GT elements set their representation")
+	}
+	g1, g2 := new(G1), new(G2)
+	if !g1.Equal(new(G1)) || !g1.IsIdentity() || !g2.Equal(new(G2)) || !g2.IsIdentity() {
+		t.Fatal("zero points compare wrongly")
+	}
+	if g1.p != nil || g2.p != nil {
+		t.Fatal("comparing points set their representation")
+	}
+
+	// Comparing projective points leaves them projective.
+	p2 := new(G1).Add(p, p)
+	before := *p2.p
+	if !p2.Equal(new(G1).ScalarMult(p, big.NewInt(2))) || *p2.p != before {
+		t.Fatal("comparing a point changed it")
+	}
+}
+
+func TestGTScalarBaseMult(t *testing.T) {
+	k, _ := rand.Int(rand.Reader, Order)
+	g1 := new(G1).ScalarBaseMult(big.NewInt(1))
+
+	e := new(GT)
+	if e.ScalarBaseMult(k) != e {
+		t.Fatal("ScalarBaseMult does not return its receiver")
+	}
+	if !e.Equal(Pair(new(G1).ScalarBaseMult(k), &G2{twistGen})) {
+		t.Fatal("ScalarBaseMult(k) != e(k·g1, g2)")
+	}
+	new(GT).ScalarBaseMult(big.NewInt(0))
+	if !new(G1).ScalarBaseMult(big.NewInt(1)).Equal(g1) {
+		t.Fatal("ScalarBaseMult changed the G1 generator")
+	}
+}
+
+func TestMultiPair(t *testing.T) {
+	var as []*G1
+	var bs []*G2
+	want := new(GT).ScalarBaseMult(big.NewInt(0))
+	for i := 0; i < 4; i++ {
+		_, a, _ := RandomG1(rand.Reader)
+		_, b, _ := RandomG2(rand.Reader)
+		as, bs = append(as, a), append(bs, b)
+		want.Add(want, Pair(a, b))
+	}
+	as, bs = append(as, new(G1).ScalarBaseMult(big.NewInt(0))), append(bs, &G2{twistGen})
+	if !MultiPair(as, bs).Equal(want) {
+		t.Fatal("MultiPair differs from the product of the pairings")
+	}
+	if !MultiPair(nil, nil).IsIdentity() {
+		t.Fatal("empty MultiPair is not the identity")
+	}
+}
+
 func BenchmarkG1(b *testing.B) {
 	x, _ := rand.Int(rand.Reader, Order)
 	b.ResetTimer()
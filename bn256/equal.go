@@ -0,0 +1,65 @@
+package bn256
+
+import "crypto/subtle"
+
+// Group elements compare equal when their Marshal outputs do. Marshal is
+// canonical: points are converted to affine form first and the point at
+// infinity encodes as all zeros. Since that conversion happens in place, and
+// GT.Marshal fills in a nil representation, elements are marshaled from
+// copies, so comparing does not touch the arguments and is safe for
+// concurrent use. The bytes are compared in constant time.
+
+// canonical returns the Marshal output of e without modifying e.
+func (e *G1) canonical() []byte {
+	if e.p == nil {
+		return new(G1).Marshal()
+	}
+	return new(G1).Set(e).Marshal()
+}
+
+// Equal reports whether e and a are the same element of G₁.
+func (e *G1) Equal(a *G1) bool {
+	return subtle.ConstantTimeCompare(e.canonical(), a.canonical()) == 1
+}
+
+// IsIdentity reports whether e is the point at infinity.
+func (e *G1) IsIdentity() bool {
+	return subtle.ConstantTimeCompare(e.canonical(), new(G1).Marshal()) == 1
+}
+
+// canonical returns the Marshal output of e without modifying e.
+func (e *G2) canonical() []byte {
+	if e.p == nil {
+		return new(G2).Marshal()
+	}
+	return new(G2).Set(e).Marshal()
+}
+
+// Equal reports whether e and a are the same element of G₂.
+func (e *G2) Equal(a *G2) bool {
+	return subtle.ConstantTimeCompare(e.canonical(), a.canonical()) == 1
+}
+
+// IsIdentity reports whether e is the point at infinity.
+func (e *G2) IsIdentity() bool {
+	return subtle.ConstantTimeCompare(e.canonical(), new(G2).Marshal()) == 1
+}
+
+// canonical returns the Marshal output of e without modifying e. A nil
+// representation is the identity, as in Marshal.
+func (e *GT) canonical() []byte {
+	if e.p == nil {
+		return new(GT).Marshal()
+	}
+	return new(GT).Set(e).Marshal()
+}
+
+// Equal reports whether e and a are the same element of GT.
+func (e *GT) Equal(a *GT) bool {
+	return subtle.ConstantTimeCompare(e.canonical(), a.canonical()) == 1
+}
+
+// IsIdentity reports whether e is the identity of GT, i.e. one.
+func (e *GT) IsIdentity() bool {
+	return subtle.ConstantTimeCompare(e.canonical(), new(GT).Marshal()) == 1
+}
@@ -0,0 +1,124 @@
+// Command issuer runs the credential issuer over HTTP, see package issuer.
+//
+//	go run ./cmd/issuer -addr :8080 -attrs 5 -key issuer-key.pem -audit audit.log -handles handles.txt
+//
+// The issuer key is read from the -key file, which is created with a fresh
+// key on first start. If OBFUSHOP_ISSUER_MNEMONIC is set the key is derived
+// from it (and OBFUSHOP_ISSUER_PASSPHRASE) instead and nothing is written.
+// The fingerprint printed at start-up is the one IssuerKeyFingerprint returns
+// on-chain for -key-version once the key is uploaded or rotated in (see
+// issuer.RotateKey).
+package main
+
+import (
+	"Obfushop/crypto/AC"
+	"Obfushop/issuer"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+)
+
+func main() {
+	addr := flag.String("addr", ":8080", "listen address")
+	attrs := flag.Int("attrs", 5, "attributes per credential, including the buyer secret, epoch and revocation handle")
+	period := flag.Duration("period", 365*24*time.Hour, "length of a validity epoch, in whole seconds")
+	keyPath := flag.String("key", "issuer-key.pem", "secret key file, created if missing")
+	keyVersion := flag.Uint64("key-version", 1, "on-chain version of the key, reported with every signature")
+	auditPath := flag.String("audit", "issuer-audit.log", "audit log file, appended to")
+	handlesPath := flag.String("handles", "issuer-handles.txt", "file the revocation handles of issued credentials are appended to")
+	flag.Parse()
+	if _, err := AC.EpochAt(time.Now(), *period); err != nil {
+		log.Fatalf("-period: %v", err)
+	}
+
+	params, err := AC.Setup(*attrs)
+	if err != nil {
+		log.Fatalf("AC setup failed: %v", err)
+	}
+	key, err := loadKey(params, *keyPath)
+	if err != nil {
+		log.Fatalf("Issuer key: %v", err)
+	}
+	fingerprint, err := key.Fingerprint()
+	if err != nil {
+		log.Fatalf("Issuer key: %v", err)
+	}
+	fmt.Printf("Issuer key fingerprint %s\n", fingerprint)
+
+	auditFile, err := os.OpenFile(*auditPath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		log.Fatalf("Failed to open audit log: %v", err)
+	}
+	defer auditFile.Close()
+	handlesFile, err := os.OpenFile(*handlesPath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		log.Fatalf("Failed to open handle file: %v", err)
+	}
+	defer handlesFile.Close()
+
+	srv := issuer.NewServer(params, key, issuer.NewAuditLog(auditFile),
+		issuer.RequireAttributes(*attrs),
+		issuer.RequireEpoch(func() uint64 {
+			epoch, _ := AC.EpochAt(time.Now(), *period) // period checked above
+			return epoch
+		}),
+		issuer.RequireRevocable(),
+	)
+	srv.SetKey(key, *keyVersion)
+	// keep the handles so credentials can be revoked with AC.RevocationList
+	var mu sync.Mutex
+	srv.OnIssue(func(id string, req *AC.Req) error {
+		mu.Lock()
+		defer mu.Unlock()
+		_, err := fmt.Fprintf(handlesFile, "%s %x\n", id, req.Handle)
+		return err
+	})
+
+	fmt.Printf("Issuer listening on %s\n", *addr)
+	log.Fatal(http.ListenAndServe(*addr, srv))
+}
+
+// loadKey derives the key from the mnemonic in the environment, or reads it
+// from path, generating and saving a new one if the file does not exist.
+func loadKey(params *AC.Params, path string) (*AC.IssuerKey, error) {
+	if mnemonic := os.Getenv("OBFUSHOP_ISSUER_MNEMONIC"); mnemonic != "" {
+		return AC.KeyGenFromMnemonic(params, mnemonic, os.Getenv("OBFUSHOP_ISSUER_PASSPHRASE"))
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		key, err := AC.KeyGen(params)
+		if err != nil {
+			return nil, err
+		}
+		data, err := key.ExportSecretPEM()
+		if err != nil {
+			return nil, err
+		}
+		f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := f.Write(data); err != nil {
+			f.Close()
+			return nil, err
+		}
+		fmt.Printf("New issuer key written to %s\n", path)
+		return key, f.Close()
+	}
+	if err != nil {
+		return nil, err
+	}
+	key, err := AC.ImportSecretPEM(data)
+	if err != nil {
+		return nil, err
+	}
+	if len(key.PK2) != len(params.Hs) {
+		return nil, fmt.Errorf("%s holds a key for %d attributes, not %d", path, len(key.PK2), len(params.Hs))
+	}
+	return key, nil
+}
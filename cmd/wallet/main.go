@@ -0,0 +1,126 @@
+// Command wallet manages a buyer wallet file, see package wallet.
+//
+//	wallet [-f file] init
+//	wallet [-f file] list
+//	wallet [-f file] new-identity NAME
+//	wallet [-f file] remove KIND NAME
+//	wallet [-f file] export KIND NAME OUT
+//	wallet [-f file] import IN
+//	wallet [-f file] rotate
+//
+// Passwords are read from OBFUSHOP_WALLET_PASSWORD, OBFUSHOP_EXPORT_PASSWORD
+// and OBFUSHOP_NEW_WALLET_PASSWORD if set, otherwise prompted for on the
+// terminal.
+package main
+
+import (
+	"Obfushop/wallet"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"golang.org/x/term"
+)
+
+// password returns the value of env, or prompts for it on the terminal
+// without echoing it.
+func password(env, prompt string) []byte {
+	if v := os.Getenv(env); v != "" {
+		return []byte(v)
+	}
+	fd := int(os.Stdin.Fd())
+	if !term.IsTerminal(fd) {
+		log.Fatalf("Failed to read password: stdin is not a terminal, set %s", env)
+	}
+	fmt.Fprintf(os.Stderr, "%s: ", prompt)
+	pw, err := term.ReadPassword(fd)
+	fmt.Fprintln(os.Stderr)
+	if err != nil {
+		log.Fatalf("Failed to read password: %v", err)
+	}
+	return pw
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage: wallet [-f file] init | list | new-identity NAME | remove KIND NAME | export KIND NAME OUT | import IN | rotate")
+	fmt.Fprintf(os.Stderr, "kinds: %s, %s, %s\n", wallet.KindIdentity, wallet.KindAttributeKey, wallet.KindOrder)
+	os.Exit(2)
+}
+
+func main() {
+	file := flag.String("f", "obfushop.wallet", "wallet file")
+	flag.Usage = usage
+	flag.Parse()
+	args := flag.Args()
+	if len(args) == 0 {
+		usage()
+	}
+	cmd, args := args[0], args[1:]
+	want := map[string]int{"init": 0, "list": 0, "new-identity": 1, "remove": 2, "export": 3, "import": 1, "rotate": 0}
+	n, ok := want[cmd]
+	if !ok || len(args) != n {
+		usage()
+	}
+
+	if cmd == "init" {
+		w, err := wallet.Create(*file, password("OBFUSHOP_WALLET_PASSWORD", "New wallet password"))
+		if err != nil {
+			log.Fatalf("Failed to create wallet: %v", err)
+		}
+		w.Close()
+		fmt.Printf("Created %s\n", *file)
+		return
+	}
+
+	w, err := wallet.Open(*file, password("OBFUSHOP_WALLET_PASSWORD", "Wallet password"))
+	if err != nil {
+		log.Fatalf("Failed to open wallet: %v", err)
+	}
+	defer w.Close()
+
+	switch cmd {
+	case "list":
+		for _, item := range w.List() {
+			fmt.Printf("%-14s %-30s %s\n", item.Kind, item.Name, item.Created.Format("2006-01-02 15:04"))
+		}
+		return
+	case "new-identity":
+		if _, err := w.NewIdentity(args[0]); err != nil {
+			log.Fatalf("Failed to create identity: %v", err)
+		}
+	case "remove":
+		if err := w.Remove(args[0], args[1]); err != nil {
+			log.Fatalf("Failed to remove entry: %v", err)
+		}
+	case "export":
+		data, err := w.Export(args[0], args[1], password("OBFUSHOP_EXPORT_PASSWORD", "Export password"))
+		if err != nil {
+			log.Fatalf("Failed to export entry: %v", err)
+		}
+		if err := os.WriteFile(args[2], data, 0600); err != nil {
+			log.Fatalf("Failed to write export: %v", err)
+		}
+		fmt.Printf("Exported %s %s to %s\n", args[0], args[1], args[2])
+		return
+	case "import":
+		data, err := os.ReadFile(args[0])
+		if err != nil {
+			log.Fatalf("Failed to read export: %v", err)
+		}
+		item, err := w.Import(data, password("OBFUSHOP_EXPORT_PASSWORD", "Export password"))
+		if err != nil {
+			log.Fatalf("Failed to import entry: %v", err)
+		}
+		fmt.Printf("Imported %s %s\n", item.Kind, item.Name)
+	case "rotate":
+		if err := w.Rotate(password("OBFUSHOP_NEW_WALLET_PASSWORD", "New wallet password")); err != nil {
+			log.Fatalf("Failed to rotate password: %v", err)
+		}
+		fmt.Println("Password changed")
+		return
+	}
+	if err := w.Save(); err != nil {
+		log.Fatalf("Failed to save wallet: %v", err)
+	}
+}
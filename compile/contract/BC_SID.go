@@ -26,7 +26,6 @@ var (
 	_ = common.Big1
 	_ = types.BloomLookup
 	_ = event.NewSubscription
-	_ = abi.ConvertType
 )
 
 // BCSIDG1Point is an auto generated low-level Go binding around an user-defined struct.
@@ -43,8 +42,8 @@ type BCSIDG2Point struct {
 
 // ContractMetaData contains all meta data concerning the Contract contract.
 var ContractMetaData = &bind.MetaData{
-	ABI: "[{\"anonymous\":false,\"inputs\":[{\"indexed\":true,\"internalType\":\"address\",\"name\":\"_seller\",\"type\":\"address\"},{\"indexed\":true,\"internalType\":\"address\",\"name\":\"_buyer\",\"type\":\"address\"},{\"indexed\":false,\"internalType\":\"string\",\"name\":\"productID\",\"type\":\"string\"},{\"indexed\":false,\"internalType\":\"uint256\",\"name\":\"quantity\",\"type\":\"uint256\"},{\"indexed\":false,\"internalType\":\"uint256\",\"name\":\"buyerPubKeyX\",\"type\":\"uint256\"},{\"indexed\":false,\"internalType\":\"uint256\",\"name\":\"buyerPubKeyY\",\"type\":\"uint256\"},{\"indexed\":false,\"internalType\":\"uint256\",\"name\":\"totalPrice\",\"type\":\"uint256\"},{\"indexed\":false,\"internalType\":\"string\",\"name\":\"orderID\",\"type\":\"string\"}],\"name\":\"BroadcastPubKey\",\"type\":\"event\"},{\"anonymous\":false,\"inputs\":[{\"indexed\":true,\"internalType\":\"address\",\"name\":\"buyer\",\"type\":\"address\"},{\"indexed\":true,\"internalType\":\"address\",\"name\":\"seller\",\"type\":\"address\"},{\"indexed\":false,\"internalType\":\"uint256\",\"name\":\"amount\",\"type\":\"uint256\"}],\"name\":\"OrderCompleted\",\"type\":\"event\"},{\"anonymous\":false,\"inputs\":[{\"indexed\":true,\"internalType\":\"address\",\"name\":\"seller\",\"type\":\"address\"},{\"indexed\":true,\"internalType\":\"address\",\"name\":\"buyer\",\"type\":\"address\"},{\"indexed\":false,\"internalType\":\"string\",\"name\":\"orderID\",\"type\":\"string\"}],\"name\":\"SellerAccepted\",\"type\":\"event\"},{\"anonymous\":false,\"inputs\":[{\"indexed\":true,\"internalType\":\"address\",\"name\":\"seller\",\"type\":\"address\"},{\"indexed\":true,\"internalType\":\"address\",\"name\":\"buyer\",\"type\":\"address\"},{\"indexed\":false,\"internalType\":\"string\",\"name\":\"orderID\",\"type\":\"string\"},{\"indexed\":false,\"internalType\":\"uint256\",\"name\":\"payment\",\"type\":\"uint256\"}],\"name\":\"SellerGetPayment\",\"type\":\"event\"},{\"inputs\":[{\"components\":[{\"internalType\":\"uint256\",\"name\":\"X\",\"type\":\"uint256\"},{\"internalType\":\"uint256\",\"name\":\"Y\",\"type\":\"uint256\"}],\"internalType\":\"structBC_SID.G1Point\",\"name\":\"pk\",\"type\":\"tuple\"},{\"internalType\":\"string\",\"name\":\"attribute\",\"type\":\"string\"}],\"name\":\"AddMapping\",\"outputs\":[],\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"inputs\":[{\"components\":[{\"internalType\":\"uint256\",\"name\":\"X\",\"type\":\"uint256\"},{\"internalType\":\"uint256\",\"name\":\"Y\",\"type\":\"uint256\"}],\"internalType\":\"structBC_SID.G1Point\",\"name\":\"pk\",\"type\":\"tuple\"},{\"internalType\":\"string\",\"name\":\"attribute\",\"type\":\"string\"}],\"name\":\"CheckClaim\",\"outputs\":[{\"internalType\":\"bool\",\"name\":\"\",\"type\":\"bool\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"address\",\"name\":\"_sellerAddr\",\"type\":\"address\"},{\"internalType\":\"address\",\"name\":\"_buyerAddr\",\"type\":\"address\"},{\"internalType\":\"string\",\"name\":\"_orderID\",\"type\":\"string\"},{\"internalType\":\"uint256\",\"name\":\"_code\",\"type\":\"uint256\"},{\"components\":[{\"internalType\":\"uint256\",\"name\":\"X\",\"type\":\"uint256\"},{\"internalType\":\"uint256\",\"name\":\"Y\",\"type\":\"uint256\"}],\"internalType\":\"structBC_SID.G1Point\",\"name\":\"_SN\",\"type\":\"tuple\"}],\"name\":\"CreateLogisticsOrder\",\"outputs\":[],\"stateMutability\":\"payable\",\"type\":\"function\"},{\"inputs\":[{\"components\":[{\"internalType\":\"uint256\",\"name\":\"X\",\"type\":\"uint256\"},{\"internalType\":\"uint256\",\"name\":\"Y\",\"type\":\"uint256\"}],\"internalType\":\"structBC_SID.G1Point\",\"name\":\"g1\",\"type\":\"tuple\"},{\"components\":[{\"internalType\":\"uint256\",\"name\":\"X\",\"type\":\"uint256\"},{\"internalType\":\"uint256\",\"name\":\"Y\",\"type\":\"uint256\"}],\"internalType\":\"structBC_SID.G1Point\",\"name\":\"y1\",\"type\":\"tuple\"},{\"components\":[{\"internalType\":\"uint256\",\"name\":\"X\",\"type\":\"uint256\"},{\"internalType\":\"uint256\",\"name\":\"Y\",\"type\":\"uint256\"}],\"internalType\":\"structBC_SID.G1Point\",\"name\":\"a1\",\"type\":\"tuple\"},{\"internalType\":\"uint256\",\"name\":\"c\",\"type\":\"uint256\"},{\"internalType\":\"uint256\",\"name\":\"z\",\"type\":\"uint256\"}],\"name\":\"DLVerify\",\"outputs\":[{\"internalType\":\"bool\",\"name\":\"\",\"type\":\"bool\"}],\"stateMutability\":\"payable\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"address\",\"name\":\"_sellerAddr\",\"type\":\"address\"},{\"internalType\":\"address\",\"name\":\"_buyerAddr\",\"type\":\"address\"},{\"internalType\":\"string\",\"name\":\"_orderID\",\"type\":\"string\"}],\"name\":\"GetConfirmResult\",\"outputs\":[{\"internalType\":\"bool\",\"name\":\"\",\"type\":\"bool\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"string\",\"name\":\"_orderID\",\"type\":\"string\"}],\"name\":\"GetCurrentSite\",\"outputs\":[{\"internalType\":\"string\",\"name\":\"\",\"type\":\"string\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[{\"components\":[{\"internalType\":\"uint256\",\"name\":\"X\",\"type\":\"uint256\"},{\"internalType\":\"uint256\",\"name\":\"Y\",\"type\":\"uint256\"}],\"internalType\":\"structBC_SID.G1Point\",\"name\":\"pk\",\"type\":\"tuple\"}],\"name\":\"GetMapping\",\"outputs\":[{\"internalType\":\"string\",\"name\":\"\",\"type\":\"string\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[{\"components\":[{\"internalType\":\"uint256\",\"name\":\"X\",\"type\":\"uint256\"},{\"internalType\":\"uint256\",\"name\":\"Y\",\"type\":\"uint256\"}],\"internalType\":\"structBC_SID.G1Point\",\"name\":\"pk\",\"type\":\"tuple\"}],\"name\":\"GetPointKey\",\"outputs\":[{\"internalType\":\"bytes32\",\"name\":\"\",\"type\":\"bytes32\"}],\"stateMutability\":\"pure\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"address\",\"name\":\"_addr\",\"type\":\"address\"},{\"internalType\":\"string\",\"name\":\"_productID\",\"type\":\"string\"}],\"name\":\"GetProduct\",\"outputs\":[{\"internalType\":\"uint256\",\"name\":\"\",\"type\":\"uint256\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"string\",\"name\":\"_orderID\",\"type\":\"string\"}],\"name\":\"GetSN\",\"outputs\":[{\"components\":[{\"internalType\":\"uint256\",\"name\":\"X\",\"type\":\"uint256\"},{\"internalType\":\"uint256\",\"name\":\"Y\",\"type\":\"uint256\"}],\"internalType\":\"structBC_SID.G1Point\",\"name\":\"\",\"type\":\"tuple\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[{\"components\":[{\"internalType\":\"uint256\",\"name\":\"X\",\"type\":\"uint256\"},{\"internalType\":\"uint256\",\"name\":\"Y\",\"type\":\"uint256\"}],\"internalType\":\"structBC_SID.G1Point\",\"name\":\"_pk1\",\"type\":\"tuple\"},{\"components\":[{\"internalType\":\"uint256\",\"name\":\"X\",\"type\":\"uint256\"},{\"internalType\":\"uint256\",\"name\":\"Y\",\"type\":\"uint256\"}],\"internalType\":\"structBC_SID.G1Point\",\"name\":\"_a1\",\"type\":\"tuple\"},{\"internalType\":\"uint256\",\"name\":\"_c\",\"type\":\"uint256\"},{\"internalType\":\"uint256\",\"name\":\"_z\",\"type\":\"uint256\"},{\"components\":[{\"internalType\":\"uint256[2]\",\"name\":\"X\",\"type\":\"uint256[2]\"},{\"internalType\":\"uint256[2]\",\"name\":\"Y\",\"type\":\"uint256[2]\"}],\"internalType\":\"structBC_SID.G2Point\",\"name\":\"_u\",\"type\":\"tuple\"},{\"components\":[{\"internalType\":\"uint256[2]\",\"name\":\"X\",\"type\":\"uint256[2]\"},{\"internalType\":\"uint256[2]\",\"name\":\"Y\",\"type\":\"uint256[2]\"}],\"internalType\":\"structBC_SID.G2Point\",\"name\":\"_s\",\"type\":\"tuple\"},{\"internalType\":\"uint256\",\"name\":\"_m\",\"type\":\"uint256\"},{\"internalType\":\"string\",\"name\":\"_attr\",\"type\":\"string\"}],\"name\":\"RegisterSID\",\"outputs\":[{\"internalType\":\"bool\",\"name\":\"\",\"type\":\"bool\"}],\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"inputs\":[{\"components\":[{\"internalType\":\"uint256\",\"name\":\"X\",\"type\":\"uint256\"},{\"internalType\":\"uint256\",\"name\":\"Y\",\"type\":\"uint256\"}],\"internalType\":\"structBC_SID.G1Point\",\"name\":\"_pk1\",\"type\":\"tuple\"},{\"components\":[{\"internalType\":\"uint256\",\"name\":\"X\",\"type\":\"uint256\"},{\"internalType\":\"uint256\",\"name\":\"Y\",\"type\":\"uint256\"}],\"internalType\":\"structBC_SID.G1Point\",\"name\":\"_a1\",\"type\":\"tuple\"},{\"internalType\":\"uint256\",\"name\":\"_c\",\"type\":\"uint256\"},{\"internalType\":\"uint256\",\"name\":\"_z\",\"type\":\"uint256\"},{\"components\":[{\"internalType\":\"uint256[2]\",\"name\":\"X\",\"type\":\"uint256[2]\"},{\"internalType\":\"uint256[2]\",\"name\":\"Y\",\"type\":\"uint256[2]\"}],\"internalType\":\"structBC_SID.G2Point[]\",\"name\":\"_u\",\"type\":\"tuple[]\"},{\"components\":[{\"internalType\":\"uint256[2]\",\"name\":\"X\",\"type\":\"uint256[2]\"},{\"internalType\":\"uint256[2]\",\"name\":\"Y\",\"type\":\"uint256[2]\"}],\"internalType\":\"structBC_SID.G2Point[]\",\"name\":\"_s\",\"type\":\"tuple[]\"},{\"internalType\":\"string[]\",\"name\":\"_attr\",\"type\":\"string[]\"}],\"name\":\"RegisterSIDSet\",\"outputs\":[{\"internalType\":\"bool\",\"name\":\"\",\"type\":\"bool\"}],\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"bytes32\",\"name\":\"\",\"type\":\"bytes32\"}],\"name\":\"SID\",\"outputs\":[{\"internalType\":\"string\",\"name\":\"\",\"type\":\"string\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"bytes32\",\"name\":\"\",\"type\":\"bytes32\"},{\"internalType\":\"uint256\",\"name\":\"\",\"type\":\"uint256\"}],\"name\":\"SIDSet\",\"outputs\":[{\"internalType\":\"string\",\"name\":\"\",\"type\":\"string\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"string\",\"name\":\"_orderID\",\"type\":\"string\"},{\"internalType\":\"string\",\"name\":\"_siteID\",\"type\":\"string\"}],\"name\":\"UpdateStatus\",\"outputs\":[],\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"inputs\":[{\"components\":[{\"internalType\":\"uint256\",\"name\":\"X\",\"type\":\"uint256\"},{\"internalType\":\"uint256\",\"name\":\"Y\",\"type\":\"uint256\"}],\"internalType\":\"structBC_SID.G1Point\",\"name\":\"_g1\",\"type\":\"tuple\"},{\"components\":[{\"internalType\":\"uint256[2]\",\"name\":\"X\",\"type\":\"uint256[2]\"},{\"internalType\":\"uint256[2]\",\"name\":\"Y\",\"type\":\"uint256[2]\"}],\"internalType\":\"structBC_SID.G2Point\",\"name\":\"_g2\",\"type\":\"tuple\"},{\"components\":[{\"internalType\":\"uint256\",\"name\":\"X\",\"type\":\"uint256\"},{\"internalType\":\"uint256\",\"name\":\"Y\",\"type\":\"uint256\"}],\"internalType\":\"structBC_SID.G1Point\",\"name\":\"_pkx\",\"type\":\"tuple\"},{\"components\":[{\"internalType\":\"uint256\",\"name\":\"X\",\"type\":\"uint256\"},{\"internalType\":\"uint256\",\"name\":\"Y\",\"type\":\"uint256\"}],\"internalType\":\"structBC_SID.G1Point\",\"name\":\"_pky\",\"type\":\"tuple\"}],\"name\":\"UploadACsParams\",\"outputs\":[],\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"string\",\"name\":\"_orderID\",\"type\":\"string\"},{\"internalType\":\"string\",\"name\":\"_code\",\"type\":\"string\"}],\"name\":\"VerifyCode\",\"outputs\":[{\"internalType\":\"bool\",\"name\":\"\",\"type\":\"bool\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"address\",\"name\":\"_seller\",\"type\":\"address\"},{\"internalType\":\"string\",\"name\":\"_orderID\",\"type\":\"string\"}],\"name\":\"buyerCancelOrder\",\"outputs\":[],\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"address\",\"name\":\"_seller\",\"type\":\"address\"},{\"internalType\":\"string\",\"name\":\"_orderID\",\"type\":\"string\"},{\"internalType\":\"string\",\"name\":\"verificationCode\",\"type\":\"string\"}],\"name\":\"buyerConfirmWithCode\",\"outputs\":[],\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"address\",\"name\":\"_seller\",\"type\":\"address\"},{\"internalType\":\"string\",\"name\":\"_productID\",\"type\":\"string\"},{\"internalType\":\"uint256\",\"name\":\"_quantity\",\"type\":\"uint256\"},{\"components\":[{\"internalType\":\"uint256\",\"name\":\"X\",\"type\":\"uint256\"},{\"internalType\":\"uint256\",\"name\":\"Y\",\"type\":\"uint256\"}],\"internalType\":\"structBC_SID.G1Point\",\"name\":\"_buyerPK\",\"type\":\"tuple\"}],\"name\":\"buyerCreateOrder\",\"outputs\":[{\"internalType\":\"string\",\"name\":\"\",\"type\":\"string\"}],\"stateMutability\":\"payable\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"address\",\"name\":\"seller\",\"type\":\"address\"}],\"name\":\"getBalanceOf\",\"outputs\":[{\"internalType\":\"uint256\",\"name\":\"\",\"type\":\"uint256\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"address\",\"name\":\"_seller\",\"type\":\"address\"},{\"internalType\":\"address\",\"name\":\"_buyer\",\"type\":\"address\"},{\"internalType\":\"string\",\"name\":\"_orderID\",\"type\":\"string\"}],\"name\":\"getOrder\",\"outputs\":[{\"internalType\":\"string\",\"name\":\"productID\",\"type\":\"string\"},{\"internalType\":\"string\",\"name\":\"orderID\",\"type\":\"string\"},{\"internalType\":\"uint256\",\"name\":\"quantity\",\"type\":\"uint256\"},{\"internalType\":\"uint256\",\"name\":\"price\",\"type\":\"uint256\"},{\"internalType\":\"bool\",\"name\":\"isOngoing\",\"type\":\"bool\"},{\"internalType\":\"bool\",\"name\":\"isLocked\",\"type\":\"bool\"},{\"internalType\":\"bool\",\"name\":\"isBuyerConfirm\",\"type\":\"bool\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[{\"components\":[{\"internalType\":\"uint256\",\"name\":\"X\",\"type\":\"uint256\"},{\"internalType\":\"uint256\",\"name\":\"Y\",\"type\":\"uint256\"}],\"internalType\":\"structBC_SID.G1Point\",\"name\":\"pk\",\"type\":\"tuple\"}],\"name\":\"getSID\",\"outputs\":[{\"internalType\":\"string\",\"name\":\"\",\"type\":\"string\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[{\"components\":[{\"internalType\":\"uint256\",\"name\":\"X\",\"type\":\"uint256\"},{\"internalType\":\"uint256\",\"name\":\"Y\",\"type\":\"uint256\"}],\"internalType\":\"structBC_SID.G1Point\",\"name\":\"pk\",\"type\":\"tuple\"}],\"name\":\"getSIDSet\",\"outputs\":[{\"internalType\":\"string[]\",\"name\":\"\",\"type\":\"string[]\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[{\"components\":[{\"internalType\":\"uint256[2]\",\"name\":\"X\",\"type\":\"uint256[2]\"},{\"internalType\":\"uint256[2]\",\"name\":\"Y\",\"type\":\"uint256[2]\"}],\"internalType\":\"structBC_SID.G2Point\",\"name\":\"point2\",\"type\":\"tuple\"}],\"name\":\"isG2Zero\",\"outputs\":[{\"internalType\":\"bool\",\"name\":\"\",\"type\":\"bool\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"address\",\"name\":\"\",\"type\":\"address\"},{\"internalType\":\"address\",\"name\":\"\",\"type\":\"address\"},{\"internalType\":\"string\",\"name\":\"\",\"type\":\"string\"}],\"name\":\"orderBook\",\"outputs\":[{\"internalType\":\"string\",\"name\":\"productID\",\"type\":\"string\"},{\"internalType\":\"uint256\",\"name\":\"quantity\",\"type\":\"uint256\"},{\"internalType\":\"uint256\",\"name\":\"price\",\"type\":\"uint256\"},{\"internalType\":\"string\",\"name\":\"orderID\",\"type\":\"string\"},{\"internalType\":\"uint256\",\"name\":\"lockedAmount\",\"type\":\"uint256\"},{\"components\":[{\"internalType\":\"uint256\",\"name\":\"X\",\"type\":\"uint256\"},{\"internalType\":\"uint256\",\"name\":\"Y\",\"type\":\"uint256\"}],\"internalType\":\"structBC_SID.G1Point\",\"name\":\"buyerPubKey\",\"type\":\"tuple\"},{\"internalType\":\"bool\",\"name\":\"isOngoing\",\"type\":\"bool\"},{\"internalType\":\"bool\",\"name\":\"isLocked\",\"type\":\"bool\"},{\"internalType\":\"bool\",\"name\":\"isBuyerConfirm\",\"type\":\"bool\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"string\",\"name\":\"\",\"type\":\"string\"}],\"name\":\"orderLogistics\",\"outputs\":[{\"components\":[{\"internalType\":\"uint256\",\"name\":\"X\",\"type\":\"uint256\"},{\"internalType\":\"uint256\",\"name\":\"Y\",\"type\":\"uint256\"}],\"internalType\":\"structBC_SID.G1Point\",\"name\":\"buyerPubKey\",\"type\":\"tuple\"},{\"internalType\":\"bool\",\"name\":\"isOngoing\",\"type\":\"bool\"},{\"internalType\":\"string\",\"name\":\"currentSite\",\"type\":\"string\"},{\"internalType\":\"uint256\",\"name\":\"code\",\"type\":\"uint256\"},{\"components\":[{\"internalType\":\"uint256\",\"name\":\"X\",\"type\":\"uint256\"},{\"internalType\":\"uint256\",\"name\":\"Y\",\"type\":\"uint256\"}],\"internalType\":\"structBC_SID.G1Point\",\"name\":\"SN\",\"type\":\"tuple\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"address\",\"name\":\"\",\"type\":\"address\"},{\"internalType\":\"string\",\"name\":\"\",\"type\":\"string\"}],\"name\":\"productPrices\",\"outputs\":[{\"internalType\":\"uint256\",\"name\":\"\",\"type\":\"uint256\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"address\",\"name\":\"_buyer\",\"type\":\"address\"},{\"internalType\":\"string\",\"name\":\"_orderID\",\"type\":\"string\"},{\"internalType\":\"string\",\"name\":\"attribute\",\"type\":\"string\"}],\"name\":\"sellerAcceptOrder\",\"outputs\":[],\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"address\",\"name\":\"_buyer\",\"type\":\"address\"},{\"internalType\":\"string\",\"name\":\"_orderID\",\"type\":\"string\"}],\"name\":\"sellerCancelOrder\",\"outputs\":[],\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"string\",\"name\":\"productID\",\"type\":\"string\"},{\"internalType\":\"uint256\",\"name\":\"unitPrice\",\"type\":\"uint256\"}],\"name\":\"setProductPrice\",\"outputs\":[],\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"string\",\"name\":\"attribute\",\"type\":\"string\"}],\"name\":\"stringToUint256\",\"outputs\":[{\"internalType\":\"uint256\",\"name\":\"\",\"type\":\"uint256\"}],\"stateMutability\":\"pure\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"address\",\"name\":\"_buyerAddr\",\"type\":\"address\"},{\"internalType\":\"string\",\"name\":\"_orderID\",\"type\":\"string\"}],\"name\":\"withdrawPayment\",\"outputs\":[],\"stateMutability\":\"payable\",\"type\":\"function\"}]",
-	Bin: "0x60808060405234601557612fd4908161001b8239f35b600080fdfe6080604052600436101561001257600080fd5b60003560e01c80630a6307e514611a21578063122030f31461192257806312451ac914611681578063146ac0991461183e5780631614e38e146117ad5780631872c257146116bb5780631aa4a01d146116815780631db9a94b1461154657806324f474571461125d57806328a431af1461152f578063303f75801461149d57806343137afd14611440578063432964e91461138c5780634d0b1eee1461129d5780634ea85bd1146112625780635683e5ea1461125d5780636163fde71461121a578063633df11f146111135780637760cd2f146110e757806399927c35146110535780639b4b8d2a14610fda5780639b96eece14610fa05780639c0f744714610f2c578063a3ebb00014610f08578063a8fb7efd14610e02578063ac5d372314610dc0578063b94b5ca214610cf1578063d8483c7514610c9f578063dc4b6e7b14610bf9578063e1a7d43214610536578063e931de801461051d578063ed758406146104fa5763fedbe99d1461018757600080fd5b60c03660031901126104f55761019b611b2e565b602435906001600160a01b03821682036104f5576044356001600160401b0381116104f5576101ce903690600401611bb7565b6101d736611d36565b926040519260ff6002845195602081818801986101f581838c611c9d565b8101600f8152030190200154166104b05760018060a01b0316600052600d60205260406000209060018060a01b0316600052602052610238604060002082611cc0565b9160405161024581611b44565b61024e84611f0c565b8152600184015460208201526002840154604082015261027060038501611f0c565b60608201526004840154608082015261010060ff600761029260058801611fe8565b968760a08601520154818116151560c0850152818160081c16151560e085015260101c1615159101526040519260a084018481106001600160401b0382111761049a576040528352602083016001815261035f60209161034761032a84604051966102fd8289611b7b565b6000885260408a0197885260608a01986064358a5260808b019b8c52604051938492839251928391611c9d565b600f90820190815203019020965180518855602001516001880155565b511515600286019060ff801983541691151516179055565b600384019151908151916001600160401b03831161049a576103818454611ed2565b601f811161045f575b5081601f84116001146103ee575082600595936103e19895936103c4936000926103e3575b50508160011b916000199060031b1c19161790565b90555b516004840155519101906020600191805184550151910155565b005b0151905038806103af565b9190601f1984168560005283600020936000905b828210610447575050926001928592600598966103e19b98961061042e575b505050811b0190556103c7565b015160001960f88460031b161c19169055388080610421565b80600186978294978701518155019601940190610402565b61048a908560005283600020601f860160051c810191858710610490575b601f0160051c01906121a8565b3861038a565b909150819061047d565b634e487b7160e01b600052604160045260246000fd5b60405162461bcd60e51b815260206004820152601e60248201527f4c6f67697374696373206f7264657220616c72656164792065786973747300006044820152606490fd5b600080fd5b346104f557602061051361050d36611e25565b90612b11565b6040519015158152f35b346104f557602061051361053036611dd5565b906129f6565b60a03660031901126104f55761054a611b2e565b6024356001600160401b0381116104f557610569903690600401611bb7565b60403660631901126104f5576040519061058282611b60565b6064358252608435602083015260443515610bb4576001600160a01b0383166000908152600c602052604090206105b99082611cc0565b54928315610b7b57604435840293840460443503610b2b57833403610b41576000194301438111610b2b5760405160208101914283523360601b806040840152605483015240606882015260688152610613608882611b7b565b5190209260409283516106268582611b7b565b601081526f181899199a1a9b1b9c1cb0b131b232b360811b60208201526060908551966106538389611b7b565b868852601f1983013660208a013760005b60208110610a96575050506001600160a01b0384166000908152600d6020908152868220338352905285902060ff906007906106a09089611cc0565b015416610a5b5784516106b281611b44565b82815260443560208083019182528783018a81528484018a815234608086015260a08501889052600060c08601819052600160e087015261010086018190526001600160a01b038a168152600d84528a812033825290935291899020610718908b611cc0565b9284518051906001600160401b03821161049a576107368654611ed2565b601f8111610a29575b50602090601f83116001146109c2576107709291600091836109b75750508160011b916000199060031b1c19161790565b84555b516001840155516002830155518051906001600160401b03821161049a5761079e6003840154611ed2565b601f8111610982575b50602090601f83116001146108e4577f85c4696d0f8a7299d49d94ece954869348bf401539ecc7f039c96a37d4d76835969593610807846108e09e9795610100956007956000926103e35750508160011b916000199060031b1c19161790565b60038201555b6080840151600482015560a084015180516005830155602001516006820155019161084a60c08201511515849060ff801983541691151516179055565b60e0810151151561ff0084549160081b169061ff0019161783550151151562ff000082549160101b169062ff00001916179055602085519501519061089988519460c0865260c0860190611d5e565b95604435602086015288850152830152608082015280830360a0820152806108cb339560018060a01b03169488611d5e565b0390a351918291602083526020830190611d5e565b0390f35b9060038401600052806000209160005b601f198516811061096a575093600184610100946108e09f9896947f85c4696d0f8a7299d49d94ece954869348bf401539ecc7f039c96a37d4d768359b9a98600796601f19811610610951575b505050811b01600382015561080d565b015160001960f88460031b161c191690558f8080610941565b919260206001819286850151815501940192016108f4565b6109b190600385016000526020600020601f850160051c8101916020861061049057601f0160051c01906121a8565b8b6107a7565b015190508f806103af565b90601f1983169187600052816000209260005b818110610a1157509084600195949392106109f8575b505050811b018455610773565b015160001960f88460031b161c191690558e80806109eb565b929360206001819287860151815501950193016109d5565b610a5590876000526020600020601f850160051c8101916020861061049057601f0160051c01906121a8565b8e61073f565b845162461bcd60e51b81526020600482015260146024820152734f7264657220616c72656164792065786973747360601b6044820152606490fd5b81811a6001600160f81b0319610ab3600483901c600f1686612f8d565b5116908260011b9183830460021484151715610b2b5760001a610ad6838d612f8d565b536001600160f81b031990610aee90600f1686612f8d565b51166000916001019182600111610b17576001939291610b10911a918c612f8d565b5301610664565b634e487b7160e01b81526011600452602490fd5b634e487b7160e01b600052601160045260246000fd5b60405162461bcd60e51b8152602060048201526012602482015271125b98dbdc9c9958dd08115512081cd95b9d60721b6044820152606490fd5b60405162461bcd60e51b8152602060048201526011602482015270141c9bd91d58dd081b9bdd08199bdd5b99607a1b6044820152606490fd5b60405162461bcd60e51b815260206004820152601960248201527f5175616e74697479206d75737420626520706f736974697665000000000000006044820152606490fd5b346104f55760403660031901126104f5576004356001600160401b0381116104f557610c29903690600401611bb7565b602435908115610c4e57610c4b9033600052600c602052604060002090611cc0565b55005b60405162461bcd60e51b8152602060048201526024808201527f556e6974207072696365206d7573742062652067726561746572207468616e206044820152637a65726f60e01b6064820152608490fd5b346104f557602060ff6007610ce1610cb636611d83565b6001600160a01b039283166000908152600d8852604080822093909416815291875291902090611cc0565b015460101c166040519015158152f35b346104f5576102003660031901126104f557610d0c36611ce6565b610d1536611d0e565b9060803660c31901126104f55760405191610d2f83611b60565b610d3a3660c4612006565b8352610d4836610104612006565b60208401526080366101431901126104f55760405191610d6783611b60565b610d7336610144612006565b8352610d8136610184612006565b60208401526101e435926001600160401b0384116104f557602094610dad610513953690600401611bb7565b936101c4359360a435916084359161285e565b346104f55760203660031901126104f5576004356001600160401b0381116104f557610dfa610df56020923690600401611bb7565b6127f9565b604051908152f35b346104f5576101203660031901126104f557610e1d36611ce6565b610e2636611d0e565b60c4356001600160401b0381116104f557610e45903690600401612062565b60e4356001600160401b0381116104f557610e64903690600401612062565b9161010435936001600160401b0385116104f557366023860112156104f5578460040135610e918161204b565b95610e9f6040519788611b7b565b8187526024602088019260051b820101903682116104f55760248101925b828410610ed95760206105138a8a8a60a4356084358c8c6124fb565b83356001600160401b0381116104f557602091610efd839260243691870101611bb7565b815201930192610ebd565b346104f55760403660031901126104f5576020610dfa610f2736611ce6565b61246c565b346104f55760203660031901126104f5576004356001600160401b0381116104f557610f8c6003610f7c6020610f696108e0953690600401611bb7565b8160405193828580945193849201611c9d565b8101600f81520301902001611f0c565b604051918291602083526020830190611d5e565b346104f55760203660031901126104f5576001600160a01b03610fc1611b2e565b16600052600e6020526020604060002054604051908152f35b346104f55760203660031901126104f5576004356001600160401b0381116104f557611041600561103160206110166040953690600401611bb7565b61101e612453565b5081865193828580945193849201611c9d565b8101600f81520301902001611fe8565b60208251918051835201516020820152f35b346104f5576103e160008080806110d16110d661106f36611bfe565b6001600160a01b03909116808552600d602090815260408087203388529091528520909391906004906110a29083611cc0565b6110b260ff60078301541661212a565b0154938552600d60209081526040808720338852909152852090611cc0565b61220e565b335af16110e16120fa565b5061224a565b346104f55760203660031901126104f55760043560005260006020526108e0610f8c6040600020611f0c565b346104f5576101403660031901126104f55761112e36611ce6565b60803660431901126104f55760405161114681611b60565b611151366044612006565b815261115e366084612006565b6020820190815260403660c31901126104f55760405161117d81611b60565b60c4358152602081019160e43583526040610103193601126104f557604051936111a685611b60565b610104358552602080860196610124358852805160025501516003555160005b600281106112055750505160005b600281106111f0575050516008555160095551600a5551600b55005b600190602083519301928160060155016111d4565b600190602083519301928160040155016111c6565b346104f55760803660031901126104f557602061051360405161123c81611b60565b611247366004612006565b8152611254366044612006565b838201526123c0565b611e6f565b6101003660031901126104f557602061051361127d36611ce6565b61128636611d0e565b9061129036611d36565b60e4359260c4359261236f565b346104f5576113396112e16112b136611d83565b9160018060a01b0316600052600d60205260406000209060018060a01b0316600052602052604060002090611cc0565b6112ea81611f0c565b9060ff600182015491602060028201549161135061130a60038301611f0c565b95600483015496600761131f60058601611fe8565b940154956040519a8b9a6101408c526101408c0190611d5e565b92868b015260408a015288820360608a0152611d5e565b946080870152805160a0870152015160c0850152818116151560e0850152818160081c16151561010085015260101c1615156101208301520390f35b346104f55760203660031901126104f5576004356001600160401b0381116104f5576113c26020610f6981933690600401611bb7565b8101600f8152030190206113d581611fe8565b908260ff6002830154166114286113ee60038501611f0c565b6113ff600560048701549601611fe8565b92604051978789985189520151858801521515604087015260e0606087015260e0860190611d5e565b926080850152805160a0850152015160c08301520390f35b346104f55760403660031901126104f557602435600435600052600160205260406000209081548110156104f55761147791611ea4565b61148757610f8c6108e091611f0c565b634e487b7160e01b600052600060045260246000fd5b346104f5576103e160008080806114b336611bfe565b90338352600d6020526040832060018060a01b038216845260205261151c6110d160046114e36040872086611cc0565b6114f360ff60078301541661212a565b0154338652600d602090815260408088206001600160a01b038716895290915286209094611cc0565b6001600160a01b03165af16110e16120fa565b346104f5576103e161154036611c40565b91612286565b346104f5576020600361157061155b36611e25565b93908160405193828580945193849201611c9d565b8101600f81520301902061158a60ff60028301541661212a565b0181516001600160401b03811161049a576115a58254611ed2565b601f811161164f575b50602092601f82116001146115ef576115e0929382916000926115e45750508160011b916000199060031b1c19161790565b9055005b0151905084806103af565b601f1982169383600052806000209160005b868110611637575083600195961061161e575b505050811b019055005b015160001960f88460031b161c19169055838080611614565b91926020600181928685015181550194019201611601565b61167b90836000526020600020601f840160051c8101916020851061049057601f0160051c01906121a8565b836115ae565b346104f55760206116b261169436611bfe565b6001600160a01b039091166000908152600c84526040902090611cc0565b54604051908152f35b346104f5576116d36116cc36611dd5565b919061246c565b6000526000602052604060002081516001600160401b03811161049a576116fa8254611ed2565b601f811161177b575b50602092601f8211600114611735576115e0929382916000926115e45750508160011b916000199060031b1c19161790565b601f1982169383600052806000209160005b868110611763575083600195961061161e57505050811b019055005b91926020600181928685015181550194019201611747565b6117a790836000526020600020601f840160051c8101916020851061049057601f0160051c01906121a8565b83611703565b346104f5576117ff6117c16112b136611d83565b600181015460ff600283015460078401549061180d6117eb60036117e488611f0c565b9701611f0c565b60405197889760e0895260e0890190611d5e565b908782036020890152611d5e565b936040860152606085015281811615156080850152818160081c16151560a085015260101c16151560c08301520390f35b346104f55760403660031901126104f55761185b610f2736611ce6565b600052600160205260406000208054906118748261204b565b916118826040519384611b7b565b80835260208301809260005260206000206000915b83831061190557848660405191829160208301906020845251809152604083019060408160051b85010192916000905b8282106118d657505050500390f35b919360019193955060206118f58192603f198a82030186528851611d5e565b96019201920185949391926118c7565b60016020819261191485611f0c565b815201920192019190611897565b346104f5576110d160026119c361193836611c40565b6001600160a01b039092166000818152600d6020908152604080832033845290915290209095919491929061196d9086611cc0565b90600782019061199a82549161198560ff841661212a565b61199460ff8460081c16612169565b88612b11565b611a0d575b505001546000858152600d6020908152604080832033845290915290209093611cc0565b81600052600e6020526040600020805490828201809211610b2b57556040519081527fcbdae027b851b48705e1072c0c9ceedd7be4c50cf428125e072438065f258d6e60203392a3005b62ff0000191662010000179055868061199f565b611a2a36611bfe565b9033600052600e602052604060002054908115611af25733600052600e60205260006040812055600080808085335af1611a626120fa565b5015611aba577fd558c6fa7308993d16f63a349da75aca9f69611232918e75423f0eaa6d68545090611a9f60405194604086526040860190611d5e565b60208501939093526001600160a01b031692339281900390a3005b60405162461bcd60e51b815260206004820152601060248201526f2a3930b739b332b9103330b4b632b21760811b6044820152606490fd5b60405162461bcd60e51b81526020600482015260146024820152734e6f2066756e647320746f20776974686472617760601b6044820152606490fd5b600435906001600160a01b03821682036104f557565b61012081019081106001600160401b0382111761049a57604052565b604081019081106001600160401b0382111761049a57604052565b90601f801991011681019081106001600160401b0382111761049a57604052565b6001600160401b03811161049a57601f01601f191660200190565b81601f820112156104f557803590611bce82611b9c565b92611bdc6040519485611b7b565b828452602083830101116104f557816000926020809301838601378301015290565b9060406003198301126104f5576004356001600160a01b03811681036104f55791602435906001600160401b0382116104f557611c3d91600401611bb7565b90565b60606003198201126104f5576004356001600160a01b03811681036104f557916024356001600160401b0381116104f55782611c7e91600401611bb7565b91604435906001600160401b0382116104f557611c3d91600401611bb7565b60005b838110611cb05750506000910152565b8181015183820152602001611ca0565b602090611cda928260405194838680955193849201611c9d565b82019081520301902090565b60409060031901126104f55760405190611cff82611b60565b60043582526024356020830152565b60409060431901126104f55760405190611d2782611b60565b60443582526064356020830152565b60409060831901126104f55760405190611d4f82611b60565b608435825260a4356020830152565b90602091611d7781518092818552858086019101611c9d565b601f01601f1916010190565b60606003198201126104f5576004356001600160a01b03811681036104f557916024356001600160a01b03811681036104f55791604435906001600160401b0382116104f557611c3d91600401611bb7565b9060606003198301126104f557604060048303126104f557604051611df981611b60565b6004358152602435602082015291604435906001600160401b0382116104f557611c3d91600401611bb7565b9060406003198301126104f5576004356001600160401b0381116104f55782611e5091600401611bb7565b91602435906001600160401b0382116104f557611c3d91600401611bb7565b346104f55760403660031901126104f557611e8c610f2736611ce6565b60005260006020526108e0610f8c6040600020611f0c565b8054821015611ebc5760005260206000200190600090565b634e487b7160e01b600052603260045260246000fd5b90600182811c92168015611f02575b6020831014611eec57565b634e487b7160e01b600052602260045260246000fd5b91607f1691611ee1565b9060405191826000825492611f2084611ed2565b8084529360018116908115611f8e5750600114611f47575b50611f4592500383611b7b565b565b90506000929192526020600020906000915b818310611f72575050906020611f459282010138611f38565b6020919350806001915483858901015201910190918492611f59565b905060209250611f4594915060ff191682840152151560051b82010138611f38565b60405190611fbd82611b60565b600a548252600b546020830152565b60405190611fd982611b60565b60085482526009546020830152565b90604051611ff581611b60565b602060018294805484520154910152565b9080601f830112156104f55760408051926120218285611b7b565b839181019283116104f557905b82821061203b5750505090565b813581526020918201910161202e565b6001600160401b03811161049a5760051b60200190565b81601f820112156104f5578035906120798261204b565b926120876040519485611b7b565b82845260208085019360071b830101918183116104f557602001925b8284106120b1575050505090565b60006080858403126120f7575060206080916040516120cf81611b60565b6120d98588612006565b81526120e88560408901612006565b838201528152019301926120a3565b80fd5b3d15612125573d9061210b82611b9c565b916121196040519384611b7b565b82523d6000602084013e565b606090565b1561213157565b60405162461bcd60e51b815260206004820152601060248201526f4f72646572206e6f742061637469766560801b6044820152606490fd5b1561217057565b60405162461bcd60e51b815260206004820152601060248201526f119d5b991cc81b9bdd081b1bd8dad95960821b6044820152606490fd5b8181106121b3575050565b600081556001016121a8565b6121c98154611ed2565b90816121d3575050565b81601f600093116001146121e5575055565b8183526020832061220191601f0160051c8101906001016121a8565b8082528160208120915555565b600760009161221c816121bf565b826001820155826002820155612234600382016121bf565b8260048201558260058201558260068201550155565b1561225157565b60405162461bcd60e51b815260206004820152600d60248201526c1499599d5b990819985a5b1959609a1b6044820152606490fd5b9133600052600d602052604060002060018060a01b0384166000526020526122b2604060002083611cc0565b6122db60078201926122ca60ff855460081c16612169565b6122d660058401611fe8565b6129f6565b156123335750600160ff198254161790557f40078477a6dc67e30ec77e8a4b1d8749dd098056fe79ee602c2456b60e7c8d0a604051926020845260018060a01b0316928061232e33946020830190611d5e565b0390a3565b60008093819350611f459561151c6110d160028596015493338652600d6020526040862060018060a01b03851687526020526040862090611cc0565b9261238261238892939561238e95612b8e565b92612b8e565b90612be2565b8151815114918215926123ac575b50506123a757600190565b600090565b60209192508101519101511415388061239c565b6040516123cc81611b60565b600081526020810191600083526040516123e581611b60565b600081526020810192600084528251519051149384612442575b5083612431575b508261241d575b505061241857600090565b600190565b60209192508101510151905114388061240d565b602082015151905114925038612406565b8251602001519051149350386123ff565b6040519061246082611b60565b60006020838281520152565b602081519101516040519060208201928352604082015260408152612492606082611b7b565b51902090565b906124a28261204b565b6124af6040519182611b7b565b82815280926124c0601f199161204b565b0190602036910137565b805115611ebc5760200190565b805160011015611ebc5760400190565b8051821015611ebc5760209160051b010190565b91949296959687519561250d8761204b565b9661251b6040519889611b7b565b80885261252a601f199161204b565b0160005b8181106127e857505090612566916125468a51612498565b938560405161255481611b60565b6002548152600354602082015261236f565b156127dd5760005b84518110156126405761258a61258482876124e7565b516123c0565b61263457806125a461259e6001938b6124e7565b516127f9565b6125ae82856124e7565b526126036125dd6125d06125c284876124e7565b516125cb611fb0565b612b8e565b6125d8611fcc565b612be2565b6125e783896124e7565b516125f187612c42565b906125fc858a6124e7565b5192612d17565b61260e575b0161256e565b612618818a6124e7565b5161262382896124e7565b5261262e81886124e7565b50612608565b50600096505050505050565b50509150506126519192935061246c565b600052600160205260406000209080519068010000000000000000821161049a5782548284558083106127ae575b50602001916000526020600020916000905b8282106126a15750505050600190565b80518051906001600160401b03821161049a576126be8654611ed2565b601f811161277c575b50602090601f831160011461270f5792612700836001959460209487966000926103e35750508160011b916000199060031b1c19161790565b87555b01940191019092612691565b90601f1983169187600052816000209260005b818110612764575093602093600196938796938388951061274b575b505050811b018755612703565b015160001960f88460031b161c1916905538808061273e565b92936020600181928786015181550195019301612722565b6127a890876000526020600020601f850160051c8101916020861061049057601f0160051c01906121a8565b386126c7565b8360005282602060002091820191015b8181106127cb575061267f565b806127d76001926121bf565b016127be565b506000955050505050565b806060602080938c0101520161252e565b600061283f60209260405161282e85828161281d8183019687815193849201611c9d565b81010301601f198101835282611b7b565b604051928392839251928391611c9d565b8101039060025afa156128525760005190565b6040513d6000823e3d90fd5b9592919097969761286e856123c0565b80156129e4575b6129d7579061288c92918760405161255481611b60565b156129cd57906128a56125d06128b594936125cb611fb0565b906128af85612c42565b91612d17565b6128c1575b5060019150565b6128ca9061246c565b6000526000602052604060002082516001600160401b03811161049a576128f18254611ed2565b601f811161299b575b506020601f821160011461293657819061292c9394956000926103e35750508160011b916000199060031b1c19161790565b90555b80386128ba565b601f1982169083600052806000209160005b8181106129835750958360019596971061296a575b505050811b01905561292f565b015160001960f88460031b161c1916905538808061295d565b9192602060018192868b015181550194019201612948565b6129c790836000526020600020601f840160051c8101916020851061049057601f0160051c01906121a8565b386128fa565b5060009450505050565b5060009750505050505050565b506129ee896127f9565b841415612875565b6129ff9061246c565b9060005b826000526001602052604060002054811015612b0957826000526001602052612a30816040600020611ea4565b506040516020810191816000825492612a4884611ed2565b9360018116908115612aeb5750600114612aa9575b50612a71925003601f198101835282611b7b565b5190206040516020810190612a91602082875161281d8187858c01611c9d565b51902014612aa157600101612a03565b505050600190565b9150506000528160206000206000905b838210612ad15750506020612a719282010138612a5d565b602091925080600191548385880101520191018391612ab9565b60ff1916875250612a71938015150283016020019150389050612a5d565b505050600090565b90612b7e6020600492612b6c60405191612b3381518092868087019101611c9d565b820191611f1f60f21b84840152610df5600282868a51818c0197612b5b82602283018b611c9d565b01010301601f198101835282611b7b565b94604051938492839251928391611c9d565b8101600f81520301902001541490565b9190604051612b9c81611b60565b600081526000602082015260808194606093602060405192612bbe8785611b7b565b863685378051845201516020830152604082015260076107cf195a01fa156104f557565b60609092919260c060405191612bf783611b60565b6000835260006020840152602083968160809360405194612c188187611b7b565b368637805185520151828401528051604084015201518482015260066107cf195a01fa156104f557565b604051612c4e81611b60565b60008152600060208201525080511580612d0b575b612cf0577f30644e72e131a029b85045b68181585d97816a916871ca8d3c208c16d87cfd4760208251920151067f30644e72e131a029b85045b68181585d97816a916871ca8d3c208c16d87cfd47037f30644e72e131a029b85045b68181585d97816a916871ca8d3c208c16d87cfd478111610b2b5760405191612ce683611b60565b8252602082015290565b50604051612cfd81611b60565b600081526000602082015290565b50602081015115612c63565b929060609260405194612d2a8587611b7b565b60028652601f19850160005b818110612f685750612d4b6040519687611b7b565b6002865260005b818110612f25575050612d64866124ca565b52612d6e856124ca565b50612d78856124d7565b52612d82846124d7565b50612d8c836124ca565b52612d96826124ca565b50612da0826124d7565b52612daa816124d7565b50815191815183036104f557600683029280840460061481151715610b2b57612dd284612498565b9260005b828110612e1357505050506020918291604051938492612df68285611b7b565b8136853760051b910160086107cf195a01fa156104f55751151590565b612e1d81856124e7565b5151600682029082820460061483151715610b2b57612e3c82886124e7565b526020612e4983876124e7565b51015160009060018301808411612f1157612e6490896124e7565b52612e6f83856124e7565b515151905060028201808311610b2b57612e8990886124e7565b526020612e9683856124e7565b5151015160009060038301808411612f1157612eb290896124e7565b526020612ebf84866124e7565b51015151905060048201808311610b2b57612eda90886124e7565b52602080612ee884866124e7565b510151015190600060058201809211610b17575090612f0a60019392886124e7565b5201612dd6565b634e487b7160e01b83526011600452602483fd5b602090604051612f3481611b60565b60408051612f428282611b7b565b813682378252805190612f558183611b7b565b3682378382015282828a01015201612d52565b602090604051612f7781611b60565b6000815260008382015282828b01015201612d36565b908151811015611ebc57016020019056fea2646970667358221220fad3e9d7efe7a98f263e860e3caaf0a525ace282fb5343257bb12c94583ff2b264736f6c634300081c0033",
+	ABI: "[{\"inputs\":[],\"stateMutability\":\"nonpayable\",\"type\":\"constructor\"},{\"anonymous\":false,\"inputs\":[{\"indexed\":true,\"internalType\":\"address\",\"name\":\"_seller\",\"type\":\"address\"},{\"indexed\":true,\"internalType\":\"address\",\"name\":\"_buyer\",\"type\":\"address\"},{\"indexed\":false,\"internalType\":\"string\",\"name\":\"productID\",\"type\":\"string\"},{\"indexed\":false,\"internalType\":\"uint256\",\"name\":\"quantity\",\"type\":\"uint256\"},{\"indexed\":false,\"internalType\":\"uint256\",\"name\":\"buyerPubKeyX\",\"type\":\"uint256\"},{\"indexed\":false,\"internalType\":\"uint256\",\"name\":\"buyerPubKeyY\",\"type\":\"uint256\"},{\"indexed\":false,\"internalType\":\"uint256\",\"name\":\"totalPrice\",\"type\":\"uint256\"},{\"indexed\":false,\"internalType\":\"string\",\"name\":\"orderID\",\"type\":\"string\"}],\"name\":\"BroadcastPubKey\",\"type\":\"event\"},{\"anonymous\":false,\"inputs\":[{\"indexed\":true,\"internalType\":\"bytes32\",\"name\":\"issuer\",\"type\":\"bytes32\"},{\"indexed\":true,\"internalType\":\"uint256\",\"name\":\"version\",\"type\":\"uint256\"},{\"indexed\":false,\"internalType\":\"uint256\",\"name\":\"epoch\",\"type\":\"uint256\"},{\"indexed\":false,\"internalType\":\"uint256\",\"name\":\"previousExpires\",\"type\":\"uint256\"}],\"name\":\"IssuerKeyRotated\",\"type\":\"event\"},{\"anonymous\":false,\"inputs\":[{\"indexed\":true,\"internalType\":\"address\",\"name\":\"buyer\",\"type\":\"address\"},{\"indexed\":true,\"internalType\":\"address\",\"name\":\"seller\",\"type\":\"address\"},{\"indexed\":false,\"internalType\":\"uint256\",\"name\":\"amount\",\"type\":\"uint256\"}],\"name\":\"OrderCompleted\",\"type\":\"event\"},{\"anonymous\":false,\"inputs\":[{\"indexed\":true,\"internalType\":\"address\",\"name\":\"seller\",\"type\":\"address\"},{\"indexed\":true,\"internalType\":\"address\",\"name\":\"buyer\",\"type\":\"address\"},{\"indexed\":false,\"internalType\":\"string\",\"name\":\"orderID\",\"type\":\"string\"}],\"name\":\"SellerAccepted\",\"type\":\"event\"},{\"anonymous\":false,\"inputs\":[{\"indexed\":true,\"internalType\":\"address\",\"name\":\"seller\",\"type\":\"address\"},{\"indexed\":true,\"internalType\":\"address\",\"name\":\"buyer\",\"type\":\"address\"},{\"indexed\":false,\"internalType\":\"string\",\"name\":\"orderID\",\"type\":\"string\"},{\"indexed\":false,\"internalType\":\"uint256\",\"name\":\"payment\",\"type\":\"uint256\"}],\"name\":\"SellerGetPayment\",\"type\":\"event\"},{\"inputs\":[{\"components\":[{\"internalType\":\"uint256\",\"name\":\"X\",\"type\":\"uint256\"},{\"internalType\":\"uint256\",\"name\":\"Y\",\"type\":\"uint256\"}],\"internalType\":\"structBC_SID.G1Point\",\"name\":\"pk\",\"type\":\"tuple\"},{\"internalType\":\"string\",\"name\":\"attribute\",\"type\":\"string\"}],\"name\":\"CheckClaim\",\"outputs\":[{\"internalType\":\"bool\",\"name\":\"\",\"type\":\"bool\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[{\"components\":[{\"internalType\":\"uint256\",\"name\":\"X\",\"type\":\"uint256\"},{\"internalType\":\"uint256\",\"name\":\"Y\",\"type\":\"uint256\"}],\"internalType\":\"structBC_SID.G1Point\",\"name\":\"pk\",\"type\":\"tuple\"},{\"internalType\":\"string\",\"name\":\"attribute\",\"type\":\"string\"},{\"internalType\":\"bytes32\",\"name\":\"_issuer\",\"type\":\"bytes32\"}],\"name\":\"CheckClaimFrom\",\"outputs\":[{\"internalType\":\"bool\",\"name\":\"\",\"type\":\"bool\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"address\",\"name\":\"_sellerAddr\",\"type\":\"address\"},{\"internalType\":\"address\",\"name\":\"_buyerAddr\",\"type\":\"address\"},{\"internalType\":\"string\",\"name\":\"_orderID\",\"type\":\"string\"},{\"internalType\":\"uint256\",\"name\":\"_code\",\"type\":\"uint256\"},{\"components\":[{\"internalType\":\"uint256\",\"name\":\"X\",\"type\":\"uint256\"},{\"internalType\":\"uint256\",\"name\":\"Y\",\"type\":\"uint256\"}],\"internalType\":\"structBC_SID.G1Point\",\"name\":\"_SN\",\"type\":\"tuple\"}],\"name\":\"CreateLogisticsOrder\",\"outputs\":[],\"stateMutability\":\"payable\",\"type\":\"function\"},{\"inputs\":[],\"name\":\"CurrentKeyVersion\",\"outputs\":[{\"internalType\":\"uint256\",\"name\":\"\",\"type\":\"uint256\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[{\"components\":[{\"internalType\":\"uint256\",\"name\":\"X\",\"type\":\"uint256\"},{\"internalType\":\"uint256\",\"name\":\"Y\",\"type\":\"uint256\"}],\"internalType\":\"structBC_SID.G1Point\",\"name\":\"g1\",\"type\":\"tuple\"},{\"components\":[{\"internalType\":\"uint256\",\"name\":\"X\",\"type\":\"uint256\"},{\"internalType\":\"uint256\",\"name\":\"Y\",\"type\":\"uint256\"}],\"internalType\":\"structBC_SID.G1Point\",\"name\":\"y1\",\"type\":\"tuple\"},{\"components\":[{\"internalType\":\"uint256\",\"name\":\"X\",\"type\":\"uint256\"},{\"internalType\":\"uint256\",\"name\":\"Y\",\"type\":\"uint256\"}],\"internalType\":\"structBC_SID.G1Point\",\"name\":\"a1\",\"type\":\"tuple\"},{\"internalType\":\"uint256\",\"name\":\"c\",\"type\":\"uint256\"},{\"internalType\":\"uint256\",\"name\":\"z\",\"type\":\"uint256\"}],\"name\":\"DLVerify\",\"outputs\":[{\"internalType\":\"bool\",\"name\":\"\",\"type\":\"bool\"}],\"stateMutability\":\"payable\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"address\",\"name\":\"_sellerAddr\",\"type\":\"address\"},{\"internalType\":\"address\",\"name\":\"_buyerAddr\",\"type\":\"address\"},{\"internalType\":\"string\",\"name\":\"_orderID\",\"type\":\"string\"}],\"name\":\"GetConfirmResult\",\"outputs\":[{\"internalType\":\"bool\",\"name\":\"\",\"type\":\"bool\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"string\",\"name\":\"_orderID\",\"type\":\"string\"}],\"name\":\"GetCurrentSite\",\"outputs\":[{\"internalType\":\"string\",\"name\":\"\",\"type\":\"string\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"uint256\",\"name\":\"_version\",\"type\":\"uint256\"}],\"name\":\"GetIssuerKey\",\"outputs\":[{\"components\":[{\"internalType\":\"uint256\",\"name\":\"X\",\"type\":\"uint256\"},{\"internalType\":\"uint256\",\"name\":\"Y\",\"type\":\"uint256\"}],\"internalType\":\"structBC_SID.G1Point\",\"name\":\"\",\"type\":\"tuple\"},{\"components\":[{\"internalType\":\"uint256\",\"name\":\"X\",\"type\":\"uint256\"},{\"internalType\":\"uint256\",\"name\":\"Y\",\"type\":\"uint256\"}],\"internalType\":\"structBC_SID.G1Point[]\",\"name\":\"\",\"type\":\"tuple[]\"},{\"internalType\":\"uint256\",\"name\":\"\",\"type\":\"uint256\"},{\"internalType\":\"uint256\",\"name\":\"\",\"type\":\"uint256\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"address\",\"name\":\"_addr\",\"type\":\"address\"},{\"internalType\":\"string\",\"name\":\"_productID\",\"type\":\"string\"}],\"name\":\"GetProduct\",\"outputs\":[{\"internalType\":\"uint256\",\"name\":\"\",\"type\":\"uint256\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[],\"name\":\"GetRevocationEpoch\",\"outputs\":[{\"internalType\":\"uint256\",\"name\":\"\",\"type\":\"uint256\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"string\",\"name\":\"_orderID\",\"type\":\"string\"}],\"name\":\"GetSN\",\"outputs\":[{\"components\":[{\"internalType\":\"uint256\",\"name\":\"X\",\"type\":\"uint256\"},{\"internalType\":\"uint256\",\"name\":\"Y\",\"type\":\"uint256\"}],\"internalType\":\"structBC_SID.G1Point\",\"name\":\"\",\"type\":\"tuple\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[],\"name\":\"GetValidityEpoch\",\"outputs\":[{\"internalType\":\"uint256\",\"name\":\"\",\"type\":\"uint256\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"uint256\",\"name\":\"_version\",\"type\":\"uint256\"}],\"name\":\"IsKeyValid\",\"outputs\":[{\"internalType\":\"bool\",\"name\":\"\",\"type\":\"bool\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[{\"components\":[{\"internalType\":\"uint256\",\"name\":\"X\",\"type\":\"uint256\"},{\"internalType\":\"uint256\",\"name\":\"Y\",\"type\":\"uint256\"}],\"internalType\":\"structBC_SID.G1Point\",\"name\":\"pk\",\"type\":\"tuple\"}],\"name\":\"IsRevoked\",\"outputs\":[{\"internalType\":\"bool\",\"name\":\"\",\"type\":\"bool\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"uint256\",\"name\":\"_version\",\"type\":\"uint256\"}],\"name\":\"IssuerKeyFingerprint\",\"outputs\":[{\"internalType\":\"bytes32\",\"name\":\"\",\"type\":\"bytes32\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"bytes32\",\"name\":\"_id\",\"type\":\"bytes32\"}],\"name\":\"IssuerKeyVersion\",\"outputs\":[{\"internalType\":\"uint256\",\"name\":\"\",\"type\":\"uint256\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"uint256\",\"name\":\"_version\",\"type\":\"uint256\"}],\"name\":\"KeyIssuerOf\",\"outputs\":[{\"internalType\":\"bytes32\",\"name\":\"\",\"type\":\"bytes32\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"bytes32\",\"name\":\"_id\",\"type\":\"bytes32\"},{\"internalType\":\"address\",\"name\":\"_addr\",\"type\":\"address\"},{\"components\":[{\"internalType\":\"uint256\",\"name\":\"X\",\"type\":\"uint256\"},{\"internalType\":\"uint256\",\"name\":\"Y\",\"type\":\"uint256\"}],\"internalType\":\"structBC_SID.G1Point\",\"name\":\"_pkx\",\"type\":\"tuple\"},{\"components\":[{\"internalType\":\"uint256\",\"name\":\"X\",\"type\":\"uint256\"},{\"internalType\":\"uint256\",\"name\":\"Y\",\"type\":\"uint256\"}],\"internalType\":\"structBC_SID.G1Point[]\",\"name\":\"_pky\",\"type\":\"tuple[]\"},{\"internalType\":\"uint256\",\"name\":\"_epoch\",\"type\":\"uint256\"}],\"name\":\"RegisterIssuer\",\"outputs\":[],\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"address\",\"name\":\"_seller\",\"type\":\"address\"},{\"components\":[{\"internalType\":\"uint256\",\"name\":\"X\",\"type\":\"uint256\"},{\"internalType\":\"uint256\",\"name\":\"Y\",\"type\":\"uint256\"}],\"internalType\":\"structBC_SID.G1Point\",\"name\":\"_nym\",\"type\":\"tuple\"},{\"components\":[{\"internalType\":\"uint256[2]\",\"name\":\"X\",\"type\":\"uint256[2]\"},{\"internalType\":\"uint256[2]\",\"name\":\"Y\",\"type\":\"uint256[2]\"}],\"internalType\":\"structBC_SID.G2Point\",\"name\":\"_u\",\"type\":\"tuple\"},{\"components\":[{\"internalType\":\"uint256[2]\",\"name\":\"X\",\"type\":\"uint256[2]\"},{\"internalType\":\"uint256[2]\",\"name\":\"Y\",\"type\":\"uint256[2]\"}],\"internalType\":\"structBC_SID.G2Point\",\"name\":\"_s\",\"type\":\"tuple\"},{\"components\":[{\"internalType\":\"uint256\",\"name\":\"X\",\"type\":\"uint256\"},{\"internalType\":\"uint256\",\"name\":\"Y\",\"type\":\"uint256\"}],\"internalType\":\"structBC_SID.G1Point\",\"name\":\"_kappa\",\"type\":\"tuple\"},{\"internalType\":\"uint256\",\"name\":\"_vc\",\"type\":\"uint256\"},{\"internalType\":\"uint256[]\",\"name\":\"_rm\",\"type\":\"uint256[]\"},{\"internalType\":\"uint256\",\"name\":\"_rt\",\"type\":\"uint256\"},{\"internalType\":\"string[]\",\"name\":\"_attr\",\"type\":\"string[]\"},{\"internalType\":\"bool[]\",\"name\":\"_disclosed\",\"type\":\"bool[]\"},{\"components\":[{\"internalType\":\"uint256\",\"name\":\"X\",\"type\":\"uint256\"},{\"internalType\":\"uint256\",\"name\":\"Y\",\"type\":\"uint256\"}],\"internalType\":\"structBC_SID.G1Point\",\"name\":\"_tag\",\"type\":\"tuple\"},{\"internalType\":\"uint256\",\"name\":\"_epoch\",\"type\":\"uint256\"},{\"internalType\":\"uint256\",\"name\":\"_keyVersion\",\"type\":\"uint256\"},{\"internalType\":\"bytes32\",\"name\":\"_nonce\",\"type\":\"bytes32\"}],\"name\":\"RegisterNymSet\",\"outputs\":[{\"internalType\":\"bool\",\"name\":\"\",\"type\":\"bool\"}],\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"inputs\":[{\"components\":[{\"internalType\":\"uint256\",\"name\":\"X\",\"type\":\"uint256\"},{\"internalType\":\"uint256\",\"name\":\"Y\",\"type\":\"uint256\"}],\"internalType\":\"structBC_SID.G1Point\",\"name\":\"_pk1\",\"type\":\"tuple\"},{\"components\":[{\"internalType\":\"uint256\",\"name\":\"X\",\"type\":\"uint256\"},{\"internalType\":\"uint256\",\"name\":\"Y\",\"type\":\"uint256\"}],\"internalType\":\"structBC_SID.G1Point\",\"name\":\"_a1\",\"type\":\"tuple\"},{\"internalType\":\"uint256\",\"name\":\"_c\",\"type\":\"uint256\"},{\"internalType\":\"uint256\",\"name\":\"_z\",\"type\":\"uint256\"},{\"components\":[{\"internalType\":\"uint256[2]\",\"name\":\"X\",\"type\":\"uint256[2]\"},{\"internalType\":\"uint256[2]\",\"name\":\"Y\",\"type\":\"uint256[2]\"}],\"internalType\":\"structBC_SID.G2Point[]\",\"name\":\"_u\",\"type\":\"tuple[]\"},{\"components\":[{\"internalType\":\"uint256[2]\",\"name\":\"X\",\"type\":\"uint256[2]\"},{\"internalType\":\"uint256[2]\",\"name\":\"Y\",\"type\":\"uint256[2]\"}],\"internalType\":\"structBC_SID.G2Point[]\",\"name\":\"_s\",\"type\":\"tuple[]\"},{\"components\":[{\"internalType\":\"uint256\",\"name\":\"X\",\"type\":\"uint256\"},{\"internalType\":\"uint256\",\"name\":\"Y\",\"type\":\"uint256\"}],\"internalType\":\"structBC_SID.G1Point[]\",\"name\":\"_kappa\",\"type\":\"tuple[]\"},{\"internalType\":\"uint256[]\",\"name\":\"_vc\",\"type\":\"uint256[]\"},{\"internalType\":\"uint256[]\",\"name\":\"_rm\",\"type\":\"uint256[]\"},{\"internalType\":\"uint256[]\",\"name\":\"_rt\",\"type\":\"uint256[]\"},{\"internalType\":\"string[]\",\"name\":\"_attr\",\"type\":\"string[]\"},{\"internalType\":\"bool[]\",\"name\":\"_disclosed\",\"type\":\"bool[]\"},{\"internalType\":\"bytes32[]\",\"name\":\"_issuers\",\"type\":\"bytes32[]\"},{\"internalType\":\"uint256[]\",\"name\":\"_keyVersions\",\"type\":\"uint256[]\"},{\"components\":[{\"internalType\":\"uint256\",\"name\":\"X\",\"type\":\"uint256\"},{\"internalType\":\"uint256\",\"name\":\"Y\",\"type\":\"uint256\"}],\"internalType\":\"structBC_SID.G1Point\",\"name\":\"_tag\",\"type\":\"tuple\"},{\"internalType\":\"uint256\",\"name\":\"_epoch\",\"type\":\"uint256\"}],\"name\":\"RegisterSIDSet\",\"outputs\":[{\"internalType\":\"bool\",\"name\":\"\",\"type\":\"bool\"}],\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"inputs\":[{\"components\":[{\"internalType\":\"uint256\",\"name\":\"X\",\"type\":\"uint256\"},{\"internalType\":\"uint256\",\"name\":\"Y\",\"type\":\"uint256\"}],\"internalType\":\"structBC_SID.G1Point[]\",\"name\":\"_tags\",\"type\":\"tuple[]\"}],\"name\":\"RevokeTags\",\"outputs\":[],\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"inputs\":[{\"components\":[{\"internalType\":\"uint256\",\"name\":\"X\",\"type\":\"uint256\"},{\"internalType\":\"uint256\",\"name\":\"Y\",\"type\":\"uint256\"}],\"internalType\":\"structBC_SID.G1Point\",\"name\":\"_pkx\",\"type\":\"tuple\"},{\"components\":[{\"internalType\":\"uint256\",\"name\":\"X\",\"type\":\"uint256\"},{\"internalType\":\"uint256\",\"name\":\"Y\",\"type\":\"uint256\"}],\"internalType\":\"structBC_SID.G1Point[]\",\"name\":\"_pky\",\"type\":\"tuple[]\"},{\"internalType\":\"uint256\",\"name\":\"_epoch\",\"type\":\"uint256\"},{\"internalType\":\"uint256\",\"name\":\"_grace\",\"type\":\"uint256\"}],\"name\":\"RotateIssuerKey\",\"outputs\":[],\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"address\",\"name\":\"_seller\",\"type\":\"address\"}],\"name\":\"ScopeBase\",\"outputs\":[{\"components\":[{\"internalType\":\"uint256\",\"name\":\"X\",\"type\":\"uint256\"},{\"internalType\":\"uint256\",\"name\":\"Y\",\"type\":\"uint256\"}],\"internalType\":\"structBC_SID.G1Point\",\"name\":\"\",\"type\":\"tuple\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"uint256\",\"name\":\"_index\",\"type\":\"uint256\"}],\"name\":\"SetNymIndex\",\"outputs\":[],\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"uint256\",\"name\":\"_index\",\"type\":\"uint256\"},{\"internalType\":\"uint256\",\"name\":\"_epoch\",\"type\":\"uint256\"}],\"name\":\"SetValidityEpoch\",\"outputs\":[],\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"string\",\"name\":\"_orderID\",\"type\":\"string\"},{\"internalType\":\"string\",\"name\":\"_siteID\",\"type\":\"string\"}],\"name\":\"UpdateStatus\",\"outputs\":[],\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"inputs\":[{\"components\":[{\"internalType\":\"uint256\",\"name\":\"X\",\"type\":\"uint256\"},{\"internalType\":\"uint256\",\"name\":\"Y\",\"type\":\"uint256\"}],\"internalType\":\"structBC_SID.G1Point\",\"name\":\"_g1\",\"type\":\"tuple\"},{\"components\":[{\"internalType\":\"uint256[2]\",\"name\":\"X\",\"type\":\"uint256[2]\"},{\"internalType\":\"uint256[2]\",\"name\":\"Y\",\"type\":\"uint256[2]\"}],\"internalType\":\"structBC_SID.G2Point\",\"name\":\"_g2\",\"type\":\"tuple\"},{\"components\":[{\"internalType\":\"uint256\",\"name\":\"X\",\"type\":\"uint256\"},{\"internalType\":\"uint256\",\"name\":\"Y\",\"type\":\"uint256\"}],\"internalType\":\"structBC_SID.G1Point\",\"name\":\"_pkx\",\"type\":\"tuple\"},{\"components\":[{\"internalType\":\"uint256\",\"name\":\"X\",\"type\":\"uint256\"},{\"internalType\":\"uint256\",\"name\":\"Y\",\"type\":\"uint256\"}],\"internalType\":\"structBC_SID.G1Point[]\",\"name\":\"_pky\",\"type\":\"tuple[]\"}],\"name\":\"UploadACsParams\",\"outputs\":[],\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"uint256\",\"name\":\"_epoch\",\"type\":\"uint256\"},{\"components\":[{\"internalType\":\"uint256\",\"name\":\"X\",\"type\":\"uint256\"},{\"internalType\":\"uint256\",\"name\":\"Y\",\"type\":\"uint256\"}],\"internalType\":\"structBC_SID.G1Point\",\"name\":\"_base\",\"type\":\"tuple\"}],\"name\":\"UploadRevocationBase\",\"outputs\":[],\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"string\",\"name\":\"_orderID\",\"type\":\"string\"},{\"internalType\":\"string\",\"name\":\"_code\",\"type\":\"string\"}],\"name\":\"VerifyCode\",\"outputs\":[{\"internalType\":\"bool\",\"name\":\"\",\"type\":\"bool\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[{\"components\":[{\"internalType\":\"uint256\",\"name\":\"X\",\"type\":\"uint256\"},{\"internalType\":\"uint256\",\"name\":\"Y\",\"type\":\"uint256\"}],\"internalType\":\"structBC_SID.G1Point\",\"name\":\"_pk1\",\"type\":\"tuple\"},{\"components\":[{\"internalType\":\"uint256[2]\",\"name\":\"X\",\"type\":\"uint256[2]\"},{\"internalType\":\"uint256[2]\",\"name\":\"Y\",\"type\":\"uint256[2]\"}],\"internalType\":\"structBC_SID.G2Point\",\"name\":\"_u\",\"type\":\"tuple\"},{\"components\":[{\"internalType\":\"uint256[2]\",\"name\":\"X\",\"type\":\"uint256[2]\"},{\"internalType\":\"uint256[2]\",\"name\":\"Y\",\"type\":\"uint256[2]\"}],\"internalType\":\"structBC_SID.G2Point\",\"name\":\"_r\",\"type\":\"tuple\"},{\"internalType\":\"uint256\",\"name\":\"_c\",\"type\":\"uint256\"},{\"internalType\":\"uint256\",\"name\":\"_z\",\"type\":\"uint256\"},{\"components\":[{\"internalType\":\"uint256\",\"name\":\"X\",\"type\":\"uint256\"},{\"internalType\":\"uint256\",\"name\":\"Y\",\"type\":\"uint256\"}],\"internalType\":\"structBC_SID.G1Point\",\"name\":\"_a1\",\"type\":\"tuple\"},{\"components\":[{\"internalType\":\"uint256[2]\",\"name\":\"X\",\"type\":\"uint256[2]\"},{\"internalType\":\"uint256[2]\",\"name\":\"Y\",\"type\":\"uint256[2]\"}],\"internalType\":\"structBC_SID.G2Point\",\"name\":\"_a2\",\"type\":\"tuple\"}],\"name\":\"VerifyKeyBinding\",\"outputs\":[{\"internalType\":\"bool\",\"name\":\"\",\"type\":\"bool\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[{\"components\":[{\"internalType\":\"uint256\",\"name\":\"X\",\"type\":\"uint256\"},{\"internalType\":\"uint256\",\"name\":\"Y\",\"type\":\"uint256\"}],\"internalType\":\"structBC_SID.G1Point\",\"name\":\"_kappa\",\"type\":\"tuple\"},{\"components\":[{\"internalType\":\"uint256[2]\",\"name\":\"X\",\"type\":\"uint256[2]\"},{\"internalType\":\"uint256[2]\",\"name\":\"Y\",\"type\":\"uint256[2]\"}],\"internalType\":\"structBC_SID.G2Point\",\"name\":\"_u\",\"type\":\"tuple\"},{\"components\":[{\"internalType\":\"uint256[2]\",\"name\":\"X\",\"type\":\"uint256[2]\"},{\"internalType\":\"uint256[2]\",\"name\":\"Y\",\"type\":\"uint256[2]\"}],\"internalType\":\"structBC_SID.G2Point\",\"name\":\"_s\",\"type\":\"tuple\"},{\"internalType\":\"bool[]\",\"name\":\"_disclosed\",\"type\":\"bool[]\"},{\"internalType\":\"uint256\",\"name\":\"_c\",\"type\":\"uint256\"},{\"internalType\":\"uint256[]\",\"name\":\"_rm\",\"type\":\"uint256[]\"},{\"internalType\":\"uint256\",\"name\":\"_rt\",\"type\":\"uint256\"},{\"components\":[{\"internalType\":\"uint256\",\"name\":\"X\",\"type\":\"uint256\"},{\"internalType\":\"uint256\",\"name\":\"Y\",\"type\":\"uint256\"}],\"internalType\":\"structBC_SID.G1Point\",\"name\":\"_tag\",\"type\":\"tuple\"},{\"internalType\":\"uint256\",\"name\":\"_keyVersion\",\"type\":\"uint256\"}],\"name\":\"VerifyPiV\",\"outputs\":[{\"internalType\":\"bool\",\"name\":\"\",\"type\":\"bool\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"address\",\"name\":\"_seller\",\"type\":\"address\"},{\"internalType\":\"string\",\"name\":\"_orderID\",\"type\":\"string\"}],\"name\":\"buyerCancelOrder\",\"outputs\":[],\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"address\",\"name\":\"_seller\",\"type\":\"address\"},{\"internalType\":\"string\",\"name\":\"_orderID\",\"type\":\"string\"},{\"internalType\":\"string\",\"name\":\"verificationCode\",\"type\":\"string\"}],\"name\":\"buyerConfirmWithCode\",\"outputs\":[],\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"address\",\"name\":\"_seller\",\"type\":\"address\"},{\"internalType\":\"string\",\"name\":\"_productID\",\"type\":\"string\"},{\"internalType\":\"uint256\",\"name\":\"_quantity\",\"type\":\"uint256\"},{\"components\":[{\"internalType\":\"uint256\",\"name\":\"X\",\"type\":\"uint256\"},{\"internalType\":\"uint256\",\"name\":\"Y\",\"type\":\"uint256\"}],\"internalType\":\"structBC_SID.G1Point\",\"name\":\"_buyerPK\",\"type\":\"tuple\"}],\"name\":\"buyerCreateOrder\",\"outputs\":[{\"internalType\":\"string\",\"name\":\"\",\"type\":\"string\"}],\"stateMutability\":\"payable\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"address\",\"name\":\"seller\",\"type\":\"address\"}],\"name\":\"getBalanceOf\",\"outputs\":[{\"internalType\":\"uint256\",\"name\":\"\",\"type\":\"uint256\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"address\",\"name\":\"_seller\",\"type\":\"address\"},{\"internalType\":\"address\",\"name\":\"_buyer\",\"type\":\"address\"},{\"internalType\":\"string\",\"name\":\"_orderID\",\"type\":\"string\"}],\"name\":\"getOrder\",\"outputs\":[{\"internalType\":\"string\",\"name\":\"productID\",\"type\":\"string\"},{\"internalType\":\"string\",\"name\":\"orderID\",\"type\":\"string\"},{\"internalType\":\"uint256\",\"name\":\"quantity\",\"type\":\"uint256\"},{\"internalType\":\"uint256\",\"name\":\"price\",\"type\":\"uint256\"},{\"internalType\":\"bool\",\"name\":\"isOngoing\",\"type\":\"bool\"},{\"internalType\":\"bool\",\"name\":\"isLocked\",\"type\":\"bool\"},{\"internalType\":\"bool\",\"name\":\"isBuyerConfirm\",\"type\":\"bool\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[{\"components\":[{\"internalType\":\"uint256\",\"name\":\"X\",\"type\":\"uint256\"},{\"internalType\":\"uint256\",\"name\":\"Y\",\"type\":\"uint256\"}],\"internalType\":\"structBC_SID.G1Point\",\"name\":\"pk\",\"type\":\"tuple\"}],\"name\":\"getSIDSet\",\"outputs\":[{\"internalType\":\"string[]\",\"name\":\"\",\"type\":\"string[]\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"address\",\"name\":\"_buyer\",\"type\":\"address\"},{\"internalType\":\"string\",\"name\":\"_orderID\",\"type\":\"string\"},{\"internalType\":\"string\",\"name\":\"attribute\",\"type\":\"string\"}],\"name\":\"sellerAcceptOrder\",\"outputs\":[],\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"address\",\"name\":\"_buyer\",\"type\":\"address\"},{\"internalType\":\"string\",\"name\":\"_orderID\",\"type\":\"string\"}],\"name\":\"sellerCancelOrder\",\"outputs\":[],\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"string\",\"name\":\"productID\",\"type\":\"string\"},{\"internalType\":\"uint256\",\"name\":\"unitPrice\",\"type\":\"uint256\"}],\"name\":\"setProductPrice\",\"outputs\":[],\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"address\",\"name\":\"_buyerAddr\",\"type\":\"address\"},{\"internalType\":\"string\",\"name\":\"_orderID\",\"type\":\"string\"}],\"name\":\"withdrawPayment\",\"outputs\":[],\"stateMutability\":\"payable\",\"type\":\"function\"}]",
+	Bin: "0x60808060405234602757600780546001600160a01b03191633179055615ee0908161002d8239f35b600080fdfe610220604052600436101561001357600080fd5b60003560e01c80630a6307e5146124d05780630c4efd2814612486578063122030f314612388578063146ac099146122a45780631614e38e146121e35780631aa4a01d146121aa5780631db9a94b1461206f5780631fe7c5141461204b57806328a431af146120345780632ea8ac7b14611e39578063303f758014611da757806332ad835114611d7b5780633b146cfe14611d5d5780633f7526ac14611d3f5780634ea85bd114611d04578063722f722314611cbe578063778b9d1614611a5c578063864a2599146119df5780638aafae65146118b157806391f0814b1461186e578063968b63141461182657806397305c311461177457806399927c35146116e05780639b4b8d2a1461165a5780639b96eece146116205780639c0f7447146115ac5780639f83afcc14611508578063a65243f214611363578063b7ee060214611183578063bc6a4a9014611041578063d8483c7514610fef578063dc4b6e7b14610f4a578063e1a7d4321461087d578063e200804114610830578063e931de80146107eb578063ed758406146107d2578063f2e549021461075b578063fb61bd731461073d578063fedbe99d146103dc578063ff922e8a1461026b5763ffc993ab146101e057600080fd5b34610266576020366003190112610266576004356001600160401b03811161026657610210903690600401612a48565b61022560018060a01b036007541633146135e4565b60005b81518110156102645780610247610241600193856130ec565b51614036565b600052601560205260406000208260ff1982541617905501610228565b005b600080fd5b34610266576102e0366003190112610266576102856125dd565b61028e366127da565b906080366063190112610266576040516102a781612625565b6102b23660646128fd565b81526102bf3660a46128fd565b602082015260803660e3190112610266576040516102dc81612625565b6102e73660e46128fd565b81526102f5366101246128fd565b6020820152604036610163190112610266576040519161031483612625565b6101643583526101843560208401526101c4356001600160401b03811161026657610343903690600401612acb565b93610204356001600160401b03811161026657610364903690600401612b28565b610224356001600160401b03811161026657610384903690600401612ba6565b90604036610243190112610266576020976103d297604051946103a686612625565b610244358652610264358b8701526102c435986102a4359861028435986101e435956101a43594613cc5565b6040519015158152f35b60c0366003190112610266576103f06125dd565b6103f86125f3565b906044356001600160401b0381116102665761041890369060040161267c565b610421366127b2565b926040519260ff60028451956020818188019861043f81838c612802565b810160238152030190200154166106f85760018060a01b0316600052602160205260406000209060018060a01b0316600052602052610482604060002082612c7f565b9160405161048f81612609565b61049884612e0d565b815260018401546020820152600284015460408201526104ba60038501612e0d565b60608201526004840154608082015261010060ff60076104dc60058801612f1b565b968760a08601520154818116151560c0850152818160081c16151560e085015260101c1615159101526040519260a084018481106001600160401b038211176106e257604052835260208301600181526105a960209161059161057484604051966105478289612640565b6000885260408a0197885260608a01986064358a5260808b019b8c52604051938492839251928391612802565b602390820190815203019020965180518855602001516001880155565b511515600286019060ff801983541691151516179055565b600384019151908151916001600160401b0383116106e2576105cb8454612d23565b601f81116106a7575b5081601f84116001146106365750826005959361026498959361060e9360009261062b575b50508160011b916000199060031b1c19161790565b90555b516004840155519101906020600191805184550151910155565b0151905089806105f9565b9190601f1984168560005283600020936000905b82821061068f575050926001928592600598966102649b989610610676575b505050811b019055610611565b015160001960f88460031b161c19169055888080610669565b8060018697829497870151815501960194019061064a565b6106d2908560005283600020601f860160051c8101918587106106d8575b601f0160051c0190612d5d565b876105d4565b90915081906106c5565b634e487b7160e01b600052604160045260246000fd5b60405162461bcd60e51b815260206004820152601e60248201527f4c6f67697374696373206f7264657220616c72656164792065786973747300006044820152606490fd5b34610266576000366003190112610266576020601454604051908152f35b346102665760203660031901126102665760043561078460018060a01b036007541633146135e4565b7f6e0956cda88cad152e89927e53611735b61a5c762d1428573c6931b0a5efcb01546000908152600a60205260409020546107c090821061361e565b601c805460ff19166001179055601d55005b346102665760206103d26107e53661289c565b90613b70565b346102665760603660031901126102665761080536612762565b6044356001600160401b0381116102665760209161082a6103d292369060040161267c565b90615917565b346102665760403660031901126102665761085261084d36612762565b614036565b60005260166020526040600020546000526015602052602060ff604060002054166040519015158152f35b60a0366003190112610266576108916125dd565b6024356001600160401b038111610266576108b090369060040161267c565b604036606319011261026657604051906108c982612625565b6064358252608435602083015260443515610f05576001600160a01b038316600090815260208052604090206108ff9082612c7f565b54928315610ecc57604435840293840460443503610e7c57833403610e92576000194301438111610e7c5760405160208101914283523360601b806040840152605483015240606882015260688152610959608882612640565b51902092604092835161096c8582612640565b601081526f181899199a1a9b1b9c1cb0b131b232b360811b60208201526060908551966109998389612640565b868852601f1983013660208a013760005b60208110610de7575050506001600160a01b038416600090815260216020908152868220338352905285902060ff906007906109e69089612c7f565b015416610dac5784516109f881612609565b82815260443560208083019182528783018a81528484018a815234608086015260a08501889052600060c08601819052600160e087015261010086018190526001600160a01b038a168152602184528a812033825290935291899020610a5e908b612c7f565b9284518051906001600160401b0382116106e257610a7c8654612d23565b601f8111610d7a575b50602090601f8311600114610d1357610ab6929160009183610d085750508160011b916000199060031b1c19161790565b84555b516001840155516002830155518051906001600160401b0382116106e257610ae46003840154612d23565b601f8111610cd3575b50602090601f8311600114610c35577f85c4696d0f8a7299d49d94ece954869348bf401539ecc7f039c96a37d4d76835969593610b4d84610c269e979561010095600795600092610c2a5750508160011b916000199060031b1c19161790565b60038201555b6080840151600482015560a0840151805160058301556020015160068201550191610b9060c08201511515849060ff801983541691151516179055565b60e0810151151561ff0084549160081b169061ff0019161783550151151562ff000082549160101b169062ff000019161790556020855195015190610bdf88519460c0865260c0860190612825565b95604435602086015288850152830152608082015280830360a082015280610c11339560018060a01b03169488612825565b0390a351918291602083526020830190612825565b0390f35b0151905038806105f9565b9060038401600052806000209160005b601f1985168110610cbb57509360018461010094610c269f9896947f85c4696d0f8a7299d49d94ece954869348bf401539ecc7f039c96a37d4d768359b9a98600796601f19811610610ca2575b505050811b016003820155610b53565b015160001960f88460031b161c191690558f8080610c92565b91926020600181928685015181550194019201610c45565b610d0290600385016000526020600020601f850160051c810191602086106106d857601f0160051c0190612d5d565b8b610aed565b015190508f806105f9565b90601f1983169187600052816000209260005b818110610d625750908460019594939210610d49575b505050811b018455610ab9565b015160001960f88460031b161c191690558e8080610d3c565b92936020600181928786015181550195019301610d26565b610da690876000526020600020601f850160051c810191602086106106d857601f0160051c0190612d5d565b8e610a85565b845162461bcd60e51b81526020600482015260146024820152734f7264657220616c72656164792065786973747360601b6044820152606490fd5b81811a6001600160f81b0319610e04600483901c600f1686615e79565b5116908260011b9183830460021484151715610e7c5760001a610e27838d615e79565b536001600160f81b031990610e3f90600f1686615e79565b51166000916001019182600111610e68576001939291610e61911a918c615e79565b53016109aa565b634e487b7160e01b81526011600452602490fd5b634e487b7160e01b600052601160045260246000fd5b60405162461bcd60e51b8152602060048201526012602482015271125b98dbdc9c9958dd08115512081cd95b9d60721b6044820152606490fd5b60405162461bcd60e51b8152602060048201526011602482015270141c9bd91d58dd081b9bdd08199bdd5b99607a1b6044820152606490fd5b60405162461bcd60e51b815260206004820152601960248201527f5175616e74697479206d75737420626520706f736974697665000000000000006044820152606490fd5b34610266576040366003190112610266576004356001600160401b03811161026657610f7a90369060040161267c565b602435908115610f9e57610f9b903360005260208052604060002090612c7f565b55005b60405162461bcd60e51b8152602060048201526024808201527f556e6974207072696365206d7573742062652067726561746572207468616e206044820152637a65726f60e01b6064820152608490fd5b3461026657602060ff60076110316110063661284a565b6001600160a01b03928316600090815260218852604080822093909416815291875291902090612c7f565b015460101c166040519015158152f35b346102665760203660031901126102665760043580151580611177575b61106790612c3d565b8060005260096020526040600020548160005260096020526001604060002001546040519160208301526040820152604081526110a5606082612640565b906000915b81600052600a6020526040600020548310156111365760019082600052600a60205261112e604060206110e08783600020613b54565b50549386600052600a8252856110f98985600020613b54565b50015494835195826111148894518092878088019101612802565b830191848301528482015203016020810184520182612640565b9201916110aa565b60006111516020928360405192828480945193849201612802565b8101039060025afa1561116b576020600051604051908152f35b6040513d6000823e3d90fd5b5060085481111561105e565b346102665760c03660031901126102665760043561119f6125f3565b906111a93661278a565b6084356001600160401b038111610266576111c8903690600401612a48565b60a435936111e160018060a01b036007541633146135e4565b8315158061134d575b15611318576001600160a01b031690811515806112ea575b156112b45780511561127f5761124992859285600052600e6020526040600020816001600160601b0360a01b825416179055600052600f602052846040600020558461563c565b7f7e44eaa917fba972be0d94b86a4329906e7d54e153fc075a44d9466e9720a615604060085493815190815260006020820152a3005b60405162461bcd60e51b815260206004820152600d60248201526c4e6f206174747269627574657360981b6044820152606490fd5b60405162461bcd60e51b815260206004820152600e60248201526d4164647265737320696e2075736560901b6044820152606490fd5b5081600052600f602052604060002054600052600e6020528160018060a01b03604060002054161415611202565b60405162461bcd60e51b815260206004820152600d60248201526c4973737565722065786973747360981b6044820152606490fd5b50836000526010602052604060002054156111ea565b346102665760a03660031901126102665761137d36612762565b6044356001600160401b0381116102665761139c903690600401612a48565b906064359133600052600f602052604060002054916008541515806114e8575b6113c5906135e4565b82600052601060205260406000205493825185600052600a602052604060002054036114a35784600052600b602052604060002054811061146757611449817f7e44eaa917fba972be0d94b86a4329906e7d54e153fc075a44d9466e9720a6159460409461143560843542612e00565b89600052600c60205286600020558761563c565b60085494600052600c602052816000205482519182526020820152a3005b60405162461bcd60e51b815260206004820152601460248201527345706f636820676f6573206261636b776172647360601b6044820152606490fd5b60405162461bcd60e51b815260206004820152601760248201527f41747472696275746520636f756e74206368616e6765640000000000000000006044820152606490fd5b506000838152600e60205260409020546001600160a01b031633146113bc565b34610266576102403660031901126102665761152336612762565b61152c36612942565b906115363661297b565b6040366101831901126102665760405161154f81612625565b6101843581526101a43560208201526080366101c3190112610266576020936103d2936040519361157f85612625565b61158b366101c46128fd565b8552611599366102046128fd565b8786015261016435926101443592613753565b34610266576020366003190112610266576004356001600160401b0381116102665761160c60036115fc60206115e9610c2695369060040161267c565b8160405193828580945193849201612802565b8101602381520301902001612e0d565b604051918291602083526020830190612825565b34610266576020366003190112610266576001600160a01b036116416125dd565b1660005260226020526020604060002054604051908152f35b34610266576020366003190112610266576004356001600160401b038111610266576116c360056116b36020611697610c2695369060040161267c565b61169f612eaf565b508160405193828580945193849201612802565b8101602381520301902001612f1b565b604051918291829190916020806040830194805184520151910152565b3461026657610264600080808061175e6117636116fc366126c3565b6001600160a01b0390911680855260216020908152604080872033885290915285209093919060049061172f9083612c7f565b61173f60ff600783015416612ca5565b0154938552602160209081526040808720338852909152852090612c7f565b612dc4565b335af161176e612c0d565b50612f39565b34610266576102203660031901126102665761178f36612762565b61179836612942565b906117a23661297b565b610144356001600160401b038111610266576117c2903690600401612ba6565b610184356001600160401b038111610266576117e2903690600401612acb565b936040366101c3190112610266576020946103d2946040519361180485612625565b6101c43585526101e4358886015261020435956101a43594610164359361365f565b346102665760803660031901126102665761184036612762565b6044356001600160401b038111610266576020916118656103d292369060040161267c565b60643591615703565b3461026657606036600319011261026657602061188a366127da565b61189f60018060a01b036007541633146135e4565b60043560145580516012550151601355005b34610266576020366003190112610266576004356118cd612eaf565b50801515806119d3575b6118e090612c3d565b6000908152600960209081526040808320600a8352818420600b845282852054600c90945291909320549261191490612f1b565b92815492611921846128e6565b9361192f6040519586612640565b80855260208501809460005260206000206000915b8383106119b5575050505060405193602060a0860196805187520151602086015260a060408601525180955260c08401926000955b808710611993575050839450606084015260808301520390f35b9093602060406001928288518051835201518382015201950196019590611979565b600260206001926119c585612f1b565b815201920192019190611944565b506008548111156118d7565b3461026657604036600319011261026657600435611a0860018060a01b036007541633146135e4565b7f6e0956cda88cad152e89927e53611735b61a5c762d1428573c6931b0a5efcb01546000908152600a6020526040902054611a4490821061361e565b6018805460ff19166001179055601955602435601a55005b346102665761012036600319011261026657611a7736612762565b611a8036612942565b60403660c31901126102665760405190611a9982612625565b60c435825260e4356020830152610104356001600160401b03811161026657611ac6903690600401612a48565b92611adc60018060a01b036007541633146135e4565b600854611c88576000808052600e60209081527fe710864318d4a32f37d6ce54cb3fadbef648dd12d8dbdf53973564d56b7f881c80546001600160a01b03191633179055825160015591909101516002558151905b60028110611c735750506020015160005b60028110611c5e5783611b7c84611b5a600854614221565b8060085560005260096020526040600020906020600191805184550151910155565b60005b8151811015611bf257600854600052600a602052604060002090611ba381846130ec565b51918054600160401b8110156106e257611bc291600182018155613b54565b611bdc578251815560209092015160019283015501611b7f565b634e487b7160e01b600052600060045260246000fd5b600854600052600b60205260006040812055600854600052600d6020526000604081205560085460008052601060205260406000205560085460007f7e44eaa917fba972be0d94b86a4329906e7d54e153fc075a44d9466e9720a61560408051838152836020820152a3005b60019060208351930192816005015501611b42565b60019060208351930192816003015501611b31565b60405162461bcd60e51b815260206004820152600e60248201526d0416c7265616479207365742075760941b6044820152606490fd5b346102665760003660031901126102665760008052601060209081527f6e0956cda88cad152e89927e53611735b61a5c762d1428573c6931b0a5efcb0154604051908152f35b6101003660031901126102665760206103d2611d1f36612762565b611d283661278a565b90611d32366127b2565b60e4359260c4359261345b565b34610266576000366003190112610266576020601a54604051908152f35b346102665760203660031901126102665760206103d26004356153d1565b346102665760203660031901126102665760043560005260106020526020604060002054604051908152f35b34610266576102646000808080611dbd366126c3565b9033835260216020526040832060018060a01b0382168452602052611e2661175e6004611ded6040872086612c7f565b611dfd60ff600783015416612ca5565b01543386526021602090815260408088206001600160a01b038716895290915286209094612c7f565b6001600160a01b03165af161176e612c0d565b346102665761026036600319011261026657611e5436612762565b611e5d3661278a565b9060c4356001600160401b03811161026657611e7d9036906004016129b0565b9060e4356001600160401b03811161026657611e9d9036906004016129b0565b92610104356001600160401b03811161026657611ebe903690600401612a48565b610124356001600160401b03811161026657611ede903690600401612acb565b610144356001600160401b03811161026657611efe903690600401612acb565b610164356001600160401b03811161026657611f1e903690600401612acb565b610184356001600160401b03811161026657611f3e903690600401612b28565b906101a4356001600160401b03811161026657611f5f903690600401612ba6565b926101c435946001600160401b038611610266573660238701121561026657856004013595611f8d876128e6565b96611f9b6040519889612640565b8088526024602089019160051b8301019136831161026657602401905b828210612024575050506101e4356001600160401b03811161026657611fe2903690600401612acb565b966040366102031901126102665760209b6103d29b6040519a6120048c612625565b610204358c52610224358f8d0152610244359c60a43591608435916131dd565b8135815260209182019101611fb8565b346102665761026461204536612705565b91612f75565b3461026657602036600319011261026657610c266116c361206a6125dd565b612ec8565b3461026657602060036120996120843661289c565b93908160405193828580945193849201612802565b810160238152030190206120b360ff600283015416612ca5565b0181516001600160401b0381116106e2576120ce8254612d23565b601f8111612178575b50602092601f8211600114612118576121099293829160009261210d5750508160011b916000199060031b1c19161790565b9055005b0151905084806105f9565b601f1982169383600052806000209160005b8681106121605750836001959610612147575b505050811b019055005b015160001960f88460031b161c1916905583808061213d565b9192602060018192868501518155019401920161212a565b6121a490836000526020600020601f840160051c810191602085106106d857601f0160051c0190612d5d565b836120d7565b346102665760206121da6121bd366126c3565b6001600160a01b0390911660009081528380526040902090612c7f565b54604051908152f35b34610266576122656122276121f73661284a565b9160018060a01b0316600052602160205260406000209060018060a01b0316600052602052604060002090612c7f565b600181015460ff6002830154600784015490612273612251600361224a88612e0d565b9701612e0d565b60405197889760e0895260e0890190612825565b908782036020890152612825565b936040860152606085015281811615156080850152818160081c16151560a085015260101c16151560c08301520390f35b34610266576040366003190112610266576122c161084d36612762565b600052600060205260406000208054906122da826128e6565b916122e86040519384612640565b80835260208301809260005260206000206000915b83831061236b57848660405191829160208301906020845251809152604083019060408160051b85010192916000905b82821061233c57505050500390f35b9193600191939550602061235b8192603f198a82030186528851612825565b960192019201859493919261232d565b60016020819261237a85612e0d565b8152019201920191906122fd565b346102665761175e600261242961239e36612705565b6001600160a01b0390921660008181526021602090815260408083203384529091529020909591949192906123d39086612c7f565b9060078201906124008254916123eb60ff8416612ca5565b6123fa60ff8460081c16612ce4565b88613b70565b612472575b50500154600085815260216020908152604080832033845290915290209093612c7f565b8160005260226020526040600020612442828254612e00565b90556040519081527fcbdae027b851b48705e1072c0c9ceedd7be4c50cf428125e072438065f258d6e60203392a3005b62ff00001916620100001790558680612405565b3461026657602036600319011261026657600435801515806124c4575b6124ac90612c3d565b600052600d6020526020604060002054604051908152f35b506008548111156124a3565b6124d9366126c3565b903360005260226020526040600020549081156125a15733600052602260205260006040812055600080808085335af1612511612c0d565b5015612569577fd558c6fa7308993d16f63a349da75aca9f69611232918e75423f0eaa6d6854509061254e60405194604086526040860190612825565b60208501939093526001600160a01b031692339281900390a3005b60405162461bcd60e51b815260206004820152601060248201526f2a3930b739b332b9103330b4b632b21760811b6044820152606490fd5b60405162461bcd60e51b81526020600482015260146024820152734e6f2066756e647320746f20776974686472617760601b6044820152606490fd5b600435906001600160a01b038216820361026657565b602435906001600160a01b038216820361026657565b61012081019081106001600160401b038211176106e257604052565b604081019081106001600160401b038211176106e257604052565b90601f801991011681019081106001600160401b038211176106e257604052565b6001600160401b0381116106e257601f01601f191660200190565b81601f820112156102665780359061269382612661565b926126a16040519485612640565b8284526020838301011161026657816000926020809301838601378301015290565b906040600319830112610266576004356001600160a01b03811681036102665791602435906001600160401b038211610266576127029160040161267c565b90565b6060600319820112610266576004356001600160a01b038116810361026657916024356001600160401b03811161026657826127439160040161267c565b91604435906001600160401b038211610266576127029160040161267c565b6040906003190112610266576040519061277b82612625565b60043582526024356020830152565b604090604319011261026657604051906127a382612625565b60443582526064356020830152565b604090608319011261026657604051906127cb82612625565b608435825260a4356020830152565b604090602319011261026657604051906127f382612625565b60243582526044356020830152565b60005b8381106128155750506000910152565b8181015183820152602001612805565b9060209161283e81518092818552858086019101612802565b601f01601f1916010190565b6060600319820112610266576004356001600160a01b038116810361026657916024356001600160a01b03811681036102665791604435906001600160401b038211610266576127029160040161267c565b906040600319830112610266576004356001600160401b03811161026657826128c79160040161267c565b91602435906001600160401b038211610266576127029160040161267c565b6001600160401b0381116106e25760051b60200190565b9080601f830112156102665760408051926129188285612640565b8391810192831161026657905b8282106129325750505090565b8135815260209182019101612925565b9060806043198301126102665760405161295b81612625565b6020612976829461296d8160446128fd565b845260846128fd565b910152565b90608060c3198301126102665760405161299481612625565b602061297682946129a68160c46128fd565b84526101046128fd565b81601f82011215610266578035906129c7826128e6565b926129d56040519485612640565b82845260208085019360071b8301019181831161026657602001925b8284106129ff575050505090565b6000608085840312612a4557506020608091604051612a1d81612625565b612a2785886128fd565b8152612a3685604089016128fd565b838201528152019301926129f1565b80fd5b81601f8201121561026657803590612a5f826128e6565b92612a6d6040519485612640565b82845260208085019360061b8301019181831161026657602001925b828410612a97575050505090565b6000604085840312612a45575060206040918251612ab481612625565b863581528287013583820152815201930192612a89565b9080601f83011215610266578135612ae2816128e6565b92612af06040519485612640565b81845260208085019260051b82010192831161026657602001905b828210612b185750505090565b8135815260209182019101612b0b565b9080601f83011215610266578135612b3f816128e6565b92612b4d6040519485612640565b81845260208085019260051b820101918383116102665760208201905b838210612b7957505050505090565b81356001600160401b03811161026657602091612b9b8784809488010161267c565b815201910190612b6a565b9080601f8301121561026657813590612bbe826128e6565b92612bcc6040519485612640565b82845260208085019360051b82010191821161026657602001915b818310612bf45750505090565b8235801515810361026657815260209283019201612be7565b3d15612c38573d90612c1e82612661565b91612c2c6040519384612640565b82523d6000602084013e565b606090565b15612c4457565b60405162461bcd60e51b81526020600482015260136024820152722ab735b737bbb71035b2bc903b32b939b4b7b760691b6044820152606490fd5b602090612c99928260405194838680955193849201612802565b82019081520301902090565b15612cac57565b60405162461bcd60e51b815260206004820152601060248201526f4f72646572206e6f742061637469766560801b6044820152606490fd5b15612ceb57565b60405162461bcd60e51b815260206004820152601060248201526f119d5b991cc81b9bdd081b1bd8dad95960821b6044820152606490fd5b90600182811c92168015612d53575b6020831014612d3d57565b634e487b7160e01b600052602260045260246000fd5b91607f1691612d32565b818110612d68575050565b60008155600101612d5d565b612d7e8154612d23565b9081612d88575050565b81601f60009311600114612d9b5750555b565b81835260208320612db791601f0160051c810190600101612d5d565b8082528160208120915555565b6007600091612dd281612d74565b826001820155826002820155612dea60038201612d74565b8260048201558260058201558260068201550155565b91908201809211610e7c57565b9060405191826000825492612e2184612d23565b8084529360018116908115612e8d5750600114612e46575b50612d9992500383612640565b90506000929192526020600020906000915b818310612e71575050906020612d999282010138612e39565b6020919350806001915483858901015201910190918492612e58565b905060209250612d9994915060ff191682840152151560051b82010138612e39565b60405190612ebc82612625565b60006020838281520152565b61270290612ed4612eaf565b5060405190714f62667573686f702f41432f73636f70652f60701b60208301526001600160601b03199060601b16603282015260268152612f16604682612640565b614062565b90604051612f2881612625565b602060018294805484520154910152565b15612f4057565b60405162461bcd60e51b815260206004820152600d60248201526c1499599d5b990819985a5b1959609a1b6044820152606490fd5b91336000526021602052604060002060018060a01b038416600052602052612fa1604060002083612c7f565b6007810191612fb660ff845460081c16612ce4565b612ff16005830191612fca61084d84612f1b565b6000908152601e60205260409020546001600160a01b031692612fec90612f1b565b615917565b908161308d575b50156130515750600160ff198254161790557f40078477a6dc67e30ec77e8a4b1d8749dd098056fe79ee602c2456b60e7c8d0a604051926020845260018060a01b0316928061304c33946020830190612825565b0390a3565b60008093819350612d9995611e2661175e6002859601549333865260216020526040862060018060a01b03851687526020526040862090612c7f565b80159150811561309f575b5038612ff8565b9050331438613098565b8051156130b65760200190565b634e487b7160e01b600052603260045260246000fd5b8051600110156130b65760400190565b8051600210156130b65760600190565b80518210156130b65760209160051b010190565b6040519061310f602083612640565b600080835282815b82811061312357505050565b60209060405161313281612625565b6000815260008382015282828501015201613117565b634e487b7160e01b81526041600452602490fd5b6040805190919061316d8382612640565b6001815291601f19018260005b82811061318657505050565b60209060405161319581612625565b600081526000838201528282850101520161317a565b906131b5826128e6565b6131c26040519182612640565b82815280926131d3601f19916128e6565b0190602036910137565b9c999e9d9b9896949290918f928e939c9997959c518b5114938415946132e4575b505050506132d25761320f98614230565b8051156132c85791926020929060005b825181101561328e5761327561323582856130ec565b5161323e613100565b613246613100565b604051916132548a84612640565b600083526000368137886040519461326c8c87612640565b60008652614785565b156132825760010161321f565b50505050505050600090565b5093601e6127029693946132a183614036565b6000525260406000206001600160601b0360a01b81541690556132c381614036565b614c75565b5050505050600090565b50505050505050505050505050600090565b61330794506040516132f581612625565b6001548152600254602082015261345b565b153880808e6131fe565b60405190613320604083612640565b600e82526d13d8999d5cda1bdc0bd050cbd91b60921b6020830152565b6040519061334c604083612640565b60098252681cdd185d195b595b9d60ba1b6020830152565b60405190613373604083612640565b60078252667769746e65737360c81b6020830152565b60405190613398604083612640565b60088252673932b630ba34b7b760c11b6020830152565b604051906133be604083612640565b60038252626c687360e81b6020830152565b604051906133df604083612640565b60048252636261736560e01b6020830152565b60405190613401604083612640565b60048252637465726d60e01b6020830152565b60405190613423604083612640565b600a82526918dbdb5b5a5d1b595b9d60b21b6020830152565b6040519061344b604083612640565b60018252606360f81b6020830152565b929390919361355e61355061353e61349261347c613477613311565b615432565b61348461333d565b61348c613311565b91615482565b61351d61350b6134f36134ca6134a6613364565b946040958651916134b78884612640565b60018352600f60fb1b6020840152615482565b6134d2613389565b8551916134df8784612640565b6002835261784760f01b6020840152615482565b6134fc8a6154fa565b906135056133af565b90615482565b6135148a6154fa565b906135056133d0565b9051906000602083015260048252613536602483612640565b6135056133f2565b613547886154fa565b90613505613414565b61355861343c565b9061551e565b81148015906135ba575b6132c85761357c6135829261358895615588565b92615588565b906155dc565b8151815114918215926135a6575b50506135a157600190565b600090565b602091925081015191015114153880613596565b507f30644e72e131a029b85045b68181585d2833e84879b9709143e1f593f0000001821015613568565b156135eb57565b60405162461bcd60e51b815260206004820152600b60248201526a27b7363c9034b9b9bab2b960a91b6044820152606490fd5b1561362557565b60405162461bcd60e51b8152602060048201526012602482015271496e646578206f7574206f662072616e676560701b6044820152606490fd5b9097959391949294613670876153d1565b158015613700575b6136f257612702986040519761368d89612609565b8852602088015260408701526060860152608085015260a084015260c083015260e082015260006101008201526136c2613100565b6136ca613100565b90602093604051936136dc8686612640565b60008552600036813761326c6040519687612640565b505050505050505050600090565b50855187600052600a60205260406000205410613678565b60405190613727604083612640565b601782527f4f62667573686f702f41432f6b65792d62696e64696e670000000000000000006020830152565b94939695909261376284615b05565b8015613b45575b8015613b1b575b613b0f5761377c613718565b61378590615432565b61378d61333d565b613795613718565b9061379f92615482565b946137a8613364565b956040968751906137b98983612640565b6002825261736b60f01b60208301526137d192615482565b6137d9613389565b8751906137e68983612640565b6002825261706b60f01b60208301526137fe92615482565b613807886154fa565b61380f6133af565b61381892615482565b9086519761382589612625565b60015492838a52600254998a602082015261383f906154fa565b6138476133d0565b61385092615482565b88516000602082015260048152613868602482612640565b6138706133f2565b61387992615482565b613882836154fa565b61388a613414565b61389392615482565b61389b613389565b8951906138a88b83612640565b60018252605560f81b60208301526138bf92615482565b6138c888615b98565b6138d06133af565b6138d992615482565b6138e287615b98565b6138ea6133d0565b6138f392615482565b8851600060208201526004815261390b602482612640565b6139136133f2565b61391c92615482565b61392586615b98565b61392d613414565b61393692615482565b61393e61343c565b6139479161551e565b8403613b0057613973906135828561357c8e8d8d519061396682612625565b8982526020820152615588565b90815181511491821592613aec575b5050613adf57906139df916139c66139ac885161399e81612625565b8381528a6020820152615bd6565b9a88516139b881612625565b8381528a6020820152615588565b978751916139d383612625565b82526020820152615588565b845160809690956139f08888612640565b60038752601f1988019060005b828110613abb57505197613a11818a612640565b600389525060005b818110613aa45750506127029798613a30876130a9565b52613a3a866130a9565b50613a44866130cc565b52613a4e856130cc565b50613a58856130dc565b52613a62846130dc565b50613a6c856130a9565b52613a76846130a9565b50613a80846130cc565b52613a8a836130cc565b50613a94836130dc565b52613a9e826130dc565b50615cfe565b602090613aaf613bf2565b82828c01015201613a19565b6020908251613ac981612625565b6000815260008382015282828c010152016139fd565b5060009750505050505050565b602091925081015191015114153880613982565b50600099505050505050505050565b50600096505050505050565b507f30644e72e131a029b85045b68181585d2833e84879b9709143e1f593f0000001881015613770565b50613b4f83615b05565b613769565b80548210156130b65760005260206000209060011b0190600090565b90613be26020600492613bd060405191613b9281518092868087019101612802565b820191611f1f60f21b84840152613bcb600282868a51818c0197613bba82602283018b612802565b01010301601f198101835282612640565b615c75565b94604051938492839251928391612802565b8101602381520301902001541490565b60405190613bff82612625565b8160206040918251613c118482612640565b833682378152825192613c248185612640565b3684370152565b60405190613c3a602083612640565b600080835282815b828110613c4e57505050565b602090604051613c5d81612609565b60008152613c69613bf2565b83820152613c75613bf2565b6040820152604051613c8681612625565b60008152600084820152606082015260006080820152606060a0820152600060c0820152606060e0820152600061010082015282828501015201613c42565b9c999693909894919a979b959260ff601c541615801561401e575b801561400e575b8015613ff7575b8015613fe8575b8015613fcf575b8015613fc3575b613fb057604080519c9060018e613d1a8382612640565b52601f19018d60005b828110613f375750505060405197613d3a89612609565b8852602088015260408701526060860152608085015260a084015260c083015260e08201526000610100820152613d70866130a9565b52613d7a856130a9565b50613d8361315c565b9587613d8d61315c565b6040988991825191613d9f8484612640565b60018352601f19840136602085013788613db8826130a9565b52613dc2816130a9565b50613dcc85612ec8565b613dd5836130a9565b52613ddf826130a9565b50601d54613dec846130a9565b52868a613df88d6130a9565b519686516020810191631b9e5b5f60e21b83523060601b60248301526001600160601b03199060601b166038820152602c8152613e36604c82612640565b80519751978893602085017227b1333ab9b437b817a0a197b9b2b9b9b4b7b760691b9052600160e51b6033860152603785015263ffffffff60e01b9060e01b166057840152519081605b8401613e8b92612802565b810103605b01601f1981018652613ea29086612640565b613eab95614785565b15613f2a57613ebc613edb94614036565b958751613ec881612625565b6001548152600254602082015287614c75565b15613f2157600052601f60205281600020600160ff19825416179055600052601e6020526000209060018060a01b03166001600160601b0360a01b825416179055600190565b50505050600090565b5050505050505050600090565b602091828260405192613f4984612609565b60008452613f55613bf2565b83850152613f61613bf2565b6040850152604051613f7281612625565b60008152600084820152606085015260006080850152606060a0850152600060c0850152606060e08501526000610100850152010152018e90613d23565b5050505050505050505050505050600090565b5085518d511415613d03565b508c5187600052600a6020526040600020541415613cfc565b50613ff28c615b05565b613cf5565b5086600052600d6020526040600020541515613cee565b50614018876153d1565b15613ce7565b508a600052601f60205260ff60406000205416613ce0565b60208151910151604051906020820192835260408201526040815261405c606082612640565b51902090565b600061409260209260405161407681612625565b8381528385820152508360405192828480945193849201612802565b8101039060025afa1561116b576000515b600080516020615e8b8339815191528110156141cf5760005b6141b957600080516020615e8b8339815191526003818381818009090860405160c081018181106001600160401b038211176106e25760405260208152602080820152602060408201528160608201527f0c19139cb84c680a6e14116da060561765e05aa45a1c72a34f082305b61f3f526080820152600080516020615e8b83398151915260a082015260c06020809260405192839161415c8484612640565b8336843760056107cf195a01fa15610266575191600080516020615e8b833981519152838009146141a4575050600080516020615e8b833981519152600160009208906140bc565b604051926141b184612625565b835282015290565b634e487b7160e01b600052601260045260246000fd5b60006141ff6020926040518481019182528481526141ee604082612640565b604051928392839251928391612802565b8101039060025afa1561116b576000516140a3565b91908203918211610e7c57565b6000198114610e7c5760010190565b60c0526101405260e052610100526101c0526101205260a052610160526101a052600061018052610180515b61016051518110156142cc5780158015614295575b90600191614280575b0161425c565b61428c61018051614221565b6101805261427a565b506142a381610160516130ec565b5160001982019190818311610e7c576142c1600193610160516130ec565b511415909150614271565b5061018051158015614736575b8015614726575b8015614715575b8015614705575b80156146f4575b80156146e3575b80156146d2575b61437357614326614316610180516128e6565b6040516102005261020051612640565b6101808051610200515251601f199061433e906128e6565b0160005b818110614658575050600080806101e0525b610180516101e0511061437b57506101c0515103614373576102005190565b612702613c2b565b909160018201808311610e7c576080525b61016051516080511080614633575b156143b3576143ab608051614221565b60805261438c565b91906143c56101e0516101a0516130ec565b51906143d0826153d1565b15801561460e575b80156145eb575b80156145cd575b6145c2576143f681608051614214565b6143ff816128e6565b9061440d6040519283612640565b80825261441c601f19916128e6565b013660208301376000825b6080518110614560575061443b8186612e00565b6101c05151106145535761444e816131ab565b9460005b82811061452857509061446491612e00565b936144746101e05160c0516130ec565b51906144866101e051610140516130ec565b516144966101e05160e0516130ec565b516144a76101e051610100516130ec565b51916144b96101e051610120516130ec565b5194604051986144c88a612609565b8952602089015260408801526060870152608086015260a085015260c084015260e08301526101008201526145036101e051610200516130ec565b526145146101e051610200516130ec565b5060805160016101e051016101e052614354565b8061454161453860019385612e00565b6101c0516130ec565b5161454c828a6130ec565b5201614452565b5050505050612702613c2b565b6145748160a09893949698979597516130ec565b51151561458a6145848884614214565b876130ec565b526145978160a0516130ec565b51156145ae575b6001019593919095949294614427565b916145ba600191614221565b92905061459e565b505050612702613c2b565b506145e66145e06101e05160c0516130ec565b51615b05565b6143e6565b5081600052600a60205260406000205461460782608051614214565b14156143df565b5081600052600d60205260406000205461462b82610160516130ec565b5114156143d8565b50614643608051610160516130ec565b5161465183610160516130ec565b511461439b565b60209060405161466781612609565b60008152614673613bf2565b8382015261467f613bf2565b604082015260405161469081612625565b60008152600084820152606082015260006080820152606060a0820152600060c0820152606060e0820152600061010082015282826102005101015201614342565b50610180516101a051511415614303565b5061018051610120515114156142fc565b5061018051610100515114156142f5565b506101805160e0515114156142ee565b5061018051610140515114156142e7565b506101805160c0515114156142e0565b5060a05151610160515114156142d9565b60405190614756604083612640565b60018252600560fc1b6020830152565b60405190614775604083612640565b60018252602160f91b6020830152565b90929195939560e08201519461479b86516131ab565b956147af6060850151608086015190615588565b9260c0850151956148556147e2604051966147c988612625565b6135826001549a8b8a52600254998a6020820152615588565b9561483360409961481a8b516147f88d82612640565b601081526f27b1333ab9b437b817a0a197b834afbb60811b6020820152615432565b928b519161482783612625565b825260208201526154fa565b908951906148418b83612640565b6002825261673160f01b6020830152615482565b9760009b8c5b85518110156149495761486e81876130ec565b511561487d575b60010161485b565b9b9a999092949660a0899e9395979e01805151841015613fb057838e9f9896948d9e9f61493b906149358f978f9e9c9a988f6001996135826149419a6148ee9351600052600a6020526148e96148e28b6148da8b89600020613b54565b5093516130ec565b5191612f1b565b615588565b9f51600052600a60205261491661491161490b8684600020613b54565b50612f1b565b6154fa565b9161492382519283612640565b898252607960f81b6020830152615482565b9f6130ec565b52614221565b9d9050614875565b509694939299959b90979a91989b60a086019c8d51518303614c6257908c6149a96149a36149c99461497e60608c01516154fa565b9084519061498c8683612640565b60058252646b6170706160d81b6020830152615482565b926154fa565b916149b682519283612640565b60018252604160f81b6020830152615482565b976149d2612eaf565b508551600052600d6020528b600020541580614c54575b614b90575b50506000965b8951881015614aed57614a0788886130ec565b51835111801590614ad0575b614abf57614ab78a614aae614aa88f613514614a938f928f908f998f8f84614a8d8960019f614aa2978f61358292614a7f614a9999614a7f89614a7681614a70614a939e6080614a6684614a869c6130ec565b5191015190615588565b9a6130ec565b519651946130ec565b51906130ec565b5190615588565b9b6130ec565b516154fa565b90613505614747565b936130ec565b916154fa565b90613505614766565b9701966149f4565b505050975050505050505050600090565b50614ae5614ade89896130ec565b51846130ec565b511515614a13565b91509450614b3a91995060809750614b8996506135509550614b6792989350614b1960208a0151615b98565b90845190614b278683612640565b60018252607560f81b6020830152615482565b614b4683890151615b98565b90835190614b548583612640565b60018252607360f81b6020830152615482565b614b7382519283612640565b6004825263189a5b9960e21b6020830152615482565b9101511490565b90919782158015614c33575b614c2157614bae608087015183615588565b8d51936000198101908111610e7c57613514614aae938f9293614c0d614bda614aa896614c199a6130ec565b5192614a99614c07875194614bee86612625565b6135826012549788885260135497886020820152615588565b996154fa565b93519161482783612625565b9538806149ee565b50505050975050505050505050600090565b5083516000198101908111610e7c57614c4c90856130ec565b511515614b9c565b50614c5d615cac565b6149e9565b5050505050975050505050505050600090565b90956060956000949093909291908590819081905b8051831015614fec57614c9d83826130ec565b51998a51600052600d60205260406000205415614f58575b614ce78b614ce26060604060009e969b9e614cce612eaf565b508451815260096020522092015191612f1b565b6155dc565b9660009260e08d01985b895151851015614db4578b8e614d078782615cc3565b15614d3a5791613582614d319260019451600052600a6020526148e961490b8a6040600020613b54565b945b0193614cf1565b90508b614d4b878d989498516130ec565b51614d5b575b5050600190614d33565b95613582826148e9614da4614d9e6001979b614daa9751600052600a602052614d988d610100614d8f826040600020613b54565b50970151612e00565b906130ec565b51615c75565b91612f1b565b9490508d8b614d51565b90959a93509d9690979c91989d9b9a93959b8b60208101516040614dd78b615bd6565b9201519060008a613148575060405192614df18785612640565b6002845260008b6131485750601f1987019460005b868110614f33575060008c613148575060405195614e248988612640565b6002875260008d613148575060005b818110614f1c5750509084939291614e4e614e9497966130a9565b52614e58846130a9565b50614e62846130cc565b52614e6c836130cc565b50614e76846130a9565b52614e80836130a9565b50614e8a836130cc565b52613a9e826130cc565b15614f095760005b8d518051821015614eec5781614eb1916130ec565b51151580614edb575b614ec7575b600101614e9c565b9b614ed3600191614221565b9c9050614ebf565b50614ee6818d615cc3565b15614eba565b5050959c97939a949b509591985096600101919897949990614c8a565b9c50505050505050505050505050600090565b602090614f27613bf2565b82828b01015201614e33565b602090604051614f4281612625565b6000815260008382015282828901015201614e06565b98614fdb57600198614f68615cac565b80614fbb575b614f845760ff6018541680614f96575b15614cb5575b50505050509650505050505050600090565b50601a5489141580614f7e5750614fb460e08c0151601954906130ec565b5115614f7e565b50614fc581614036565b600052601560205260ff60406000205416614f6e565b505050509650505050505050600090565b98979950505091939097508615806153b7575b6132825793615010829794976128e6565b9161501e6040519384612640565b808352601f1961502d826128e6565b0160005b8181106153a4575050615043906131ab565b9360009586985b88518a10156151275760005b60e06150628c8c6130ec565b5101515181101561511b578a8a6150878360e061507f85856130ec565b5101516130ec565b51151580615100575b61509f575b5050600101615056565b996150c96150c3846101006150bb6001979f966150f6976130ec565b510151612e00565b8a6130ec565b516150d4828a6130ec565b526150df81896130ec565b506150ea8d8d6130ec565b515161493b828c6130ec565b9890508a8a615095565b506151158361510f84846130ec565b51615cc3565b15615090565b5060019099019861504a565b975097509392509350846000526000602052604060002090805190600160401b82116106e2578254828455808310615375575b50602001916000526020600020916000905b828210615268575050505083600052601160205260406000208151916001600160401b0383116106e257600160401b83116106e257602090825484845580851061524b575b500190600052602060002060005b838110615237575050505080615229575b15615213576151de90614036565b8160005260166020526040600020556014548160005260176020526040600020555b600052601b602052604060002055600190565b5080600052601660205260006040812055615200565b50615232615cac565b6151d0565b6001906020845194019381840155016151bf565b615262908460005285846000209182019101612d5d565b386151b1565b80518051906001600160401b0382116106e2576152858654612d23565b601f8111615343575b50602090601f83116001146152d657926152c783600195946020948796600092610c2a5750508160011b916000199060031b1c19161790565b87555b0194019101909261516c565b90601f1983169187600052816000209260005b81811061532b5750936020936001969387969383889510615312575b505050811b0187556152ca565b015160001960f88460031b161c19169055388080615305565b929360206001819287860151815501950193016152e9565b61536f90876000526020600020601f850160051c810191602086106106d857601f0160051c0190612d5d565b3861528e565b8360005282602060002091820191015b818110615392575061515a565b8061539e600192612d74565b01615385565b6060602082870181019190915201615031565b506153c0615cac565b80614fff575060ff60185416614fff565b8015159081615425575b816153e4575090565b809150600052600d60205260406000205460005260106020526040600020548114908115615410575090565b9050600052600c602052604060002054421090565b60085481111591506153db565b61270290604051615444602082612640565b6000815260405190615457604083612640565b601982527f4f62667573686f702f41432f7472616e7363726970742f76310000000000000060208301525b600490816127029394855195602082519160405198866154ab8b9851809286808c019101612802565b87019063ffffffff60e01b9060e01b16838201526154d28251809385602485019101612802565b01019063ffffffff60e01b9060e01b1683820152613bba825180936020600885019101612802565b60208151910151604051916020830152604082015260408152612702606082612640565b615553615540600092602094604051916155388784612640565b858352615482565b8360405192828480945193849201612802565b8101039060025afa1561116b577f30644e72e131a029b85045b68181585d2833e84879b9709143e1f593f00000016000510690565b919060405161559681612625565b6000815260006020820152608081946060936020604051926155b88785612640565b863685378051845201516020830152604082015260076107cf195a01fa1561026657565b60609092919260c0604051916155f183612625565b60008352600060208401526020839681608093604051946156128187612640565b368637805185520151828401528051604084015201518482015260066107cf195a01fa1561026657565b9061564f90949294611b5a600854614221565b60005b84518110156156af57600854600052600a60205260406000209061567681876130ec565b51918054600160401b8110156106e25761569591600182018155613b54565b611bdc578251815560209092015160019283015501615652565b5091909250600854600052600b602052604060002055600854600052600d60205280604060002055600854906000526010602052604060002055565b80548210156130b65760005260206000200190600090565b61570c90614036565b806000526016602052604060002054600052601560205260ff604060002054166158c557615738615cac565b806158e7575b6158c55760ff60185416806158cd575b6158c55760005b816000526000602052604060002054811015613f21578160005260116020526157828160406000206156eb565b90549060031b1c615792816153d1565b159081156158ac575b506158a4578160005260006020526157b78160406000206156eb565b5060405160208101918160008254926157cf84612d23565b93600181169081156158865750600114615844575b506157f8925003601f198101835282612640565b519020604051602081019061582960208288516158188187858d01612802565b81010301601f198101835282612640565b5190201461583b576001905b01615755565b50505050600190565b9150506000528160206000206000905b83821061586c57505060206157f892820101386157e4565b602091925080600191548385880101520191018391615854565b60ff19168752506157f89380151502830160200191503890506157e4565b600190615835565b9050600052600d6020528360406000205414153861579b565b505050600090565b5080600052601b602052604060002054601a54141561574e565b50806000526016602052604060002054158061573e5750806000526017602052604060002054601454141561573e565b61592090614036565b90816000526016602052604060002054600052601560205260ff60406000205416615ab45761594d615cac565b80615ad5575b615ab45760ff6018541680615abb575b615ab45760005b8260005260006020526040600020548110156158c5578260005260116020526159a661599a8260406000206156eb565b90549060031b1c6153d1565b158015615aac575b615aa4578260005260006020526159c98160406000206156eb565b5060405160208101918160008254926159e184612d23565b9360018116908115615a865750600114615a44575b50615a0a925003601f198101835282612640565b5190206040516020810190615a2a60208287516158188187858c01612802565b51902014615a3c576001905b0161596a565b505050600190565b9150506000528160206000206000905b838210615a6c5750506020615a0a92820101386159f6565b602091925080600191548385880101520191018391615a54565b60ff1916875250615a0a9380151502830160200191503890506159f6565b600190615a36565b5060006159ae565b5050600090565b5081600052601b602052604060002054601a541415615963565b50816000526016602052604060002054158061595357508160005260176020526040600020546014541415615953565b604051615b1181612625565b60008152602081019160008352604051615b2a81612625565b600081526020810192600084528251519051149384615b87575b5083615b76575b5082615b62575b5050615b5d57600090565b600190565b602091925081015101519051143880615b52565b602082015151905114925038615b4b565b825160200151905114935038615b44565b80515190602080825101519101602081515191510151916040519360208501526040840152606083015260808201526080815261270260a082612640565b604051615be281612625565b60008152600060208201525080511580615c69575b615c4e57600080516020615e8b8339815191526020825192015106600080516020615e8b83398151915203600080516020615e8b8339815191528111610e7c5760405191615c4483612625565b8252602082015290565b50604051615c5b81612625565b600081526000602082015290565b50602081015115615bf7565b6000615c996020926040516141ee8582816158188183019687815193849201612802565b8101039060025afa1561116b5760005190565b60125415801590615cba5790565b50601354151590565b9060ff601854169182615ce4575b5081615cdb575090565b90506019541490565b90915051600052600d602052604060002054159038615cd1565b908151918151830361026657600683029280840460061481151715610e7c57615d26846131ab565b9260005b828110615d6757505050506020918291604051938492615d4a8285612640565b8136853760051b910160086107cf195a01fa156102665751151590565b615d7181856130ec565b5151600682029082820460061483151715610e7c57615d9082886130ec565b526020615d9d83876130ec565b51015160009060018301808411615e6557615db890896130ec565b52615dc383856130ec565b515151905060028201808311610e7c57615ddd90886130ec565b526020615dea83856130ec565b5151015160009060038301808411615e6557615e0690896130ec565b526020615e1384866130ec565b51015151905060048201808311610e7c57615e2e90886130ec565b52602080615e3c84866130ec565b510151015190600060058201809211610e68575090615e5e60019392886130ec565b5201615d2a565b634e487b7160e01b83526011600452602483fd5b9081518110156130b657016020019056fe30644e72e131a029b85045b68181585d97816a916871ca8d3c208c16d87cfd47a2646970667358221220db91f3e6f13802ab5d94ce6ecb8d24a8f8a398c4a37da311d7f882451966e0e964736f6c634300081e0033",
 }
 
 // ContractABI is the input ABI used to generate the binding from.
@@ -169,11 +168,11 @@ func NewContractFilterer(address common.Address, filterer bind.ContractFilterer)
 
 // bindContract binds a generic wrapper to an already deployed contract.
 func bindContract(address common.Address, caller bind.ContractCaller, transactor bind.ContractTransactor, filterer bind.ContractFilterer) (*bind.BoundContract, error) {
-	parsed, err := ContractMetaData.GetAbi()
+	parsed, err := abi.JSON(strings.NewReader(ContractABI))
 	if err != nil {
 		return nil, err
 	}
-	return bind.NewBoundContract(address, *parsed, caller, transactor, filterer), nil
+	return bind.NewBoundContract(address, parsed, caller, transactor, filterer), nil
 }
 
 // Call invokes the (constant) contract method with params as input values and
@@ -245,6 +244,68 @@ func (_Contract *ContractCallerSession) CheckClaim(pk BCSIDG1Point, attribute st
 	return _Contract.Contract.CheckClaim(&_Contract.CallOpts, pk, attribute)
 }
 
+// CheckClaimFrom is a free data retrieval call binding the contract method 0x968b6314.
+//
+// Solidity: function CheckClaimFrom((uint256,uint256) pk, string attribute, bytes32 _issuer) view returns(bool)
+func (_Contract *ContractCaller) CheckClaimFrom(opts *bind.CallOpts, pk BCSIDG1Point, attribute string, _issuer [32]byte) (bool, error) {
+	var out []interface{}
+	err := _Contract.contract.Call(opts, &out, "CheckClaimFrom", pk, attribute, _issuer)
+
+	if err != nil {
+		return *new(bool), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(bool)).(*bool)
+
+	return out0, err
+
+}
+
+// CheckClaimFrom is a free data retrieval call binding the contract method 0x968b6314.
+//
+// Solidity: function CheckClaimFrom((uint256,uint256) pk, string attribute, bytes32 _issuer) view returns(bool)
+func (_Contract *ContractSession) CheckClaimFrom(pk BCSIDG1Point, attribute string, _issuer [32]byte) (bool, error) {
+	return _Contract.Contract.CheckClaimFrom(&_Contract.CallOpts, pk, attribute, _issuer)
+}
+
+// CheckClaimFrom is a free data retrieval call binding the contract method 0x968b6314.
+//
+// Solidity: function CheckClaimFrom((uint256,uint256) pk, string attribute, bytes32 _issuer) view returns(bool)
+func (_Contract *ContractCallerSession) CheckClaimFrom(pk BCSIDG1Point, attribute string, _issuer [32]byte) (bool, error) {
+	return _Contract.Contract.CheckClaimFrom(&_Contract.CallOpts, pk, attribute, _issuer)
+}
+
+// CurrentKeyVersion is a free data retrieval call binding the contract method 0x722f7223.
+//
+// Solidity: function CurrentKeyVersion() view returns(uint256)
+func (_Contract *ContractCaller) CurrentKeyVersion(opts *bind.CallOpts) (*big.Int, error) {
+	var out []interface{}
+	err := _Contract.contract.Call(opts, &out, "CurrentKeyVersion")
+
+	if err != nil {
+		return *new(*big.Int), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(*big.Int)).(**big.Int)
+
+	return out0, err
+
+}
+
+// CurrentKeyVersion is a free data retrieval call binding the contract method 0x722f7223.
+//
+// Solidity: function CurrentKeyVersion() view returns(uint256)
+func (_Contract *ContractSession) CurrentKeyVersion() (*big.Int, error) {
+	return _Contract.Contract.CurrentKeyVersion(&_Contract.CallOpts)
+}
+
+// CurrentKeyVersion is a free data retrieval call binding the contract method 0x722f7223.
+//
+// Solidity: function CurrentKeyVersion() view returns(uint256)
+func (_Contract *ContractCallerSession) CurrentKeyVersion() (*big.Int, error) {
+	return _Contract.Contract.CurrentKeyVersion(&_Contract.CallOpts)
+}
+
 // GetConfirmResult is a free data retrieval call binding the contract method 0xd8483c75.
 //
 // Solidity: function GetConfirmResult(address _sellerAddr, address _buyerAddr, string _orderID) view returns(bool)
@@ -307,74 +368,77 @@ func (_Contract *ContractCallerSession) GetCurrentSite(_orderID string) (string,
 	return _Contract.Contract.GetCurrentSite(&_Contract.CallOpts, _orderID)
 }
 
-// GetMapping is a free data retrieval call binding the contract method 0x24f47457.
+// GetIssuerKey is a free data retrieval call binding the contract method 0x8aafae65.
 //
-// Solidity: function GetMapping((uint256,uint256) pk) view returns(string)
-func (_Contract *ContractCaller) GetMapping(opts *bind.CallOpts, pk BCSIDG1Point) (string, error) {
+// Solidity: function GetIssuerKey(uint256 _version) view returns((uint256,uint256), (uint256,uint256)[], uint256, uint256)
+func (_Contract *ContractCaller) GetIssuerKey(opts *bind.CallOpts, _version *big.Int) (BCSIDG1Point, []BCSIDG1Point, *big.Int, *big.Int, error) {
 	var out []interface{}
-	err := _Contract.contract.Call(opts, &out, "GetMapping", pk)
+	err := _Contract.contract.Call(opts, &out, "GetIssuerKey", _version)
 
 	if err != nil {
-		return *new(string), err
+		return *new(BCSIDG1Point), *new([]BCSIDG1Point), *new(*big.Int), *new(*big.Int), err
 	}
 
-	out0 := *abi.ConvertType(out[0], new(string)).(*string)
+	out0 := *abi.ConvertType(out[0], new(BCSIDG1Point)).(*BCSIDG1Point)
+	out1 := *abi.ConvertType(out[1], new([]BCSIDG1Point)).(*[]BCSIDG1Point)
+	out2 := *abi.ConvertType(out[2], new(*big.Int)).(**big.Int)
+	out3 := *abi.ConvertType(out[3], new(*big.Int)).(**big.Int)
 
-	return out0, err
+	return out0, out1, out2, out3, err
 
 }
 
-// GetMapping is a free data retrieval call binding the contract method 0x24f47457.
+// GetIssuerKey is a free data retrieval call binding the contract method 0x8aafae65.
 //
-// Solidity: function GetMapping((uint256,uint256) pk) view returns(string)
-func (_Contract *ContractSession) GetMapping(pk BCSIDG1Point) (string, error) {
-	return _Contract.Contract.GetMapping(&_Contract.CallOpts, pk)
+// Solidity: function GetIssuerKey(uint256 _version) view returns((uint256,uint256), (uint256,uint256)[], uint256, uint256)
+func (_Contract *ContractSession) GetIssuerKey(_version *big.Int) (BCSIDG1Point, []BCSIDG1Point, *big.Int, *big.Int, error) {
+	return _Contract.Contract.GetIssuerKey(&_Contract.CallOpts, _version)
 }
 
-// GetMapping is a free data retrieval call binding the contract method 0x24f47457.
+// GetIssuerKey is a free data retrieval call binding the contract method 0x8aafae65.
 //
-// Solidity: function GetMapping((uint256,uint256) pk) view returns(string)
-func (_Contract *ContractCallerSession) GetMapping(pk BCSIDG1Point) (string, error) {
-	return _Contract.Contract.GetMapping(&_Contract.CallOpts, pk)
+// Solidity: function GetIssuerKey(uint256 _version) view returns((uint256,uint256), (uint256,uint256)[], uint256, uint256)
+func (_Contract *ContractCallerSession) GetIssuerKey(_version *big.Int) (BCSIDG1Point, []BCSIDG1Point, *big.Int, *big.Int, error) {
+	return _Contract.Contract.GetIssuerKey(&_Contract.CallOpts, _version)
 }
 
-// GetPointKey is a free data retrieval call binding the contract method 0xa3ebb000.
+// GetProduct is a free data retrieval call binding the contract method 0x1aa4a01d.
 //
-// Solidity: function GetPointKey((uint256,uint256) pk) pure returns(bytes32)
-func (_Contract *ContractCaller) GetPointKey(opts *bind.CallOpts, pk BCSIDG1Point) ([32]byte, error) {
+// Solidity: function GetProduct(address _addr, string _productID) view returns(uint256)
+func (_Contract *ContractCaller) GetProduct(opts *bind.CallOpts, _addr common.Address, _productID string) (*big.Int, error) {
 	var out []interface{}
-	err := _Contract.contract.Call(opts, &out, "GetPointKey", pk)
+	err := _Contract.contract.Call(opts, &out, "GetProduct", _addr, _productID)
 
 	if err != nil {
-		return *new([32]byte), err
+		return *new(*big.Int), err
 	}
 
-	out0 := *abi.ConvertType(out[0], new([32]byte)).(*[32]byte)
+	out0 := *abi.ConvertType(out[0], new(*big.Int)).(**big.Int)
 
 	return out0, err
 
 }
 
-// GetPointKey is a free data retrieval call binding the contract method 0xa3ebb000.
+// GetProduct is a free data retrieval call binding the contract method 0x1aa4a01d.
 //
-// Solidity: function GetPointKey((uint256,uint256) pk) pure returns(bytes32)
-func (_Contract *ContractSession) GetPointKey(pk BCSIDG1Point) ([32]byte, error) {
-	return _Contract.Contract.GetPointKey(&_Contract.CallOpts, pk)
+// Solidity: function GetProduct(address _addr, string _productID) view returns(uint256)
+func (_Contract *ContractSession) GetProduct(_addr common.Address, _productID string) (*big.Int, error) {
+	return _Contract.Contract.GetProduct(&_Contract.CallOpts, _addr, _productID)
 }
 
-// GetPointKey is a free data retrieval call binding the contract method 0xa3ebb000.
+// GetProduct is a free data retrieval call binding the contract method 0x1aa4a01d.
 //
-// Solidity: function GetPointKey((uint256,uint256) pk) pure returns(bytes32)
-func (_Contract *ContractCallerSession) GetPointKey(pk BCSIDG1Point) ([32]byte, error) {
-	return _Contract.Contract.GetPointKey(&_Contract.CallOpts, pk)
+// Solidity: function GetProduct(address _addr, string _productID) view returns(uint256)
+func (_Contract *ContractCallerSession) GetProduct(_addr common.Address, _productID string) (*big.Int, error) {
+	return _Contract.Contract.GetProduct(&_Contract.CallOpts, _addr, _productID)
 }
 
-// GetProduct is a free data retrieval call binding the contract method 0x1aa4a01d.
+// GetRevocationEpoch is a free data retrieval call binding the contract method 0xfb61bd73.
 //
-// Solidity: function GetProduct(address _addr, string _productID) view returns(uint256)
-func (_Contract *ContractCaller) GetProduct(opts *bind.CallOpts, _addr common.Address, _productID string) (*big.Int, error) {
+// Solidity: function GetRevocationEpoch() view returns(uint256)
+func (_Contract *ContractCaller) GetRevocationEpoch(opts *bind.CallOpts) (*big.Int, error) {
 	var out []interface{}
-	err := _Contract.contract.Call(opts, &out, "GetProduct", _addr, _productID)
+	err := _Contract.contract.Call(opts, &out, "GetRevocationEpoch")
 
 	if err != nil {
 		return *new(*big.Int), err
@@ -386,18 +450,18 @@ func (_Contract *ContractCaller) GetProduct(opts *bind.CallOpts, _addr common.Ad
 
 }
 
-// GetProduct is a free data retrieval call binding the contract method 0x1aa4a01d.
+// GetRevocationEpoch is a free data retrieval call binding the contract method 0xfb61bd73.
 //
-// Solidity: function GetProduct(address _addr, string _productID) view returns(uint256)
-func (_Contract *ContractSession) GetProduct(_addr common.Address, _productID string) (*big.Int, error) {
-	return _Contract.Contract.GetProduct(&_Contract.CallOpts, _addr, _productID)
+// Solidity: function GetRevocationEpoch() view returns(uint256)
+func (_Contract *ContractSession) GetRevocationEpoch() (*big.Int, error) {
+	return _Contract.Contract.GetRevocationEpoch(&_Contract.CallOpts)
 }
 
-// GetProduct is a free data retrieval call binding the contract method 0x1aa4a01d.
+// GetRevocationEpoch is a free data retrieval call binding the contract method 0xfb61bd73.
 //
-// Solidity: function GetProduct(address _addr, string _productID) view returns(uint256)
-func (_Contract *ContractCallerSession) GetProduct(_addr common.Address, _productID string) (*big.Int, error) {
-	return _Contract.Contract.GetProduct(&_Contract.CallOpts, _addr, _productID)
+// Solidity: function GetRevocationEpoch() view returns(uint256)
+func (_Contract *ContractCallerSession) GetRevocationEpoch() (*big.Int, error) {
+	return _Contract.Contract.GetRevocationEpoch(&_Contract.CallOpts)
 }
 
 // GetSN is a free data retrieval call binding the contract method 0x9b4b8d2a.
@@ -431,66 +495,221 @@ func (_Contract *ContractCallerSession) GetSN(_orderID string) (BCSIDG1Point, er
 	return _Contract.Contract.GetSN(&_Contract.CallOpts, _orderID)
 }
 
-// SID is a free data retrieval call binding the contract method 0x7760cd2f.
+// GetValidityEpoch is a free data retrieval call binding the contract method 0x3f7526ac.
 //
-// Solidity: function SID(bytes32 ) view returns(string)
-func (_Contract *ContractCaller) SID(opts *bind.CallOpts, arg0 [32]byte) (string, error) {
+// Solidity: function GetValidityEpoch() view returns(uint256)
+func (_Contract *ContractCaller) GetValidityEpoch(opts *bind.CallOpts) (*big.Int, error) {
 	var out []interface{}
-	err := _Contract.contract.Call(opts, &out, "SID", arg0)
+	err := _Contract.contract.Call(opts, &out, "GetValidityEpoch")
 
 	if err != nil {
-		return *new(string), err
+		return *new(*big.Int), err
 	}
 
-	out0 := *abi.ConvertType(out[0], new(string)).(*string)
+	out0 := *abi.ConvertType(out[0], new(*big.Int)).(**big.Int)
 
 	return out0, err
 
 }
 
-// SID is a free data retrieval call binding the contract method 0x7760cd2f.
+// GetValidityEpoch is a free data retrieval call binding the contract method 0x3f7526ac.
 //
-// Solidity: function SID(bytes32 ) view returns(string)
-func (_Contract *ContractSession) SID(arg0 [32]byte) (string, error) {
-	return _Contract.Contract.SID(&_Contract.CallOpts, arg0)
+// Solidity: function GetValidityEpoch() view returns(uint256)
+func (_Contract *ContractSession) GetValidityEpoch() (*big.Int, error) {
+	return _Contract.Contract.GetValidityEpoch(&_Contract.CallOpts)
 }
 
-// SID is a free data retrieval call binding the contract method 0x7760cd2f.
+// GetValidityEpoch is a free data retrieval call binding the contract method 0x3f7526ac.
 //
-// Solidity: function SID(bytes32 ) view returns(string)
-func (_Contract *ContractCallerSession) SID(arg0 [32]byte) (string, error) {
-	return _Contract.Contract.SID(&_Contract.CallOpts, arg0)
+// Solidity: function GetValidityEpoch() view returns(uint256)
+func (_Contract *ContractCallerSession) GetValidityEpoch() (*big.Int, error) {
+	return _Contract.Contract.GetValidityEpoch(&_Contract.CallOpts)
 }
 
-// SIDSet is a free data retrieval call binding the contract method 0x43137afd.
+// IsKeyValid is a free data retrieval call binding the contract method 0x3b146cfe.
 //
-// Solidity: function SIDSet(bytes32 , uint256 ) view returns(string)
-func (_Contract *ContractCaller) SIDSet(opts *bind.CallOpts, arg0 [32]byte, arg1 *big.Int) (string, error) {
+// Solidity: function IsKeyValid(uint256 _version) view returns(bool)
+func (_Contract *ContractCaller) IsKeyValid(opts *bind.CallOpts, _version *big.Int) (bool, error) {
 	var out []interface{}
-	err := _Contract.contract.Call(opts, &out, "SIDSet", arg0, arg1)
+	err := _Contract.contract.Call(opts, &out, "IsKeyValid", _version)
 
 	if err != nil {
-		return *new(string), err
+		return *new(bool), err
 	}
 
-	out0 := *abi.ConvertType(out[0], new(string)).(*string)
+	out0 := *abi.ConvertType(out[0], new(bool)).(*bool)
+
+	return out0, err
+
+}
+
+// IsKeyValid is a free data retrieval call binding the contract method 0x3b146cfe.
+//
+// Solidity: function IsKeyValid(uint256 _version) view returns(bool)
+func (_Contract *ContractSession) IsKeyValid(_version *big.Int) (bool, error) {
+	return _Contract.Contract.IsKeyValid(&_Contract.CallOpts, _version)
+}
+
+// IsKeyValid is a free data retrieval call binding the contract method 0x3b146cfe.
+//
+// Solidity: function IsKeyValid(uint256 _version) view returns(bool)
+func (_Contract *ContractCallerSession) IsKeyValid(_version *big.Int) (bool, error) {
+	return _Contract.Contract.IsKeyValid(&_Contract.CallOpts, _version)
+}
+
+// IsRevoked is a free data retrieval call binding the contract method 0xe2008041.
+//
+// Solidity: function IsRevoked((uint256,uint256) pk) view returns(bool)
+func (_Contract *ContractCaller) IsRevoked(opts *bind.CallOpts, pk BCSIDG1Point) (bool, error) {
+	var out []interface{}
+	err := _Contract.contract.Call(opts, &out, "IsRevoked", pk)
+
+	if err != nil {
+		return *new(bool), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(bool)).(*bool)
+
+	return out0, err
+
+}
+
+// IsRevoked is a free data retrieval call binding the contract method 0xe2008041.
+//
+// Solidity: function IsRevoked((uint256,uint256) pk) view returns(bool)
+func (_Contract *ContractSession) IsRevoked(pk BCSIDG1Point) (bool, error) {
+	return _Contract.Contract.IsRevoked(&_Contract.CallOpts, pk)
+}
+
+// IsRevoked is a free data retrieval call binding the contract method 0xe2008041.
+//
+// Solidity: function IsRevoked((uint256,uint256) pk) view returns(bool)
+func (_Contract *ContractCallerSession) IsRevoked(pk BCSIDG1Point) (bool, error) {
+	return _Contract.Contract.IsRevoked(&_Contract.CallOpts, pk)
+}
+
+// IssuerKeyFingerprint is a free data retrieval call binding the contract method 0xbc6a4a90.
+//
+// Solidity: function IssuerKeyFingerprint(uint256 _version) view returns(bytes32)
+func (_Contract *ContractCaller) IssuerKeyFingerprint(opts *bind.CallOpts, _version *big.Int) ([32]byte, error) {
+	var out []interface{}
+	err := _Contract.contract.Call(opts, &out, "IssuerKeyFingerprint", _version)
+
+	if err != nil {
+		return *new([32]byte), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new([32]byte)).(*[32]byte)
+
+	return out0, err
+
+}
+
+// IssuerKeyFingerprint is a free data retrieval call binding the contract method 0xbc6a4a90.
+//
+// Solidity: function IssuerKeyFingerprint(uint256 _version) view returns(bytes32)
+func (_Contract *ContractSession) IssuerKeyFingerprint(_version *big.Int) ([32]byte, error) {
+	return _Contract.Contract.IssuerKeyFingerprint(&_Contract.CallOpts, _version)
+}
+
+// IssuerKeyFingerprint is a free data retrieval call binding the contract method 0xbc6a4a90.
+//
+// Solidity: function IssuerKeyFingerprint(uint256 _version) view returns(bytes32)
+func (_Contract *ContractCallerSession) IssuerKeyFingerprint(_version *big.Int) ([32]byte, error) {
+	return _Contract.Contract.IssuerKeyFingerprint(&_Contract.CallOpts, _version)
+}
+
+// IssuerKeyVersion is a free data retrieval call binding the contract method 0x32ad8351.
+//
+// Solidity: function IssuerKeyVersion(bytes32 _id) view returns(uint256)
+func (_Contract *ContractCaller) IssuerKeyVersion(opts *bind.CallOpts, _id [32]byte) (*big.Int, error) {
+	var out []interface{}
+	err := _Contract.contract.Call(opts, &out, "IssuerKeyVersion", _id)
+
+	if err != nil {
+		return *new(*big.Int), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(*big.Int)).(**big.Int)
+
+	return out0, err
+
+}
+
+// IssuerKeyVersion is a free data retrieval call binding the contract method 0x32ad8351.
+//
+// Solidity: function IssuerKeyVersion(bytes32 _id) view returns(uint256)
+func (_Contract *ContractSession) IssuerKeyVersion(_id [32]byte) (*big.Int, error) {
+	return _Contract.Contract.IssuerKeyVersion(&_Contract.CallOpts, _id)
+}
+
+// IssuerKeyVersion is a free data retrieval call binding the contract method 0x32ad8351.
+//
+// Solidity: function IssuerKeyVersion(bytes32 _id) view returns(uint256)
+func (_Contract *ContractCallerSession) IssuerKeyVersion(_id [32]byte) (*big.Int, error) {
+	return _Contract.Contract.IssuerKeyVersion(&_Contract.CallOpts, _id)
+}
+
+// KeyIssuerOf is a free data retrieval call binding the contract method 0x0c4efd28.
+//
+// Solidity: function KeyIssuerOf(uint256 _version) view returns(bytes32)
+func (_Contract *ContractCaller) KeyIssuerOf(opts *bind.CallOpts, _version *big.Int) ([32]byte, error) {
+	var out []interface{}
+	err := _Contract.contract.Call(opts, &out, "KeyIssuerOf", _version)
+
+	if err != nil {
+		return *new([32]byte), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new([32]byte)).(*[32]byte)
 
 	return out0, err
 
 }
 
-// SIDSet is a free data retrieval call binding the contract method 0x43137afd.
+// KeyIssuerOf is a free data retrieval call binding the contract method 0x0c4efd28.
 //
-// Solidity: function SIDSet(bytes32 , uint256 ) view returns(string)
-func (_Contract *ContractSession) SIDSet(arg0 [32]byte, arg1 *big.Int) (string, error) {
-	return _Contract.Contract.SIDSet(&_Contract.CallOpts, arg0, arg1)
+// Solidity: function KeyIssuerOf(uint256 _version) view returns(bytes32)
+func (_Contract *ContractSession) KeyIssuerOf(_version *big.Int) ([32]byte, error) {
+	return _Contract.Contract.KeyIssuerOf(&_Contract.CallOpts, _version)
 }
 
-// SIDSet is a free data retrieval call binding the contract method 0x43137afd.
+// KeyIssuerOf is a free data retrieval call binding the contract method 0x0c4efd28.
 //
-// Solidity: function SIDSet(bytes32 , uint256 ) view returns(string)
-func (_Contract *ContractCallerSession) SIDSet(arg0 [32]byte, arg1 *big.Int) (string, error) {
-	return _Contract.Contract.SIDSet(&_Contract.CallOpts, arg0, arg1)
+// Solidity: function KeyIssuerOf(uint256 _version) view returns(bytes32)
+func (_Contract *ContractCallerSession) KeyIssuerOf(_version *big.Int) ([32]byte, error) {
+	return _Contract.Contract.KeyIssuerOf(&_Contract.CallOpts, _version)
+}
+
+// ScopeBase is a free data retrieval call binding the contract method 0x1fe7c514.
+//
+// Solidity: function ScopeBase(address _seller) view returns((uint256,uint256))
+func (_Contract *ContractCaller) ScopeBase(opts *bind.CallOpts, _seller common.Address) (BCSIDG1Point, error) {
+	var out []interface{}
+	err := _Contract.contract.Call(opts, &out, "ScopeBase", _seller)
+
+	if err != nil {
+		return *new(BCSIDG1Point), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(BCSIDG1Point)).(*BCSIDG1Point)
+
+	return out0, err
+
+}
+
+// ScopeBase is a free data retrieval call binding the contract method 0x1fe7c514.
+//
+// Solidity: function ScopeBase(address _seller) view returns((uint256,uint256))
+func (_Contract *ContractSession) ScopeBase(_seller common.Address) (BCSIDG1Point, error) {
+	return _Contract.Contract.ScopeBase(&_Contract.CallOpts, _seller)
+}
+
+// ScopeBase is a free data retrieval call binding the contract method 0x1fe7c514.
+//
+// Solidity: function ScopeBase(address _seller) view returns((uint256,uint256))
+func (_Contract *ContractCallerSession) ScopeBase(_seller common.Address) (BCSIDG1Point, error) {
+	return _Contract.Contract.ScopeBase(&_Contract.CallOpts, _seller)
 }
 
 // VerifyCode is a free data retrieval call binding the contract method 0xed758406.
@@ -524,6 +743,68 @@ func (_Contract *ContractCallerSession) VerifyCode(_orderID string, _code string
 	return _Contract.Contract.VerifyCode(&_Contract.CallOpts, _orderID, _code)
 }
 
+// VerifyKeyBinding is a free data retrieval call binding the contract method 0x9f83afcc.
+//
+// Solidity: function VerifyKeyBinding((uint256,uint256) _pk1, (uint256[2],uint256[2]) _u, (uint256[2],uint256[2]) _r, uint256 _c, uint256 _z, (uint256,uint256) _a1, (uint256[2],uint256[2]) _a2) view returns(bool)
+func (_Contract *ContractCaller) VerifyKeyBinding(opts *bind.CallOpts, _pk1 BCSIDG1Point, _u BCSIDG2Point, _r BCSIDG2Point, _c *big.Int, _z *big.Int, _a1 BCSIDG1Point, _a2 BCSIDG2Point) (bool, error) {
+	var out []interface{}
+	err := _Contract.contract.Call(opts, &out, "VerifyKeyBinding", _pk1, _u, _r, _c, _z, _a1, _a2)
+
+	if err != nil {
+		return *new(bool), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(bool)).(*bool)
+
+	return out0, err
+
+}
+
+// VerifyKeyBinding is a free data retrieval call binding the contract method 0x9f83afcc.
+//
+// Solidity: function VerifyKeyBinding((uint256,uint256) _pk1, (uint256[2],uint256[2]) _u, (uint256[2],uint256[2]) _r, uint256 _c, uint256 _z, (uint256,uint256) _a1, (uint256[2],uint256[2]) _a2) view returns(bool)
+func (_Contract *ContractSession) VerifyKeyBinding(_pk1 BCSIDG1Point, _u BCSIDG2Point, _r BCSIDG2Point, _c *big.Int, _z *big.Int, _a1 BCSIDG1Point, _a2 BCSIDG2Point) (bool, error) {
+	return _Contract.Contract.VerifyKeyBinding(&_Contract.CallOpts, _pk1, _u, _r, _c, _z, _a1, _a2)
+}
+
+// VerifyKeyBinding is a free data retrieval call binding the contract method 0x9f83afcc.
+//
+// Solidity: function VerifyKeyBinding((uint256,uint256) _pk1, (uint256[2],uint256[2]) _u, (uint256[2],uint256[2]) _r, uint256 _c, uint256 _z, (uint256,uint256) _a1, (uint256[2],uint256[2]) _a2) view returns(bool)
+func (_Contract *ContractCallerSession) VerifyKeyBinding(_pk1 BCSIDG1Point, _u BCSIDG2Point, _r BCSIDG2Point, _c *big.Int, _z *big.Int, _a1 BCSIDG1Point, _a2 BCSIDG2Point) (bool, error) {
+	return _Contract.Contract.VerifyKeyBinding(&_Contract.CallOpts, _pk1, _u, _r, _c, _z, _a1, _a2)
+}
+
+// VerifyPiV is a free data retrieval call binding the contract method 0x97305c31.
+//
+// Solidity: function VerifyPiV((uint256,uint256) _kappa, (uint256[2],uint256[2]) _u, (uint256[2],uint256[2]) _s, bool[] _disclosed, uint256 _c, uint256[] _rm, uint256 _rt, (uint256,uint256) _tag, uint256 _keyVersion) view returns(bool)
+func (_Contract *ContractCaller) VerifyPiV(opts *bind.CallOpts, _kappa BCSIDG1Point, _u BCSIDG2Point, _s BCSIDG2Point, _disclosed []bool, _c *big.Int, _rm []*big.Int, _rt *big.Int, _tag BCSIDG1Point, _keyVersion *big.Int) (bool, error) {
+	var out []interface{}
+	err := _Contract.contract.Call(opts, &out, "VerifyPiV", _kappa, _u, _s, _disclosed, _c, _rm, _rt, _tag, _keyVersion)
+
+	if err != nil {
+		return *new(bool), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(bool)).(*bool)
+
+	return out0, err
+
+}
+
+// VerifyPiV is a free data retrieval call binding the contract method 0x97305c31.
+//
+// Solidity: function VerifyPiV((uint256,uint256) _kappa, (uint256[2],uint256[2]) _u, (uint256[2],uint256[2]) _s, bool[] _disclosed, uint256 _c, uint256[] _rm, uint256 _rt, (uint256,uint256) _tag, uint256 _keyVersion) view returns(bool)
+func (_Contract *ContractSession) VerifyPiV(_kappa BCSIDG1Point, _u BCSIDG2Point, _s BCSIDG2Point, _disclosed []bool, _c *big.Int, _rm []*big.Int, _rt *big.Int, _tag BCSIDG1Point, _keyVersion *big.Int) (bool, error) {
+	return _Contract.Contract.VerifyPiV(&_Contract.CallOpts, _kappa, _u, _s, _disclosed, _c, _rm, _rt, _tag, _keyVersion)
+}
+
+// VerifyPiV is a free data retrieval call binding the contract method 0x97305c31.
+//
+// Solidity: function VerifyPiV((uint256,uint256) _kappa, (uint256[2],uint256[2]) _u, (uint256[2],uint256[2]) _s, bool[] _disclosed, uint256 _c, uint256[] _rm, uint256 _rt, (uint256,uint256) _tag, uint256 _keyVersion) view returns(bool)
+func (_Contract *ContractCallerSession) VerifyPiV(_kappa BCSIDG1Point, _u BCSIDG2Point, _s BCSIDG2Point, _disclosed []bool, _c *big.Int, _rm []*big.Int, _rt *big.Int, _tag BCSIDG1Point, _keyVersion *big.Int) (bool, error) {
+	return _Contract.Contract.VerifyPiV(&_Contract.CallOpts, _kappa, _u, _s, _disclosed, _c, _rm, _rt, _tag, _keyVersion)
+}
+
 // GetBalanceOf is a free data retrieval call binding the contract method 0x9b96eece.
 //
 // Solidity: function getBalanceOf(address seller) view returns(uint256)
@@ -625,37 +906,6 @@ func (_Contract *ContractCallerSession) GetOrder(_seller common.Address, _buyer
 	return _Contract.Contract.GetOrder(&_Contract.CallOpts, _seller, _buyer, _orderID)
 }
 
-// GetSID is a free data retrieval call binding the contract method 0x5683e5ea.
-//
-// Solidity: function getSID((uint256,uint256) pk) view returns(string)
-func (_Contract *ContractCaller) GetSID(opts *bind.CallOpts, pk BCSIDG1Point) (string, error) {
-	var out []interface{}
-	err := _Contract.contract.Call(opts, &out, "getSID", pk)
-
-	if err != nil {
-		return *new(string), err
-	}
-
-	out0 := *abi.ConvertType(out[0], new(string)).(*string)
-
-	return out0, err
-
-}
-
-// GetSID is a free data retrieval call binding the contract method 0x5683e5ea.
-//
-// Solidity: function getSID((uint256,uint256) pk) view returns(string)
-func (_Contract *ContractSession) GetSID(pk BCSIDG1Point) (string, error) {
-	return _Contract.Contract.GetSID(&_Contract.CallOpts, pk)
-}
-
-// GetSID is a free data retrieval call binding the contract method 0x5683e5ea.
-//
-// Solidity: function getSID((uint256,uint256) pk) view returns(string)
-func (_Contract *ContractCallerSession) GetSID(pk BCSIDG1Point) (string, error) {
-	return _Contract.Contract.GetSID(&_Contract.CallOpts, pk)
-}
-
 // GetSIDSet is a free data retrieval call binding the contract method 0x146ac099.
 //
 // Solidity: function getSIDSet((uint256,uint256) pk) view returns(string[])
@@ -687,342 +937,193 @@ func (_Contract *ContractCallerSession) GetSIDSet(pk BCSIDG1Point) ([]string, er
 	return _Contract.Contract.GetSIDSet(&_Contract.CallOpts, pk)
 }
 
-// IsG2Zero is a free data retrieval call binding the contract method 0x6163fde7.
-//
-// Solidity: function isG2Zero((uint256[2],uint256[2]) point2) view returns(bool)
-func (_Contract *ContractCaller) IsG2Zero(opts *bind.CallOpts, point2 BCSIDG2Point) (bool, error) {
-	var out []interface{}
-	err := _Contract.contract.Call(opts, &out, "isG2Zero", point2)
-
-	if err != nil {
-		return *new(bool), err
-	}
-
-	out0 := *abi.ConvertType(out[0], new(bool)).(*bool)
-
-	return out0, err
-
-}
-
-// IsG2Zero is a free data retrieval call binding the contract method 0x6163fde7.
-//
-// Solidity: function isG2Zero((uint256[2],uint256[2]) point2) view returns(bool)
-func (_Contract *ContractSession) IsG2Zero(point2 BCSIDG2Point) (bool, error) {
-	return _Contract.Contract.IsG2Zero(&_Contract.CallOpts, point2)
-}
-
-// IsG2Zero is a free data retrieval call binding the contract method 0x6163fde7.
-//
-// Solidity: function isG2Zero((uint256[2],uint256[2]) point2) view returns(bool)
-func (_Contract *ContractCallerSession) IsG2Zero(point2 BCSIDG2Point) (bool, error) {
-	return _Contract.Contract.IsG2Zero(&_Contract.CallOpts, point2)
-}
-
-// OrderBook is a free data retrieval call binding the contract method 0x4d0b1eee.
+// CreateLogisticsOrder is a paid mutator transaction binding the contract method 0xfedbe99d.
 //
-// Solidity: function orderBook(address , address , string ) view returns(string productID, uint256 quantity, uint256 price, string orderID, uint256 lockedAmount, (uint256,uint256) buyerPubKey, bool isOngoing, bool isLocked, bool isBuyerConfirm)
-func (_Contract *ContractCaller) OrderBook(opts *bind.CallOpts, arg0 common.Address, arg1 common.Address, arg2 string) (struct {
-	ProductID      string
-	Quantity       *big.Int
-	Price          *big.Int
-	OrderID        string
-	LockedAmount   *big.Int
-	BuyerPubKey    BCSIDG1Point
-	IsOngoing      bool
-	IsLocked       bool
-	IsBuyerConfirm bool
-}, error) {
-	var out []interface{}
-	err := _Contract.contract.Call(opts, &out, "orderBook", arg0, arg1, arg2)
-
-	outstruct := new(struct {
-		ProductID      string
-		Quantity       *big.Int
-		Price          *big.Int
-		OrderID        string
-		LockedAmount   *big.Int
-		BuyerPubKey    BCSIDG1Point
-		IsOngoing      bool
-		IsLocked       bool
-		IsBuyerConfirm bool
-	})
-	if err != nil {
-		return *outstruct, err
-	}
-
-	outstruct.ProductID = *abi.ConvertType(out[0], new(string)).(*string)
-	outstruct.Quantity = *abi.ConvertType(out[1], new(*big.Int)).(**big.Int)
-	outstruct.Price = *abi.ConvertType(out[2], new(*big.Int)).(**big.Int)
-	outstruct.OrderID = *abi.ConvertType(out[3], new(string)).(*string)
-	outstruct.LockedAmount = *abi.ConvertType(out[4], new(*big.Int)).(**big.Int)
-	outstruct.BuyerPubKey = *abi.ConvertType(out[5], new(BCSIDG1Point)).(*BCSIDG1Point)
-	outstruct.IsOngoing = *abi.ConvertType(out[6], new(bool)).(*bool)
-	outstruct.IsLocked = *abi.ConvertType(out[7], new(bool)).(*bool)
-	outstruct.IsBuyerConfirm = *abi.ConvertType(out[8], new(bool)).(*bool)
-
-	return *outstruct, err
-
+// Solidity: function CreateLogisticsOrder(address _sellerAddr, address _buyerAddr, string _orderID, uint256 _code, (uint256,uint256) _SN) payable returns()
+func (_Contract *ContractTransactor) CreateLogisticsOrder(opts *bind.TransactOpts, _sellerAddr common.Address, _buyerAddr common.Address, _orderID string, _code *big.Int, _SN BCSIDG1Point) (*types.Transaction, error) {
+	return _Contract.contract.Transact(opts, "CreateLogisticsOrder", _sellerAddr, _buyerAddr, _orderID, _code, _SN)
 }
 
-// OrderBook is a free data retrieval call binding the contract method 0x4d0b1eee.
+// CreateLogisticsOrder is a paid mutator transaction binding the contract method 0xfedbe99d.
 //
-// Solidity: function orderBook(address , address , string ) view returns(string productID, uint256 quantity, uint256 price, string orderID, uint256 lockedAmount, (uint256,uint256) buyerPubKey, bool isOngoing, bool isLocked, bool isBuyerConfirm)
-func (_Contract *ContractSession) OrderBook(arg0 common.Address, arg1 common.Address, arg2 string) (struct {
-	ProductID      string
-	Quantity       *big.Int
-	Price          *big.Int
-	OrderID        string
-	LockedAmount   *big.Int
-	BuyerPubKey    BCSIDG1Point
-	IsOngoing      bool
-	IsLocked       bool
-	IsBuyerConfirm bool
-}, error) {
-	return _Contract.Contract.OrderBook(&_Contract.CallOpts, arg0, arg1, arg2)
+// Solidity: function CreateLogisticsOrder(address _sellerAddr, address _buyerAddr, string _orderID, uint256 _code, (uint256,uint256) _SN) payable returns()
+func (_Contract *ContractSession) CreateLogisticsOrder(_sellerAddr common.Address, _buyerAddr common.Address, _orderID string, _code *big.Int, _SN BCSIDG1Point) (*types.Transaction, error) {
+	return _Contract.Contract.CreateLogisticsOrder(&_Contract.TransactOpts, _sellerAddr, _buyerAddr, _orderID, _code, _SN)
 }
 
-// OrderBook is a free data retrieval call binding the contract method 0x4d0b1eee.
+// CreateLogisticsOrder is a paid mutator transaction binding the contract method 0xfedbe99d.
 //
-// Solidity: function orderBook(address , address , string ) view returns(string productID, uint256 quantity, uint256 price, string orderID, uint256 lockedAmount, (uint256,uint256) buyerPubKey, bool isOngoing, bool isLocked, bool isBuyerConfirm)
-func (_Contract *ContractCallerSession) OrderBook(arg0 common.Address, arg1 common.Address, arg2 string) (struct {
-	ProductID      string
-	Quantity       *big.Int
-	Price          *big.Int
-	OrderID        string
-	LockedAmount   *big.Int
-	BuyerPubKey    BCSIDG1Point
-	IsOngoing      bool
-	IsLocked       bool
-	IsBuyerConfirm bool
-}, error) {
-	return _Contract.Contract.OrderBook(&_Contract.CallOpts, arg0, arg1, arg2)
+// Solidity: function CreateLogisticsOrder(address _sellerAddr, address _buyerAddr, string _orderID, uint256 _code, (uint256,uint256) _SN) payable returns()
+func (_Contract *ContractTransactorSession) CreateLogisticsOrder(_sellerAddr common.Address, _buyerAddr common.Address, _orderID string, _code *big.Int, _SN BCSIDG1Point) (*types.Transaction, error) {
+	return _Contract.Contract.CreateLogisticsOrder(&_Contract.TransactOpts, _sellerAddr, _buyerAddr, _orderID, _code, _SN)
 }
 
-// OrderLogistics is a free data retrieval call binding the contract method 0x432964e9.
+// DLVerify is a paid mutator transaction binding the contract method 0x4ea85bd1.
 //
-// Solidity: function orderLogistics(string ) view returns((uint256,uint256) buyerPubKey, bool isOngoing, string currentSite, uint256 code, (uint256,uint256) SN)
-func (_Contract *ContractCaller) OrderLogistics(opts *bind.CallOpts, arg0 string) (struct {
-	BuyerPubKey BCSIDG1Point
-	IsOngoing   bool
-	CurrentSite string
-	Code        *big.Int
-	SN          BCSIDG1Point
-}, error) {
-	var out []interface{}
-	err := _Contract.contract.Call(opts, &out, "orderLogistics", arg0)
-
-	outstruct := new(struct {
-		BuyerPubKey BCSIDG1Point
-		IsOngoing   bool
-		CurrentSite string
-		Code        *big.Int
-		SN          BCSIDG1Point
-	})
-	if err != nil {
-		return *outstruct, err
-	}
-
-	outstruct.BuyerPubKey = *abi.ConvertType(out[0], new(BCSIDG1Point)).(*BCSIDG1Point)
-	outstruct.IsOngoing = *abi.ConvertType(out[1], new(bool)).(*bool)
-	outstruct.CurrentSite = *abi.ConvertType(out[2], new(string)).(*string)
-	outstruct.Code = *abi.ConvertType(out[3], new(*big.Int)).(**big.Int)
-	outstruct.SN = *abi.ConvertType(out[4], new(BCSIDG1Point)).(*BCSIDG1Point)
-
-	return *outstruct, err
-
+// Solidity: function DLVerify((uint256,uint256) g1, (uint256,uint256) y1, (uint256,uint256) a1, uint256 c, uint256 z) payable returns(bool)
+func (_Contract *ContractTransactor) DLVerify(opts *bind.TransactOpts, g1 BCSIDG1Point, y1 BCSIDG1Point, a1 BCSIDG1Point, c *big.Int, z *big.Int) (*types.Transaction, error) {
+	return _Contract.contract.Transact(opts, "DLVerify", g1, y1, a1, c, z)
 }
 
-// OrderLogistics is a free data retrieval call binding the contract method 0x432964e9.
+// DLVerify is a paid mutator transaction binding the contract method 0x4ea85bd1.
 //
-// Solidity: function orderLogistics(string ) view returns((uint256,uint256) buyerPubKey, bool isOngoing, string currentSite, uint256 code, (uint256,uint256) SN)
-func (_Contract *ContractSession) OrderLogistics(arg0 string) (struct {
-	BuyerPubKey BCSIDG1Point
-	IsOngoing   bool
-	CurrentSite string
-	Code        *big.Int
-	SN          BCSIDG1Point
-}, error) {
-	return _Contract.Contract.OrderLogistics(&_Contract.CallOpts, arg0)
+// Solidity: function DLVerify((uint256,uint256) g1, (uint256,uint256) y1, (uint256,uint256) a1, uint256 c, uint256 z) payable returns(bool)
+func (_Contract *ContractSession) DLVerify(g1 BCSIDG1Point, y1 BCSIDG1Point, a1 BCSIDG1Point, c *big.Int, z *big.Int) (*types.Transaction, error) {
+	return _Contract.Contract.DLVerify(&_Contract.TransactOpts, g1, y1, a1, c, z)
 }
 
-// OrderLogistics is a free data retrieval call binding the contract method 0x432964e9.
+// DLVerify is a paid mutator transaction binding the contract method 0x4ea85bd1.
 //
-// Solidity: function orderLogistics(string ) view returns((uint256,uint256) buyerPubKey, bool isOngoing, string currentSite, uint256 code, (uint256,uint256) SN)
-func (_Contract *ContractCallerSession) OrderLogistics(arg0 string) (struct {
-	BuyerPubKey BCSIDG1Point
-	IsOngoing   bool
-	CurrentSite string
-	Code        *big.Int
-	SN          BCSIDG1Point
-}, error) {
-	return _Contract.Contract.OrderLogistics(&_Contract.CallOpts, arg0)
+// Solidity: function DLVerify((uint256,uint256) g1, (uint256,uint256) y1, (uint256,uint256) a1, uint256 c, uint256 z) payable returns(bool)
+func (_Contract *ContractTransactorSession) DLVerify(g1 BCSIDG1Point, y1 BCSIDG1Point, a1 BCSIDG1Point, c *big.Int, z *big.Int) (*types.Transaction, error) {
+	return _Contract.Contract.DLVerify(&_Contract.TransactOpts, g1, y1, a1, c, z)
 }
 
-// ProductPrices is a free data retrieval call binding the contract method 0x12451ac9.
+// RegisterIssuer is a paid mutator transaction binding the contract method 0xb7ee0602.
 //
-// Solidity: function productPrices(address , string ) view returns(uint256)
-func (_Contract *ContractCaller) ProductPrices(opts *bind.CallOpts, arg0 common.Address, arg1 string) (*big.Int, error) {
-	var out []interface{}
-	err := _Contract.contract.Call(opts, &out, "productPrices", arg0, arg1)
-
-	if err != nil {
-		return *new(*big.Int), err
-	}
-
-	out0 := *abi.ConvertType(out[0], new(*big.Int)).(**big.Int)
-
-	return out0, err
-
+// Solidity: function RegisterIssuer(bytes32 _id, address _addr, (uint256,uint256) _pkx, (uint256,uint256)[] _pky, uint256 _epoch) returns()
+func (_Contract *ContractTransactor) RegisterIssuer(opts *bind.TransactOpts, _id [32]byte, _addr common.Address, _pkx BCSIDG1Point, _pky []BCSIDG1Point, _epoch *big.Int) (*types.Transaction, error) {
+	return _Contract.contract.Transact(opts, "RegisterIssuer", _id, _addr, _pkx, _pky, _epoch)
 }
 
-// ProductPrices is a free data retrieval call binding the contract method 0x12451ac9.
+// RegisterIssuer is a paid mutator transaction binding the contract method 0xb7ee0602.
 //
-// Solidity: function productPrices(address , string ) view returns(uint256)
-func (_Contract *ContractSession) ProductPrices(arg0 common.Address, arg1 string) (*big.Int, error) {
-	return _Contract.Contract.ProductPrices(&_Contract.CallOpts, arg0, arg1)
+// Solidity: function RegisterIssuer(bytes32 _id, address _addr, (uint256,uint256) _pkx, (uint256,uint256)[] _pky, uint256 _epoch) returns()
+func (_Contract *ContractSession) RegisterIssuer(_id [32]byte, _addr common.Address, _pkx BCSIDG1Point, _pky []BCSIDG1Point, _epoch *big.Int) (*types.Transaction, error) {
+	return _Contract.Contract.RegisterIssuer(&_Contract.TransactOpts, _id, _addr, _pkx, _pky, _epoch)
 }
 
-// ProductPrices is a free data retrieval call binding the contract method 0x12451ac9.
+// RegisterIssuer is a paid mutator transaction binding the contract method 0xb7ee0602.
 //
-// Solidity: function productPrices(address , string ) view returns(uint256)
-func (_Contract *ContractCallerSession) ProductPrices(arg0 common.Address, arg1 string) (*big.Int, error) {
-	return _Contract.Contract.ProductPrices(&_Contract.CallOpts, arg0, arg1)
+// Solidity: function RegisterIssuer(bytes32 _id, address _addr, (uint256,uint256) _pkx, (uint256,uint256)[] _pky, uint256 _epoch) returns()
+func (_Contract *ContractTransactorSession) RegisterIssuer(_id [32]byte, _addr common.Address, _pkx BCSIDG1Point, _pky []BCSIDG1Point, _epoch *big.Int) (*types.Transaction, error) {
+	return _Contract.Contract.RegisterIssuer(&_Contract.TransactOpts, _id, _addr, _pkx, _pky, _epoch)
 }
 
-// StringToUint256 is a free data retrieval call binding the contract method 0xac5d3723.
+// RegisterNymSet is a paid mutator transaction binding the contract method 0xff922e8a.
 //
-// Solidity: function stringToUint256(string attribute) pure returns(uint256)
-func (_Contract *ContractCaller) StringToUint256(opts *bind.CallOpts, attribute string) (*big.Int, error) {
-	var out []interface{}
-	err := _Contract.contract.Call(opts, &out, "stringToUint256", attribute)
-
-	if err != nil {
-		return *new(*big.Int), err
-	}
-
-	out0 := *abi.ConvertType(out[0], new(*big.Int)).(**big.Int)
-
-	return out0, err
-
+// Solidity: function RegisterNymSet(address _seller, (uint256,uint256) _nym, (uint256[2],uint256[2]) _u, (uint256[2],uint256[2]) _s, (uint256,uint256) _kappa, uint256 _vc, uint256[] _rm, uint256 _rt, string[] _attr, bool[] _disclosed, (uint256,uint256) _tag, uint256 _epoch, uint256 _keyVersion, bytes32 _nonce) returns(bool)
+func (_Contract *ContractTransactor) RegisterNymSet(opts *bind.TransactOpts, _seller common.Address, _nym BCSIDG1Point, _u BCSIDG2Point, _s BCSIDG2Point, _kappa BCSIDG1Point, _vc *big.Int, _rm []*big.Int, _rt *big.Int, _attr []string, _disclosed []bool, _tag BCSIDG1Point, _epoch *big.Int, _keyVersion *big.Int, _nonce [32]byte) (*types.Transaction, error) {
+	return _Contract.contract.Transact(opts, "RegisterNymSet", _seller, _nym, _u, _s, _kappa, _vc, _rm, _rt, _attr, _disclosed, _tag, _epoch, _keyVersion, _nonce)
 }
 
-// StringToUint256 is a free data retrieval call binding the contract method 0xac5d3723.
+// RegisterNymSet is a paid mutator transaction binding the contract method 0xff922e8a.
 //
-// Solidity: function stringToUint256(string attribute) pure returns(uint256)
-func (_Contract *ContractSession) StringToUint256(attribute string) (*big.Int, error) {
-	return _Contract.Contract.StringToUint256(&_Contract.CallOpts, attribute)
+// Solidity: function RegisterNymSet(address _seller, (uint256,uint256) _nym, (uint256[2],uint256[2]) _u, (uint256[2],uint256[2]) _s, (uint256,uint256) _kappa, uint256 _vc, uint256[] _rm, uint256 _rt, string[] _attr, bool[] _disclosed, (uint256,uint256) _tag, uint256 _epoch, uint256 _keyVersion, bytes32 _nonce) returns(bool)
+func (_Contract *ContractSession) RegisterNymSet(_seller common.Address, _nym BCSIDG1Point, _u BCSIDG2Point, _s BCSIDG2Point, _kappa BCSIDG1Point, _vc *big.Int, _rm []*big.Int, _rt *big.Int, _attr []string, _disclosed []bool, _tag BCSIDG1Point, _epoch *big.Int, _keyVersion *big.Int, _nonce [32]byte) (*types.Transaction, error) {
+	return _Contract.Contract.RegisterNymSet(&_Contract.TransactOpts, _seller, _nym, _u, _s, _kappa, _vc, _rm, _rt, _attr, _disclosed, _tag, _epoch, _keyVersion, _nonce)
 }
 
-// StringToUint256 is a free data retrieval call binding the contract method 0xac5d3723.
+// RegisterNymSet is a paid mutator transaction binding the contract method 0xff922e8a.
 //
-// Solidity: function stringToUint256(string attribute) pure returns(uint256)
-func (_Contract *ContractCallerSession) StringToUint256(attribute string) (*big.Int, error) {
-	return _Contract.Contract.StringToUint256(&_Contract.CallOpts, attribute)
+// Solidity: function RegisterNymSet(address _seller, (uint256,uint256) _nym, (uint256[2],uint256[2]) _u, (uint256[2],uint256[2]) _s, (uint256,uint256) _kappa, uint256 _vc, uint256[] _rm, uint256 _rt, string[] _attr, bool[] _disclosed, (uint256,uint256) _tag, uint256 _epoch, uint256 _keyVersion, bytes32 _nonce) returns(bool)
+func (_Contract *ContractTransactorSession) RegisterNymSet(_seller common.Address, _nym BCSIDG1Point, _u BCSIDG2Point, _s BCSIDG2Point, _kappa BCSIDG1Point, _vc *big.Int, _rm []*big.Int, _rt *big.Int, _attr []string, _disclosed []bool, _tag BCSIDG1Point, _epoch *big.Int, _keyVersion *big.Int, _nonce [32]byte) (*types.Transaction, error) {
+	return _Contract.Contract.RegisterNymSet(&_Contract.TransactOpts, _seller, _nym, _u, _s, _kappa, _vc, _rm, _rt, _attr, _disclosed, _tag, _epoch, _keyVersion, _nonce)
 }
 
-// AddMapping is a paid mutator transaction binding the contract method 0x1872c257.
+// RegisterSIDSet is a paid mutator transaction binding the contract method 0x2ea8ac7b.
 //
-// Solidity: function AddMapping((uint256,uint256) pk, string attribute) returns()
-func (_Contract *ContractTransactor) AddMapping(opts *bind.TransactOpts, pk BCSIDG1Point, attribute string) (*types.Transaction, error) {
-	return _Contract.contract.Transact(opts, "AddMapping", pk, attribute)
+// Solidity: function RegisterSIDSet((uint256,uint256) _pk1, (uint256,uint256) _a1, uint256 _c, uint256 _z, (uint256[2],uint256[2])[] _u, (uint256[2],uint256[2])[] _s, (uint256,uint256)[] _kappa, uint256[] _vc, uint256[] _rm, uint256[] _rt, string[] _attr, bool[] _disclosed, bytes32[] _issuers, uint256[] _keyVersions, (uint256,uint256) _tag, uint256 _epoch) returns(bool)
+func (_Contract *ContractTransactor) RegisterSIDSet(opts *bind.TransactOpts, _pk1 BCSIDG1Point, _a1 BCSIDG1Point, _c *big.Int, _z *big.Int, _u []BCSIDG2Point, _s []BCSIDG2Point, _kappa []BCSIDG1Point, _vc []*big.Int, _rm []*big.Int, _rt []*big.Int, _attr []string, _disclosed []bool, _issuers [][32]byte, _keyVersions []*big.Int, _tag BCSIDG1Point, _epoch *big.Int) (*types.Transaction, error) {
+	return _Contract.contract.Transact(opts, "RegisterSIDSet", _pk1, _a1, _c, _z, _u, _s, _kappa, _vc, _rm, _rt, _attr, _disclosed, _issuers, _keyVersions, _tag, _epoch)
 }
 
-// AddMapping is a paid mutator transaction binding the contract method 0x1872c257.
+// RegisterSIDSet is a paid mutator transaction binding the contract method 0x2ea8ac7b.
 //
-// Solidity: function AddMapping((uint256,uint256) pk, string attribute) returns()
-func (_Contract *ContractSession) AddMapping(pk BCSIDG1Point, attribute string) (*types.Transaction, error) {
-	return _Contract.Contract.AddMapping(&_Contract.TransactOpts, pk, attribute)
+// Solidity: function RegisterSIDSet((uint256,uint256) _pk1, (uint256,uint256) _a1, uint256 _c, uint256 _z, (uint256[2],uint256[2])[] _u, (uint256[2],uint256[2])[] _s, (uint256,uint256)[] _kappa, uint256[] _vc, uint256[] _rm, uint256[] _rt, string[] _attr, bool[] _disclosed, bytes32[] _issuers, uint256[] _keyVersions, (uint256,uint256) _tag, uint256 _epoch) returns(bool)
+func (_Contract *ContractSession) RegisterSIDSet(_pk1 BCSIDG1Point, _a1 BCSIDG1Point, _c *big.Int, _z *big.Int, _u []BCSIDG2Point, _s []BCSIDG2Point, _kappa []BCSIDG1Point, _vc []*big.Int, _rm []*big.Int, _rt []*big.Int, _attr []string, _disclosed []bool, _issuers [][32]byte, _keyVersions []*big.Int, _tag BCSIDG1Point, _epoch *big.Int) (*types.Transaction, error) {
+	return _Contract.Contract.RegisterSIDSet(&_Contract.TransactOpts, _pk1, _a1, _c, _z, _u, _s, _kappa, _vc, _rm, _rt, _attr, _disclosed, _issuers, _keyVersions, _tag, _epoch)
 }
 
-// AddMapping is a paid mutator transaction binding the contract method 0x1872c257.
+// RegisterSIDSet is a paid mutator transaction binding the contract method 0x2ea8ac7b.
 //
-// Solidity: function AddMapping((uint256,uint256) pk, string attribute) returns()
-func (_Contract *ContractTransactorSession) AddMapping(pk BCSIDG1Point, attribute string) (*types.Transaction, error) {
-	return _Contract.Contract.AddMapping(&_Contract.TransactOpts, pk, attribute)
+// Solidity: function RegisterSIDSet((uint256,uint256) _pk1, (uint256,uint256) _a1, uint256 _c, uint256 _z, (uint256[2],uint256[2])[] _u, (uint256[2],uint256[2])[] _s, (uint256,uint256)[] _kappa, uint256[] _vc, uint256[] _rm, uint256[] _rt, string[] _attr, bool[] _disclosed, bytes32[] _issuers, uint256[] _keyVersions, (uint256,uint256) _tag, uint256 _epoch) returns(bool)
+func (_Contract *ContractTransactorSession) RegisterSIDSet(_pk1 BCSIDG1Point, _a1 BCSIDG1Point, _c *big.Int, _z *big.Int, _u []BCSIDG2Point, _s []BCSIDG2Point, _kappa []BCSIDG1Point, _vc []*big.Int, _rm []*big.Int, _rt []*big.Int, _attr []string, _disclosed []bool, _issuers [][32]byte, _keyVersions []*big.Int, _tag BCSIDG1Point, _epoch *big.Int) (*types.Transaction, error) {
+	return _Contract.Contract.RegisterSIDSet(&_Contract.TransactOpts, _pk1, _a1, _c, _z, _u, _s, _kappa, _vc, _rm, _rt, _attr, _disclosed, _issuers, _keyVersions, _tag, _epoch)
 }
 
-// CreateLogisticsOrder is a paid mutator transaction binding the contract method 0xfedbe99d.
+// RevokeTags is a paid mutator transaction binding the contract method 0xffc993ab.
 //
-// Solidity: function CreateLogisticsOrder(address _sellerAddr, address _buyerAddr, string _orderID, uint256 _code, (uint256,uint256) _SN) payable returns()
-func (_Contract *ContractTransactor) CreateLogisticsOrder(opts *bind.TransactOpts, _sellerAddr common.Address, _buyerAddr common.Address, _orderID string, _code *big.Int, _SN BCSIDG1Point) (*types.Transaction, error) {
-	return _Contract.contract.Transact(opts, "CreateLogisticsOrder", _sellerAddr, _buyerAddr, _orderID, _code, _SN)
+// Solidity: function RevokeTags((uint256,uint256)[] _tags) returns()
+func (_Contract *ContractTransactor) RevokeTags(opts *bind.TransactOpts, _tags []BCSIDG1Point) (*types.Transaction, error) {
+	return _Contract.contract.Transact(opts, "RevokeTags", _tags)
 }
 
-// CreateLogisticsOrder is a paid mutator transaction binding the contract method 0xfedbe99d.
+// RevokeTags is a paid mutator transaction binding the contract method 0xffc993ab.
 //
-// Solidity: function CreateLogisticsOrder(address _sellerAddr, address _buyerAddr, string _orderID, uint256 _code, (uint256,uint256) _SN) payable returns()
-func (_Contract *ContractSession) CreateLogisticsOrder(_sellerAddr common.Address, _buyerAddr common.Address, _orderID string, _code *big.Int, _SN BCSIDG1Point) (*types.Transaction, error) {
-	return _Contract.Contract.CreateLogisticsOrder(&_Contract.TransactOpts, _sellerAddr, _buyerAddr, _orderID, _code, _SN)
+// Solidity: function RevokeTags((uint256,uint256)[] _tags) returns()
+func (_Contract *ContractSession) RevokeTags(_tags []BCSIDG1Point) (*types.Transaction, error) {
+	return _Contract.Contract.RevokeTags(&_Contract.TransactOpts, _tags)
 }
 
-// CreateLogisticsOrder is a paid mutator transaction binding the contract method 0xfedbe99d.
+// RevokeTags is a paid mutator transaction binding the contract method 0xffc993ab.
 //
-// Solidity: function CreateLogisticsOrder(address _sellerAddr, address _buyerAddr, string _orderID, uint256 _code, (uint256,uint256) _SN) payable returns()
-func (_Contract *ContractTransactorSession) CreateLogisticsOrder(_sellerAddr common.Address, _buyerAddr common.Address, _orderID string, _code *big.Int, _SN BCSIDG1Point) (*types.Transaction, error) {
-	return _Contract.Contract.CreateLogisticsOrder(&_Contract.TransactOpts, _sellerAddr, _buyerAddr, _orderID, _code, _SN)
+// Solidity: function RevokeTags((uint256,uint256)[] _tags) returns()
+func (_Contract *ContractTransactorSession) RevokeTags(_tags []BCSIDG1Point) (*types.Transaction, error) {
+	return _Contract.Contract.RevokeTags(&_Contract.TransactOpts, _tags)
 }
 
-// DLVerify is a paid mutator transaction binding the contract method 0x4ea85bd1.
+// RotateIssuerKey is a paid mutator transaction binding the contract method 0xa65243f2.
 //
-// Solidity: function DLVerify((uint256,uint256) g1, (uint256,uint256) y1, (uint256,uint256) a1, uint256 c, uint256 z) payable returns(bool)
-func (_Contract *ContractTransactor) DLVerify(opts *bind.TransactOpts, g1 BCSIDG1Point, y1 BCSIDG1Point, a1 BCSIDG1Point, c *big.Int, z *big.Int) (*types.Transaction, error) {
-	return _Contract.contract.Transact(opts, "DLVerify", g1, y1, a1, c, z)
+// Solidity: function RotateIssuerKey((uint256,uint256) _pkx, (uint256,uint256)[] _pky, uint256 _epoch, uint256 _grace) returns()
+func (_Contract *ContractTransactor) RotateIssuerKey(opts *bind.TransactOpts, _pkx BCSIDG1Point, _pky []BCSIDG1Point, _epoch *big.Int, _grace *big.Int) (*types.Transaction, error) {
+	return _Contract.contract.Transact(opts, "RotateIssuerKey", _pkx, _pky, _epoch, _grace)
 }
 
-// DLVerify is a paid mutator transaction binding the contract method 0x4ea85bd1.
+// RotateIssuerKey is a paid mutator transaction binding the contract method 0xa65243f2.
 //
-// Solidity: function DLVerify((uint256,uint256) g1, (uint256,uint256) y1, (uint256,uint256) a1, uint256 c, uint256 z) payable returns(bool)
-func (_Contract *ContractSession) DLVerify(g1 BCSIDG1Point, y1 BCSIDG1Point, a1 BCSIDG1Point, c *big.Int, z *big.Int) (*types.Transaction, error) {
-	return _Contract.Contract.DLVerify(&_Contract.TransactOpts, g1, y1, a1, c, z)
+// Solidity: function RotateIssuerKey((uint256,uint256) _pkx, (uint256,uint256)[] _pky, uint256 _epoch, uint256 _grace) returns()
+func (_Contract *ContractSession) RotateIssuerKey(_pkx BCSIDG1Point, _pky []BCSIDG1Point, _epoch *big.Int, _grace *big.Int) (*types.Transaction, error) {
+	return _Contract.Contract.RotateIssuerKey(&_Contract.TransactOpts, _pkx, _pky, _epoch, _grace)
 }
 
-// DLVerify is a paid mutator transaction binding the contract method 0x4ea85bd1.
+// RotateIssuerKey is a paid mutator transaction binding the contract method 0xa65243f2.
 //
-// Solidity: function DLVerify((uint256,uint256) g1, (uint256,uint256) y1, (uint256,uint256) a1, uint256 c, uint256 z) payable returns(bool)
-func (_Contract *ContractTransactorSession) DLVerify(g1 BCSIDG1Point, y1 BCSIDG1Point, a1 BCSIDG1Point, c *big.Int, z *big.Int) (*types.Transaction, error) {
-	return _Contract.Contract.DLVerify(&_Contract.TransactOpts, g1, y1, a1, c, z)
+// Solidity: function RotateIssuerKey((uint256,uint256) _pkx, (uint256,uint256)[] _pky, uint256 _epoch, uint256 _grace) returns()
+func (_Contract *ContractTransactorSession) RotateIssuerKey(_pkx BCSIDG1Point, _pky []BCSIDG1Point, _epoch *big.Int, _grace *big.Int) (*types.Transaction, error) {
+	return _Contract.Contract.RotateIssuerKey(&_Contract.TransactOpts, _pkx, _pky, _epoch, _grace)
 }
 
-// RegisterSID is a paid mutator transaction binding the contract method 0xb94b5ca2.
+// SetNymIndex is a paid mutator transaction binding the contract method 0xf2e54902.
 //
-// Solidity: function RegisterSID((uint256,uint256) _pk1, (uint256,uint256) _a1, uint256 _c, uint256 _z, (uint256[2],uint256[2]) _u, (uint256[2],uint256[2]) _s, uint256 _m, string _attr) returns(bool)
-func (_Contract *ContractTransactor) RegisterSID(opts *bind.TransactOpts, _pk1 BCSIDG1Point, _a1 BCSIDG1Point, _c *big.Int, _z *big.Int, _u BCSIDG2Point, _s BCSIDG2Point, _m *big.Int, _attr string) (*types.Transaction, error) {
-	return _Contract.contract.Transact(opts, "RegisterSID", _pk1, _a1, _c, _z, _u, _s, _m, _attr)
+// Solidity: function SetNymIndex(uint256 _index) returns()
+func (_Contract *ContractTransactor) SetNymIndex(opts *bind.TransactOpts, _index *big.Int) (*types.Transaction, error) {
+	return _Contract.contract.Transact(opts, "SetNymIndex", _index)
 }
 
-// RegisterSID is a paid mutator transaction binding the contract method 0xb94b5ca2.
+// SetNymIndex is a paid mutator transaction binding the contract method 0xf2e54902.
 //
-// Solidity: function RegisterSID((uint256,uint256) _pk1, (uint256,uint256) _a1, uint256 _c, uint256 _z, (uint256[2],uint256[2]) _u, (uint256[2],uint256[2]) _s, uint256 _m, string _attr) returns(bool)
-func (_Contract *ContractSession) RegisterSID(_pk1 BCSIDG1Point, _a1 BCSIDG1Point, _c *big.Int, _z *big.Int, _u BCSIDG2Point, _s BCSIDG2Point, _m *big.Int, _attr string) (*types.Transaction, error) {
-	return _Contract.Contract.RegisterSID(&_Contract.TransactOpts, _pk1, _a1, _c, _z, _u, _s, _m, _attr)
+// Solidity: function SetNymIndex(uint256 _index) returns()
+func (_Contract *ContractSession) SetNymIndex(_index *big.Int) (*types.Transaction, error) {
+	return _Contract.Contract.SetNymIndex(&_Contract.TransactOpts, _index)
 }
 
-// RegisterSID is a paid mutator transaction binding the contract method 0xb94b5ca2.
+// SetNymIndex is a paid mutator transaction binding the contract method 0xf2e54902.
 //
-// Solidity: function RegisterSID((uint256,uint256) _pk1, (uint256,uint256) _a1, uint256 _c, uint256 _z, (uint256[2],uint256[2]) _u, (uint256[2],uint256[2]) _s, uint256 _m, string _attr) returns(bool)
-func (_Contract *ContractTransactorSession) RegisterSID(_pk1 BCSIDG1Point, _a1 BCSIDG1Point, _c *big.Int, _z *big.Int, _u BCSIDG2Point, _s BCSIDG2Point, _m *big.Int, _attr string) (*types.Transaction, error) {
-	return _Contract.Contract.RegisterSID(&_Contract.TransactOpts, _pk1, _a1, _c, _z, _u, _s, _m, _attr)
+// Solidity: function SetNymIndex(uint256 _index) returns()
+func (_Contract *ContractTransactorSession) SetNymIndex(_index *big.Int) (*types.Transaction, error) {
+	return _Contract.Contract.SetNymIndex(&_Contract.TransactOpts, _index)
 }
 
-// RegisterSIDSet is a paid mutator transaction binding the contract method 0xa8fb7efd.
+// SetValidityEpoch is a paid mutator transaction binding the contract method 0x864a2599.
 //
-// Solidity: function RegisterSIDSet((uint256,uint256) _pk1, (uint256,uint256) _a1, uint256 _c, uint256 _z, (uint256[2],uint256[2])[] _u, (uint256[2],uint256[2])[] _s, string[] _attr) returns(bool)
-func (_Contract *ContractTransactor) RegisterSIDSet(opts *bind.TransactOpts, _pk1 BCSIDG1Point, _a1 BCSIDG1Point, _c *big.Int, _z *big.Int, _u []BCSIDG2Point, _s []BCSIDG2Point, _attr []string) (*types.Transaction, error) {
-	return _Contract.contract.Transact(opts, "RegisterSIDSet", _pk1, _a1, _c, _z, _u, _s, _attr)
+// Solidity: function SetValidityEpoch(uint256 _index, uint256 _epoch) returns()
+func (_Contract *ContractTransactor) SetValidityEpoch(opts *bind.TransactOpts, _index *big.Int, _epoch *big.Int) (*types.Transaction, error) {
+	return _Contract.contract.Transact(opts, "SetValidityEpoch", _index, _epoch)
 }
 
-// RegisterSIDSet is a paid mutator transaction binding the contract method 0xa8fb7efd.
+// SetValidityEpoch is a paid mutator transaction binding the contract method 0x864a2599.
 //
-// Solidity: function RegisterSIDSet((uint256,uint256) _pk1, (uint256,uint256) _a1, uint256 _c, uint256 _z, (uint256[2],uint256[2])[] _u, (uint256[2],uint256[2])[] _s, string[] _attr) returns(bool)
-func (_Contract *ContractSession) RegisterSIDSet(_pk1 BCSIDG1Point, _a1 BCSIDG1Point, _c *big.Int, _z *big.Int, _u []BCSIDG2Point, _s []BCSIDG2Point, _attr []string) (*types.Transaction, error) {
-	return _Contract.Contract.RegisterSIDSet(&_Contract.TransactOpts, _pk1, _a1, _c, _z, _u, _s, _attr)
+// Solidity: function SetValidityEpoch(uint256 _index, uint256 _epoch) returns()
+func (_Contract *ContractSession) SetValidityEpoch(_index *big.Int, _epoch *big.Int) (*types.Transaction, error) {
+	return _Contract.Contract.SetValidityEpoch(&_Contract.TransactOpts, _index, _epoch)
 }
 
-// RegisterSIDSet is a paid mutator transaction binding the contract method 0xa8fb7efd.
+// SetValidityEpoch is a paid mutator transaction binding the contract method 0x864a2599.
 //
-// Solidity: function RegisterSIDSet((uint256,uint256) _pk1, (uint256,uint256) _a1, uint256 _c, uint256 _z, (uint256[2],uint256[2])[] _u, (uint256[2],uint256[2])[] _s, string[] _attr) returns(bool)
-func (_Contract *ContractTransactorSession) RegisterSIDSet(_pk1 BCSIDG1Point, _a1 BCSIDG1Point, _c *big.Int, _z *big.Int, _u []BCSIDG2Point, _s []BCSIDG2Point, _attr []string) (*types.Transaction, error) {
-	return _Contract.Contract.RegisterSIDSet(&_Contract.TransactOpts, _pk1, _a1, _c, _z, _u, _s, _attr)
+// Solidity: function SetValidityEpoch(uint256 _index, uint256 _epoch) returns()
+func (_Contract *ContractTransactorSession) SetValidityEpoch(_index *big.Int, _epoch *big.Int) (*types.Transaction, error) {
+	return _Contract.Contract.SetValidityEpoch(&_Contract.TransactOpts, _index, _epoch)
 }
 
 // UpdateStatus is a paid mutator transaction binding the contract method 0x1db9a94b.
@@ -1046,27 +1147,48 @@ func (_Contract *ContractTransactorSession) UpdateStatus(_orderID string, _siteI
 	return _Contract.Contract.UpdateStatus(&_Contract.TransactOpts, _orderID, _siteID)
 }
 
-// UploadACsParams is a paid mutator transaction binding the contract method 0x633df11f.
+// UploadACsParams is a paid mutator transaction binding the contract method 0x778b9d16.
 //
-// Solidity: function UploadACsParams((uint256,uint256) _g1, (uint256[2],uint256[2]) _g2, (uint256,uint256) _pkx, (uint256,uint256) _pky) returns()
-func (_Contract *ContractTransactor) UploadACsParams(opts *bind.TransactOpts, _g1 BCSIDG1Point, _g2 BCSIDG2Point, _pkx BCSIDG1Point, _pky BCSIDG1Point) (*types.Transaction, error) {
+// Solidity: function UploadACsParams((uint256,uint256) _g1, (uint256[2],uint256[2]) _g2, (uint256,uint256) _pkx, (uint256,uint256)[] _pky) returns()
+func (_Contract *ContractTransactor) UploadACsParams(opts *bind.TransactOpts, _g1 BCSIDG1Point, _g2 BCSIDG2Point, _pkx BCSIDG1Point, _pky []BCSIDG1Point) (*types.Transaction, error) {
 	return _Contract.contract.Transact(opts, "UploadACsParams", _g1, _g2, _pkx, _pky)
 }
 
-// UploadACsParams is a paid mutator transaction binding the contract method 0x633df11f.
+// UploadACsParams is a paid mutator transaction binding the contract method 0x778b9d16.
 //
-// Solidity: function UploadACsParams((uint256,uint256) _g1, (uint256[2],uint256[2]) _g2, (uint256,uint256) _pkx, (uint256,uint256) _pky) returns()
-func (_Contract *ContractSession) UploadACsParams(_g1 BCSIDG1Point, _g2 BCSIDG2Point, _pkx BCSIDG1Point, _pky BCSIDG1Point) (*types.Transaction, error) {
+// Solidity: function UploadACsParams((uint256,uint256) _g1, (uint256[2],uint256[2]) _g2, (uint256,uint256) _pkx, (uint256,uint256)[] _pky) returns()
+func (_Contract *ContractSession) UploadACsParams(_g1 BCSIDG1Point, _g2 BCSIDG2Point, _pkx BCSIDG1Point, _pky []BCSIDG1Point) (*types.Transaction, error) {
 	return _Contract.Contract.UploadACsParams(&_Contract.TransactOpts, _g1, _g2, _pkx, _pky)
 }
 
-// UploadACsParams is a paid mutator transaction binding the contract method 0x633df11f.
+// UploadACsParams is a paid mutator transaction binding the contract method 0x778b9d16.
 //
-// Solidity: function UploadACsParams((uint256,uint256) _g1, (uint256[2],uint256[2]) _g2, (uint256,uint256) _pkx, (uint256,uint256) _pky) returns()
-func (_Contract *ContractTransactorSession) UploadACsParams(_g1 BCSIDG1Point, _g2 BCSIDG2Point, _pkx BCSIDG1Point, _pky BCSIDG1Point) (*types.Transaction, error) {
+// Solidity: function UploadACsParams((uint256,uint256) _g1, (uint256[2],uint256[2]) _g2, (uint256,uint256) _pkx, (uint256,uint256)[] _pky) returns()
+func (_Contract *ContractTransactorSession) UploadACsParams(_g1 BCSIDG1Point, _g2 BCSIDG2Point, _pkx BCSIDG1Point, _pky []BCSIDG1Point) (*types.Transaction, error) {
 	return _Contract.Contract.UploadACsParams(&_Contract.TransactOpts, _g1, _g2, _pkx, _pky)
 }
 
+// UploadRevocationBase is a paid mutator transaction binding the contract method 0x91f0814b.
+//
+// Solidity: function UploadRevocationBase(uint256 _epoch, (uint256,uint256) _base) returns()
+func (_Contract *ContractTransactor) UploadRevocationBase(opts *bind.TransactOpts, _epoch *big.Int, _base BCSIDG1Point) (*types.Transaction, error) {
+	return _Contract.contract.Transact(opts, "UploadRevocationBase", _epoch, _base)
+}
+
+// UploadRevocationBase is a paid mutator transaction binding the contract method 0x91f0814b.
+//
+// Solidity: function UploadRevocationBase(uint256 _epoch, (uint256,uint256) _base) returns()
+func (_Contract *ContractSession) UploadRevocationBase(_epoch *big.Int, _base BCSIDG1Point) (*types.Transaction, error) {
+	return _Contract.Contract.UploadRevocationBase(&_Contract.TransactOpts, _epoch, _base)
+}
+
+// UploadRevocationBase is a paid mutator transaction binding the contract method 0x91f0814b.
+//
+// Solidity: function UploadRevocationBase(uint256 _epoch, (uint256,uint256) _base) returns()
+func (_Contract *ContractTransactorSession) UploadRevocationBase(_epoch *big.Int, _base BCSIDG1Point) (*types.Transaction, error) {
+	return _Contract.Contract.UploadRevocationBase(&_Contract.TransactOpts, _epoch, _base)
+}
+
 // BuyerCancelOrder is a paid mutator transaction binding the contract method 0x99927c35.
 //
 // Solidity: function buyerCancelOrder(address _seller, string _orderID) returns()
@@ -1373,6 +1495,161 @@ func (_Contract *ContractFilterer) ParseBroadcastPubKey(log types.Log) (*Contrac
 	return event, nil
 }
 
+// ContractIssuerKeyRotatedIterator is returned from FilterIssuerKeyRotated and is used to iterate over the raw logs and unpacked data for IssuerKeyRotated events raised by the Contract contract.
+type ContractIssuerKeyRotatedIterator struct {
+	Event *ContractIssuerKeyRotated // Event containing the contract specifics and raw log
+
+	contract *bind.BoundContract // Generic contract to use for unpacking event data
+	event    string              // Event name to use for unpacking event data
+
+	logs chan types.Log        // Log channel receiving the found contract events
+	sub  ethereum.Subscription // Subscription for errors, completion and termination
+	done bool                  // Whether the subscription completed delivering logs
+	fail error                 // Occurred error to stop iteration
+}
+
+// Next advances the iterator to the subsequent event, returning whether there
+// are any more events found. In case of a retrieval or parsing error, false is
+// returned and Error() can be queried for the exact failure.
+func (it *ContractIssuerKeyRotatedIterator) Next() bool {
+	// If the iterator failed, stop iterating
+	if it.fail != nil {
+		return false
+	}
+	// If the iterator completed, deliver directly whatever's available
+	if it.done {
+		select {
+		case log := <-it.logs:
+			it.Event = new(ContractIssuerKeyRotated)
+			if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+				it.fail = err
+				return false
+			}
+			it.Event.Raw = log
+			return true
+
+		default:
+			return false
+		}
+	}
+	// Iterator still in progress, wait for either a data or an error event
+	select {
+	case log := <-it.logs:
+		it.Event = new(ContractIssuerKeyRotated)
+		if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+			it.fail = err
+			return false
+		}
+		it.Event.Raw = log
+		return true
+
+	case err := <-it.sub.Err():
+		it.done = true
+		it.fail = err
+		return it.Next()
+	}
+}
+
+// Error returns any retrieval or parsing error occurred during filtering.
+func (it *ContractIssuerKeyRotatedIterator) Error() error {
+	return it.fail
+}
+
+// Close terminates the iteration process, releasing any pending underlying
+// resources.
+func (it *ContractIssuerKeyRotatedIterator) Close() error {
+	it.sub.Unsubscribe()
+	return nil
+}
+
+// ContractIssuerKeyRotated represents a IssuerKeyRotated event raised by the Contract contract.
+type ContractIssuerKeyRotated struct {
+	Issuer          [32]byte
+	Version         *big.Int
+	Epoch           *big.Int
+	PreviousExpires *big.Int
+	Raw             types.Log // Blockchain specific contextual infos
+}
+
+// FilterIssuerKeyRotated is a free log retrieval operation binding the contract event 0x7e44eaa917fba972be0d94b86a4329906e7d54e153fc075a44d9466e9720a615.
+//
+// Solidity: event IssuerKeyRotated(bytes32 indexed issuer, uint256 indexed version, uint256 epoch, uint256 previousExpires)
+func (_Contract *ContractFilterer) FilterIssuerKeyRotated(opts *bind.FilterOpts, issuer [][32]byte, version []*big.Int) (*ContractIssuerKeyRotatedIterator, error) {
+
+	var issuerRule []interface{}
+	for _, issuerItem := range issuer {
+		issuerRule = append(issuerRule, issuerItem)
+	}
+	var versionRule []interface{}
+	for _, versionItem := range version {
+		versionRule = append(versionRule, versionItem)
+	}
+
+	logs, sub, err := _Contract.contract.FilterLogs(opts, "IssuerKeyRotated", issuerRule, versionRule)
+	if err != nil {
+		return nil, err
+	}
+	return &ContractIssuerKeyRotatedIterator{contract: _Contract.contract, event: "IssuerKeyRotated", logs: logs, sub: sub}, nil
+}
+
+// WatchIssuerKeyRotated is a free log subscription operation binding the contract event 0x7e44eaa917fba972be0d94b86a4329906e7d54e153fc075a44d9466e9720a615.
+//
+// Solidity: event IssuerKeyRotated(bytes32 indexed issuer, uint256 indexed version, uint256 epoch, uint256 previousExpires)
+func (_Contract *ContractFilterer) WatchIssuerKeyRotated(opts *bind.WatchOpts, sink chan<- *ContractIssuerKeyRotated, issuer [][32]byte, version []*big.Int) (event.Subscription, error) {
+
+	var issuerRule []interface{}
+	for _, issuerItem := range issuer {
+		issuerRule = append(issuerRule, issuerItem)
+	}
+	var versionRule []interface{}
+	for _, versionItem := range version {
+		versionRule = append(versionRule, versionItem)
+	}
+
+	logs, sub, err := _Contract.contract.WatchLogs(opts, "IssuerKeyRotated", issuerRule, versionRule)
+	if err != nil {
+		return nil, err
+	}
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case log := <-logs:
+				// New log arrived, parse the event and forward to the user
+				event := new(ContractIssuerKeyRotated)
+				if err := _Contract.contract.UnpackLog(event, "IssuerKeyRotated", log); err != nil {
+					return err
+				}
+				event.Raw = log
+
+				select {
+				case sink <- event:
+				case err := <-sub.Err():
+					return err
+				case <-quit:
+					return nil
+				}
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
+}
+
+// ParseIssuerKeyRotated is a log parse operation binding the contract event 0x7e44eaa917fba972be0d94b86a4329906e7d54e153fc075a44d9466e9720a615.
+//
+// Solidity: event IssuerKeyRotated(bytes32 indexed issuer, uint256 indexed version, uint256 epoch, uint256 previousExpires)
+func (_Contract *ContractFilterer) ParseIssuerKeyRotated(log types.Log) (*ContractIssuerKeyRotated, error) {
+	event := new(ContractIssuerKeyRotated)
+	if err := _Contract.contract.UnpackLog(event, "IssuerKeyRotated", log); err != nil {
+		return nil, err
+	}
+	event.Raw = log
+	return event, nil
+}
+
 // ContractOrderCompletedIterator is returned from FilterOrderCompleted and is used to iterate over the raw logs and unpacked data for OrderCompleted events raised by the Contract contract.
 type ContractOrderCompletedIterator struct {
 	Event *ContractOrderCompleted // Event containing the contract specifics and raw log
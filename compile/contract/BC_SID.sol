@@ -27,7 +27,7 @@ contract BC_SID {
 
 
     // 比较 proof.U 是否为 G2 群的单位元
-    function isG2Zero(G2Point memory point2) public view returns (bool) {
+    function isG2Zero(G2Point memory point2) internal pure returns (bool) {
         // 比较 proof.U 的 X 和 Y 是否都为单位元
         uint256[2] memory G2_ZERO_X = [uint256(0), uint256(0)];
         uint256[2] memory G2_ZERO_Y = [uint256(0), uint256(0)];
@@ -84,9 +84,45 @@ contract BC_SID {
 
   
 
+    // Fiat–Shamir transcripts, the same encoding as AC.Transcript: frames
+    // uint32 len(label) || label || uint32 len(data) || data, challenges are
+    // sha256 of the transcript and the frame (label, "") reduced mod GEN_ORDER.
+    function tFrame(bytes memory _t, string memory _label, bytes memory _data) internal pure returns (bytes memory) {
+        return abi.encodePacked(_t, uint32(bytes(_label).length), _label, uint32(_data.length), _data);
+    }
+
+    function newTranscript(string memory _protocol) internal pure returns (bytes memory) {
+        return tFrame("", "Obfushop/AC/transcript/v1", bytes(_protocol));
+    }
+
+    function tChallenge(bytes memory _t, string memory _label) internal pure returns (uint256) {
+        return uint256(sha256(tFrame(_t, _label, ""))) % GEN_ORDER;
+    }
+
+    function g1Bytes(G1Point memory p) internal pure returns (bytes memory) {
+        return abi.encodePacked(p.X, p.Y);
+    }
+
+    function g2Bytes(G2Point memory p) internal pure returns (bytes memory) {
+        return abi.encodePacked(p.X[0], p.X[1], p.Y[0], p.Y[1]);
+    }
+
+    // Checks AC.DLProof: a1 == g1^z * y1^c with c rebuilt from the "Obfushop/AC/dl" transcript.
     function DLVerify(G1Point memory g1, G1Point memory y1, G1Point memory a1, 
                        uint256 c, uint256 z) public payable returns (bool)
     {
+        bytes memory t = newTranscript("Obfushop/AC/dl");
+        t = tFrame(t, "statement", "Obfushop/AC/dl");
+        t = tFrame(t, "witness", "x");
+        t = tFrame(t, "relation", "xG");
+        t = tFrame(t, "lhs", g1Bytes(y1));
+        t = tFrame(t, "base", g1Bytes(g1));
+        t = tFrame(t, "term", abi.encodePacked(uint32(0)));
+        t = tFrame(t, "commitment", g1Bytes(a1));
+        if (c != tChallenge(t, "c") || z >= GEN_ORDER)
+        {
+            return false;
+        }
         G1Point memory g1G = g1mul(g1, z);
         G1Point memory y1G = g1mul(y1, c);
         G1Point memory pt1 =  g1add(g1G, y1G);
@@ -97,6 +133,66 @@ contract BC_SID {
         return true;
     }
 
+    // Checks AC.KeyBinding: _pk1 = G1^sk and _u = _r^sk for the same sk, so a presentation
+    // is bound to the buyer key without a G2 copy of it. The G2 commitment _a2 = _r^z * _u^c
+    // is checked as e(-G1, _a2) * e(G1^z, _r) * e(G1^c, _u) == 1.
+    function VerifyKeyBinding(G1Point memory _pk1, G2Point memory _u, G2Point memory _r, uint256 _c, uint256 _z,
+        G1Point memory _a1, G2Point memory _a2) public view returns (bool)
+    {
+        if (isG2Zero(_u) || isG2Zero(_r) || _z >= GEN_ORDER) {
+            return false;
+        }
+        bytes memory t = newTranscript("Obfushop/AC/key-binding");
+        t = tFrame(t, "statement", "Obfushop/AC/key-binding");
+        t = tFrame(t, "witness", "sk");
+        t = tFrame(t, "relation", "pk");
+        t = tFrame(t, "lhs", g1Bytes(_pk1));
+        t = tFrame(t, "base", g1Bytes(G1));
+        t = tFrame(t, "term", abi.encodePacked(uint32(0)));
+        t = tFrame(t, "commitment", g1Bytes(_a1));
+        t = tFrame(t, "relation", "U");
+        t = tFrame(t, "lhs", g2Bytes(_u));
+        t = tFrame(t, "base", g2Bytes(_r));
+        t = tFrame(t, "term", abi.encodePacked(uint32(0)));
+        t = tFrame(t, "commitment", g2Bytes(_a2));
+        if (_c != tChallenge(t, "c")) {
+            return false;
+        }
+        G1Point memory a1 = g1add(g1mul(G1, _z), g1mul(_pk1, _c));
+        if (a1.X != _a1.X || a1.Y != _a1.Y) {
+            return false;
+        }
+        return pairingProd3(g1neg(G1), _a2, g1mul(G1, _z), _r, g1mul(G1, _c), _u);
+    }
+
+    function expMod(uint256 _base, uint256 _exp, uint256 _mod) internal view returns (uint256) {
+        uint256[6] memory input = [uint256(32), 32, 32, _base, _exp, _mod];
+        uint256[1] memory out;
+        bool success;
+        assembly("memory-safe") {
+            success := staticcall(sub(gas(), 2000), 5, input, 0xc0, out, 0x20)
+        }
+        require(success);
+        return out[0];
+    }
+
+    /// same as bn256.HashG1: try-and-increment from sha256(_m), y = (x^3+3)^((p+1)/4)
+    function hashToG1(bytes memory _m) internal view returns (G1Point memory) {
+        bytes32 h = sha256(_m);
+        while (uint256(h) >= FIELD_ORDER) {
+            h = sha256(abi.encodePacked(h));
+        }
+        uint256 x = uint256(h);
+        while (true) {
+            uint256 rhs = addmod(mulmod(mulmod(x, x, FIELD_ORDER), x, FIELD_ORDER), CURVE_B, FIELD_ORDER);
+            uint256 y = expMod(rhs, CURVE_A, FIELD_ORDER);
+            if (mulmod(y, y, FIELD_ORDER) == rhs) {
+                return G1Point(x, y);
+            }
+            x = addmod(x, 1, FIELD_ORDER);
+        }
+    }
+
     /// return the result of computing the pairing check
     /// e(p1[0], p2[0]) *  .... * e(p1[n], p2[n]) == 1
     /// For example pairing([P1(), P1().negate()], [P2(), P2()]) should
@@ -137,83 +233,503 @@ contract BC_SID {
         return pairing(p1, p2);
     }
 
+    /// Convenience method for a pairing check for three pairs.
+    function pairingProd3(G1Point memory a1, G2Point memory a2, G1Point memory b1, G2Point memory b2,
+        G1Point memory c1, G2Point memory c2) view internal returns (bool) {
+        G1Point[] memory p1 = new G1Point[](3);
+        G2Point[] memory p2 = new G2Point[](3);
+        p1[0] = a1;
+        p1[1] = b1;
+        p1[2] = c1;
+        p2[0] = a2;
+        p2[1] = b2;
+        p2[2] = c2;
+        return pairing(p1, p2);
+    }
+
 
     //映射相关运算
     // 将G1Point转换为映射使用的key
-    function GetPointKey(G1Point memory pk) public pure returns (bytes32) {
+    function GetPointKey(G1Point memory pk) internal pure returns (bytes32) {
         return keccak256(abi.encodePacked(pk.X, pk.Y));
     }
-    // 添加映射项
-    function AddMapping(G1Point memory pk, string memory attribute) public {
-        bytes32 key = GetPointKey(pk);
-        SID[key]=attribute;
-    }
-    // 获取对应字符串数组
-    function GetMapping(G1Point memory pk) public view returns (string memory) {
-        return SID[GetPointKey(pk)];
-    }
-
     // Function to convert a string to uint256 using keccak256 hash
-    function stringToUint256(string memory attribute) public pure returns (uint256) {
+    function stringToUint256(string memory attribute) internal pure returns (uint256) {
         // 使用 sha256 计算哈希
         bytes32 hash = sha256(abi.encodePacked(attribute));
         // 将 bytes32 转换为 uint256 并返回
         return uint256(hash);
     }
 
-    mapping(bytes32 => string) public SID;
-    mapping(bytes32 => string[])public SIDSet;
+    mapping(bytes32 => string[]) SIDSet;
     G1Point G1;
     G2Point G2;
-    G1Point[2] IssuerKey;
+    address Issuer;
+
+    // Issuer key history: version v (from 1) is (KeyX[v], KeyY[v]) of issuer KeyIssuer[v], in use from
+    // epoch KeyEpoch[v]. The current version of an issuer never expires; a superseded one is accepted
+    // until KeyExpires[v]. Versions are numbered across all issuers.
+    uint256 KeyVersion;
+    mapping(uint256 => G1Point) KeyX;
+    mapping(uint256 => G1Point[]) KeyY;
+    mapping(uint256 => uint256) KeyEpoch;
+    mapping(uint256 => uint256) KeyExpires;
+    mapping(uint256 => bytes32) KeyIssuer;
+
+    // Issuers by ID. The deployer is the root issuer, ID 0: it registers the other
+    // issuers and runs revocation, validity and pseudonyms, which apply to its credentials only.
+    mapping(bytes32 => address) IssuerAddr;
+    mapping(address => bytes32) IssuerIDOf;
+    mapping(bytes32 => uint256) IssuerKeyOf;
+
+    // key version behind each claim of SIDSet
+    mapping(bytes32 => uint256[]) SIDClaimKey;
+
+    event IssuerKeyRotated(bytes32 indexed issuer, uint256 indexed version, uint256 epoch, uint256 previousExpires);
+
+    // Revocation: the issuer publishes the tag base of the current epoch and the
+    // tags base^handle of revoked credentials; each identity keeps the tag it registered with.
+    // Tags change with the epoch, so an identity must register again in every revocation epoch.
+    G1Point RevocationBase;
+    uint256 RevocationEpoch;
+    mapping(bytes32 => bool) RevokedTags;
+    mapping(bytes32 => bytes32) SIDTag;
+    mapping(bytes32 => uint256) SIDRevocationEpoch;
+
+    // Validity: attribute ValidityIndex of every credential is the epoch it was issued for,
+    // and identities only hold their claims during the epoch they registered in.
+    bool ValidityEnabled;
+    uint256 ValidityIndex;
+    uint256 ValidityEpoch;
+    mapping(bytes32 => uint256) SIDEpoch;
+
+    // Pseudonyms: attribute NymIndex of every credential is the buyer's secret sk, and a buyer
+    // registers nym = ScopeBase(seller)^sk for each seller instead of one long-lived pkB.
+    bool NymEnabled;
+    uint256 NymIndex;
+    mapping(bytes32 => address) NymSeller;
+
+    // Nonces of the key-free presentations registered so far; each is accepted once.
+    mapping(bytes32 => bool) SpentNonces;
+
+    // The deployer is the root issuer, so nobody can upload parameters ahead of it.
+    constructor() {
+        Issuer=msg.sender;
+    }
 
-    //Upload issuer's key
-    function UploadACsParams(G1Point memory _g1,G2Point memory _g2,G1Point memory _pkx, G1Point memory _pky) public {
+    //Upload the parameters and the first issuer key (X, Y_1..Y_q). This can only be done once, by the
+    //root issuer; later keys are registered with RotateIssuerKey.
+    function UploadACsParams(G1Point memory _g1,G2Point memory _g2,G1Point memory _pkx, G1Point[] memory _pky) public {
+        require(msg.sender==Issuer, "Only issuer");
+        require(KeyVersion==0, "Already set up");
+        IssuerAddr[0]=msg.sender;
         G1=_g1;
         G2=_g2;
-        IssuerKey[0]=_pkx;
-        IssuerKey[1]=_pky;
+        addIssuerKey(0, _pkx, _pky, 0);
+        emit IssuerKeyRotated(0, KeyVersion, 0, 0);
+    }
+
+    // Register another issuer, e.g. a bank for KYC claims, with its first key. _addr rotates its keys.
+    function RegisterIssuer(bytes32 _id, address _addr, G1Point memory _pkx, G1Point[] memory _pky, uint256 _epoch) public {
+        require(msg.sender==Issuer, "Only issuer");
+        require(_id!=0&&IssuerKeyOf[_id]==0, "Issuer exists");
+        require(_addr!=address(0)&&IssuerAddr[IssuerIDOf[_addr]]!=_addr, "Address in use");
+        require(_pky.length>0, "No attributes");
+        IssuerAddr[_id]=_addr;
+        IssuerIDOf[_addr]=_id;
+        addIssuerKey(_id, _pkx, _pky, _epoch);
+        emit IssuerKeyRotated(_id, KeyVersion, _epoch, 0);
+    }
+
+    // Make (_pkx,_pky) the caller's current key from epoch _epoch on. Credentials under the previous key
+    // can still be registered, and claims registered with it still hold, for _grace more seconds.
+    function RotateIssuerKey(G1Point memory _pkx, G1Point[] memory _pky, uint256 _epoch, uint256 _grace) public {
+        bytes32 id = IssuerIDOf[msg.sender];
+        require(KeyVersion!=0&&msg.sender==IssuerAddr[id], "Only issuer");
+        uint256 current = IssuerKeyOf[id];
+        require(_pky.length==KeyY[current].length, "Attribute count changed");
+        require(_epoch>=KeyEpoch[current], "Epoch goes backwards");
+        KeyExpires[current]=block.timestamp+_grace;
+        addIssuerKey(id, _pkx, _pky, _epoch);
+        emit IssuerKeyRotated(id, KeyVersion, _epoch, KeyExpires[current]);
+    }
+
+    function addIssuerKey(bytes32 _id, G1Point memory _pkx, G1Point[] memory _pky, uint256 _epoch) internal {
+        KeyVersion++;
+        KeyX[KeyVersion]=_pkx;
+        for (uint i=0;i<_pky.length;i++){
+            KeyY[KeyVersion].push(_pky[i]);
+        }
+        KeyEpoch[KeyVersion]=_epoch;
+        KeyIssuer[KeyVersion]=_id;
+        IssuerKeyOf[_id]=KeyVersion;
+    }
+
+    function keyValid(uint256 _version) internal view returns (bool) {
+        return _version!=0&&_version<=KeyVersion&&(_version==IssuerKeyOf[KeyIssuer[_version]]||block.timestamp<KeyExpires[_version]);
+    }
+
+    // Current key version of the root issuer.
+    function CurrentKeyVersion() public view returns (uint256) {
+        return IssuerKeyOf[0];
+    }
+
+    // Current key version of issuer _id, 0 if there is no such issuer.
+    function IssuerKeyVersion(bytes32 _id) public view returns (uint256) {
+        return IssuerKeyOf[_id];
+    }
+
+    function KeyIssuerOf(uint256 _version) public view returns (bytes32) {
+        require(_version!=0&&_version<=KeyVersion, "Unknown key version");
+        return KeyIssuer[_version];
+    }
+
+    function IsKeyValid(uint256 _version) public view returns (bool) {
+        return keyValid(_version);
+    }
+
+    // Key _version with the epoch it was registered for and its expiry (0 while it is current).
+    function GetIssuerKey(uint256 _version) public view returns (G1Point memory, G1Point[] memory, uint256, uint256) {
+        require(_version!=0&&_version<=KeyVersion, "Unknown key version");
+        return (KeyX[_version], KeyY[_version], KeyEpoch[_version], KeyExpires[_version]);
+    }
+
+    // sha256(X || Y_1 || .. || Y_q) of key _version, the same as IssuerKey.Fingerprint in crypto/AC
+    function IssuerKeyFingerprint(uint256 _version) public view returns (bytes32) {
+        require(_version!=0&&_version<=KeyVersion, "Unknown key version");
+        bytes memory packed = abi.encodePacked(KeyX[_version].X, KeyX[_version].Y);
+        for (uint i=0;i<KeyY[_version].length;i++){
+            packed = abi.encodePacked(packed, KeyY[_version][i].X, KeyY[_version][i].Y);
+        }
+        return sha256(packed);
+    }
+
+    // X * Y_1^m_1 * ... * Y_q^m_q for key _version
+    function aggregateIssuerKey(uint256 _version, uint256[] memory _m) internal view returns (G1Point memory agg) {
+        agg = KeyX[_version];
+        for (uint i=0;i<_m.length;i++){
+            agg = g1add(agg, g1mul(KeyY[_version][i], _m[i]));
+        }
+    }
+
+    // Start a revocation epoch; the last attribute of every credential registered from now on is its revocation handle.
+    // Identities registered in earlier epochs lose their claims until they register with a tag of this one.
+    function UploadRevocationBase(uint256 _epoch, G1Point memory _base) public {
+        require(msg.sender==Issuer, "Only issuer");
+        RevocationEpoch=_epoch;
+        RevocationBase=_base;
+    }
+
+    function RevokeTags(G1Point[] memory _tags) public {
+        require(msg.sender==Issuer, "Only issuer");
+        for (uint i=0;i<_tags.length;i++){
+            RevokedTags[GetPointKey(_tags[i])]=true;
+        }
+    }
+
+    // Move to a new validity epoch; claims registered in earlier epochs lapse.
+    function SetValidityEpoch(uint256 _index, uint256 _epoch) public {
+        require(msg.sender==Issuer, "Only issuer");
+        require(_index<KeyY[IssuerKeyOf[0]].length, "Index out of range");
+        ValidityEnabled=true;
+        ValidityIndex=_index;
+        ValidityEpoch=_epoch;
+    }
+
+    function SetNymIndex(uint256 _index) public {
+        require(msg.sender==Issuer, "Only issuer");
+        require(_index<KeyY[IssuerKeyOf[0]].length, "Index out of range");
+        NymEnabled=true;
+        NymIndex=_index;
+    }
+
+    function ScopeBase(address _seller) public view returns (G1Point memory) {
+        return hashToG1(abi.encodePacked("Obfushop/AC/scope/", _seller));
+    }
+
+    function GetValidityEpoch() public view returns (uint256) {
+        return ValidityEpoch;
+    }
+
+    function GetRevocationEpoch() public view returns (uint256) {
+        return RevocationEpoch;
+    }
+
+    function IsRevoked(G1Point memory pk) public view returns (bool) {
+        return RevokedTags[SIDTag[GetPointKey(pk)]];
+    }
+
+    function revocationEnabled() internal view returns (bool) {
+        return RevocationBase.X!=0||RevocationBase.Y!=0;
+    }
+
+    // One credential of a presentation: (u, s, kappa) with its π_v (c, rm, rt) under key version,
+    // covering the attributes start .. start+disclosed.length-1 of the presented vector.
+    struct Part {
+        uint256 version;
+        G2Point u;
+        G2Point s;
+        G1Point kappa;
+        uint256 c;
+        uint256[] rm;
+        uint256 rt;
+        bool[] disclosed;
+        uint256 start;
     }
 
-    function RegisterSID(G1Point memory _pk1,G1Point memory _a1, uint256 _c, uint256 _z, G2Point memory _u, G2Point memory _s, uint256 _m,string memory _attr) public returns (bool) 
+    // π_v: knowledge of the hidden m_i and t in kappa = g1^t * ∏_{hidden} Y_i^m_i, bound to (_u,_s).
+    // With revocation enabled it also shows _tag = RevocationBase^m_q for the (hidden) last attribute
+    // of root issuer credentials. Y_i are those of issuer key _keyVersion.
+    function VerifyPiV(G1Point memory _kappa, G2Point memory _u, G2Point memory _s, bool[] memory _disclosed,
+                       uint256 _c, uint256[] memory _rm, uint256 _rt, G1Point memory _tag, uint256 _keyVersion) public view returns (bool)
     {
-        if(isG2Zero(_u)||_m!=stringToUint256(_attr))
-        {
+        if(!keyValid(_keyVersion)||_disclosed.length>KeyY[_keyVersion].length){
             return false;
         }
-        if(!DLVerify(G1, _pk1 , _a1, _c, _z)){
+        Part memory part = Part(_keyVersion, _u, _s, _kappa, _c, _rm, _rt, _disclosed, 0);
+        return verifyPiVLinks(part, _tag, new G1Point[](0), new G1Point[](0), new uint256[](0), "");
+    }
+
+    // The bind data of AC.Session{Nonce: _nonce, Context: _context}.
+    function sessionBind(bytes32 _nonce, bytes memory _context) internal pure returns (bytes memory) {
+        return abi.encodePacked("Obfushop/AC/session", uint32(32), _nonce, uint32(_context.length), _context);
+    }
+
+    // π_v with extra links _p[k] = _base[k]^m_{_idx[k]} on hidden attributes, after the revocation tag,
+    // and with _bind hashed into the challenge.
+    function verifyPiVLinks(Part memory _part, G1Point memory _tag,
+        G1Point[] memory _p, G1Point[] memory _base, uint256[] memory _idx, bytes memory _bind) internal view returns (bool)
+    {
+        bool[] memory disclosed = _part.disclosed;
+        uint256[] memory pos = new uint256[](disclosed.length);
+        G1Point memory aw = g1add(g1mul(_part.kappa, _part.c), g1mul(G1, _part.rt));
+        // the transcript of AC.piVChallenge
+        bytes memory t = tFrame(newTranscript("Obfushop/AC/pi_v"), "g1", g1Bytes(G1));
+        uint j=0;
+        for (uint i=0;i<disclosed.length;i++){
+            if(!disclosed[i]){
+                if(j>=_part.rm.length){
+                    return false;
+                }
+                aw = g1add(aw, g1mul(KeyY[_part.version][i], _part.rm[j]));
+                t = tFrame(t, "y", g1Bytes(KeyY[_part.version][i]));
+                pos[i]=j;
+                j++;
+            }
+        }
+        if(j!=_part.rm.length){
             return false;
         }
-        if(pairingProd2(g1add(IssuerKey[0], g1mul(IssuerKey[1], _m)), _u, g1neg(_pk1), _s)){
-            SID[GetPointKey(_pk1)]=_attr;
+        t = tFrame(tFrame(t, "kappa", g1Bytes(_part.kappa)), "A", g1Bytes(aw));
+        G1Point memory bw;
+        if(KeyIssuer[_part.version]==0&&revocationEnabled()){
+            if(j==0||disclosed[disclosed.length-1]){
+                return false;
+            }
+            bw = g1add(g1mul(_tag, _part.c), g1mul(RevocationBase, _part.rm[j-1]));
+            t = tFrame(tFrame(tFrame(t, "P", g1Bytes(_tag)), "base", g1Bytes(RevocationBase)), "B", g1Bytes(bw));
+        }
+        for (uint k=0;k<_p.length;k++){
+            if(_idx[k]>=disclosed.length||disclosed[_idx[k]]){
+                return false;
+            }
+            bw = g1add(g1mul(_p[k], _part.c), g1mul(_base[k], _part.rm[pos[_idx[k]]]));
+            t = tFrame(tFrame(tFrame(t, "P", g1Bytes(_p[k])), "base", g1Bytes(_base[k])), "B", g1Bytes(bw));
         }
-        return true;
+        t = tFrame(tFrame(tFrame(t, "u", g2Bytes(_part.u)), "s", g2Bytes(_part.s)), "bind", _bind);
+        return tChallenge(t, "c") == _part.c;
     }
 
+    // Splits a presentation into its credentials: each run of equal _issuers is one credential, in order,
+    // checked under key version _keyVersions[k] of that issuer with (_u[k], _s[k], _kappa[k]) and
+    // π_v (_vc[k], its slice of _rm, _rt[k]). Returns no parts if the arrays do not fit together.
+    function splitParts(G2Point[] memory _u, G2Point[] memory _s, G1Point[] memory _kappa, uint256[] memory _vc,
+        uint256[] memory _rm, uint256[] memory _rt, bool[] memory _disclosed, bytes32[] memory _issuers,
+        uint256[] memory _keyVersions) internal view returns (Part[] memory)
+    {
+        uint n=0;
+        for (uint i=0;i<_issuers.length;i++){
+            if(i==0||_issuers[i]!=_issuers[i-1]){
+                n++;
+            }
+        }
+        if(n==0||_disclosed.length!=_issuers.length||_u.length!=n||_s.length!=n||_kappa.length!=n||
+            _vc.length!=n||_rt.length!=n||_keyVersions.length!=n){
+            return new Part[](0);
+        }
+        Part[] memory parts = new Part[](n);
+        uint start=0;
+        uint r=0;
+        for (uint k=0;k<n;k++){
+            uint end=start+1;
+            while(end<_issuers.length&&_issuers[end]==_issuers[start]){
+                end++;
+            }
+            uint256 v=_keyVersions[k];
+            if(!keyValid(v)||KeyIssuer[v]!=_issuers[start]||KeyY[v].length!=end-start||isG2Zero(_u[k])){
+                return new Part[](0);
+            }
+            bool[] memory disclosed = new bool[](end-start);
+            uint hidden=0;
+            for (uint i=start;i<end;i++){
+                disclosed[i-start]=_disclosed[i];
+                if(!_disclosed[i]){
+                    hidden++;
+                }
+            }
+            if(r+hidden>_rm.length){
+                return new Part[](0);
+            }
+            uint256[] memory rm = new uint256[](hidden);
+            for (uint i=0;i<hidden;i++){
+                rm[i]=_rm[r+i];
+            }
+            r+=hidden;
+            parts[k] = Part(v, _u[k], _s[k], _kappa[k], _vc[k], rm, _rt[k], disclosed, start);
+            start=end;
+        }
+        if(r!=_rm.length){
+            return new Part[](0);
+        }
+        return parts;
+    }
 
-    function getSID(G1Point memory pk) public view returns (string memory) {
-        return SID[GetPointKey(pk)];
+    // whether attribute _i of _part is the validity epoch rather than a claim
+    function isEpochAttr(Part memory _part, uint _i) internal view returns (bool) {
+        return ValidityEnabled&&KeyIssuer[_part.version]==0&&_i==ValidityIndex;
     }
-    
-    function RegisterSIDSet(G1Point memory _pk1,G1Point memory _a1, uint256 _c, uint256 _z,G2Point[] memory _u, G2Point[] memory _s,string[] memory _attr) public returns (bool) 
+
+    // X * kappa * ∏_{disclosed} Y_i^m_i of _part
+    function partAggregate(Part memory _part, string[] memory _attr, uint256 _epoch) internal view returns (G1Point memory agg) {
+        agg = g1add(KeyX[_part.version], _part.kappa);
+        for (uint i=0;i<_part.disclosed.length;i++){
+            if(isEpochAttr(_part, i)){
+                agg = g1add(agg, g1mul(KeyY[_part.version][i], _epoch));
+            }else if(_part.disclosed[i]){
+                agg = g1add(agg, g1mul(KeyY[_part.version][i], stringToUint256(_attr[_part.start+i])));
+            }
+        }
+    }
+
+    // Checks e(X * kappa * ∏_{disclosed} Y_i^m_i, u) == e(_pk1, s) for every part and records the
+    // disclosed claims under _key, each with the key version that signed it. Revocation and validity
+    // concern the root issuer's credential, which must be present (once) while they are enabled.
+    function registerClaims(bytes32 _key, G1Point memory _pk1, Part[] memory _parts,
+        string[] memory _attr, G1Point memory _tag, uint256 _epoch) internal returns (bool)
     {
-        string[] memory sidSet = new string[](_attr.length);
-        uint256[] memory m = new uint256[](_attr.length);
-        if(!DLVerify(G1, _pk1 , _a1, _c, _z)){
+        bool root=false;
+        uint n=0;
+        for (uint k=0;k<_parts.length;k++){
+            Part memory part = _parts[k];
+            if(KeyIssuer[part.version]==0){
+                if(root){
+                    return false;
+                }
+                root=true;
+                if(revocationEnabled()&&RevokedTags[GetPointKey(_tag)]){
+                    return false;
+                }
+                if(ValidityEnabled&&(_epoch!=ValidityEpoch||!part.disclosed[ValidityIndex])){
+                    return false;
+                }
+            }
+            if(!pairingProd2(partAggregate(part, _attr, _epoch), part.u, g1neg(_pk1), part.s)){
+                return false;
+            }
+            for (uint i=0;i<part.disclosed.length;i++){
+                if(part.disclosed[i]&&!isEpochAttr(part, i)){
+                    n++;
+                }
+            }
+        }
+        if(!root&&(revocationEnabled()||ValidityEnabled)){
             return false;
         }
+        string[] memory sidSet = new string[](n);
+        uint256[] memory claimKey = new uint256[](n);
+        n=0;
+        for (uint k=0;k<_parts.length;k++){
+            for (uint i=0;i<_parts[k].disclosed.length;i++){
+                if(_parts[k].disclosed[i]&&!isEpochAttr(_parts[k], i)){
+                    sidSet[n]=_attr[_parts[k].start+i];
+                    claimKey[n]=_parts[k].version;
+                    n++;
+                }
+            }
+        }
+        SIDSet[_key]=sidSet;
+        SIDClaimKey[_key]=claimKey;
+        if(root&&revocationEnabled()){
+            SIDTag[_key]=GetPointKey(_tag);
+            SIDRevocationEpoch[_key]=RevocationEpoch;
+        }else{
+            delete SIDTag[_key];
+        }
+        SIDEpoch[_key]=_epoch;
+        return true;
+    }
 
-        for (uint i=0;i<_u.length;i++){
-            if(isG2Zero(_u[i]))
-            {
+    // Register the claims of a presentation over one or more credentials, all bound to _pk1 (proven by
+    // the DL proof _a1, _c, _z). _issuers[i] is the issuer of attribute _attr[i]; each run of equal
+    // issuers is one credential, presented as in AC.ProveAggregate, with the per-credential values
+    // _u, _s, _kappa, _vc, _rt and key version _keyVersions (current or within its grace period).
+    // _rm holds the π_v responses of all credentials in order.
+    // Hidden attributes are passed as empty strings and only the disclosed ones are recorded.
+    // _tag is the revocation tag of the current epoch (ignored while revocation is not enabled).
+    // _epoch is the current validity epoch, disclosed at ValidityIndex (ignored while validity is not enabled).
+    // The presentation is bound to _pk1 by the DL proof and the pairings, not to a session: sent again it
+    // only registers the same claims for _pk1 again. Key-free presentations go through RegisterNymSet.
+    function RegisterSIDSet(G1Point memory _pk1,G1Point memory _a1, uint256 _c, uint256 _z,G2Point[] memory _u, G2Point[] memory _s,
+        G1Point[] memory _kappa, uint256[] memory _vc, uint256[] memory _rm, uint256[] memory _rt, string[] memory _attr, bool[] memory _disclosed,
+        bytes32[] memory _issuers, uint256[] memory _keyVersions, G1Point memory _tag, uint256 _epoch) public returns (bool) 
+    {
+        if(_attr.length!=_issuers.length||!DLVerify(G1, _pk1 , _a1, _c, _z)){
+            return false;
+        }
+        Part[] memory parts = splitParts(_u, _s, _kappa, _vc, _rm, _rt, _disclosed, _issuers, _keyVersions);
+        if(parts.length==0){
+            return false;
+        }
+        for (uint k=0;k<parts.length;k++){
+            if(!verifyPiVLinks(parts[k], _tag, new G1Point[](0), new G1Point[](0), new uint256[](0), "")){
                 return false;
             }
-            m[i]=stringToUint256(_attr[i]);
-            if(pairingProd2(g1add(IssuerKey[0], g1mul(IssuerKey[1], m[i])), _u[i], g1neg(_pk1), _s[i])){
-                sidSet[i]=_attr[i];
-            }
         }
-        SIDSet[GetPointKey(_pk1)]=sidSet;
+        delete NymSeller[GetPointKey(_pk1)];
+        return registerClaims(GetPointKey(_pk1), _pk1, parts, _attr, _tag, _epoch);
+    }
+
+    // Register the pseudonym _nym = ScopeBase(_seller)^sk of a key-free presentation, where sk is the
+    // hidden attribute NymIndex of a root issuer credential. The nym can then be used as buyerPubKey in
+    // orders with _seller only. _keyVersion is the root issuer key the credential was issued under.
+    // The presentation is made for AC.Session{Nonce: _nonce, Context: AC.NymContext(this, _seller)}, and
+    // every nonce is accepted once.
+    function RegisterNymSet(address _seller, G1Point memory _nym, G2Point memory _u, G2Point memory _s,
+        G1Point memory _kappa, uint256 _vc, uint256[] memory _rm, uint256 _rt, string[] memory _attr, bool[] memory _disclosed,
+        G1Point memory _tag, uint256 _epoch, uint256 _keyVersion, bytes32 _nonce) public returns (bool)
+    {
+        if(!NymEnabled||SpentNonces[_nonce]||!keyValid(_keyVersion)||KeyIssuer[_keyVersion]!=0||isG2Zero(_u)||
+            _attr.length!=KeyY[_keyVersion].length||_disclosed.length!=_attr.length)
+        {
+            return false;
+        }
+        Part[] memory parts = new Part[](1);
+        parts[0] = Part(_keyVersion, _u, _s, _kappa, _vc, _rm, _rt, _disclosed, 0);
+        G1Point[] memory p = new G1Point[](1);
+        G1Point[] memory base = new G1Point[](1);
+        uint256[] memory idx = new uint256[](1);
+        p[0]=_nym;
+        base[0]=ScopeBase(_seller);
+        idx[0]=NymIndex;
+        if(!verifyPiVLinks(parts[0], _tag, p, base, idx, sessionBind(_nonce, abi.encodePacked("nym|", address(this), _seller)))){
+            return false;
+        }
+        bytes32 key = GetPointKey(_nym);
+        if(!registerClaims(key, G1, parts, _attr, _tag, _epoch)){
+            return false;
+        }
+        SpentNonces[_nonce]=true;
+        NymSeller[key]=_seller;
         return true;
     }
 
@@ -223,8 +739,31 @@ contract BC_SID {
     }
 
     function CheckClaim(G1Point memory pk, string memory attribute) public view returns (bool) {
+        return checkClaim(pk, attribute, false, 0);
+    }
+
+    // CheckClaim for a claim certified by issuer _issuer.
+    function CheckClaimFrom(G1Point memory pk, string memory attribute, bytes32 _issuer) public view returns (bool) {
+        return checkClaim(pk, attribute, true, _issuer);
+    }
+
+    function checkClaim(G1Point memory pk, string memory attribute, bool _byIssuer, bytes32 _issuer) internal view returns (bool) {
         bytes32 key = GetPointKey(pk);
+        if(RevokedTags[SIDTag[key]]){
+            return false;
+        }
+        // a tag of an earlier epoch is never revoked, so the claims lapse with it
+        if(revocationEnabled()&&(SIDTag[key]==0||SIDRevocationEpoch[key]!=RevocationEpoch)){
+            return false;
+        }
+        if(ValidityEnabled&&SIDEpoch[key]!=ValidityEpoch){
+            return false;
+        }
         for (uint i=0;i<SIDSet[key].length;i++){
+            uint256 version = SIDClaimKey[key][i];
+            if(!keyValid(version)||(_byIssuer&&KeyIssuer[version]!=_issuer)){
+                continue;
+            }
             if (keccak256(abi.encodePacked(SIDSet[key][i])) == keccak256(abi.encodePacked(attribute))){
                 return true;
             }
@@ -248,11 +787,11 @@ contract BC_SID {
         bool isBuyerConfirm;
     }
 
-    mapping(address => mapping(string => uint256)) public productPrices;
+    mapping(address => mapping(string => uint256)) productPrices;
 
-    // orderBook[seller][buyer] => Purchase
+    // orderBook[seller][buyer] => Purchase; buyerPubKey is a pkB or a pseudonym registered for the seller
     // 改为三级映射，支持多个订单
-    mapping(address => mapping(address => mapping(string => Purchase))) public orderBook;
+    mapping(address => mapping(address => mapping(string => Purchase))) orderBook;
     mapping(address => uint256) balances; //stores the Eth balances of sellers
 
     event BroadcastPubKey(
@@ -354,7 +893,9 @@ contract BC_SID {
         Purchase storage order = orderBook[msg.sender][_buyer][_orderID];
         require(order.isLocked, "Funds not locked");
 
-        bool checkPassed = CheckClaim(order.buyerPubKey, attribute);
+        // a pseudonym only counts towards the seller it was registered for
+        address nymSeller = NymSeller[GetPointKey(order.buyerPubKey)];
+        bool checkPassed = CheckClaim(order.buyerPubKey, attribute) && (nymSeller == address(0) || nymSeller == msg.sender);
         if (!checkPassed) {
             uint256 refundAmount = order.price;
             delete orderBook[msg.sender][_buyer][_orderID];
@@ -449,7 +990,7 @@ contract BC_SID {
         G1Point SN;
     }
 
-    mapping(string => Logistics) public orderLogistics;
+    mapping(string => Logistics) orderLogistics;
 
     // 创建物流订单
     function CreateLogisticsOrder(
@@ -0,0 +1,568 @@
+package contract_test
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"math/big"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"Obfushop/bn256"
+	"Obfushop/compile/contract"
+	"Obfushop/crypto/AC"
+	"Obfushop/crypto/Convert"
+	"Obfushop/issuer"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind/backends"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// TestArtifacts checks that BC_SID.abi, BC_SID.bin and the binding agree,
+// i.e. that all three were produced by one run of compile.sh.
+func TestArtifacts(t *testing.T) {
+	abiBytes, err := os.ReadFile("BC_SID.abi")
+	if err != nil {
+		t.Fatal(err)
+	}
+	bin, err := os.ReadFile("BC_SID.bin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	fileABI, err := abi.JSON(strings.NewReader(string(abiBytes)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	bindingABI, err := contract.ContractMetaData.GetAbi()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for name, method := range fileABI.Methods {
+		if m, ok := bindingABI.Methods[name]; !ok || m.Sig != method.Sig {
+			t.Errorf("BC_SID.go does not bind %s", method.Sig)
+		}
+	}
+	if len(bindingABI.Methods) != len(fileABI.Methods) {
+		t.Errorf("BC_SID.go binds %d methods, BC_SID.abi has %d", len(bindingABI.Methods), len(fileABI.Methods))
+	}
+	if strings.TrimPrefix(contract.ContractMetaData.Bin, "0x") != strings.TrimSpace(string(bin)) {
+		t.Error("the bytecode in BC_SID.go differs from BC_SID.bin")
+	}
+}
+
+// testChain is a simulated chain with BC_SID deployed by accounts[0].
+type testChain struct {
+	sim      *backends.SimulatedBackend
+	address  common.Address
+	c        *contract.Contract
+	accounts []*bind.TransactOpts
+}
+
+// newTestChain funds n accounts and deploys the contract from the first.
+func newTestChain(t *testing.T, n int) *testChain {
+	t.Helper()
+	chain := &testChain{}
+	alloc := core.GenesisAlloc{}
+	for i := 0; i < n; i++ {
+		key, err := crypto.GenerateKey()
+		if err != nil {
+			t.Fatal(err)
+		}
+		auth, err := bind.NewKeyedTransactorWithChainID(key, big.NewInt(1337))
+		if err != nil {
+			t.Fatal(err)
+		}
+		chain.accounts = append(chain.accounts, auth)
+		alloc[auth.From] = core.GenesisAccount{Balance: new(big.Int).Lsh(big.NewInt(1), 100)}
+	}
+	chain.sim = backends.NewSimulatedBackend(alloc, 30_000_000)
+	t.Cleanup(func() { chain.sim.Close() })
+	address, tx, c, err := contract.DeployContract(chain.accounts[0], chain.sim)
+	if !chain.mined(t, tx, err) {
+		t.Fatalf("deploying BC_SID: %v", err)
+	}
+	chain.address, chain.c = address, c
+	return chain
+}
+
+// mined reports whether tx, sent with error err, was mined and succeeded.
+// A call that reverts already fails gas estimation, so err is set.
+func (chain *testChain) mined(t *testing.T, tx *types.Transaction, err error) bool {
+	t.Helper()
+	if err != nil {
+		return false
+	}
+	chain.sim.Commit()
+	receipt, err := bind.WaitMined(context.Background(), chain.sim, tx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return receipt.Status == types.ReceiptStatusSuccessful
+}
+
+func TestUploadACsParamsOnlyDeployer(t *testing.T) {
+	chain := newTestChain(t, 2)
+	g1 := Convert.G1ToG1Point(new(bn256.G1).ScalarBaseMult(big.NewInt(1)))
+	g2 := Convert.G2ToG2Point(new(bn256.G2).ScalarBaseMult(big.NewInt(1)))
+	upload := func(auth *bind.TransactOpts) bool {
+		tx, err := chain.c.UploadACsParams(auth, g1, g2, g1, []contract.BCSIDG1Point{g1})
+		return chain.mined(t, tx, err)
+	}
+	if upload(chain.accounts[1]) {
+		t.Fatal("another account uploaded the parameters before the deployer")
+	}
+	if !upload(chain.accounts[0]) {
+		t.Fatal("the deployer cannot upload the parameters")
+	}
+	if upload(chain.accounts[0]) {
+		t.Error("the parameters were uploaded twice")
+	}
+}
+
+// nymIssuer is the root issuer of a testChain, accounts[0]. Its credentials
+// hold a claim, the holder secret behind the pseudonym and a revocation
+// handle.
+type nymIssuer struct {
+	*testChain
+	params *AC.Params
+	key    *AC.IssuerKey
+}
+
+const nymIndex = 1
+
+func newNymIssuer(t *testing.T, accounts int) *nymIssuer {
+	t.Helper()
+	ni := &nymIssuer{testChain: newTestChain(t, accounts)}
+	var err error
+	if ni.params, err = AC.Setup(3); err != nil {
+		t.Fatal(err)
+	}
+	if ni.key, err = AC.KeyGen(ni.params); err != nil {
+		t.Fatal(err)
+	}
+	Ys := make([]contract.BCSIDG1Point, len(ni.key.PK2))
+	for i, Y := range ni.key.PK2 {
+		Ys[i] = Convert.G1ToG1Point(Y)
+	}
+	auth := ni.accounts[0]
+	tx, err := ni.c.UploadACsParams(auth, Convert.G1ToG1Point(ni.params.G1), Convert.G2ToG2Point(ni.params.G2),
+		Convert.G1ToG1Point(ni.key.PK1), Ys)
+	if !ni.mined(t, tx, err) {
+		t.Fatalf("UploadACsParams failed: %v", err)
+	}
+	tx, err = ni.c.SetNymIndex(auth, big.NewInt(nymIndex))
+	if !ni.mined(t, tx, err) {
+		t.Fatalf("SetNymIndex failed: %v", err)
+	}
+	return ni
+}
+
+// TestIssuerKeyFingerprint checks that the contract fingerprints an uploaded
+// key as AC.IssuerKey.Fingerprint does.
+func TestIssuerKeyFingerprint(t *testing.T) {
+	ni := newNymIssuer(t, 1)
+	want, err := ni.key.Fingerprint()
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := ni.c.IssuerKeyFingerprint(&bind.CallOpts{}, big.NewInt(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if hex.EncodeToString(got[:]) != want {
+		t.Errorf("on-chain fingerprint %x, want %s", got, want)
+	}
+}
+
+// holding is a credential over (claim, sk, handle).
+type holding struct {
+	claim string
+	m     []*big.Int
+	cred  *AC.Cred
+}
+
+func (ni *nymIssuer) issue(t *testing.T, claim string) *holding {
+	t.Helper()
+	sk, err := AC.NewRevocationHandle(ni.params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	handle, err := AC.NewRevocationHandle(ni.params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	d, req, err := AC.PrepareRevocableBlindSign(ni.params, []*big.Int{Convert.StringToBigInt(claim), sk}, handle)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sig, err := AC.BlindSign(ni.params, ni.key, req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cred, err := AC.ObtainCred(sig, d)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &holding{claim: claim, m: []*big.Int{Convert.StringToBigInt(claim), sk, handle}, cred: cred}
+}
+
+// registerNym presents h with its nym for scope and registers it with
+// RegisterNymSet for seller. rl may be nil while revocation is off. It
+// returns the nym and whether the registration succeeded.
+func (ni *nymIssuer) registerNym(t *testing.T, h *holding, rl *AC.RevocationList, scope, seller common.Address) (*bn256.G1, bool) {
+	t.Helper()
+	nonce, err := AC.NewNonce()
+	if err != nil {
+		t.Fatal(err)
+	}
+	sess := &AC.Session{Nonce: nonce, Context: AC.NymContext(ni.address.Bytes(), seller.Bytes())}
+	return ni.registerNymFor(t, h, rl, scope, seller, sess, [32]byte(nonce))
+}
+
+// registerNymFor is registerNym with the presentation made for sess and
+// sent with nonce.
+func (ni *nymIssuer) registerNymFor(t *testing.T, h *holding, rl *AC.RevocationList, scope, seller common.Address,
+	sess *AC.Session, nonce [32]byte) (*bn256.G1, bool) {
+	t.Helper()
+	disclose := []bool{true, false, false}
+	proof, err := AC.ProveNym(ni.params, ni.key, h.cred, h.m, disclose, nil, rl, nil,
+		&AC.Pseudonym{Index: nymIndex, Scope: scope.Bytes()}, sess)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tag := contract.BCSIDG1Point{X: new(big.Int), Y: new(big.Int)}
+	if proof.Tag != nil {
+		tag = Convert.G1ToG1Point(proof.Tag)
+	}
+	args := []interface{}{seller, Convert.G1ToG1Point(proof.Nym),
+		Convert.G2ToG2Point(proof.U), Convert.G2ToG2Point(proof.S), Convert.G1ToG1Point(proof.Kappa),
+		proof.PiV.C, proof.PiV.Rm, proof.PiV.Rt, []string{h.claim, "", ""}, disclose, tag, new(big.Int), big.NewInt(1), nonce}
+	return proof.Nym, ni.send(t, "RegisterNymSet", args...)
+}
+
+// send calls method, which reports a rejected presentation by returning
+// false, and sends it as a transaction from accounts[1] if it returns true.
+func (chain *testChain) send(t *testing.T, method string, args ...interface{}) bool {
+	t.Helper()
+	var out []interface{}
+	raw := &contract.ContractCallerRaw{Contract: &chain.c.ContractCaller}
+	if err := raw.Call(&bind.CallOpts{From: chain.accounts[1].From}, &out, method, args...); err != nil {
+		t.Fatalf("%s: %v", method, err)
+	}
+	if ok, _ := out[0].(bool); !ok {
+		return false
+	}
+	tx, err := (&contract.ContractTransactorRaw{Contract: &chain.c.ContractTransactor}).Transact(chain.accounts[1], method, args...)
+	if !chain.mined(t, tx, err) {
+		t.Fatalf("%s passes as a call but fails as a transaction: %v", method, err)
+	}
+	return true
+}
+
+// holds reports whether CheckClaim accepts claim for nym.
+func (ni *nymIssuer) holds(t *testing.T, nym *bn256.G1, claim string) bool {
+	t.Helper()
+	ok, err := ni.c.CheckClaim(&bind.CallOpts{}, Convert.G1ToG1Point(nym), claim)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return ok
+}
+
+func TestRevocationEpochs(t *testing.T) {
+	ni := newNymIssuer(t, 3)
+	ctx := context.Background()
+	auth, seller := ni.accounts[0], ni.accounts[2].From
+	alice, bob := ni.issue(t, "Age>18"), ni.issue(t, "Age>18")
+
+	if err := ni.run(func() error { return issuer.StartRevocationEpoch(ctx, ni.sim, ni.c, auth, 7) }); err != nil {
+		t.Fatal(err)
+	}
+	rl := AC.NewRevocationList(7)
+	aliceNym, ok := ni.registerNym(t, alice, rl, seller, seller)
+	if !ok {
+		t.Fatal("alice cannot register in epoch 7")
+	}
+	bobNym, ok := ni.registerNym(t, bob, rl, seller, seller)
+	if !ok {
+		t.Fatal("bob cannot register in epoch 7")
+	}
+
+	rl.Revoke(alice.m[2])
+	if err := ni.run(func() error { return issuer.RevokeTags(ctx, ni.sim, ni.c, auth, rl) }); err != nil {
+		t.Fatal(err)
+	}
+	if ni.holds(t, aliceNym, alice.claim) {
+		t.Error("revoked identity still holds its claim")
+	}
+	if !ni.holds(t, bobNym, bob.claim) {
+		t.Error("identity lost its claim when another was revoked")
+	}
+
+	// In epoch 8 every identity has to register again, and alice can until
+	// the issuer lists her handle for epoch 8 too.
+	if err := ni.run(func() error { return issuer.StartRevocationEpoch(ctx, ni.sim, ni.c, auth, 8) }); err != nil {
+		t.Fatal(err)
+	}
+	if ni.holds(t, bobNym, bob.claim) {
+		t.Error("registration of epoch 7 holds in epoch 8")
+	}
+	if err := ni.run(func() error { return issuer.RevokeTags(ctx, ni.sim, ni.c, auth, rl) }); err == nil {
+		t.Error("published epoch 7 tags in epoch 8")
+	}
+	rl = AC.NewRevocationList(8)
+	if _, ok := ni.registerNym(t, bob, rl, seller, seller); !ok {
+		t.Error("bob cannot register again in epoch 8")
+	}
+	if _, ok := ni.registerNym(t, alice, rl, seller, seller); !ok {
+		t.Error("alice cannot register in epoch 8 before being revoked in it")
+	}
+	rl.Revoke(alice.m[2])
+	if err := ni.run(func() error { return issuer.RevokeTags(ctx, ni.sim, ni.c, auth, rl) }); err != nil {
+		t.Fatal(err)
+	}
+	if ni.holds(t, aliceNym, alice.claim) {
+		t.Error("identity revoked in epoch 8 still holds its claim")
+	}
+	if !ni.holds(t, bobNym, bob.claim) {
+		t.Error("identity registered in epoch 8 lost its claim")
+	}
+}
+
+func TestRegisterNymSession(t *testing.T) {
+	ni := newNymIssuer(t, 3)
+	seller := ni.accounts[2].From
+	h := ni.issue(t, "Age>18")
+	session := func(contract, seller common.Address) (*AC.Session, [32]byte) {
+		nonce, err := AC.NewNonce()
+		if err != nil {
+			t.Fatal(err)
+		}
+		return &AC.Session{Nonce: nonce, Context: AC.NymContext(contract.Bytes(), seller.Bytes())}, [32]byte(nonce)
+	}
+
+	sess, nonce := session(ni.address, seller)
+	if _, ok := ni.registerNymFor(t, h, nil, seller, seller, sess, nonce); !ok {
+		t.Fatal("registration rejected")
+	}
+	if _, ok := ni.registerNymFor(t, h, nil, seller, seller, sess, nonce); ok {
+		t.Error("registration with a spent nonce accepted")
+	}
+
+	other := common.HexToAddress("0x00000000000000000000000000000000000000ff")
+	sess, nonce = session(other, seller)
+	if _, ok := ni.registerNymFor(t, h, nil, seller, seller, sess, nonce); ok {
+		t.Error("presentation for another contract accepted")
+	}
+	sess, nonce = session(ni.address, other)
+	if _, ok := ni.registerNymFor(t, h, nil, seller, seller, sess, nonce); ok {
+		t.Error("presentation for another seller accepted")
+	}
+	if _, ok := ni.registerNym(t, h, nil, other, seller); ok {
+		t.Error("nym for another seller's scope accepted")
+	}
+	sess, _ = session(ni.address, seller)
+	_, nonce = session(ni.address, seller)
+	if _, ok := ni.registerNymFor(t, h, nil, seller, seller, sess, nonce); ok {
+		t.Error("presentation sent with another nonce accepted")
+	}
+}
+
+// run calls f, which waits for transactions to be mined, and mines blocks
+// until it returns.
+func (chain *testChain) run(f func() error) error {
+	done := make(chan error, 1)
+	go func() { done <- f() }()
+	for {
+		select {
+		case err := <-done:
+			return err
+		case <-time.After(10 * time.Millisecond):
+			chain.sim.Commit()
+		}
+	}
+}
+
+// transcriptVectors is the part of crypto/AC/testdata/transcript_vectors.json
+// that the contract can check.
+type transcriptVectors struct {
+	DL struct {
+		G1, Y1, A1, C, Z string
+	} `json:"dl"`
+	KeyBinding struct {
+		PK1, U, R, A1, A2, C, Z string
+	} `json:"keyBinding"`
+}
+
+func vectorG1(t *testing.T, s string) contract.BCSIDG1Point {
+	t.Helper()
+	b, _ := hex.DecodeString(s)
+	p := new(bn256.G1)
+	if _, err := p.Unmarshal(b); err != nil {
+		t.Fatal(err)
+	}
+	return Convert.G1ToG1Point(p)
+}
+
+func vectorG2(t *testing.T, s string) contract.BCSIDG2Point {
+	t.Helper()
+	b, _ := hex.DecodeString(s)
+	p := new(bn256.G2)
+	if _, err := p.Unmarshal(b); err != nil {
+		t.Fatal(err)
+	}
+	return Convert.G2ToG2Point(p)
+}
+
+func vectorScalar(s string) *big.Int {
+	b, _ := hex.DecodeString(s)
+	return new(big.Int).SetBytes(b)
+}
+
+// TestTranscriptVectors runs the golden vectors of the AC transcript
+// through DLVerify and VerifyKeyBinding, and presentations made by
+// crypto/AC through VerifyPiV, RegisterSIDSet and RegisterNymSet, on a
+// simulated chain, so the contract and crypto/AC cannot drift apart. Each
+// presentation is sent again with its challenge changed, which the contract
+// must reject.
+func TestTranscriptVectors(t *testing.T) {
+	data, err := os.ReadFile("../../crypto/AC/testdata/transcript_vectors.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var v transcriptVectors
+	if err := json.Unmarshal(data, &v); err != nil {
+		t.Fatal(err)
+	}
+	ni := newNymIssuer(t, 3)
+	opts := &bind.CallOpts{From: ni.accounts[1].From}
+	tamper := func(c *big.Int) *big.Int { return new(big.Int).Mod(new(big.Int).Add(c, big.NewInt(1)), bn256.Order) }
+
+	t.Run("DLVerify", func(t *testing.T) {
+		dlVerify := func(c *big.Int) bool {
+			var out []interface{}
+			raw := &contract.ContractCallerRaw{Contract: &ni.c.ContractCaller}
+			err := raw.Call(opts, &out, "DLVerify", vectorG1(t, v.DL.G1), vectorG1(t, v.DL.Y1), vectorG1(t, v.DL.A1),
+				c, vectorScalar(v.DL.Z))
+			if err != nil {
+				t.Fatal(err)
+			}
+			ok, _ := out[0].(bool)
+			return ok
+		}
+		if !dlVerify(vectorScalar(v.DL.C)) {
+			t.Error("DLVerify rejects the DL vector")
+		}
+		if dlVerify(tamper(vectorScalar(v.DL.C))) {
+			t.Error("DLVerify accepts the DL vector with another challenge")
+		}
+	})
+
+	t.Run("VerifyKeyBinding", func(t *testing.T) {
+		kb := v.KeyBinding
+		verify := func(c *big.Int) bool {
+			ok, err := ni.c.VerifyKeyBinding(opts, vectorG1(t, kb.PK1), vectorG2(t, kb.U), vectorG2(t, kb.R),
+				c, vectorScalar(kb.Z), vectorG1(t, kb.A1), vectorG2(t, kb.A2))
+			if err != nil {
+				t.Fatal(err)
+			}
+			return ok
+		}
+		if !verify(vectorScalar(kb.C)) {
+			t.Error("VerifyKeyBinding rejects the key binding vector")
+		}
+		if verify(tamper(vectorScalar(kb.C))) {
+			t.Error("VerifyKeyBinding accepts the key binding vector with another challenge")
+		}
+	})
+
+	h := ni.issue(t, "Age>18")
+	sk := h.m[nymIndex]
+	disclose := []bool{true, false, false}
+	attrs := []string{h.claim, "", ""}
+	noTag := contract.BCSIDG1Point{X: new(big.Int), Y: new(big.Int)}
+
+	t.Run("VerifyPiV", func(t *testing.T) {
+		proof, err := AC.ProveCred(ni.params, sk, ni.key, h.cred, h.m, disclose, nil, nil, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		verify := func(c *big.Int) bool {
+			ok, err := ni.c.VerifyPiV(opts, Convert.G1ToG1Point(proof.Kappa), Convert.G2ToG2Point(proof.U),
+				Convert.G2ToG2Point(proof.S), disclose, c, proof.PiV.Rm, proof.PiV.Rt, noTag, big.NewInt(1))
+			if err != nil {
+				t.Fatal(err)
+			}
+			return ok
+		}
+		if !verify(proof.PiV.C) {
+			t.Error("VerifyPiV rejects a presentation of crypto/AC")
+		}
+		if verify(tamper(proof.PiV.C)) {
+			t.Error("VerifyPiV accepts a presentation with another challenge")
+		}
+	})
+
+	t.Run("RegisterSIDSet", func(t *testing.T) {
+		pk1 := new(bn256.G1).ScalarMult(ni.params.G1, sk)
+		dl, err := AC.DLProof(ni.params.G1, pk1, sk)
+		if err != nil {
+			t.Fatal(err)
+		}
+		proof, err := AC.ProveCred(ni.params, sk, ni.key, h.cred, h.m, disclose, nil, nil, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		register := func(c *big.Int) bool {
+			return ni.send(t, "RegisterSIDSet", Convert.G1ToG1Point(pk1), Convert.G1ToG1Point(dl.RG), dl.C, dl.Z,
+				[]contract.BCSIDG2Point{Convert.G2ToG2Point(proof.U)}, []contract.BCSIDG2Point{Convert.G2ToG2Point(proof.S)},
+				[]contract.BCSIDG1Point{Convert.G1ToG1Point(proof.Kappa)}, []*big.Int{c}, proof.PiV.Rm, []*big.Int{proof.PiV.Rt},
+				attrs, disclose, make([][32]byte, len(attrs)), []*big.Int{big.NewInt(1)}, noTag, new(big.Int))
+		}
+		if register(tamper(proof.PiV.C)) {
+			t.Error("RegisterSIDSet accepts a presentation with another challenge")
+		}
+		if !register(proof.PiV.C) {
+			t.Fatal("RegisterSIDSet rejects a presentation of crypto/AC")
+		}
+		if !ni.holds(t, pk1, h.claim) {
+			t.Error("registered key does not hold its claim")
+		}
+	})
+
+	t.Run("RegisterNymSet", func(t *testing.T) {
+		seller := ni.accounts[2].From
+		nonce, err := AC.NewNonce()
+		if err != nil {
+			t.Fatal(err)
+		}
+		sess := &AC.Session{Nonce: nonce, Context: AC.NymContext(ni.address.Bytes(), seller.Bytes())}
+		proof, err := AC.ProveNym(ni.params, ni.key, h.cred, h.m, disclose, nil, nil, nil,
+			&AC.Pseudonym{Index: nymIndex, Scope: seller.Bytes()}, sess)
+		if err != nil {
+			t.Fatal(err)
+		}
+		register := func(c *big.Int) bool {
+			return ni.send(t, "RegisterNymSet", seller, Convert.G1ToG1Point(proof.Nym),
+				Convert.G2ToG2Point(proof.U), Convert.G2ToG2Point(proof.S), Convert.G1ToG1Point(proof.Kappa),
+				c, proof.PiV.Rm, proof.PiV.Rt, attrs, disclose, noTag, new(big.Int), big.NewInt(1), [32]byte(nonce))
+		}
+		if register(tamper(proof.PiV.C)) {
+			t.Error("RegisterNymSet accepts a presentation with another challenge")
+		}
+		if !register(proof.PiV.C) {
+			t.Fatal("RegisterNymSet rejects a presentation of crypto/AC")
+		}
+		if !ni.holds(t, proof.Nym, h.claim) {
+			t.Error("registered nym does not hold its claim")
+		}
+	})
+}
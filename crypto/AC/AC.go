@@ -2,30 +2,32 @@ package AC
 
 import (
 	bn256 "Obfushop/bn256"
-	"crypto/rand"
-	"log"
+	"fmt"
 	"math/big"
+	"strconv"
 )
 
 type IssuerKey struct {
-	SK1 *big.Int
-	SK2 *big.Int
-	PK1 *bn256.G1
-	PK2 *bn256.G1
+	SK1 *big.Int    // x
+	SK2 []*big.Int  // y_1..y_q, one per attribute
+	PK1 *bn256.G1   // X = g1^x
+	PK2 []*bn256.G1 // Y_i = g1^y_i
 }
 
 type Params struct {
 	Order *big.Int
 	G1    *bn256.G1
-	H1    *bn256.G1
+	Hs    []*bn256.G1 // h_1..h_q, attribute bases of the commitment cm
 	G2    *bn256.G2
 }
 
 type Req struct {
-	gamma *bn256.G2
-	Cm    *bn256.G1   // 承诺 cm
-	C     []*bn256.G2 // ElGamal 密文 (aᵢ, bᵢ)
-	PiS   *PiS        // 零知识证明 π_s
+	gamma  *bn256.G2
+	Cm     *bn256.G1     // 承诺 cm
+	C      [][]*bn256.G2 // ElGamal 密文 (aᵢ, bᵢ), one pair per attribute
+	PiS    *PiS          // 零知识证明 π_s
+	Epoch  *big.Int      // validity epoch revealed to the issuer, signed before the handle
+	Handle *big.Int      // revocation handle revealed to the issuer, signed as the last attribute
 }
 
 type BlindSignature struct {
@@ -39,151 +41,609 @@ type Cred struct {
 }
 
 type Proof struct {
-	Value *big.Int
-	U     *bn256.G2 //
-	S     *bn256.G2 //
-	// W     *bn256.G1
-	// V     *bn256.G1
-	// DLEQ  *DLEQ
+	Value     []*big.Int    // disclosed attributes, nil where hidden
+	Disclosed []bool        // disclosure mask over the signed attribute vector
+	U         *bn256.G2     //
+	S         *bn256.G2     //
+	Kappa     *bn256.G1     // g1^t · ∏_{hidden} Yᵢ^mᵢ
+	PiV       *PiV          // 零知识证明 π_v for the hidden mᵢ and t
+	Preds     []*Predicate  // predicates proven over numeric attributes
+	Ranges    []*RangeProof // one per predicate, nil for disclosed attributes
+	Tag       *bn256.G1     // revocation tag base(epoch)^handle, nil if not revocable
+	Nym       *bn256.G1     // scope pseudonym ScopeBase(scope)^sk, nil if not requested
+	Binding   *KeyBinding   // proves pk1 and U share sk, nil unless made by ProveCredBound
 }
 
-func Setup() *Params {
+// Setup generates the public parameters for credentials over q attributes.
+func Setup(q int) (*Params, error) {
+	if q < 1 {
+		return nil, fmt.Errorf("%w: need at least one attribute", ErrMalformedInput)
+	}
 	//Generate public parameters
 	order := bn256.Order
 	g1 := new(bn256.G1).ScalarBaseMult(big.NewInt(1))
 	hScalar, _ := new(big.Int).SetString("9868996996480530350723936346388037348513707152826932716320380442065450531909", 10)
-	h1 := new(bn256.G1).ScalarBaseMult(hScalar)
 	g2 := new(bn256.G2).ScalarBaseMult(big.NewInt(1))
 
+	hs := make([]*bn256.G1, q)
+	for i := 0; i < q; i++ {
+		if i == 0 {
+			hs[i] = new(bn256.G1).ScalarBaseMult(hScalar)
+			continue
+		}
+		var err error
+		hs[i], err = bn256.HashG1("Obfushop/AC/h" + strconv.Itoa(i))
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	return &Params{
 		Order: order,
 		G1:    g1,
-		H1:    h1,
+		Hs:    hs,
 		G2:    g2,
-	}
+	}, nil
 
 }
 
-func KeyGen(params *Params) *IssuerKey {
-	x, _ := rand.Int(rand.Reader, params.Order)
-	y, _ := rand.Int(rand.Reader, params.Order)
-
+// KeyGen generates an issuer key with one y_i per attribute in params.
+func KeyGen(params *Params) (*IssuerKey, error) {
+	x, err := randScalar(params.Order)
+	if err != nil {
+		return nil, err
+	}
 	X := new(bn256.G1).ScalarBaseMult(x)
-	Y := new(bn256.G1).ScalarBaseMult(y)
+
+	q := len(params.Hs)
+	ys, err := randScalars(params.Order, q)
+	if err != nil {
+		return nil, err
+	}
+	Ys := make([]*bn256.G1, q)
+	for i := 0; i < q; i++ {
+		Ys[i] = new(bn256.G1).ScalarBaseMult(ys[i])
+	}
 
 	return &IssuerKey{
 		SK1: x,
-		SK2: y,
+		SK2: ys,
 		PK1: X,
-		PK2: Y,
+		PK2: Ys,
+	}, nil
+
+}
+
+// Public returns a copy of the key without the secrets, for publishing.
+func (issuerkey *IssuerKey) Public() *IssuerKey {
+	return &IssuerKey{PK1: issuerkey.PK1, PK2: issuerkey.PK2}
+}
+
+// PrepareBlindSign commits to the attribute vector m and encrypts each
+// u^m_i under a fresh ElGamal key gamma = g2^d. d is returned to unblind.
+func PrepareBlindSign(params *Params, m []*big.Int) (*big.Int, *Req, error) {
+	return prepareBlindSign(params, m, nil, nil)
+}
+
+// PrepareRevocableBlindSign appends the revocation handle to m as the last
+// attribute and reveals it to the issuer, who keeps it to revoke the
+// credential later.
+func PrepareRevocableBlindSign(params *Params, m []*big.Int, handle *big.Int) (*big.Int, *Req, error) {
+	if err := checkScalar("revocation handle", handle); err != nil {
+		return nil, nil, err
 	}
+	return prepareBlindSign(params, m, nil, handle)
+}
 
+// PrepareEpochBlindSign appends the validity epoch to m, followed by the
+// revocation handle if handle is not nil. Both are revealed to the issuer so
+// it can refuse epochs it does not issue for. See Validity.
+func PrepareEpochBlindSign(params *Params, m []*big.Int, epoch uint64, handle *big.Int) (*big.Int, *Req, error) {
+	if handle != nil {
+		if err := checkScalar("revocation handle", handle); err != nil {
+			return nil, nil, err
+		}
+	}
+	return prepareBlindSign(params, m, new(big.Int).SetUint64(epoch), handle)
 }
 
-func PrepareBlindSign(params *Params, m *big.Int) (*big.Int, *Req) {
+func prepareBlindSign(params *Params, m []*big.Int, epoch *big.Int, handle *big.Int) (*big.Int, *Req, error) {
+	m = append([]*big.Int(nil), m...)
+	if epoch != nil {
+		m = append(m, epoch)
+	}
+	if handle != nil {
+		m = append(m, handle)
+	}
+	if len(m) == 0 || len(m) > len(params.Hs) {
+		return nil, nil, fmt.Errorf("%w: %d attributes for %d bases", ErrMalformedInput, len(m), len(params.Hs))
+	}
+	if err := checkAttrs(m); err != nil {
+		return nil, nil, err
+	}
 
-	d, _ := rand.Int(rand.Reader, params.Order)
+	d, err := randScalar(params.Order)
+	if err != nil {
+		return nil, nil, err
+	}
 	gamma := new(bn256.G2).ScalarBaseMult(d)
 
-	o, _ := rand.Int(rand.Reader, params.Order)
-	Cm := new(bn256.G1).Add(new(bn256.G1).ScalarBaseMult(o), new(bn256.G1).ScalarMult(params.H1, m))
+	o, err := randScalar(params.Order)
+	if err != nil {
+		return nil, nil, err
+	}
+	Cm := new(bn256.G1).ScalarBaseMult(o)
+	for i := range m {
+		Cm.Add(Cm, new(bn256.G1).ScalarMult(params.Hs[i], m[i]))
+	}
 
-	u, _ := bn256.HashG2(string(Cm.Marshal()))
-	k, _ := rand.Int(rand.Reader, params.Order)
-	C := make([]*bn256.G2, 2)
-	C[0] = new(bn256.G2).ScalarBaseMult(k)
-	C[1] = new(bn256.G2).Add(new(bn256.G2).ScalarMult(gamma, k), new(bn256.G2).ScalarMult(u, m))
+	u, err := bn256.HashG2(string(Cm.Marshal()))
+	if err != nil {
+		return nil, nil, err
+	}
+	k, err := randScalars(params.Order, len(m))
+	if err != nil {
+		return nil, nil, err
+	}
+	C := make([][]*bn256.G2, len(m))
+	for i := range m {
+		C[i] = make([]*bn256.G2, 2)
+		C[i][0] = new(bn256.G2).ScalarBaseMult(k[i])
+		C[i][1] = new(bn256.G2).Add(new(bn256.G2).ScalarMult(gamma, k[i]), new(bn256.G2).ScalarMult(u, m[i]))
+	}
 
 	// 5. 构建零知识证明 π_s
-	piS, _ := MakePiS(params, gamma, C, Cm, k, o, m)
+	req := &Req{
+		gamma:  gamma,
+		Cm:     Cm,
+		C:      C,
+		Epoch:  epoch,
+		Handle: handle,
+	}
+	public := make([]bool, len(m))
+	for i, v := range req.publicAttrs() {
+		public[i] = v != nil
+	}
+	req.PiS, err = MakePiS(params, gamma, C, Cm, k, o, m, public)
+	if err != nil {
+		return nil, nil, err
+	}
 
 	// 6. 返回 commitment
-	return d, &Req{
-		gamma: gamma,
-		Cm:    Cm,
-		C:     C,
-		PiS:   piS,
-	}
+	return d, req, nil
 }
 
-func BlindSign(params *Params, issuerkey *IssuerKey, req *Req) *BlindSignature {
+// publicAttrs returns the attribute vector with only the values revealed to
+// the issuer filled in, or nil if everything is hidden.
+func (req *Req) publicAttrs() []*big.Int {
+	if req.Epoch == nil && req.Handle == nil {
+		return nil
+	}
+	public := make([]*big.Int, len(req.C))
+	n := len(public)
+	if req.Handle != nil && n > 0 {
+		public[n-1] = req.Handle
+		n--
+	}
+	if req.Epoch != nil && n > 0 {
+		public[n-1] = req.Epoch
+	}
+	return public
+}
 
-	_, err := VerifyPiS(params, req.gamma, req.C, req.Cm, req.PiS)
-	if err != nil {
-		log.Fatal("proof verification failed:", err)
+// BlindSign signs the committed attribute vector without learning it. The
+// vector must have one attribute per y_i of the key, so that a credential
+// cannot be presented with attributes added or dropped. A malformed request or one whose π_s does not verify is rejected with an
+// error wrapping ErrInvalidProof, ErrIdentityPoint or ErrMalformedInput.
+func BlindSign(params *Params, issuerkey *IssuerKey, req *Req) (*BlindSignature, error) {
+	if req == nil || issuerkey == nil {
+		return nil, fmt.Errorf("%w: missing request or key", ErrMalformedInput)
+	}
+	if len(req.C) != len(issuerkey.SK2) {
+		return nil, fmt.Errorf("%w: %d attributes for an issuer key over %d", ErrMalformedInput, len(req.C), len(issuerkey.SK2))
+	}
+	if err := checkScalar("issuer key x", issuerkey.SK1); err != nil {
+		return nil, err
+	}
+	if err := checkReq(req); err != nil {
+		return nil, err
+	}
+	if _, err := VerifyPiS(params, req.gamma, req.C, req.Cm, req.PiS, req.publicAttrs()); err != nil {
+		return nil, err
 	}
 	//fmt.Println("π_s valid:", Result)
 
 	// 1. 计算 h = HashG2(cm)
 	hBytes := req.Cm.Marshal()
 	u, err := bn256.HashG2(string(hBytes))
+	if err != nil {
+		return nil, err
+	}
 
+	// 2. ã = ∏ aᵢ^yᵢ, b̃ = u^x · ∏ bᵢ^yᵢ
 	_C := make([]*bn256.G2, 2)
-	_C[0] = new(bn256.G2).ScalarMult(req.C[0], issuerkey.SK2)
-	_C[1] = new(bn256.G2).Add(new(bn256.G2).ScalarMult(u, issuerkey.SK1), new(bn256.G2).ScalarMult(req.C[1], issuerkey.SK2))
+	_C[0] = new(bn256.G2).ScalarBaseMult(big.NewInt(0))
+	_C[1] = new(bn256.G2).ScalarMult(u, issuerkey.SK1)
+	for i := range req.C {
+		_C[0].Add(_C[0], new(bn256.G2).ScalarMult(req.C[i][0], issuerkey.SK2[i]))
+		_C[1].Add(_C[1], new(bn256.G2).ScalarMult(req.C[i][1], issuerkey.SK2[i]))
+	}
 
 	return &BlindSignature{
 		U: u,
 		C: _C,
+	}, nil
+}
+
+// checkReq validates the points of a request before its proof is checked.
+func checkReq(req *Req) error {
+	if err := checkG2("gamma", req.gamma); err != nil {
+		return err
 	}
+	if err := checkG1("cm", req.Cm); err != nil {
+		return err
+	}
+	if len(req.C) == 0 || req.PiS == nil {
+		return fmt.Errorf("%w: empty request", ErrMalformedInput)
+	}
+	for _, c := range req.C {
+		if len(c) != 2 {
+			return fmt.Errorf("%w: ciphertext is not a pair", ErrMalformedInput)
+		}
+		if err := checkG2("ciphertext a", c[0]); err != nil {
+			return err
+		}
+		if c[1] == nil {
+			return fmt.Errorf("%w: ciphertext b missing", ErrMalformedInput)
+		}
+	}
+	return nil
 }
 
-func ObtainCred(blindSigs *BlindSignature, d *big.Int) *Cred {
+// ObtainCred unblinds the issuer's signature with the d from PrepareBlindSign.
+func ObtainCred(blindSigs *BlindSignature, d *big.Int) (*Cred, error) {
+	if blindSigs == nil || len(blindSigs.C) != 2 {
+		return nil, fmt.Errorf("%w: blind signature", ErrMalformedInput)
+	}
+	if err := checkG2("u", blindSigs.U); err != nil {
+		return nil, err
+	}
+	if blindSigs.C[0] == nil || blindSigs.C[1] == nil {
+		return nil, fmt.Errorf("%w: blind signature", ErrMalformedInput)
+	}
+	if err := checkScalar("d", d); err != nil {
+		return nil, err
+	}
 	// Step 1: 解盲BlindSignature → Signature
 	sigma := new(bn256.G2).Add(blindSigs.C[1], new(bn256.G2).Neg(new(bn256.G2).ScalarMult(blindSigs.C[0], d)))
+	if err := checkG2("sigma", sigma); err != nil {
+		return nil, err
+	}
 
 	return &Cred{
 		U:     blindSigs.U,
 		Sigma: sigma,
-	}
+	}, nil
+}
+
+// ProveCred randomizes cred into a presentation bound to pk1 = g1^sk.
+// Only the attributes with disclose[i] set are revealed; the others are
+// folded into Kappa and proven in zero knowledge by PiV. Each predicate
+// over a hidden attribute gets a RangeProof on a commitment linked to Kappa.
+// If rl is set the last attribute is the revocation handle, and the proof
+// carries its tag for rl.Epoch so verifiers can check it against rl.
+// If now is set the epoch attribute is disclosed and must equal now.Epoch.
+func ProveCred(params *Params, sk *big.Int, issuerkey *IssuerKey, cred *Cred, m []*big.Int, disclose []bool, preds []*Predicate, rl *RevocationList, now *Validity) (*Proof, error) {
+	return proveCred(params, sk, issuerkey, cred, m, disclose, preds, &presentOpts{rl: rl, now: now})
 }
 
-func ProveCred(params *Params, sk *big.Int, issuerkey *IssuerKey, cred *Cred, m *big.Int) (*Proof, error) {
+// presentOpts collects the optional parts of a presentation.
+type presentOpts struct {
+	rl    *RevocationList // prove a revocation tag for rl.Epoch
+	now   *Validity       // disclose the current epoch
+	nym   *Pseudonym      // prove a scope pseudonym
+	bind  []byte          // extra data hashed into the π_v challenge
+	bound bool            // prove a KeyBinding instead of relying on pk2
+}
+
+func proveCred(params *Params, sk *big.Int, issuerkey *IssuerKey, cred *Cred, m []*big.Int, disclose []bool, preds []*Predicate, opts *presentOpts) (*Proof, error) {
+	rl, now := opts.rl, opts.now
+	if err := checkIssuerKey(issuerkey); err != nil {
+		return nil, err
+	}
+	if err := checkScalar("sk", sk); err != nil {
+		return nil, err
+	}
+	if cred == nil {
+		return nil, fmt.Errorf("%w: credential missing", ErrMalformedInput)
+	}
+	if err := checkG2("credential u", cred.U); err != nil {
+		return nil, err
+	}
+	if err := checkG2("credential sigma", cred.Sigma); err != nil {
+		return nil, err
+	}
+	if err := checkAttrs(m); err != nil {
+		return nil, err
+	}
+	if len(disclose) != len(m) || len(m) != len(issuerkey.PK2) {
+		return nil, fmt.Errorf("%w: disclosure mask does not match attributes", ErrMalformedInput)
+	}
+	if now != nil {
+		if now.Index < 0 || now.Index >= len(m) {
+			return nil, fmt.Errorf("%w: epoch index out of range", ErrMalformedInput)
+		}
+		if !now.current(m[now.Index]) {
+			return nil, fmt.Errorf("%w: credential is not valid in the current epoch", ErrMalformedInput)
+		}
+		disclose = append([]bool(nil), disclose...)
+		disclose[now.Index] = true
+	}
+	if rl != nil && (len(m) == 0 || disclose[len(m)-1]) {
+		return nil, fmt.Errorf("%w: revocation handle must be a hidden attribute", ErrMalformedInput)
+	}
+	if opts.nym != nil && (opts.nym.Index < 0 || opts.nym.Index >= len(m) || disclose[opts.nym.Index]) {
+		return nil, fmt.Errorf("%w: pseudonym secret must be a hidden attribute", ErrMalformedInput)
+	}
+	for _, pred := range preds {
+		if pred == nil || pred.Index < 0 || pred.Index >= len(m) {
+			return nil, fmt.Errorf("%w: predicate index out of range", ErrMalformedInput)
+		}
+	}
+
 	// 1.r, randomize sigma
-	r, _ := rand.Int(rand.Reader, params.Order)
-	u := new(bn256.G2).ScalarMult(cred.U, r)
-	u.ScalarMult(u, sk)
+	r, err := randScalar(params.Order)
+	if err != nil {
+		return nil, err
+	}
+	ru := new(bn256.G2).ScalarMult(cred.U, r)
+	u := new(bn256.G2).ScalarMult(ru, sk)
 	s := new(bn256.G2).ScalarMult(cred.Sigma, r)
-	// w := new(bn256.G1).ScalarMult(issuerkey.PK1, sk)
-	// v := new(bn256.G1).ScalarMult(issuerkey.PK2, sk)
-	//dleq, _ := DLEQProof(params, params.G1, issuerkey.PK1, issuerkey.PK2, sk, pk1, w, v)
+	var binding *KeyBinding
+	if opts.bound {
+		if binding, err = proveKeyBinding(params, sk, ru, u); err != nil {
+			return nil, err
+		}
+	}
+
+	// 2. kappa = g1^t · ∏_{hidden} Yᵢ^mᵢ, s' = s · (u^r)^t
+	t, err := randScalar(params.Order)
+	if err != nil {
+		return nil, err
+	}
+	kappa := new(bn256.G1).ScalarMult(params.G1, t)
+	value := make([]*big.Int, len(m))
+	hiddenPos := make([]int, len(m))
+	var hiddenM []*big.Int
+	var hiddenY []*bn256.G1
+	for i := range m {
+		if disclose[i] {
+			value[i] = m[i]
+			continue
+		}
+		kappa.Add(kappa, new(bn256.G1).ScalarMult(issuerkey.PK2[i], m[i]))
+		hiddenPos[i] = len(hiddenM)
+		hiddenM = append(hiddenM, m[i])
+		hiddenY = append(hiddenY, issuerkey.PK2[i])
+	}
+	s.Add(s, new(bn256.G2).ScalarMult(ru, t))
+
+	// 3. commit to every hidden attribute a predicate is asked about
+	var links []*AttrLink
+	for _, pred := range preds {
+		if disclose[pred.Index] {
+			if !pred.Holds(m[pred.Index]) {
+				return nil, fmt.Errorf("%w: attribute does not satisfy predicate %v", ErrMalformedInput, pred)
+			}
+			continue
+		}
+		rc, err := randScalar(params.Order)
+		if err != nil {
+			return nil, err
+		}
+		commit := new(bn256.G1).Add(new(bn256.G1).ScalarMult(params.G1, rc), new(bn256.G1).ScalarMult(RangeBase(), m[pred.Index]))
+		links = append(links, &AttrLink{Index: hiddenPos[pred.Index], Base: RangeBase(), P: commit, Blinded: true, R: rc})
+	}
+	commitLinks := links
+
+	// 4. revocation tag over the handle
+	var tag *bn256.G1
+	if rl != nil {
+		handle := len(m) - 1
+		tag = RevocationTag(rl.Epoch, m[handle])
+		links = append(links, &AttrLink{Index: hiddenPos[handle], Base: RevocationBase(rl.Epoch), P: tag})
+	}
+
+	// 5. scope pseudonym over the user secret
+	var nym *bn256.G1
+	if opts.nym != nil {
+		nym = Nym(opts.nym.Scope, m[opts.nym.Index])
+		links = append(links, &AttrLink{Index: hiddenPos[opts.nym.Index], Base: ScopeBase(opts.nym.Scope), P: nym})
+	}
+
+	// 6. 构造 π_v
+	piv, err := MakePiV(params, hiddenY, kappa, u, s, hiddenM, t, links, opts.bind)
+	if err != nil {
+		return nil, err
+	}
+
+	// 7. range proofs over the committed attributes
+	ranges := make([]*RangeProof, len(preds))
+	k := 0
+	for j, pred := range preds {
+		if disclose[pred.Index] {
+			continue
+		}
+		ranges[j], err = proveRange(params, pred, m[pred.Index], commitLinks[k].R, commitLinks[k].P, u)
+		if err != nil {
+			return nil, err
+		}
+		k++
+	}
 
 	// 4. output theta
 	proof := &Proof{
-		Value: m,
-		U:     u,
-		S:     s,
+		Value:     value,
+		Disclosed: append([]bool(nil), disclose...),
+		U:         u,
+		S:         s,
+		Kappa:     kappa,
+		PiV:       piv,
+		Preds:     preds,
+		Ranges:    ranges,
+		Tag:       tag,
+		Nym:       nym,
+		Binding:   binding,
 	}
 	return proof, nil
 }
 
-func VerifyCred(params *Params, pk1 *bn256.G1, pk2 *bn256.G2, issuerkey *IssuerKey, proof *Proof) (bool, error) {
+// VerifyCred checks a presentation and that every predicate in preds was
+// proven over the credential, either by a RangeProof or on a disclosed value.
+// If rl is set the presentation must carry a revocation tag for rl.Epoch
+// that is not on the list. If now is set the credential must be issued for
+// now.Epoch. A rejected presentation returns false with an error wrapping
+// ErrInvalidProof, ErrIdentityPoint or ErrMalformedInput.
+func VerifyCred(params *Params, pk1 *bn256.G1, pk2 *bn256.G2, issuerkey *IssuerKey, proof *Proof, preds []*Predicate, rl *RevocationList, now *Validity) (bool, error) {
+	return verifyCred(params, pk1, pk2, issuerkey, proof, preds, &presentOpts{rl: rl, now: now})
+}
 
-	// Check h != identity && pairing matches
-	if proof.U.String() == new(bn256.G2).ScalarBaseMult(big.NewInt(0)).String() {
-		//fmt.Printf("proof.U False!!!\n")
-		return false, nil
+func verifyCred(params *Params, pk1 *bn256.G1, pk2 *bn256.G2, issuerkey *IssuerKey, proof *Proof, preds []*Predicate, opts *presentOpts) (bool, error) {
+	if err := checkG1("pk1", pk1); err != nil {
+		return false, err
+	}
+	if !opts.bound {
+		if err := checkG2("pk2", pk2); err != nil {
+			return false, err
+		}
+		// Check pairing matches
+		if !bn256.Pair(params.G1, pk2).Equal(bn256.Pair(pk1, params.G2)) {
+			return false, invalid("pk1 and pk2 do not match")
+		}
+	}
+	agg, err := checkCred(params, issuerkey, proof, preds, opts)
+	if err != nil {
+		return false, err
 	}
-	if bn256.Pair(params.G1, pk2).String() != bn256.Pair(pk1, params.G2).String() {
-		return false, nil
+	if opts.bound {
+		if err := checkBinding(params, pk1, proof); err != nil {
+			return false, err
+		}
 	}
-	// if !VerifyDLEQ(params, params.G1, issuerkey.PK1, issuerkey.PK2, pk1, proof.W, proof.V, proof.DLEQ) {
-	// 	fmt.Printf("DLEQ False!!!\n")
-	// 	return false, nil
-	// }
-	left3 := bn256.Pair(new(bn256.G1).Add(issuerkey.PK1, new(bn256.G1).ScalarMult(issuerkey.PK2, proof.Value)), proof.U)
+	left3 := bn256.Pair(agg, proof.U)
 	right3 := bn256.Pair(pk1, proof.S)
-	return left3.String() == right3.String(), nil
-
-	// left1 := bn256.Pair(pk1, issuerkey.PK1)
-	// right1 := bn256.Pair(params.G1, proof.PiV.Base1)
-	// if left1.String() != right1.String() {
-	// 	return false, nil
-	// }
-
-	// left2 := bn256.Pair(pk1, issuerkey.PK2)
-	// right2 := bn256.Pair(params.G1, proof.PiV.Base2)
-	// if left2.String() != right2.String() {
-	// 	return false, nil
-	// }
+	if !left3.Equal(right3) {
+		return false, invalid("signature pairing does not hold")
+	}
+	return true, nil
+}
+
+// checkCred runs every check of a presentation except the pairings and
+// returns agg = X · kappa · ∏_{disclosed} Yᵢ^mᵢ. The presentation is valid
+// iff e(agg, U) = e(pk1, S).
+func checkCred(params *Params, issuerkey *IssuerKey, proof *Proof, preds []*Predicate, opts *presentOpts) (*bn256.G1, error) {
+	rl, now := opts.rl, opts.now
+
+	if err := checkIssuerKey(issuerkey); err != nil {
+		return nil, err
+	}
+	if proof == nil {
+		return nil, fmt.Errorf("%w: proof missing", ErrMalformedInput)
+	}
+	// Check h != identity
+	if err := checkG2("U", proof.U); err != nil {
+		return nil, err
+	}
+	if err := checkG2("S", proof.S); err != nil {
+		return nil, err
+	}
+	if err := checkG1("kappa", proof.Kappa); err != nil {
+		return nil, err
+	}
+	if len(proof.Value) != len(issuerkey.PK2) || len(proof.Disclosed) != len(proof.Value) {
+		return nil, invalid("attributes do not match the issuer key")
+	}
+	if now != nil {
+		if now.Index < 0 || now.Index >= len(proof.Value) || !proof.Disclosed[now.Index] || !now.current(proof.Value[now.Index]) {
+			return nil, invalid("credential not valid for the current epoch")
+		}
+	}
+	// X · kappa · ∏_{disclosed} Yᵢ^mᵢ
+	agg := new(bn256.G1).Add(issuerkey.PK1, proof.Kappa)
+	hiddenPos := make([]int, len(proof.Value))
+	var hiddenY []*bn256.G1
+	for i, m := range proof.Value {
+		if !proof.Disclosed[i] {
+			hiddenPos[i] = len(hiddenY)
+			hiddenY = append(hiddenY, issuerkey.PK2[i])
+			continue
+		}
+		if m == nil || m.Sign() < 0 {
+			return nil, fmt.Errorf("%w: missing or negative disclosed attribute", ErrMalformedInput)
+		}
+		agg.Add(agg, new(bn256.G1).ScalarMult(issuerkey.PK2[i], m))
+	}
+
+	// every requested predicate must be among the proven ones
+	for _, want := range preds {
+		found := false
+		for _, got := range proof.Preds {
+			if want.Equal(got) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, invalid("requested predicate not proven")
+		}
+	}
+	if len(proof.Ranges) != len(proof.Preds) {
+		return nil, invalid("range proofs do not match the predicates")
+	}
+	var links []*AttrLink
+	for j, pred := range proof.Preds {
+		if pred == nil || pred.Index < 0 || pred.Index >= len(proof.Value) {
+			return nil, invalid("predicate out of range")
+		}
+		if proof.Disclosed[pred.Index] {
+			if !pred.Holds(proof.Value[pred.Index]) {
+				return nil, invalid("disclosed attribute fails its predicate")
+			}
+			continue
+		}
+		if err := verifyRange(params, pred, proof.Ranges[j], proof.U); err != nil {
+			return nil, err
+		}
+		links = append(links, &AttrLink{Index: hiddenPos[pred.Index], Base: RangeBase(), P: proof.Ranges[j].Commit, Blinded: true})
+	}
+
+	if rl != nil {
+		handle := len(proof.Value) - 1
+		if handle < 0 || proof.Disclosed[handle] {
+			return nil, invalid("revocation handle not hidden")
+		}
+		if err := checkG1("revocation tag", proof.Tag); err != nil {
+			return nil, err
+		}
+		if rl.IsRevoked(proof.Tag) {
+			return nil, invalid("credential revoked")
+		}
+		links = append(links, &AttrLink{Index: hiddenPos[handle], Base: RevocationBase(rl.Epoch), P: proof.Tag})
+	}
+
+	if opts.nym != nil {
+		idx := opts.nym.Index
+		if idx < 0 || idx >= len(proof.Value) || proof.Disclosed[idx] {
+			return nil, invalid("pseudonym secret not hidden")
+		}
+		if err := checkG1("nym", proof.Nym); err != nil {
+			return nil, err
+		}
+		links = append(links, &AttrLink{Index: hiddenPos[idx], Base: ScopeBase(opts.nym.Scope), P: proof.Nym})
+	}
+
+	if _, err := VerifyPiV(params, hiddenY, proof.Kappa, proof.U, proof.S, links, opts.bind, proof.PiV); err != nil {
+		return nil, err
+	}
+	return agg, nil
 }
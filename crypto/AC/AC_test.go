@@ -0,0 +1,199 @@
+package AC
+
+import (
+	"Obfushop/bn256"
+	"errors"
+	"math/big"
+	"testing"
+)
+
+// issue runs PrepareBlindSign, BlindSign and ObtainCred for m under key.
+func issue(t *testing.T, params *Params, key *IssuerKey, m []*big.Int) *Cred {
+	t.Helper()
+	d, req, err := PrepareBlindSign(params, m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sig, err := BlindSign(params, key, req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cred, err := ObtainCred(sig, d)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cred
+}
+
+// userKeys returns pk1 = g1^sk and pk2 = g2^sk.
+func userKeys(params *Params, sk *big.Int) (*bn256.G1, *bn256.G2) {
+	return new(bn256.G1).ScalarMult(params.G1, sk), new(bn256.G2).ScalarMult(params.G2, sk)
+}
+
+func TestCredAttributeVector(t *testing.T) {
+	sk := big.NewInt(42)
+	for q := 1; q <= 6; q++ {
+		params, err := Setup(q)
+		if err != nil {
+			t.Fatal(err)
+		}
+		key, err := KeyGen(params)
+		if err != nil {
+			t.Fatal(err)
+		}
+		pk1, pk2 := userKeys(params, sk)
+		m := make([]*big.Int, q)
+		disclose := make([]bool, q)
+		for i := range m {
+			m[i] = big.NewInt(int64(100*q + i))
+			disclose[i] = i%2 == 0
+		}
+		cred := issue(t, params, key, m)
+		proof, err := ProveCred(params, sk, key, cred, m, disclose, nil, nil, nil)
+		if err != nil {
+			t.Fatalf("%d attributes: %v", q, err)
+		}
+		if ok, err := VerifyCred(params, pk1, pk2, key.Public(), proof, nil, nil, nil); !ok {
+			t.Errorf("%d attributes: %v", q, err)
+		}
+	}
+}
+
+func TestCredAttributeCount(t *testing.T) {
+	params, err := Setup(4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	key, err := KeyGen(params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sk := big.NewInt(42)
+	pk1, pk2 := userKeys(params, sk)
+	m := []*big.Int{big.NewInt(1), big.NewInt(2), big.NewInt(3), big.NewInt(4)}
+	cred := issue(t, params, key, m)
+
+	// A shorter vector could later be padded with zeros, which Y_i^0 does
+	// not notice, so the issuer only signs full vectors.
+	_, short, err := PrepareBlindSign(params, m[:3])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := BlindSign(params, key, short); !errors.Is(err, ErrMalformedInput) {
+		t.Errorf("signing 3 attributes with a 4-attribute key: %v, want ErrMalformedInput", err)
+	}
+
+	// The holder claims one attribute more or one less than was signed.
+	longer := append(append([]*big.Int(nil), m...), big.NewInt(0))
+	for _, claimed := range [][]*big.Int{longer, m[:3]} {
+		if _, err := ProveCred(params, sk, key, cred, claimed, make([]bool, len(claimed)), nil, nil, nil); !errors.Is(err, ErrMalformedInput) {
+			t.Errorf("proving 4 attributes as %d: %v, want ErrMalformedInput", len(claimed), err)
+		}
+	}
+	proof, err := ProveCred(params, sk, key, cred, m, []bool{false, true, true, true}, nil, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ok, err := VerifyCred(params, pk1, pk2, key.Public(), proof, nil, nil, nil); !ok {
+		t.Fatal(err)
+	}
+	edits := map[string]func(p *Proof){
+		"dropped": func(p *Proof) { p.Value, p.Disclosed = p.Value[:3], p.Disclosed[:3] },
+		"added": func(p *Proof) {
+			p.Value, p.Disclosed = append(p.Value, big.NewInt(0)), append(p.Disclosed, true)
+		},
+	}
+	for name, edit := range edits {
+		p := *proof
+		p.Value = append([]*big.Int(nil), proof.Value...)
+		p.Disclosed = append([]bool(nil), proof.Disclosed...)
+		edit(&p)
+		if ok, err := VerifyCred(params, pk1, pk2, key.Public(), &p, nil, nil, nil); ok || !errors.Is(err, ErrInvalidProof) {
+			t.Errorf("presentation with an attribute %s: %v, want ErrInvalidProof", name, err)
+		}
+	}
+}
+
+func TestSelectiveDisclosure(t *testing.T) {
+	params, err := Setup(4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	key, err := KeyGen(params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sk := big.NewInt(42)
+	pk1, pk2 := userKeys(params, sk)
+	m := []*big.Int{big.NewInt(11), big.NewInt(22), big.NewInt(33), big.NewInt(44)}
+	cred := issue(t, params, key, m)
+
+	// Every mask over four attributes, from all hidden to all disclosed.
+	for mask := 0; mask < 1<<len(m); mask++ {
+		disclose := make([]bool, len(m))
+		for i := range disclose {
+			disclose[i] = mask>>i&1 == 1
+		}
+		proof, err := ProveCred(params, sk, key, cred, m, disclose, nil, nil, nil)
+		if err != nil {
+			t.Fatalf("mask %04b: %v", mask, err)
+		}
+		for i := range m {
+			if disclose[i] && proof.Value[i].Cmp(m[i]) != 0 || !disclose[i] && proof.Value[i] != nil {
+				t.Errorf("mask %04b: value %d is %v", mask, i, proof.Value[i])
+			}
+		}
+		if ok, err := VerifyCred(params, pk1, pk2, key.Public(), proof, nil, nil, nil); !ok {
+			t.Errorf("mask %04b: %v", mask, err)
+		}
+		if mask == 0 {
+			continue
+		}
+
+		// Changing a disclosed value breaks the signature pairing.
+		for i := range m {
+			if !disclose[i] {
+				continue
+			}
+			p := *proof
+			p.Value = append([]*big.Int(nil), proof.Value...)
+			p.Value[i] = new(big.Int).Add(m[i], big.NewInt(1))
+			if ok, err := VerifyCred(params, pk1, pk2, key.Public(), &p, nil, nil, nil); ok || !errors.Is(err, ErrInvalidProof) {
+				t.Errorf("mask %04b with value %d changed: %v, want ErrInvalidProof", mask, i, err)
+			}
+		}
+
+		// Flipping a disclosed attribute to hidden changes the π_v statement.
+		p := *proof
+		p.Disclosed = append([]bool(nil), proof.Disclosed...)
+		p.Value = append([]*big.Int(nil), proof.Value...)
+		for i := range p.Disclosed {
+			if p.Disclosed[i] {
+				p.Disclosed[i], p.Value[i] = false, nil
+				break
+			}
+		}
+		if ok, err := VerifyCred(params, pk1, pk2, key.Public(), &p, nil, nil, nil); ok || !errors.Is(err, ErrInvalidProof) {
+			t.Errorf("mask %04b with an attribute hidden afterwards: %v, want ErrInvalidProof", mask, err)
+		}
+	}
+
+	// The mask must have one entry per attribute.
+	for _, n := range []int{0, 3, 5} {
+		if _, err := ProveCred(params, sk, key, cred, m, make([]bool, n), nil, nil, nil); !errors.Is(err, ErrMalformedInput) {
+			t.Errorf("mask of %d for 4 attributes: %v, want ErrMalformedInput", n, err)
+		}
+	}
+	proof, err := ProveCred(params, sk, key, cred, m, []bool{true, false, true, false}, nil, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	proof.Disclosed = proof.Disclosed[:3]
+	if ok, err := VerifyCred(params, pk1, pk2, key.Public(), proof, nil, nil, nil); ok || !errors.Is(err, ErrInvalidProof) {
+		t.Errorf("presentation with a short mask: %v, want ErrInvalidProof", err)
+	}
+	proof.Disclosed = []bool{true, true, true, false}
+	if ok, err := VerifyCred(params, pk1, pk2, key.Public(), proof, nil, nil, nil); ok || !errors.Is(err, ErrMalformedInput) {
+		t.Errorf("presentation disclosing a missing value: %v, want ErrMalformedInput", err)
+	}
+}
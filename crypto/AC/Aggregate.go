@@ -0,0 +1,207 @@
+package AC
+
+import (
+	"Obfushop/bn256"
+	"crypto/rand"
+	"encoding/binary"
+	"fmt"
+	"math/big"
+	"sort"
+	"sync"
+)
+
+// A shopper can hold credentials from several authorities, e.g. an age
+// credential from the government and a KYC credential from a bank. An
+// aggregated presentation shows some of them at once: one key-bound
+// presentation per credential, all for the same pk1, checked with a single
+// pairing product against the keys of their issuers.
+
+// IssuerRegistry maps issuer IDs to the public keys a verifier accepts.
+type IssuerRegistry struct {
+	mu   sync.RWMutex
+	keys map[string]*IssuerKey
+}
+
+// NewIssuerRegistry returns an empty registry.
+func NewIssuerRegistry() *IssuerRegistry {
+	return &IssuerRegistry{keys: make(map[string]*IssuerKey)}
+}
+
+// Register adds the public part of key under id. IDs cannot be reused.
+func (reg *IssuerRegistry) Register(id string, key *IssuerKey) error {
+	if id == "" {
+		return fmt.Errorf("%w: empty issuer ID", ErrMalformedInput)
+	}
+	if err := checkIssuerKey(key); err != nil {
+		return err
+	}
+	reg.mu.Lock()
+	defer reg.mu.Unlock()
+	if _, ok := reg.keys[id]; ok {
+		return fmt.Errorf("%w: issuer %q already registered", ErrMalformedInput, id)
+	}
+	reg.keys[id] = key.Public()
+	return nil
+}
+
+// Key returns the public key of issuer id.
+func (reg *IssuerRegistry) Key(id string) (*IssuerKey, error) {
+	reg.mu.RLock()
+	defer reg.mu.RUnlock()
+	key, ok := reg.keys[id]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownIssuer, id)
+	}
+	return key, nil
+}
+
+// IDs returns the registered issuer IDs, sorted.
+func (reg *IssuerRegistry) IDs() []string {
+	reg.mu.RLock()
+	defer reg.mu.RUnlock()
+	ids := make([]string, 0, len(reg.keys))
+	for id := range reg.keys {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// AggregatePart says what a presentation shows about the credential of one
+// issuer. Prover and verifier pass the same parts in the same order.
+type AggregatePart struct {
+	Issuer     string
+	Preds      []*Predicate
+	Revocation *RevocationList // the issuer's list, if its credentials are revocable
+	Validity   *Validity       // the issuer's current epoch, if its credentials expire
+}
+
+// Holding is a credential presented as one part of an aggregated presentation.
+type Holding struct {
+	AggregatePart
+	Cred     *Cred
+	Attrs    []*big.Int
+	Disclose []bool
+}
+
+// AggregateProof is a presentation over credentials from several issuers.
+// Parts[k] is a ProveCredBound presentation of the credential of Issuers[k].
+type AggregateProof struct {
+	Issuers []string
+	Parts   []*Proof
+}
+
+// aggregateBind binds part k to the issuer list and the session. Without a
+// session the parts carry no extra binding, as on-chain.
+func aggregateBind(issuers []string, k int, sess *Session) []byte {
+	if sess == nil {
+		return nil
+	}
+	buf := []byte("Obfushop/AC/aggregate")
+	buf = binary.BigEndian.AppendUint32(buf, uint32(len(issuers)))
+	for _, id := range issuers {
+		buf = binary.BigEndian.AppendUint32(buf, uint32(len(id)))
+		buf = append(buf, id...)
+	}
+	buf = binary.BigEndian.AppendUint32(buf, uint32(k))
+	return append(buf, sess.bytes()...)
+}
+
+// checkIssuers rejects empty and repeated issuers: each issuer contributes
+// at most one credential, which is also what RegisterSIDSet expects.
+func checkIssuers(issuers []string) error {
+	if len(issuers) == 0 {
+		return fmt.Errorf("%w: no credentials to present", ErrMalformedInput)
+	}
+	seen := make(map[string]bool, len(issuers))
+	for _, id := range issuers {
+		if seen[id] {
+			return fmt.Errorf("%w: issuer %q presented twice", ErrMalformedInput, id)
+		}
+		seen[id] = true
+	}
+	return nil
+}
+
+// ProveAggregate presents the holdings of the owner of sk in one proof, with
+// the keys of their issuers taken from reg. sess may be nil for
+// presentations checked on-chain by RegisterSIDSet, which are bound to the
+// buyer key only and not to a session.
+func ProveAggregate(params *Params, reg *IssuerRegistry, sk *big.Int, holdings []*Holding, sess *Session) (*AggregateProof, error) {
+	issuers := make([]string, len(holdings))
+	for k, h := range holdings {
+		if h == nil {
+			return nil, fmt.Errorf("%w: holding missing", ErrMalformedInput)
+		}
+		issuers[k] = h.Issuer
+	}
+	if err := checkIssuers(issuers); err != nil {
+		return nil, err
+	}
+	proof := &AggregateProof{Issuers: issuers, Parts: make([]*Proof, len(holdings))}
+	for k, h := range holdings {
+		key, err := reg.Key(h.Issuer)
+		if err != nil {
+			return nil, err
+		}
+		opts := &presentOpts{rl: h.Revocation, now: h.Validity, bind: aggregateBind(issuers, k, sess), bound: true}
+		if proof.Parts[k], err = proveCred(params, sk, key, h.Cred, h.Attrs, h.Disclose, h.Preds, opts); err != nil {
+			return nil, fmt.Errorf("issuer %q: %w", h.Issuer, err)
+		}
+	}
+	return proof, nil
+}
+
+// VerifyAggregate checks an aggregated presentation for the buyer key pk1
+// against the issuer keys in reg. Every part gets the checks of
+// VerifyCredBound; the pairing equations e(aggₖ, Uₖ) = e(pk1, Sₖ) are folded
+// with random weights δₖ into one bn256.PairingCheck
+//
+//	∏ e(δₖ·aggₖ, Uₖ) · e(-pk1, Σ δₖ·Sₖ) = 1
+//
+// with len(parts)+1 pairs.
+func VerifyAggregate(params *Params, reg *IssuerRegistry, pk1 *bn256.G1, proof *AggregateProof, parts []*AggregatePart, sess *Session) (bool, error) {
+	if err := checkG1("pk1", pk1); err != nil {
+		return false, err
+	}
+	if proof == nil || len(proof.Parts) != len(proof.Issuers) || len(parts) != len(proof.Parts) {
+		return false, invalid("presentation does not match the requested parts")
+	}
+	if err := checkIssuers(proof.Issuers); err != nil {
+		return false, err
+	}
+	bound := new(big.Int).Lsh(big.NewInt(1), batchSecurity)
+	a := make([]*bn256.G1, 0, len(parts)+1)
+	b := make([]*bn256.G2, 0, len(parts)+1)
+	sSum := new(bn256.G2).ScalarBaseMult(big.NewInt(0))
+	for k, part := range parts {
+		if part == nil || part.Issuer != proof.Issuers[k] {
+			return false, invalid(fmt.Sprintf("part %d is not from the requested issuer", k))
+		}
+		key, err := reg.Key(part.Issuer)
+		if err != nil {
+			return false, err
+		}
+		opts := &presentOpts{rl: part.Revocation, now: part.Validity, bind: aggregateBind(proof.Issuers, k, sess), bound: true}
+		agg, err := checkCred(params, key, proof.Parts[k], part.Preds, opts)
+		if err == nil {
+			err = checkBinding(params, pk1, proof.Parts[k])
+		}
+		if err != nil {
+			return false, fmt.Errorf("issuer %q: %w", part.Issuer, err)
+		}
+		delta, err := rand.Int(rand.Reader, bound)
+		if err != nil {
+			return false, fmt.Errorf("%w: %v", ErrBadRandomness, err)
+		}
+		a = append(a, new(bn256.G1).ScalarMult(agg, delta))
+		b = append(b, proof.Parts[k].U)
+		sSum.Add(sSum, new(bn256.G2).ScalarMult(proof.Parts[k].S, delta))
+	}
+	a = append(a, new(bn256.G1).Neg(pk1))
+	b = append(b, sSum)
+	if !bn256.PairingCheck(a, b) {
+		return false, invalid("signature pairing does not hold")
+	}
+	return true, nil
+}
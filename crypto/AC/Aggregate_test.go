@@ -0,0 +1,161 @@
+package AC
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+)
+
+// aggregateFixture holds credentials of one buyer from two issuers.
+type aggregateFixture struct {
+	params   *Params
+	reg      *IssuerRegistry
+	keys     map[string]*IssuerKey
+	sk       *big.Int
+	holdings []*Holding
+	parts    []*AggregatePart
+}
+
+func newAggregateFixture(t *testing.T) *aggregateFixture {
+	t.Helper()
+	f := &aggregateFixture{params: testParams(t), reg: NewIssuerRegistry(), keys: make(map[string]*IssuerKey), sk: big.NewInt(42)}
+	for i, id := range []string{"government", "bank"} {
+		key, err := KeyGen(f.params)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := f.reg.Register(id, key); err != nil {
+			t.Fatal(err)
+		}
+		f.keys[id] = key
+		m := []*big.Int{big.NewInt(int64(30 + i)), big.NewInt(int64(7 + i))}
+		part := AggregatePart{Issuer: id}
+		f.holdings = append(f.holdings, &Holding{AggregatePart: part, Cred: issue(t, f.params, key, m), Attrs: m, Disclose: []bool{i == 0, i == 1}})
+		f.parts = append(f.parts, &part)
+	}
+	return f
+}
+
+func (f *aggregateFixture) prove(t *testing.T, reg *IssuerRegistry, sess *Session) *AggregateProof {
+	t.Helper()
+	proof, err := ProveAggregate(f.params, reg, f.sk, f.holdings, sess)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return proof
+}
+
+func TestAggregate(t *testing.T) {
+	f := newAggregateFixture(t)
+	pk1, _ := userKeys(f.params, f.sk)
+	otherPK1, _ := userKeys(f.params, big.NewInt(43))
+	nonce, err := NewNonce()
+	if err != nil {
+		t.Fatal(err)
+	}
+	sess := &Session{Nonce: nonce, Context: []byte("aggregate test")}
+
+	for _, s := range []*Session{nil, sess} {
+		proof := f.prove(t, f.reg, s)
+		if ok, err := VerifyAggregate(f.params, f.reg, pk1, proof, f.parts, s); !ok {
+			t.Errorf("two issuers, session %v: %v", s != nil, err)
+		}
+		if ok, err := VerifyAggregate(f.params, f.reg, otherPK1, proof, f.parts, s); ok || !errors.Is(err, ErrInvalidProof) {
+			t.Errorf("another pk1, session %v: %v, want ErrInvalidProof", s != nil, err)
+		}
+	}
+
+	proof := f.prove(t, f.reg, sess)
+	if ok, err := VerifyAggregate(f.params, f.reg, pk1, proof, f.parts, nil); ok || !errors.Is(err, ErrInvalidProof) {
+		t.Errorf("without the session: %v, want ErrInvalidProof", err)
+	}
+	if ok, err := VerifyAggregate(f.params, f.reg, pk1, proof, f.parts[:1], sess); ok || !errors.Is(err, ErrInvalidProof) {
+		t.Errorf("fewer parts requested: %v, want ErrInvalidProof", err)
+	}
+	swapped := &AggregateProof{Issuers: []string{"bank", "government"}, Parts: []*Proof{proof.Parts[1], proof.Parts[0]}}
+	if ok, err := VerifyAggregate(f.params, f.reg, pk1, swapped, []*AggregatePart{f.parts[1], f.parts[0]}, sess); ok || !errors.Is(err, ErrInvalidProof) {
+		t.Errorf("parts reordered: %v, want ErrInvalidProof", err)
+	}
+	twice := &AggregateProof{Issuers: []string{"bank", "bank"}, Parts: []*Proof{proof.Parts[1], proof.Parts[1]}}
+	if ok, err := VerifyAggregate(f.params, f.reg, pk1, twice, []*AggregatePart{f.parts[1], f.parts[1]}, sess); ok || !errors.Is(err, ErrMalformedInput) {
+		t.Errorf("one issuer twice: %v, want ErrMalformedInput", err)
+	}
+}
+
+func TestAggregateIssuers(t *testing.T) {
+	f := newAggregateFixture(t)
+	pk1, _ := userKeys(f.params, f.sk)
+	proof := f.prove(t, f.reg, nil)
+
+	// Neither prover nor verifier can use an issuer they have no key for.
+	partial := NewIssuerRegistry()
+	if err := partial.Register("government", f.keys["government"]); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ProveAggregate(f.params, partial, f.sk, f.holdings, nil); !errors.Is(err, ErrUnknownIssuer) {
+		t.Errorf("proving for an unknown issuer: %v, want ErrUnknownIssuer", err)
+	}
+	if ok, err := VerifyAggregate(f.params, partial, pk1, proof, f.parts, nil); ok || !errors.Is(err, ErrUnknownIssuer) {
+		t.Errorf("verifying for an unknown issuer: %v, want ErrUnknownIssuer", err)
+	}
+	if err := f.reg.Register("bank", f.keys["government"]); !errors.Is(err, ErrMalformedInput) {
+		t.Errorf("registering an issuer twice: %v, want ErrMalformedInput", err)
+	}
+
+	// After the bank rotates its key, presentations of credentials under
+	// the old key no longer hold.
+	rotated := NewIssuerRegistry()
+	newKey, err := KeyGen(f.params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := rotated.Register("government", f.keys["government"]); err != nil {
+		t.Fatal(err)
+	}
+	if err := rotated.Register("bank", newKey); err != nil {
+		t.Fatal(err)
+	}
+	if ok, err := VerifyAggregate(f.params, rotated, pk1, proof, f.parts, nil); ok || !errors.Is(err, ErrInvalidProof) {
+		t.Errorf("rotated issuer key: %v, want ErrInvalidProof", err)
+	}
+	if ok, err := VerifyAggregate(f.params, rotated, pk1, f.prove(t, rotated, nil), f.parts, nil); ok || !errors.Is(err, ErrInvalidProof) {
+		t.Errorf("credential of the old key presented for the new one: %v, want ErrInvalidProof", err)
+	}
+}
+
+func TestAggregateBadComponent(t *testing.T) {
+	f := newAggregateFixture(t)
+	pk1, _ := userKeys(f.params, f.sk)
+	forger, err := KeyGen(f.params)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A credential the bank never signed only fails the folded pairing
+	// check, which then fails for the whole presentation.
+	forged := *f.holdings[1]
+	forged.Cred = issue(t, f.params, forger, forged.Attrs)
+	proof, err := ProveAggregate(f.params, f.reg, f.sk, []*Holding{f.holdings[0], &forged}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ok, err := VerifyAggregate(f.params, f.reg, pk1, proof, f.parts, nil); ok || !errors.Is(err, ErrInvalidProof) {
+		t.Errorf("forged component: %v, want ErrInvalidProof", err)
+	}
+
+	// A valid part taken from another buyer's presentation.
+	other := *f
+	other.sk = big.NewInt(43)
+	mixed := f.prove(t, f.reg, nil)
+	mixed.Parts[1] = other.prove(t, f.reg, nil).Parts[1]
+	if ok, err := VerifyAggregate(f.params, f.reg, pk1, mixed, f.parts, nil); ok || !errors.Is(err, ErrInvalidProof) {
+		t.Errorf("component of another buyer: %v, want ErrInvalidProof", err)
+	}
+
+	// A component without its key binding.
+	unbound := f.prove(t, f.reg, nil)
+	unbound.Parts[0].Binding = nil
+	if ok, err := VerifyAggregate(f.params, f.reg, pk1, unbound, f.parts, nil); ok || !errors.Is(err, ErrInvalidProof) {
+		t.Errorf("unbound component: %v, want ErrInvalidProof", err)
+	}
+}
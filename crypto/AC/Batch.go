@@ -0,0 +1,145 @@
+package AC
+
+import (
+	"Obfushop/bn256"
+	"crypto/rand"
+	"fmt"
+	"math/big"
+	"sort"
+)
+
+// batchSecurity is the bit length of the random weights. A batch containing
+// an invalid presentation passes with probability about 2^-batchSecurity.
+const batchSecurity = 128
+
+// BatchItem is one presentation for BatchVerifyCred. PK1 and PK2 are the
+// buyer keys for ProveCred presentations; ProveCredBound ones need only PK1.
+// Leave them nil for key-free ones made by ProveCredFor (set Session) or
+// ProveNym (set Nym and Session).
+type BatchItem struct {
+	PK1     *bn256.G1
+	PK2     *bn256.G2
+	Proof   *Proof
+	Preds   []*Predicate
+	Session *Session
+	Nym     *Pseudonym
+}
+
+// BatchVerifyCred checks many presentations under one issuer key. Every
+// item gets the non-pairing checks of VerifyCred; the pairing equations of
+// all items are then folded with random weights into one bn256.PairingCheck
+// with 2N+2 pairs. If that fails the batch is bisected to find the culprits.
+// It returns the indexes of the items that do not verify, nil if all do; the
+// error is only set if no randomness could be read.
+func BatchVerifyCred(params *Params, issuerkey *IssuerKey, items []*BatchItem, rl *RevocationList, now *Validity) ([]int, error) {
+	aggs := make([]*bn256.G1, len(items))
+	var bad, pending []int
+	for i, item := range items {
+		opts := &presentOpts{rl: rl, now: now, nym: item.Nym, bound: item.PK1 != nil && item.PK2 == nil}
+		if item.Session != nil {
+			opts.bind = item.Session.bytes()
+		}
+		if item.PK1 == nil && (item.Session == nil || len(item.Session.Nonce) == 0) {
+			// a key-free presentation must be bound to a session
+			bad = append(bad, i)
+			continue
+		}
+		if item.PK1 == nil && item.PK2 != nil {
+			bad = append(bad, i)
+			continue
+		}
+		agg, err := checkCred(params, issuerkey, item.Proof, item.Preds, opts)
+		if err == nil && opts.bound {
+			err = checkBinding(params, item.PK1, item.Proof)
+		}
+		if err != nil {
+			bad = append(bad, i)
+			continue
+		}
+		aggs[i] = agg
+		pending = append(pending, i)
+	}
+
+	failed, err := bisect(params, items, aggs, pending)
+	if err != nil {
+		return nil, err
+	}
+	bad = append(bad, failed...)
+	if len(bad) == 0 {
+		return nil, nil
+	}
+	sort.Ints(bad)
+	return bad, nil
+}
+
+// bisect returns the items among idx whose pairing equations do not hold.
+func bisect(params *Params, items []*BatchItem, aggs []*bn256.G1, idx []int) ([]int, error) {
+	if len(idx) == 0 {
+		return nil, nil
+	}
+	ok, err := batchPairing(params, items, aggs, idx)
+	if err != nil {
+		return nil, err
+	}
+	if ok {
+		return nil, nil
+	}
+	if len(idx) == 1 {
+		return idx, nil
+	}
+	mid := len(idx) / 2
+	left, err := bisect(params, items, aggs, idx[:mid])
+	if err != nil {
+		return nil, err
+	}
+	right, err := bisect(params, items, aggs, idx[mid:])
+	if err != nil {
+		return nil, err
+	}
+	return append(left, right...), nil
+}
+
+// batchPairing checks, for random δᵢ and δ'ᵢ,
+//
+//	∏ e(δᵢ·aggᵢ, Uᵢ) · e(-δᵢ·pk1ᵢ, Sᵢ) · e(g1, Σ δ'ᵢ·pk2ᵢ) · e(-Σ δ'ᵢ·pk1ᵢ, g2) = 1
+//
+// which holds for all δ only if e(aggᵢ, Uᵢ) = e(pk1ᵢ, Sᵢ) and
+// e(g1, pk2ᵢ) = e(pk1ᵢ, g2) hold for every item. Items without pk2 are
+// key-bound and skip the second equation.
+func batchPairing(params *Params, items []*BatchItem, aggs []*bn256.G1, idx []int) (bool, error) {
+	bound := new(big.Int).Lsh(big.NewInt(1), batchSecurity)
+	a := make([]*bn256.G1, 0, 2*len(idx)+2)
+	b := make([]*bn256.G2, 0, 2*len(idx)+2)
+	pk1Sum := new(bn256.G1).ScalarBaseMult(big.NewInt(0))
+	pk2Sum := new(bn256.G2).ScalarBaseMult(big.NewInt(0))
+	keyed := false
+	for _, i := range idx {
+		item := items[i]
+		delta, err := rand.Int(rand.Reader, bound)
+		if err != nil {
+			return false, fmt.Errorf("%w: %v", ErrBadRandomness, err)
+		}
+		pk1 := params.G1
+		if item.PK1 != nil {
+			pk1 = item.PK1
+		}
+		if item.PK2 != nil {
+			deltaKey, err := rand.Int(rand.Reader, bound)
+			if err != nil {
+				return false, fmt.Errorf("%w: %v", ErrBadRandomness, err)
+			}
+			pk1Sum.Add(pk1Sum, new(bn256.G1).ScalarMult(item.PK1, deltaKey))
+			pk2Sum.Add(pk2Sum, new(bn256.G2).ScalarMult(item.PK2, deltaKey))
+			keyed = true
+		}
+		a = append(a, new(bn256.G1).ScalarMult(aggs[i], delta))
+		b = append(b, item.Proof.U)
+		a = append(a, new(bn256.G1).Neg(new(bn256.G1).ScalarMult(pk1, delta)))
+		b = append(b, item.Proof.S)
+	}
+	if keyed {
+		a = append(a, params.G1, new(bn256.G1).Neg(pk1Sum))
+		b = append(b, pk2Sum, params.G2)
+	}
+	return bn256.PairingCheck(a, b), nil
+}
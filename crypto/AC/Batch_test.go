@@ -0,0 +1,148 @@
+package AC
+
+import (
+	"math/big"
+	"reflect"
+	"testing"
+)
+
+// batchFixture makes presentations of every BatchItem kind under key.
+type batchFixture struct {
+	params *Params
+	key    *IssuerKey
+	forger *IssuerKey // signs credentials that do not hold under key
+	m      []*big.Int
+	sk     *big.Int
+}
+
+// item presents a credential from signer in the given kind: "keys" for
+// ProveCred, "bound" for ProveCredBound, "session" for ProveCredFor and
+// "nym" for ProveNym. The presentation is always made for f.key.
+func (f *batchFixture) item(t *testing.T, kind string, signer *IssuerKey) *BatchItem {
+	t.Helper()
+	cred := issue(t, f.params, signer, f.m)
+	disclose := []bool{true, false}
+	pk1, pk2 := userKeys(f.params, f.sk)
+	nonce, err := NewNonce()
+	if err != nil {
+		t.Fatal(err)
+	}
+	sess := &Session{Nonce: nonce, Context: []byte("batch test")}
+	var item *BatchItem
+	switch kind {
+	case "keys":
+		proof, err := ProveCred(f.params, f.sk, f.key, cred, f.m, disclose, nil, nil, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		item = &BatchItem{PK1: pk1, PK2: pk2, Proof: proof}
+	case "bound":
+		proof, err := ProveCredBound(f.params, f.sk, f.key, cred, f.m, disclose, nil, nil, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		item = &BatchItem{PK1: pk1, Proof: proof}
+	case "session":
+		proof, err := ProveCredFor(f.params, f.key, cred, f.m, disclose, nil, nil, nil, sess)
+		if err != nil {
+			t.Fatal(err)
+		}
+		item = &BatchItem{Proof: proof, Session: sess}
+	case "nym":
+		nym := &Pseudonym{Index: 1, Scope: []byte("seller")}
+		proof, err := ProveNym(f.params, f.key, cred, f.m, disclose, nil, nil, nil, nym, sess)
+		if err != nil {
+			t.Fatal(err)
+		}
+		item = &BatchItem{Proof: proof, Session: sess, Nym: nym}
+	default:
+		t.Fatalf("unknown kind %s", kind)
+	}
+	return item
+}
+
+func newBatchFixture(t *testing.T) *batchFixture {
+	t.Helper()
+	f := &batchFixture{params: testParams(t), sk: big.NewInt(42)}
+	f.m = []*big.Int{big.NewInt(30), f.sk}
+	var err error
+	if f.key, err = KeyGen(f.params); err != nil {
+		t.Fatal(err)
+	}
+	if f.forger, err = KeyGen(f.params); err != nil {
+		t.Fatal(err)
+	}
+	return f
+}
+
+var batchKinds = []string{"keys", "bound", "session", "nym"}
+
+func TestBatchVerifyCred(t *testing.T) {
+	f := newBatchFixture(t)
+	var items []*BatchItem
+	for i := 0; i < 2; i++ {
+		for _, kind := range batchKinds {
+			items = append(items, f.item(t, kind, f.key))
+		}
+	}
+	bad, err := BatchVerifyCred(f.params, f.key.Public(), items, nil, nil)
+	if err != nil || bad != nil {
+		t.Fatalf("valid batch: bad %v, %v", bad, err)
+	}
+	for _, item := range items {
+		if bad, err := BatchVerifyCred(f.params, f.key.Public(), []*BatchItem{item}, nil, nil); err != nil || bad != nil {
+			t.Errorf("batch of one: bad %v, %v", bad, err)
+		}
+	}
+	for _, empty := range [][]*BatchItem{nil, {}} {
+		if bad, err := BatchVerifyCred(f.params, f.key.Public(), empty, nil, nil); err != nil || bad != nil {
+			t.Errorf("empty batch: bad %v, %v", bad, err)
+		}
+	}
+}
+
+func TestBatchVerifyCredBisect(t *testing.T) {
+	f := newBatchFixture(t)
+
+	// Forged credentials pass the per-item checks and only fail the pairing
+	// equations, which bisection has to pin down; one of every kind.
+	var items []*BatchItem
+	var want []int
+	for i := 0; i < 12; i++ {
+		kind := batchKinds[i%len(batchKinds)]
+		signer := f.key
+		if i == 1 || i == 6 || i == 8 || i == 11 {
+			signer, want = f.forger, append(want, i)
+		}
+		items = append(items, f.item(t, kind, signer))
+	}
+
+	// A ProveCred presentation checked against another holder's keys fails
+	// the pairing equations too.
+	wrongKeys := f.item(t, "keys", f.key)
+	wrongKeys.PK1, wrongKeys.PK2 = userKeys(f.params, big.NewInt(43))
+	items, want = append(items, wrongKeys), append(want, len(items))
+
+	// A key-free presentation needs a session and a bound one a matching
+	// PK1; both are caught before the pairing.
+	noSession := f.item(t, "session", f.key)
+	noSession.Session = nil
+	items, want = append(items, noSession), append(want, len(items))
+	wrongPK1 := f.item(t, "bound", f.key)
+	wrongPK1.PK1, _ = userKeys(f.params, big.NewInt(43))
+	items, want = append(items, wrongPK1), append(want, len(items))
+	items = append(items, f.item(t, "nym", f.key))
+
+	bad, err := BatchVerifyCred(f.params, f.key.Public(), items, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(bad, want) {
+		t.Errorf("bad items %v, want %v", bad, want)
+	}
+
+	all := []*BatchItem{f.item(t, "keys", f.forger), f.item(t, "nym", f.forger)}
+	if bad, err := BatchVerifyCred(f.params, f.key.Public(), all, nil, nil); err != nil || !reflect.DeepEqual(bad, []int{0, 1}) {
+		t.Errorf("batch of forgeries: bad %v, %v, want [0 1]", bad, err)
+	}
+}
@@ -0,0 +1,989 @@
+package AC
+
+import (
+	"Obfushop/bn256"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"sort"
+)
+
+// Wire format. Every AC type has a canonical binary and JSON encoding so the
+// holder, the issuer and the verifiers can run on different machines and
+// credentials can be stored on disk.
+//
+// Binary: one version byte, one type byte, then the fields in declaration
+// order. Points are bn256 Marshal output (64 bytes in G1, 128 in G2),
+// scalars are 32 bytes big-endian, optional values carry a 0/1 presence
+// byte, lists a 4-byte count. Signed integers (predicate bounds) carry a
+// sign byte before the magnitude.
+//
+// JSON: an object with "version" and "type" and one key per field, points
+// and scalars as fixed-width hex strings. encoding/json sorts the keys, so
+// the output is canonical as well; absent optional fields are omitted.
+//
+// Decoding rejects other versions, trailing bytes, unknown JSON keys, points
+// that are not on the curve and identity points.
+
+// WireVersion is the version of the encoding written by this package.
+const WireVersion = 1
+
+const (
+	scalarSize  = 32
+	g1Size      = 64
+	g2Size      = 128
+	maxListSize = 1 << 16
+)
+
+// type bytes of the binary encoding and names of the JSON encoding
+const (
+	wireParams byte = iota + 1
+	wireReq
+	wireBlindSignature
+	wireCred
+	wireProof
+	wirePiS
+	wirePiV
+	wireDL
+	wireRangeProof
+	wirePredicate
+	wireIssuerKey
+	wireSecretKey
+	wireDLEQ
+	wireSigmaProof
+	wireKeyBinding
+	wireAggregateProof
+)
+
+var wireNames = map[byte]string{
+	wireParams:         "params",
+	wireReq:            "req",
+	wireBlindSignature: "blindsignature",
+	wireCred:           "cred",
+	wireProof:          "proof",
+	wirePiS:            "pis",
+	wirePiV:            "piv",
+	wireDL:             "dl",
+	wireRangeProof:     "rangeproof",
+	wirePredicate:      "predicate",
+	wireIssuerKey:      "issuerkey",
+	wireSecretKey:      "issuersecretkey",
+	wireDLEQ:           "dleq",
+	wireSigmaProof:     "sigmaproof",
+	wireKeyBinding:     "keybinding",
+	wireAggregateProof: "aggregateproof",
+}
+
+// wireType is implemented by every encodable AC type. fields visits each
+// field once with the codec, which either encodes or decodes it.
+type wireType interface {
+	wireTag() byte
+	fields(c *codec)
+}
+
+// codec walks the fields of a wireType in one of four modes: binary or JSON,
+// encoding or decoding. The first error sticks and later calls are no-ops.
+type codec struct {
+	decode bool
+	json   bool
+	buf    []byte                     // binary data, consumed when decoding
+	out    map[string]any             // JSON object being written
+	in     map[string]json.RawMessage // JSON object being read
+	err    error
+}
+
+func (c *codec) fail(format string, args ...any) {
+	if c.err == nil {
+		c.err = fmt.Errorf("%w: "+format, append([]any{ErrMalformedInput}, args...)...)
+	}
+}
+
+// take consumes n bytes of binary input.
+func (c *codec) take(n int) []byte {
+	if c.err != nil {
+		return nil
+	}
+	if len(c.buf) < n {
+		c.fail("truncated data")
+		return nil
+	}
+	b := c.buf[:n]
+	c.buf = c.buf[n:]
+	return b
+}
+
+// raw returns the JSON value of a field, or nil if it is absent.
+func (c *codec) raw(name string, optional bool) json.RawMessage {
+	if c.err != nil {
+		return nil
+	}
+	v, ok := c.in[name]
+	if !ok || string(v) == "null" {
+		if !optional {
+			c.fail("missing field %q", name)
+		}
+		return nil
+	}
+	delete(c.in, name)
+	return v
+}
+
+// hexField reads a fixed-width hex string field.
+func (c *codec) hexField(name string, raw json.RawMessage, size int) []byte {
+	var s string
+	if err := json.Unmarshal(raw, &s); err != nil {
+		c.fail("field %q: %v", name, err)
+		return nil
+	}
+	b, err := hex.DecodeString(s)
+	if err != nil || len(b) != size || hex.EncodeToString(b) != s {
+		c.fail("field %q: want %d bytes of lower-case hex", name, size)
+		return nil
+	}
+	return b
+}
+
+// present handles the presence byte or key of an optional field and reports
+// whether the value follows.
+func (c *codec) present(name string, isSet bool) (json.RawMessage, bool) {
+	if c.err != nil {
+		return nil, false
+	}
+	switch {
+	case c.json && c.decode:
+		raw := c.raw(name, true)
+		return raw, raw != nil
+	case c.json:
+		return nil, isSet
+	case c.decode:
+		b := c.take(1)
+		if b == nil {
+			return nil, false
+		}
+		if b[0] > 1 {
+			c.fail("bad presence byte")
+		}
+		return nil, b[0] == 1
+	default:
+		if isSet {
+			c.buf = append(c.buf, 1)
+		} else {
+			c.buf = append(c.buf, 0)
+		}
+		return nil, isSet
+	}
+}
+
+// value reads or writes the fixed-size encoding b of a required field.
+func (c *codec) value(name string, raw json.RawMessage, b []byte, size int) []byte {
+	if c.err != nil {
+		return nil
+	}
+	switch {
+	case c.json && c.decode:
+		if raw == nil {
+			raw = c.raw(name, false)
+		}
+		if raw == nil {
+			return nil
+		}
+		return c.hexField(name, raw, size)
+	case c.json:
+		c.out[name] = hex.EncodeToString(b)
+	case c.decode:
+		return c.take(size)
+	default:
+		c.buf = append(c.buf, b...)
+	}
+	return nil
+}
+
+func decodeG1(b []byte) (*bn256.G1, error) {
+	p := new(bn256.G1)
+	if _, err := p.Unmarshal(b); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrMalformedInput, err)
+	}
+	return p, nil
+}
+
+func decodeG2(b []byte) (*bn256.G2, error) {
+	p := new(bn256.G2)
+	if _, err := p.Unmarshal(b); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrMalformedInput, err)
+	}
+	return p, nil
+}
+
+// g1 encodes a point in G1. Decoded points must be on the curve and not the
+// identity.
+func (c *codec) g1(name string, p **bn256.G1, optional bool) {
+	var raw json.RawMessage
+	if optional {
+		var ok bool
+		if raw, ok = c.present(name, *p != nil); !ok {
+			return
+		}
+	}
+	if !c.decode {
+		if c.err != nil {
+			return
+		}
+		if err := checkG1(name, *p); err != nil {
+			c.err = err
+			return
+		}
+		c.value(name, nil, (*p).Marshal(), g1Size)
+		return
+	}
+	b := c.value(name, raw, nil, g1Size)
+	if b == nil {
+		return
+	}
+	q, err := decodeG1(b)
+	if err == nil {
+		err = checkG1(name, q)
+	}
+	if err != nil {
+		c.err = err
+		return
+	}
+	*p = q
+}
+
+// g2 is g1 for points in G2, which are also checked to be in the subgroup.
+func (c *codec) g2(name string, p **bn256.G2, optional bool) {
+	var raw json.RawMessage
+	if optional {
+		var ok bool
+		if raw, ok = c.present(name, *p != nil); !ok {
+			return
+		}
+	}
+	if !c.decode {
+		if c.err != nil {
+			return
+		}
+		if err := checkG2(name, *p); err != nil {
+			c.err = err
+			return
+		}
+		c.value(name, nil, (*p).Marshal(), g2Size)
+		return
+	}
+	b := c.value(name, raw, nil, g2Size)
+	if b == nil {
+		return
+	}
+	q, err := decodeG2(b)
+	if err == nil {
+		err = checkG2(name, q)
+	}
+	if err != nil {
+		c.err = err
+		return
+	}
+	*p = q
+}
+
+// scalar encodes a non-negative integer of at most 256 bits: attributes,
+// challenges and responses.
+func (c *codec) scalar(name string, k **big.Int, optional bool) {
+	var raw json.RawMessage
+	if optional {
+		var ok bool
+		if raw, ok = c.present(name, *k != nil); !ok {
+			return
+		}
+	}
+	if !c.decode {
+		if c.err != nil {
+			return
+		}
+		if *k == nil || (*k).Sign() < 0 || (*k).BitLen() > 8*scalarSize {
+			c.fail("field %q: not a 256-bit scalar", name)
+			return
+		}
+		c.value(name, nil, (*k).FillBytes(make([]byte, scalarSize)), scalarSize)
+		return
+	}
+	if b := c.value(name, raw, nil, scalarSize); b != nil {
+		*k = new(big.Int).SetBytes(b)
+	}
+}
+
+// signed encodes an integer of at most 256 bits that may be negative.
+func (c *codec) signed(name string, k **big.Int) {
+	if c.err != nil {
+		return
+	}
+	if !c.decode && (*k == nil || (*k).BitLen() > 8*scalarSize) {
+		c.fail("field %q: not a 256-bit integer", name)
+		return
+	}
+	switch {
+	case c.json && c.decode:
+		raw := c.raw(name, false)
+		if raw == nil {
+			return
+		}
+		var s string
+		v, ok := new(big.Int), false
+		if json.Unmarshal(raw, &s) == nil {
+			v, ok = v.SetString(s, 10)
+		}
+		if !ok || v.String() != s || v.BitLen() > 8*scalarSize {
+			c.fail("field %q: want a decimal integer", name)
+			return
+		}
+		*k = v
+	case c.json:
+		c.out[name] = (*k).String()
+	case c.decode:
+		b := c.take(1 + scalarSize)
+		if b == nil {
+			return
+		}
+		v := new(big.Int).SetBytes(b[1:])
+		switch {
+		case b[0] == 1 && v.Sign() != 0:
+			v.Neg(v)
+		case b[0] != 0:
+			c.fail("field %q: bad sign byte", name)
+			return
+		}
+		*k = v
+	default:
+		sign := byte(0)
+		if (*k).Sign() < 0 {
+			sign = 1
+		}
+		c.buf = append(c.buf, sign)
+		c.buf = append(c.buf, new(big.Int).Abs(*k).FillBytes(make([]byte, scalarSize))...)
+	}
+}
+
+// num encodes a small non-negative int such as an attribute index.
+func (c *codec) num(name string, n *int) {
+	if c.err != nil {
+		return
+	}
+	if !c.decode && (*n < 0 || *n > maxListSize) {
+		c.fail("field %q: out of range", name)
+		return
+	}
+	switch {
+	case c.json && c.decode:
+		raw := c.raw(name, false)
+		if raw == nil {
+			return
+		}
+		var v int
+		if err := json.Unmarshal(raw, &v); err != nil || v < 0 || v > maxListSize {
+			c.fail("field %q: want an index", name)
+			return
+		}
+		*n = v
+	case c.json:
+		c.out[name] = *n
+	case c.decode:
+		if b := c.take(4); b != nil {
+			v := binary.BigEndian.Uint32(b)
+			if v > maxListSize {
+				c.fail("field %q: out of range", name)
+				return
+			}
+			*n = int(v)
+		}
+	default:
+		c.buf = binary.BigEndian.AppendUint32(c.buf, uint32(*n))
+	}
+}
+
+// str encodes a short string such as a predicate operator.
+func (c *codec) str(name string, s *string) {
+	if c.err != nil {
+		return
+	}
+	if !c.decode && len(*s) > 255 {
+		c.fail("field %q: too long", name)
+		return
+	}
+	switch {
+	case c.json && c.decode:
+		raw := c.raw(name, false)
+		if raw != nil && json.Unmarshal(raw, s) != nil {
+			c.fail("field %q: want a string", name)
+		}
+	case c.json:
+		c.out[name] = *s
+	case c.decode:
+		if n := c.take(1); n != nil {
+			if b := c.take(int(n[0])); b != nil {
+				*s = string(b)
+			}
+		}
+	default:
+		c.buf = append(c.buf, byte(len(*s)))
+		c.buf = append(c.buf, *s...)
+	}
+}
+
+// list encodes n elements, each through elem with a codec for that element.
+// When decoding, alloc is called with the count first.
+func (c *codec) list(name string, n int, alloc func(n int), elem func(c *codec, i int)) {
+	if c.err != nil {
+		return
+	}
+	switch {
+	case c.json && c.decode:
+		raw := c.raw(name, false)
+		if raw == nil {
+			return
+		}
+		var items []json.RawMessage
+		if err := json.Unmarshal(raw, &items); err != nil || len(items) > maxListSize {
+			c.fail("field %q: want a list", name)
+			return
+		}
+		alloc(len(items))
+		for i, item := range items {
+			ec := &codec{decode: true, json: true, in: map[string]json.RawMessage{"": item}}
+			elem(ec, i)
+			if ec.err != nil {
+				c.err = ec.err
+				return
+			}
+		}
+	case c.json:
+		items := make([]any, n)
+		for i := range items {
+			ec := &codec{json: true, out: make(map[string]any)}
+			elem(ec, i)
+			if ec.err != nil {
+				c.err = ec.err
+				return
+			}
+			items[i] = ec.out[""]
+		}
+		c.out[name] = items
+	case c.decode:
+		b := c.take(4)
+		if b == nil {
+			return
+		}
+		count := binary.BigEndian.Uint32(b)
+		if count > maxListSize || int(count) > len(c.buf) {
+			c.fail("field %q: bad list length", name)
+			return
+		}
+		alloc(int(count))
+		for i := 0; i < int(count) && c.err == nil; i++ {
+			elem(c, i)
+		}
+	default:
+		c.buf = binary.BigEndian.AppendUint32(c.buf, uint32(n))
+		for i := 0; i < n && c.err == nil; i++ {
+			elem(c, i)
+		}
+	}
+}
+
+// object encodes a nested wireType without its own version and type. When
+// decoding, v must already be allocated.
+func (c *codec) object(name string, v wireType) {
+	if c.err != nil {
+		return
+	}
+	if isNil(v) {
+		c.fail("field %q missing", name)
+		return
+	}
+	if !c.json {
+		v.fields(c)
+		return
+	}
+	if c.decode {
+		raw := c.raw(name, false)
+		if raw == nil {
+			return
+		}
+		var in map[string]json.RawMessage
+		if err := json.Unmarshal(raw, &in); err != nil {
+			c.fail("field %q: want an object", name)
+			return
+		}
+		oc := &codec{decode: true, json: true, in: in}
+		v.fields(oc)
+		c.err = oc.finish()
+		return
+	}
+	oc := &codec{json: true, out: make(map[string]any)}
+	v.fields(oc)
+	if oc.err != nil {
+		c.err = oc.err
+		return
+	}
+	c.out[name] = oc.out
+}
+
+// optionalObject encodes an object that may be absent. get returns the
+// object to encode, or allocates the one to decode into.
+func (c *codec) optionalObject(name string, isSet bool, get func() wireType) {
+	if c.err != nil {
+		return
+	}
+	switch {
+	case c.json && c.decode:
+		if raw, ok := c.in[name]; !ok || string(raw) == "null" {
+			delete(c.in, name)
+			return
+		}
+	case c.json:
+		if !isSet {
+			return
+		}
+	default:
+		if _, ok := c.present(name, isSet); !ok {
+			return
+		}
+	}
+	c.object(name, get())
+}
+
+// flag encodes a bool.
+func (c *codec) flag(name string, b *bool) {
+	if c.err != nil {
+		return
+	}
+	switch {
+	case c.json && c.decode:
+		raw := c.raw(name, false)
+		if raw != nil && json.Unmarshal(raw, b) != nil {
+			c.fail("field %q: want a bool", name)
+		}
+	case c.json:
+		c.out[name] = *b
+	default:
+		_, *b = c.present(name, *b)
+	}
+}
+
+// finish rejects unknown JSON keys once all fields are read.
+func (c *codec) finish() error {
+	if c.err != nil || !c.json || !c.decode {
+		return c.err
+	}
+	if len(c.in) > 0 {
+		keys := make([]string, 0, len(c.in))
+		for k := range c.in {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		return fmt.Errorf("%w: unknown field %q", ErrMalformedInput, keys[0])
+	}
+	return nil
+}
+
+func isNil(v wireType) bool {
+	switch v := v.(type) {
+	case *Proof:
+		return v == nil
+	case *PiS:
+		return v == nil
+	case *g2Pair:
+		return v == nil
+	case *PiV:
+		return v == nil
+	case *RangeProof:
+		return v == nil
+	case *Predicate:
+		return v == nil
+	}
+	return false
+}
+
+// g1s, g2s and scalars encode lists of points or scalars. Entries of an
+// optional scalar list may be nil.
+
+func (c *codec) g1s(name string, ps *[]*bn256.G1) {
+	c.list(name, len(*ps), func(n int) { *ps = make([]*bn256.G1, n) }, func(ec *codec, i int) {
+		ec.g1("", &(*ps)[i], false)
+	})
+}
+
+func (c *codec) scalars(name string, ks *[]*big.Int, optional bool) {
+	c.list(name, len(*ks), func(n int) { *ks = make([]*big.Int, n) }, func(ec *codec, i int) {
+		ec.scalar("", &(*ks)[i], optional)
+	})
+}
+
+func (c *codec) bools(name string, bs *[]bool) {
+	c.list(name, len(*bs), func(n int) { *bs = make([]bool, n) }, func(ec *codec, i int) {
+		ec.flag("", &(*bs)[i])
+	})
+}
+
+// marshalWire and unmarshalWire add and check the version and type.
+
+func marshalWire(v wireType) ([]byte, error) {
+	c := &codec{buf: []byte{WireVersion, v.wireTag()}}
+	v.fields(c)
+	if c.err != nil {
+		return nil, c.err
+	}
+	return c.buf, nil
+}
+
+func unmarshalWire(v wireType, data []byte) error {
+	if len(data) < 2 || data[0] != WireVersion {
+		return fmt.Errorf("%w: unsupported wire version", ErrMalformedInput)
+	}
+	if data[1] != v.wireTag() {
+		return fmt.Errorf("%w: not a %s", ErrMalformedInput, wireNames[v.wireTag()])
+	}
+	c := &codec{decode: true, buf: data[2:]}
+	v.fields(c)
+	if c.err != nil {
+		return c.err
+	}
+	if len(c.buf) != 0 {
+		return fmt.Errorf("%w: trailing data", ErrMalformedInput)
+	}
+	return nil
+}
+
+func marshalWireJSON(v wireType) ([]byte, error) {
+	c := &codec{json: true, out: map[string]any{"version": WireVersion, "type": wireNames[v.wireTag()]}}
+	v.fields(c)
+	if c.err != nil {
+		return nil, c.err
+	}
+	return json.Marshal(c.out)
+}
+
+func unmarshalWireJSON(v wireType, data []byte) error {
+	var in map[string]json.RawMessage
+	if err := json.Unmarshal(data, &in); err != nil {
+		return fmt.Errorf("%w: %v", ErrMalformedInput, err)
+	}
+	var version int
+	var name string
+	if json.Unmarshal(in["version"], &version) != nil || version != WireVersion {
+		return fmt.Errorf("%w: unsupported wire version", ErrMalformedInput)
+	}
+	if json.Unmarshal(in["type"], &name) != nil || name != wireNames[v.wireTag()] {
+		return fmt.Errorf("%w: not a %s", ErrMalformedInput, wireNames[v.wireTag()])
+	}
+	delete(in, "version")
+	delete(in, "type")
+	c := &codec{decode: true, json: true, in: in}
+	v.fields(c)
+	return c.finish()
+}
+
+// Fields of each type, in declaration order.
+
+func (params *Params) wireTag() byte { return wireParams }
+
+func (params *Params) fields(c *codec) {
+	if c.decode {
+		params.Order = bn256.Order
+	}
+	c.g1("g1", &params.G1, false)
+	c.g1s("hs", &params.Hs)
+	c.g2("g2", &params.G2, false)
+}
+
+func (issuerkey *IssuerKey) wireTag() byte { return wireIssuerKey }
+
+// fields covers only the public part; the secrets are never encoded.
+func (issuerkey *IssuerKey) fields(c *codec) {
+	c.g1("x", &issuerkey.PK1, false)
+	c.g1s("ys", &issuerkey.PK2)
+}
+
+// secretKey holds the secret scalars of an IssuerKey for ExportSecretPEM.
+type secretKey struct {
+	x  *big.Int
+	ys []*big.Int
+}
+
+func (sk *secretKey) wireTag() byte { return wireSecretKey }
+
+func (sk *secretKey) fields(c *codec) {
+	c.scalar("x", &sk.x, false)
+	c.scalars("ys", &sk.ys, false)
+}
+
+func (req *Req) wireTag() byte { return wireReq }
+
+func (req *Req) fields(c *codec) {
+	c.g2("gamma", &req.gamma, false)
+	c.g1("cm", &req.Cm, false)
+	c.list("c", len(req.C), func(n int) { req.C = make([][]*bn256.G2, n) }, func(ec *codec, i int) {
+		if ec.decode {
+			req.C[i] = make([]*bn256.G2, 2)
+		} else if len(req.C[i]) != 2 {
+			ec.fail("ciphertext is not a pair")
+			return
+		}
+		ec.object("", &g2Pair{req.C[i]})
+	})
+	if c.decode {
+		req.PiS = new(PiS)
+	}
+	c.object("pis", req.PiS)
+	c.scalar("epoch", &req.Epoch, true)
+	c.scalar("handle", &req.Handle, true)
+	if c.decode && c.err == nil && (len(req.PiS.Rk) != len(req.C) || len(req.PiS.Rm) != len(req.C)) {
+		c.fail("π_s does not match the ciphertexts")
+	}
+}
+
+// g2Pair is one ElGamal ciphertext (a, b) of a Req. b may be the identity
+// only in the negligible case u^m = gamma^-k, so both are checked as usual.
+type g2Pair struct{ p []*bn256.G2 }
+
+func (pair *g2Pair) wireTag() byte { return 0 }
+
+func (pair *g2Pair) fields(c *codec) {
+	c.g2("a", &pair.p[0], false)
+	c.g2("b", &pair.p[1], false)
+}
+
+func (sig *BlindSignature) wireTag() byte { return wireBlindSignature }
+
+func (sig *BlindSignature) fields(c *codec) {
+	c.g2("u", &sig.U, false)
+	if c.decode {
+		sig.C = make([]*bn256.G2, 2)
+	} else if len(sig.C) != 2 {
+		c.fail("blind signature is not a pair")
+		return
+	}
+	c.object("c", &g2Pair{sig.C})
+}
+
+func (cred *Cred) wireTag() byte { return wireCred }
+
+func (cred *Cred) fields(c *codec) {
+	c.g2("u", &cred.U, false)
+	c.g2("sigma", &cred.Sigma, false)
+}
+
+func (proof *Proof) wireTag() byte { return wireProof }
+
+func (proof *Proof) fields(c *codec) {
+	c.scalars("value", &proof.Value, true)
+	c.bools("disclosed", &proof.Disclosed)
+	c.g2("u", &proof.U, false)
+	c.g2("s", &proof.S, false)
+	c.g1("kappa", &proof.Kappa, false)
+	if c.decode {
+		proof.PiV = new(PiV)
+	}
+	c.object("piv", proof.PiV)
+	c.list("preds", len(proof.Preds), func(n int) { proof.Preds = make([]*Predicate, n) }, func(ec *codec, i int) {
+		if ec.decode {
+			proof.Preds[i] = new(Predicate)
+		}
+		ec.object("", proof.Preds[i])
+	})
+	c.list("ranges", len(proof.Ranges), func(n int) { proof.Ranges = make([]*RangeProof, n) }, func(ec *codec, i int) {
+		if ec.decode {
+			proof.Ranges[i] = new(RangeProof)
+		}
+		ec.object("", proof.Ranges[i])
+	})
+	c.g1("tag", &proof.Tag, true)
+	c.g1("nym", &proof.Nym, true)
+	c.optionalObject("binding", proof.Binding != nil, func() wireType {
+		if c.decode {
+			proof.Binding = new(KeyBinding)
+		}
+		return proof.Binding
+	})
+	if c.decode && c.err == nil {
+		if len(proof.Disclosed) != len(proof.Value) {
+			c.fail("disclosure mask does not match attributes")
+			return
+		}
+		for i, m := range proof.Value {
+			if (m != nil) != proof.Disclosed[i] {
+				c.fail("attribute %d: value does not match the mask", i)
+				return
+			}
+		}
+	}
+}
+
+func (proof *AggregateProof) wireTag() byte { return wireAggregateProof }
+
+func (proof *AggregateProof) fields(c *codec) {
+	c.list("issuers", len(proof.Issuers), func(n int) { proof.Issuers = make([]string, n) }, func(ec *codec, i int) {
+		ec.str("", &proof.Issuers[i])
+	})
+	c.list("parts", len(proof.Parts), func(n int) { proof.Parts = make([]*Proof, n) }, func(ec *codec, i int) {
+		if ec.decode {
+			proof.Parts[i] = new(Proof)
+		}
+		ec.object("", proof.Parts[i])
+	})
+}
+
+func (proof *PiS) wireTag() byte { return wirePiS }
+
+func (proof *PiS) fields(c *codec) {
+	c.scalar("c", &proof.C, false)
+	c.scalars("rk", &proof.Rk, false)
+	c.scalars("rm", &proof.Rm, false)
+	c.scalar("ro", &proof.Ro, false)
+}
+
+func (proof *PiV) wireTag() byte { return wirePiV }
+
+func (proof *PiV) fields(c *codec) {
+	c.scalar("c", &proof.C, false)
+	c.scalars("rm", &proof.Rm, false)
+	c.scalar("rt", &proof.Rt, false)
+	c.scalars("rr", &proof.Rr, false)
+}
+
+func (proof *DL) wireTag() byte { return wireDL }
+
+func (proof *DL) fields(c *codec) {
+	c.scalar("c", &proof.C, false)
+	c.scalar("z", &proof.Z, false)
+	c.g1("rg", &proof.RG, false)
+}
+
+func (proof *DLEQ) wireTag() byte { return wireDLEQ }
+
+func (proof *DLEQ) fields(c *codec) {
+	c.scalar("c", &proof.C, false)
+	c.scalar("z", &proof.Z, false)
+}
+
+func (proof *KeyBinding) wireTag() byte { return wireKeyBinding }
+
+func (proof *KeyBinding) fields(c *codec) {
+	c.g2("r", &proof.R, false)
+	c.scalar("c", &proof.C, false)
+	c.scalar("z", &proof.Z, false)
+	c.g1("a1", &proof.A1, false)
+	c.g2("a2", &proof.A2, false)
+}
+
+func (proof *SigmaProof) wireTag() byte { return wireSigmaProof }
+
+func (proof *SigmaProof) fields(c *codec) {
+	c.scalar("c", &proof.C, false)
+	c.scalars("r", &proof.R, false)
+}
+
+func (proof *RangeProof) wireTag() byte { return wireRangeProof }
+
+func (proof *RangeProof) fields(c *codec) {
+	c.g1("commit", &proof.Commit, false)
+	c.g1s("bits", &proof.Bits)
+	c.scalars("c0", &proof.C0, false)
+	c.scalars("c1", &proof.C1, false)
+	c.scalars("z0", &proof.Z0, false)
+	c.scalars("z1", &proof.Z1, false)
+}
+
+func (p *Predicate) wireTag() byte { return wirePredicate }
+
+func (p *Predicate) fields(c *codec) {
+	c.num("index", &p.Index)
+	c.str("op", &p.Op)
+	c.signed("bound", &p.Bound)
+	if c.decode && c.err == nil {
+		if _, _, err := p.shift(); err != nil {
+			c.err = err
+		}
+	}
+}
+
+// MarshalBinary, UnmarshalBinary, MarshalJSON and UnmarshalJSON implement
+// the wire format described at the top of this file. For an IssuerKey they
+// read and write the public key only.
+
+func (params *Params) MarshalBinary() ([]byte, error)    { return marshalWire(params) }
+func (params *Params) UnmarshalBinary(data []byte) error { return unmarshalWire(params, data) }
+func (params *Params) MarshalJSON() ([]byte, error)      { return marshalWireJSON(params) }
+func (params *Params) UnmarshalJSON(data []byte) error   { return unmarshalWireJSON(params, data) }
+
+func (issuerkey *IssuerKey) MarshalBinary() ([]byte, error)    { return marshalWire(issuerkey) }
+func (issuerkey *IssuerKey) UnmarshalBinary(data []byte) error { return unmarshalWire(issuerkey, data) }
+func (issuerkey *IssuerKey) MarshalJSON() ([]byte, error)      { return marshalWireJSON(issuerkey) }
+func (issuerkey *IssuerKey) UnmarshalJSON(data []byte) error {
+	return unmarshalWireJSON(issuerkey, data)
+}
+
+func (req *Req) MarshalBinary() ([]byte, error)    { return marshalWire(req) }
+func (req *Req) UnmarshalBinary(data []byte) error { return unmarshalWire(req, data) }
+func (req *Req) MarshalJSON() ([]byte, error)      { return marshalWireJSON(req) }
+func (req *Req) UnmarshalJSON(data []byte) error   { return unmarshalWireJSON(req, data) }
+
+func (sig *BlindSignature) MarshalBinary() ([]byte, error)    { return marshalWire(sig) }
+func (sig *BlindSignature) UnmarshalBinary(data []byte) error { return unmarshalWire(sig, data) }
+func (sig *BlindSignature) MarshalJSON() ([]byte, error)      { return marshalWireJSON(sig) }
+func (sig *BlindSignature) UnmarshalJSON(data []byte) error   { return unmarshalWireJSON(sig, data) }
+
+func (cred *Cred) MarshalBinary() ([]byte, error)    { return marshalWire(cred) }
+func (cred *Cred) UnmarshalBinary(data []byte) error { return unmarshalWire(cred, data) }
+func (cred *Cred) MarshalJSON() ([]byte, error)      { return marshalWireJSON(cred) }
+func (cred *Cred) UnmarshalJSON(data []byte) error   { return unmarshalWireJSON(cred, data) }
+
+func (proof *Proof) MarshalBinary() ([]byte, error)    { return marshalWire(proof) }
+func (proof *Proof) UnmarshalBinary(data []byte) error { return unmarshalWire(proof, data) }
+func (proof *Proof) MarshalJSON() ([]byte, error)      { return marshalWireJSON(proof) }
+func (proof *Proof) UnmarshalJSON(data []byte) error   { return unmarshalWireJSON(proof, data) }
+
+func (proof *AggregateProof) MarshalBinary() ([]byte, error)    { return marshalWire(proof) }
+func (proof *AggregateProof) UnmarshalBinary(data []byte) error { return unmarshalWire(proof, data) }
+func (proof *AggregateProof) MarshalJSON() ([]byte, error)      { return marshalWireJSON(proof) }
+func (proof *AggregateProof) UnmarshalJSON(data []byte) error   { return unmarshalWireJSON(proof, data) }
+
+func (proof *PiS) MarshalBinary() ([]byte, error)    { return marshalWire(proof) }
+func (proof *PiS) UnmarshalBinary(data []byte) error { return unmarshalWire(proof, data) }
+func (proof *PiS) MarshalJSON() ([]byte, error)      { return marshalWireJSON(proof) }
+func (proof *PiS) UnmarshalJSON(data []byte) error   { return unmarshalWireJSON(proof, data) }
+
+func (proof *PiV) MarshalBinary() ([]byte, error)    { return marshalWire(proof) }
+func (proof *PiV) UnmarshalBinary(data []byte) error { return unmarshalWire(proof, data) }
+func (proof *PiV) MarshalJSON() ([]byte, error)      { return marshalWireJSON(proof) }
+func (proof *PiV) UnmarshalJSON(data []byte) error   { return unmarshalWireJSON(proof, data) }
+
+func (proof *DL) MarshalBinary() ([]byte, error)    { return marshalWire(proof) }
+func (proof *DL) UnmarshalBinary(data []byte) error { return unmarshalWire(proof, data) }
+func (proof *DL) MarshalJSON() ([]byte, error)      { return marshalWireJSON(proof) }
+func (proof *DL) UnmarshalJSON(data []byte) error   { return unmarshalWireJSON(proof, data) }
+
+func (proof *DLEQ) MarshalBinary() ([]byte, error)    { return marshalWire(proof) }
+func (proof *DLEQ) UnmarshalBinary(data []byte) error { return unmarshalWire(proof, data) }
+func (proof *DLEQ) MarshalJSON() ([]byte, error)      { return marshalWireJSON(proof) }
+func (proof *DLEQ) UnmarshalJSON(data []byte) error   { return unmarshalWireJSON(proof, data) }
+
+func (proof *KeyBinding) MarshalBinary() ([]byte, error)    { return marshalWire(proof) }
+func (proof *KeyBinding) UnmarshalBinary(data []byte) error { return unmarshalWire(proof, data) }
+func (proof *KeyBinding) MarshalJSON() ([]byte, error)      { return marshalWireJSON(proof) }
+func (proof *KeyBinding) UnmarshalJSON(data []byte) error   { return unmarshalWireJSON(proof, data) }
+
+func (proof *SigmaProof) MarshalBinary() ([]byte, error)    { return marshalWire(proof) }
+func (proof *SigmaProof) UnmarshalBinary(data []byte) error { return unmarshalWire(proof, data) }
+func (proof *SigmaProof) MarshalJSON() ([]byte, error)      { return marshalWireJSON(proof) }
+func (proof *SigmaProof) UnmarshalJSON(data []byte) error   { return unmarshalWireJSON(proof, data) }
+
+func (proof *RangeProof) MarshalBinary() ([]byte, error)    { return marshalWire(proof) }
+func (proof *RangeProof) UnmarshalBinary(data []byte) error { return unmarshalWire(proof, data) }
+func (proof *RangeProof) MarshalJSON() ([]byte, error)      { return marshalWireJSON(proof) }
+func (proof *RangeProof) UnmarshalJSON(data []byte) error   { return unmarshalWireJSON(proof, data) }
+
+func (p *Predicate) MarshalBinary() ([]byte, error)    { return marshalWire(p) }
+func (p *Predicate) UnmarshalBinary(data []byte) error { return unmarshalWire(p, data) }
+func (p *Predicate) MarshalJSON() ([]byte, error)      { return marshalWireJSON(p) }
+func (p *Predicate) UnmarshalJSON(data []byte) error   { return unmarshalWireJSON(p, data) }
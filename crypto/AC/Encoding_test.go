@@ -0,0 +1,210 @@
+package AC
+
+import (
+	"Obfushop/bn256"
+	"bytes"
+	"encoding"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"strings"
+	"testing"
+)
+
+type wireValue interface {
+	encoding.BinaryMarshaler
+	encoding.BinaryUnmarshaler
+	json.Marshaler
+	json.Unmarshaler
+}
+
+type wireFixture struct {
+	params *Params
+	key    *IssuerKey
+	req    *Req
+	sig    *BlindSignature
+	cred   *Cred
+	proof  *Proof // with a range proof and a key binding
+	dl     *DL
+}
+
+func newWireFixture(t *testing.T) *wireFixture {
+	t.Helper()
+	f := &wireFixture{params: testParams(t)}
+	var err error
+	if f.key, err = KeyGen(f.params); err != nil {
+		t.Fatal(err)
+	}
+	m := []*big.Int{big.NewInt(7), big.NewInt(25)}
+	d, req, err := PrepareBlindSign(f.params, m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.req = req
+	if f.sig, err = BlindSign(f.params, f.key, req); err != nil {
+		t.Fatal(err)
+	}
+	if f.cred, err = ObtainCred(f.sig, d); err != nil {
+		t.Fatal(err)
+	}
+	sk := big.NewInt(42)
+	preds := []*Predicate{{Index: 1, Op: ">=", Bound: big.NewInt(18)}}
+	if f.proof, err = ProveCredBound(f.params, sk, f.key, f.cred, m, []bool{true, false}, preds, nil, nil); err != nil {
+		t.Fatal(err)
+	}
+	g := f.params.G1
+	if f.dl, err = DLProof(g, new(bn256.G1).ScalarMult(g, sk), sk); err != nil {
+		t.Fatal(err)
+	}
+	return f
+}
+
+func TestWireRoundTrip(t *testing.T) {
+	f := newWireFixture(t)
+	cases := []struct {
+		name  string
+		v     wireValue
+		fresh func() wireValue
+	}{
+		{"params", f.params, func() wireValue { return new(Params) }},
+		{"issuer key", f.key.Public(), func() wireValue { return new(IssuerKey) }},
+		{"req", f.req, func() wireValue { return new(Req) }},
+		{"blind signature", f.sig, func() wireValue { return new(BlindSignature) }},
+		{"cred", f.cred, func() wireValue { return new(Cred) }},
+		{"proof", f.proof, func() wireValue { return new(Proof) }},
+		{"pi_v", f.proof.PiV, func() wireValue { return new(PiV) }},
+		{"pi_s", f.req.PiS, func() wireValue { return new(PiS) }},
+		{"range proof", f.proof.Ranges[0], func() wireValue { return new(RangeProof) }},
+		{"predicate", &Predicate{Index: 1, Op: "<", Bound: big.NewInt(-5)}, func() wireValue { return new(Predicate) }},
+		{"key binding", f.proof.Binding, func() wireValue { return new(KeyBinding) }},
+		{"dl", f.dl, func() wireValue { return new(DL) }},
+		{"aggregate", &AggregateProof{Issuers: []string{"root"}, Parts: []*Proof{f.proof}}, func() wireValue { return new(AggregateProof) }},
+	}
+	for _, tt := range cases {
+		data, err := tt.v.MarshalBinary()
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		got := tt.fresh()
+		if err := got.UnmarshalBinary(data); err != nil {
+			t.Fatalf("%s: decoding binary: %v", tt.name, err)
+		}
+		again, err := got.MarshalBinary()
+		if err != nil || !bytes.Equal(again, data) {
+			t.Errorf("%s: binary encoding does not round-trip", tt.name)
+		}
+
+		js, err := tt.v.MarshalJSON()
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		got = tt.fresh()
+		if err := got.UnmarshalJSON(js); err != nil {
+			t.Fatalf("%s: decoding JSON: %v", tt.name, err)
+		}
+		again, err = got.MarshalJSON()
+		if err != nil || !bytes.Equal(again, js) {
+			t.Errorf("%s: JSON encoding does not round-trip", tt.name)
+		}
+	}
+
+	// A decoded presentation still verifies.
+	data, err := f.proof.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	proof := new(Proof)
+	if err := proof.UnmarshalBinary(data); err != nil {
+		t.Fatal(err)
+	}
+	pk1 := new(bn256.G1).ScalarMult(f.params.G1, big.NewInt(42))
+	if ok, err := VerifyCredBound(f.params, pk1, f.key, proof, proof.Preds, nil, nil); !ok {
+		t.Fatalf("decoded presentation does not verify: %v", err)
+	}
+}
+
+// editJSON decodes an encoding, applies edit to its fields and encodes it
+// again.
+func editJSON(t *testing.T, data []byte, edit func(map[string]any)) []byte {
+	t.Helper()
+	var fields map[string]any
+	if err := json.Unmarshal(data, &fields); err != nil {
+		t.Fatal(err)
+	}
+	edit(fields)
+	out, err := json.Marshal(fields)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return out
+}
+
+func TestWireRejects(t *testing.T) {
+	f := newWireFixture(t)
+	dlBin, err := f.dl.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	dlJSON, err := f.dl.MarshalJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	credBin, err := f.cred.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The DL encoding ends with rg, a point in G1, and the cred encoding
+	// with sigma, a point in G2.
+	modify := func(data []byte, edit func(b []byte)) []byte {
+		b := append([]byte(nil), data...)
+		edit(b)
+		return b
+	}
+	offCurveG1 := modify(dlBin, func(b []byte) { b[len(b)-1] ^= 1 })
+	identityG1 := modify(dlBin, func(b []byte) { copy(b[len(b)-g1Size:], make([]byte, g1Size)) })
+	offCurveG2 := modify(credBin, func(b []byte) { b[len(b)-1] ^= 1 })
+	identityG2 := modify(credBin, func(b []byte) { copy(b[len(b)-g2Size:], make([]byte, g2Size)) })
+
+	binary := []struct {
+		name string
+		v    wireValue
+		data []byte
+		want error
+	}{
+		{"trailing bytes", new(DL), append(append([]byte(nil), dlBin...), 0), ErrMalformedInput},
+		{"truncated", new(DL), dlBin[:len(dlBin)-1], ErrMalformedInput},
+		{"other version", new(DL), modify(dlBin, func(b []byte) { b[0] = WireVersion + 1 }), ErrMalformedInput},
+		{"other type", new(Cred), dlBin, ErrMalformedInput},
+		{"G1 point off the curve", new(DL), offCurveG1, ErrMalformedInput},
+		{"G1 identity", new(DL), identityG1, ErrIdentityPoint},
+		{"G2 point off the curve", new(Cred), offCurveG2, ErrMalformedInput},
+		{"G2 identity", new(Cred), identityG2, ErrIdentityPoint},
+	}
+	for _, tt := range binary {
+		if err := tt.v.UnmarshalBinary(tt.data); !errors.Is(err, tt.want) {
+			t.Errorf("binary, %s: %v, want %v", tt.name, err, tt.want)
+		}
+	}
+
+	zeroG1 := strings.Repeat("00", g1Size)
+	offCurveHex := hex.EncodeToString(offCurveG1[len(offCurveG1)-g1Size:])
+	jsonCases := []struct {
+		name string
+		data []byte
+		want error
+	}{
+		{"unknown key", editJSON(t, dlJSON, func(m map[string]any) { m["extra"] = 1 }), ErrMalformedInput},
+		{"missing key", editJSON(t, dlJSON, func(m map[string]any) { delete(m, "z") }), ErrMalformedInput},
+		{"trailing data", append(append([]byte(nil), dlJSON...), []byte(" {}")...), ErrMalformedInput},
+		{"G1 point off the curve", editJSON(t, dlJSON, func(m map[string]any) { m["rg"] = offCurveHex }), ErrMalformedInput},
+		{"G1 identity", editJSON(t, dlJSON, func(m map[string]any) { m["rg"] = zeroG1 }), ErrIdentityPoint},
+		{"short scalar", editJSON(t, dlJSON, func(m map[string]any) { m["c"] = "01" }), ErrMalformedInput},
+	}
+	for _, tt := range jsonCases {
+		if err := new(DL).UnmarshalJSON(tt.data); !errors.Is(err, tt.want) {
+			t.Errorf("JSON, %s: %v, want %v", tt.name, err, tt.want)
+		}
+	}
+}
@@ -0,0 +1,39 @@
+package AC
+
+import (
+	"fmt"
+	"math/big"
+	"time"
+)
+
+// A credential issued with PrepareEpochBlindSign carries the epoch it is
+// valid for (e.g. the year) as a signed attribute. A presentation discloses
+// that attribute only when it equals the current epoch, which every verifier
+// knows anyway, so nothing finer than the epoch, such as the issuance date,
+// is revealed. Claims like "Age>18" then lapse at the end of the epoch and
+// are re-issued under the same issuer key.
+
+// Validity names the epoch attribute of a credential and the current epoch.
+type Validity struct {
+	Index int    // attribute position of the epoch
+	Epoch uint64 // current epoch
+}
+
+// EpochAt returns the epoch containing t when epochs of length period are
+// counted from the Unix epoch. Periods are counted in whole seconds, so a
+// period that is not a positive number of seconds, or a time before 1970,
+// is rejected with ErrMalformedInput.
+func EpochAt(t time.Time, period time.Duration) (uint64, error) {
+	if period < time.Second || period%time.Second != 0 {
+		return 0, fmt.Errorf("%w: epoch period %v is not a whole number of seconds", ErrMalformedInput, period)
+	}
+	if t.Unix() < 0 {
+		return 0, fmt.Errorf("%w: time %v before the Unix epoch", ErrMalformedInput, t)
+	}
+	return uint64(t.Unix()) / uint64(period/time.Second), nil
+}
+
+// current reports whether the signed epoch m is the current one.
+func (v *Validity) current(m *big.Int) bool {
+	return m != nil && m.Cmp(new(big.Int).SetUint64(v.Epoch)) == 0
+}
@@ -0,0 +1,35 @@
+package AC
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestEpochAt(t *testing.T) {
+	at := time.Unix(1000, 0)
+	tests := []struct {
+		at     time.Time
+		period time.Duration
+		want   uint64
+		err    error
+	}{
+		{at, time.Second, 1000, nil},
+		{at, time.Minute, 16, nil},
+		{at.Add(999 * time.Millisecond), time.Second, 1000, nil},
+		{time.Unix(0, 0), time.Hour, 0, nil},
+		{at, 1500 * time.Millisecond, 0, ErrMalformedInput},
+		{at, time.Minute + time.Nanosecond, 0, ErrMalformedInput},
+		{at, 999 * time.Millisecond, 0, ErrMalformedInput},
+		{at, 0, 0, ErrMalformedInput},
+		{at, -time.Hour, 0, ErrMalformedInput},
+		{time.Unix(-1, 0), time.Second, 0, ErrMalformedInput},
+		{time.Date(1969, 12, 31, 0, 0, 0, 0, time.UTC), 24 * time.Hour, 0, ErrMalformedInput},
+	}
+	for _, tt := range tests {
+		got, err := EpochAt(tt.at, tt.period)
+		if !errors.Is(err, tt.err) || got != tt.want {
+			t.Errorf("EpochAt(%v, %v): %d, %v, want %d, %v", tt.at.Unix(), tt.period, got, err, tt.want, tt.err)
+		}
+	}
+}
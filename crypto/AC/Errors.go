@@ -0,0 +1,130 @@
+package AC
+
+import (
+	"Obfushop/bn256"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"math/big"
+)
+
+// Errors returned by the AC API. They are wrapped with details, so compare
+// with errors.Is.
+var (
+	ErrInvalidProof     = errors.New("AC: invalid proof")
+	ErrIdentityPoint    = errors.New("AC: identity point")
+	ErrBadRandomness    = errors.New("AC: cannot read randomness")
+	ErrScalarOutOfRange = errors.New("AC: scalar out of range")
+	ErrMalformedInput   = errors.New("AC: malformed input")
+	ErrUnknownIssuer    = errors.New("AC: unknown issuer")
+)
+
+// randScalar draws a uniform non-zero scalar mod order.
+func randScalar(order *big.Int) (*big.Int, error) {
+	for {
+		k, err := rand.Int(rand.Reader, order)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrBadRandomness, err)
+		}
+		if k.Sign() != 0 {
+			return k, nil
+		}
+	}
+}
+
+// randScalars draws n scalars with randScalar.
+func randScalars(order *big.Int, n int) ([]*big.Int, error) {
+	ks := make([]*big.Int, n)
+	for i := range ks {
+		k, err := randScalar(order)
+		if err != nil {
+			return nil, err
+		}
+		ks[i] = k
+	}
+	return ks, nil
+}
+
+// checkScalar rejects scalars outside [1, order) such as secret keys.
+func checkScalar(name string, k *big.Int) error {
+	if k == nil || k.Sign() <= 0 || k.Cmp(bn256.Order) >= 0 {
+		return fmt.Errorf("%w: %s", ErrScalarOutOfRange, name)
+	}
+	return nil
+}
+
+// checkResponse rejects proof responses outside [0, order).
+func checkResponse(k *big.Int) error {
+	if k == nil || k.Sign() < 0 || k.Cmp(bn256.Order) >= 0 {
+		return fmt.Errorf("%w: response out of range", ErrInvalidProof)
+	}
+	return nil
+}
+
+// checkResponses applies checkResponse to every entry of ks.
+func checkResponses(ks ...*big.Int) error {
+	for _, k := range ks {
+		if err := checkResponse(k); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkG1 rejects nil and identity points in G1.
+func checkG1(name string, p *bn256.G1) error {
+	if p == nil {
+		return fmt.Errorf("%w: %s missing", ErrMalformedInput, name)
+	}
+	if p.IsIdentity() {
+		return fmt.Errorf("%w: %s", ErrIdentityPoint, name)
+	}
+	return nil
+}
+
+// checkG2 rejects nil and identity points in G2.
+func checkG2(name string, p *bn256.G2) error {
+	if p == nil {
+		return fmt.Errorf("%w: %s missing", ErrMalformedInput, name)
+	}
+	if p.IsIdentity() {
+		return fmt.Errorf("%w: %s", ErrIdentityPoint, name)
+	}
+	return nil
+}
+
+// checkIssuerKey validates the public part of an issuer key.
+func checkIssuerKey(issuerkey *IssuerKey) error {
+	if issuerkey == nil {
+		return fmt.Errorf("%w: issuer key missing", ErrMalformedInput)
+	}
+	if err := checkG1("issuer key X", issuerkey.PK1); err != nil {
+		return err
+	}
+	for _, Y := range issuerkey.PK2 {
+		if err := checkG1("issuer key Y", Y); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkAttrs rejects missing attribute values and values that do not fit in
+// 256 bits. Attributes are not reduced mod the order since the contract
+// hashes them to full 256-bit integers.
+func checkAttrs(m []*big.Int) error {
+	for _, mi := range m {
+		if mi == nil || mi.Sign() < 0 {
+			return fmt.Errorf("%w: missing or negative attribute", ErrMalformedInput)
+		}
+		if mi.BitLen() > 256 {
+			return fmt.Errorf("%w: attribute longer than 256 bits", ErrMalformedInput)
+		}
+	}
+	return nil
+}
+
+// invalid wraps ErrInvalidProof with a reason.
+func invalid(reason string) error {
+	return fmt.Errorf("%w: %s", ErrInvalidProof, reason)
+}
@@ -0,0 +1,32 @@
+package AC
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+)
+
+func TestCheckAttrs(t *testing.T) {
+	params := testParams(t)
+	max := new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 256), big.NewInt(1))
+	tests := []struct {
+		name string
+		m    *big.Int
+		ok   bool
+	}{
+		{"zero", big.NewInt(0), true},
+		{"2^256-1", max, true},
+		{"2^256", new(big.Int).Add(max, big.NewInt(1)), false},
+		{"negative", big.NewInt(-1), false},
+		{"missing", nil, false},
+	}
+	for _, tt := range tests {
+		_, _, err := PrepareBlindSign(params, []*big.Int{big.NewInt(1), tt.m})
+		if tt.ok && err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+		}
+		if !tt.ok && !errors.Is(err, ErrMalformedInput) {
+			t.Errorf("%s: %v, want ErrMalformedInput", tt.name, err)
+		}
+	}
+}
@@ -0,0 +1,93 @@
+package AC
+
+import (
+	"Obfushop/bn256"
+	"fmt"
+	"math/big"
+)
+
+// A ProveCred presentation is tied to the buyer key through U = R^sk, where
+// R is the re-randomized credential base r·cred.U. VerifyCred checks that
+// sk against the G2 copy pk2 of the buyer key. A key-bound presentation
+// instead reveals R and proves
+//
+//	pk1 = g1^sk  ∧  U = R^sk
+//
+// so verifiers only need pkB = pk1 in G1. The contract checks the same
+// proof with VerifyKeyBinding; it cannot compute in G2, so the proof carries
+// its G2 commitment and the contract checks that one with a pairing.
+
+// KeyBinding proves that pk1 and the presentation share the secret sk.
+type KeyBinding struct {
+	R  *bn256.G2 // base of U, U = R^sk
+	C  *big.Int  // challenge
+	Z  *big.Int  // response for sk
+	A1 *bn256.G1 // commitment g1^z · pk1^c
+	A2 *bn256.G2 // commitment R^z · U^c
+}
+
+func keyBindingStatement(params *Params, pk1 *bn256.G1, R, U *bn256.G2) *Statement {
+	st := NewStatement("Obfushop/AC/key-binding")
+	sk := st.Witness("sk")
+	st.G1("pk", pk1, G1Term{params.G1, sk})
+	return st.G2("U", U, G2Term{R, sk})
+}
+
+func proveKeyBinding(params *Params, sk *big.Int, R, U *bn256.G2) (*KeyBinding, error) {
+	pk1 := new(bn256.G1).ScalarMult(params.G1, sk)
+	proof, A, err := keyBindingStatement(params, pk1, R, U).prove(NewTranscript("Obfushop/AC/key-binding"), []*big.Int{sk})
+	if err != nil {
+		return nil, err
+	}
+	return &KeyBinding{R: R, C: proof.C, Z: proof.R[0], A1: A.g1[0], A2: A.g2[1]}, nil
+}
+
+// VerifyKeyBinding checks that binding proves pk1 = g1^sk and U = R^sk for
+// the same sk.
+func VerifyKeyBinding(params *Params, pk1 *bn256.G1, U *bn256.G2, binding *KeyBinding) (bool, error) {
+	if err := checkG1("pk1", pk1); err != nil {
+		return false, err
+	}
+	if err := checkG2("U", U); err != nil {
+		return false, err
+	}
+	if binding == nil {
+		return false, invalid("key binding missing")
+	}
+	if err := checkG2("key binding base", binding.R); err != nil {
+		return false, err
+	}
+	if binding.A1 == nil || binding.A2 == nil {
+		return false, invalid("key binding commitment missing")
+	}
+	st := keyBindingStatement(params, pk1, binding.R, U)
+	A, err := st.verify(NewTranscript("Obfushop/AC/key-binding"), &SigmaProof{C: binding.C, R: []*big.Int{binding.Z}})
+	if err != nil {
+		return false, err
+	}
+	if !binding.A1.Equal(A.g1[0]) || !binding.A2.Equal(A.g2[1]) {
+		return false, invalid("key binding commitment mismatch")
+	}
+	return true, nil
+}
+
+// ProveCredBound is ProveCred for verifiers that only know pk1: the proof
+// carries a KeyBinding in place of the pk2 check. See VerifyCredBound.
+func ProveCredBound(params *Params, sk *big.Int, issuerkey *IssuerKey, cred *Cred, m []*big.Int, disclose []bool, preds []*Predicate, rl *RevocationList, now *Validity) (*Proof, error) {
+	return proveCred(params, sk, issuerkey, cred, m, disclose, preds, &presentOpts{rl: rl, now: now, bound: true})
+}
+
+// VerifyCredBound checks a presentation made by ProveCredBound for the buyer
+// key pk1. It accepts exactly what VerifyCred accepts for (pk1, g2^sk).
+func VerifyCredBound(params *Params, pk1 *bn256.G1, issuerkey *IssuerKey, proof *Proof, preds []*Predicate, rl *RevocationList, now *Validity) (bool, error) {
+	return verifyCred(params, pk1, nil, issuerkey, proof, preds, &presentOpts{rl: rl, now: now, bound: true})
+}
+
+// checkBinding checks the key binding of a bound presentation.
+func checkBinding(params *Params, pk1 *bn256.G1, proof *Proof) error {
+	if proof.Binding == nil {
+		return fmt.Errorf("%w: presentation has no key binding", ErrInvalidProof)
+	}
+	_, err := VerifyKeyBinding(params, pk1, proof.U, proof.Binding)
+	return err
+}
@@ -0,0 +1,100 @@
+package AC
+
+import (
+	"Obfushop/bn256"
+	"errors"
+	"math/big"
+	"testing"
+)
+
+func TestVerifyKeyBinding(t *testing.T) {
+	params := testParams(t)
+	sk, other := big.NewInt(42), big.NewInt(43)
+	pk1, _ := userKeys(params, sk)
+	otherPK1, _ := userKeys(params, other)
+	R := randomG2(t)
+	U := new(bn256.G2).ScalarMult(R, sk)
+	binding, err := proveKeyBinding(params, sk, R, U)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ok, err := VerifyKeyBinding(params, pk1, U, binding); !ok {
+		t.Fatalf("key binding: %v", err)
+	}
+
+	// U made with another secret than pk1.
+	otherU := new(bn256.G2).ScalarMult(R, other)
+	mixed, err := proveKeyBinding(params, sk, R, otherU)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tt := range []struct {
+		name    string
+		pk1     *bn256.G1
+		U       *bn256.G2
+		binding *KeyBinding
+	}{
+		{"another pk1", otherPK1, U, binding},
+		{"another U", pk1, otherU, binding},
+		{"U of another secret", pk1, otherU, mixed},
+		{"U of another secret, its pk1", otherPK1, otherU, mixed},
+		{"another R", pk1, U, &KeyBinding{R: randomG2(t), C: binding.C, Z: binding.Z, A1: binding.A1, A2: binding.A2}},
+		{"changed z", pk1, U, &KeyBinding{R: R, C: binding.C, Z: new(big.Int).Add(binding.Z, big.NewInt(1)), A1: binding.A1, A2: binding.A2}},
+		{"changed A1", pk1, U, &KeyBinding{R: R, C: binding.C, Z: binding.Z, A1: new(bn256.G1).Add(binding.A1, params.G1), A2: binding.A2}},
+		{"changed A2", pk1, U, &KeyBinding{R: R, C: binding.C, Z: binding.Z, A1: binding.A1, A2: new(bn256.G2).Add(binding.A2, params.G2)}},
+		{"no commitments", pk1, U, &KeyBinding{R: R, C: binding.C, Z: binding.Z}},
+		{"no binding", pk1, U, nil},
+	} {
+		if ok, err := VerifyKeyBinding(params, tt.pk1, tt.U, tt.binding); ok || !errors.Is(err, ErrInvalidProof) {
+			t.Errorf("%s: %v, want ErrInvalidProof", tt.name, err)
+		}
+	}
+}
+
+func TestVerifyCredBound(t *testing.T) {
+	params := testParams(t)
+	key, err := KeyGen(params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sk := big.NewInt(42)
+	m := []*big.Int{big.NewInt(30), big.NewInt(7)}
+	cred := issue(t, params, key, m)
+	disclose := []bool{true, false}
+	pk1, _ := userKeys(params, sk)
+	otherPK1, _ := userKeys(params, big.NewInt(43))
+
+	proof, err := ProveCredBound(params, sk, key, cred, m, disclose, nil, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ok, err := VerifyCredBound(params, pk1, key.Public(), proof, nil, nil, nil); !ok {
+		t.Fatalf("bound presentation: %v", err)
+	}
+	if ok, err := VerifyCredBound(params, otherPK1, key.Public(), proof, nil, nil, nil); ok || !errors.Is(err, ErrInvalidProof) {
+		t.Errorf("bound presentation under another pk1: %v, want ErrInvalidProof", err)
+	}
+
+	// A presentation for another secret keeps its own binding or borrows
+	// the one above; neither holds for pk1.
+	other, err := ProveCredBound(params, big.NewInt(43), key, cred, m, disclose, nil, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ok, err := VerifyCredBound(params, pk1, key.Public(), other, nil, nil, nil); ok || !errors.Is(err, ErrInvalidProof) {
+		t.Errorf("presentation with U of another secret: %v, want ErrInvalidProof", err)
+	}
+	borrowed := *other
+	borrowed.Binding = proof.Binding
+	if ok, err := VerifyCredBound(params, pk1, key.Public(), &borrowed, nil, nil, nil); ok || !errors.Is(err, ErrInvalidProof) {
+		t.Errorf("presentation with a borrowed binding: %v, want ErrInvalidProof", err)
+	}
+
+	unbound, err := ProveCred(params, sk, key, cred, m, disclose, nil, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ok, err := VerifyCredBound(params, pk1, key.Public(), unbound, nil, nil, nil); ok || !errors.Is(err, ErrInvalidProof) {
+		t.Errorf("presentation without a binding: %v, want ErrInvalidProof", err)
+	}
+}
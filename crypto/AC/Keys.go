@@ -0,0 +1,209 @@
+package AC
+
+import (
+	"Obfushop/bn256"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"golang.org/x/crypto/pbkdf2"
+)
+
+// Issuer keys can be derived from a seed so the issuer survives restarts,
+// and backed up as PEM blocks: the public key in the wire format, the secret
+// key as its scalars. Fingerprint identifies a public key and can be
+// recomputed from the X and Y_i uploaded with UploadACsParams.
+
+// SeedSize is the size of the seeds made by NewSeed and the minimum accepted
+// by KeyGenFromSeed.
+const SeedSize = 32
+
+// PEM block types.
+const (
+	PublicKeyPEMType = "OBFUSHOP AC ISSUER PUBLIC KEY"
+	SecretKeyPEMType = "OBFUSHOP AC ISSUER SECRET KEY"
+)
+
+const keyDomain = "Obfushop/AC/issuer-key/v1"
+
+// NewSeed returns a fresh random seed for KeyGenFromSeed.
+func NewSeed() ([]byte, error) {
+	seed := make([]byte, SeedSize)
+	if _, err := rand.Read(seed); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrBadRandomness, err)
+	}
+	return seed, nil
+}
+
+// KeyGenFromSeed derives the issuer key for params from seed. The same seed
+// always gives the same key; y_i depends only on the seed and i, so a key
+// for more attributes extends the key for fewer.
+func KeyGenFromSeed(params *Params, seed []byte) (*IssuerKey, error) {
+	if len(seed) < SeedSize {
+		return nil, fmt.Errorf("%w: seed shorter than %d bytes", ErrMalformedInput, SeedSize)
+	}
+	x := seedScalar(params.Order, seed, 0)
+	ys := make([]*big.Int, len(params.Hs))
+	for i := range ys {
+		ys[i] = seedScalar(params.Order, seed, uint32(i+1))
+	}
+	return keyFromScalars(x, ys)
+}
+
+// SeedFromMnemonic turns a mnemonic and an optional passphrase into a seed
+// the way BIP-39 does (PBKDF2-HMAC-SHA512, 2048 rounds, salt "mnemonic" +
+// passphrase). Words are separated by single spaces before hashing; the
+// words themselves are not checked against a wordlist.
+func SeedFromMnemonic(mnemonic, passphrase string) ([]byte, error) {
+	words := strings.Fields(mnemonic)
+	if len(words) < 12 {
+		return nil, fmt.Errorf("%w: mnemonic needs at least 12 words", ErrMalformedInput)
+	}
+	return pbkdf2.Key([]byte(strings.Join(words, " ")), []byte("mnemonic"+passphrase), 2048, 64, sha512.New), nil
+}
+
+// KeyGenFromMnemonic is KeyGenFromSeed on SeedFromMnemonic(mnemonic, passphrase).
+func KeyGenFromMnemonic(params *Params, mnemonic, passphrase string) (*IssuerKey, error) {
+	seed, err := SeedFromMnemonic(mnemonic, passphrase)
+	if err != nil {
+		return nil, err
+	}
+	return KeyGenFromSeed(params, seed)
+}
+
+// seedScalar derives the index-th secret: HMAC-SHA512 of the domain, the
+// index and a counter under the seed, reduced mod order, with the counter
+// bumped in the negligible case the result is zero.
+func seedScalar(order *big.Int, seed []byte, index uint32) *big.Int {
+	for counter := uint32(0); ; counter++ {
+		mac := hmac.New(sha512.New, seed)
+		mac.Write([]byte(keyDomain))
+		mac.Write(binary.BigEndian.AppendUint32(nil, index))
+		mac.Write(binary.BigEndian.AppendUint32(nil, counter))
+		k := new(big.Int).SetBytes(mac.Sum(nil))
+		if k.Mod(k, order).Sign() != 0 {
+			return k
+		}
+	}
+}
+
+// keyFromScalars checks the secrets and computes the public key.
+func keyFromScalars(x *big.Int, ys []*big.Int) (*IssuerKey, error) {
+	if err := checkScalar("issuer secret x", x); err != nil {
+		return nil, err
+	}
+	Ys := make([]*bn256.G1, len(ys))
+	for i, y := range ys {
+		if err := checkScalar("issuer secret y", y); err != nil {
+			return nil, err
+		}
+		Ys[i] = new(bn256.G1).ScalarBaseMult(y)
+	}
+	return &IssuerKey{
+		SK1: x,
+		SK2: ys,
+		PK1: new(bn256.G1).ScalarBaseMult(x),
+		PK2: Ys,
+	}, nil
+}
+
+// Fingerprint returns the hex sha256 of X || Y_1 || .. || Y_q, each point as
+// 32-byte x and y coordinates. This is sha256(abi.encodePacked(...)) of the
+// G1Points on-chain, see IssuerKeyFingerprint in BC_SID.sol.
+func (issuerkey *IssuerKey) Fingerprint() (string, error) {
+	if err := checkIssuerKey(issuerkey); err != nil {
+		return "", err
+	}
+	h := sha256.New()
+	h.Write(issuerkey.PK1.Marshal())
+	for _, Y := range issuerkey.PK2 {
+		h.Write(Y.Marshal())
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// ExportPublicPEM encodes the public key as a PEM block holding its binary
+// wire encoding.
+func (issuerkey *IssuerKey) ExportPublicPEM() ([]byte, error) {
+	der, err := issuerkey.Public().MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	fp, err := issuerkey.Fingerprint()
+	if err != nil {
+		return nil, err
+	}
+	return pem.EncodeToMemory(&pem.Block{
+		Type:    PublicKeyPEMType,
+		Headers: map[string]string{"Fingerprint": fp},
+		Bytes:   der,
+	}), nil
+}
+
+// ExportSecretPEM encodes the secret scalars x, y_1..y_q as a PEM block. The
+// block is not encrypted; keep it like a private key.
+func (issuerkey *IssuerKey) ExportSecretPEM() ([]byte, error) {
+	der, err := marshalWire(&secretKey{x: issuerkey.SK1, ys: issuerkey.SK2})
+	if err != nil {
+		return nil, err
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: SecretKeyPEMType, Bytes: der}), nil
+}
+
+// ImportPublicPEM decodes the output of ExportPublicPEM. A Fingerprint
+// header, if present, must match the key.
+func ImportPublicPEM(data []byte) (*IssuerKey, error) {
+	block, err := decodePEM(data, PublicKeyPEMType)
+	if err != nil {
+		return nil, err
+	}
+	issuerkey := new(IssuerKey)
+	if err := issuerkey.UnmarshalBinary(block.Bytes); err != nil {
+		return nil, err
+	}
+	if want, ok := block.Headers["Fingerprint"]; ok {
+		fp, err := issuerkey.Fingerprint()
+		if err != nil {
+			return nil, err
+		}
+		if fp != strings.ToLower(want) {
+			return nil, fmt.Errorf("%w: fingerprint header does not match the key", ErrMalformedInput)
+		}
+	}
+	return issuerkey, nil
+}
+
+// ImportSecretPEM decodes the output of ExportSecretPEM and recomputes the
+// public key from the secrets.
+func ImportSecretPEM(data []byte) (*IssuerKey, error) {
+	block, err := decodePEM(data, SecretKeyPEMType)
+	if err != nil {
+		return nil, err
+	}
+	sk := new(secretKey)
+	if err := unmarshalWire(sk, block.Bytes); err != nil {
+		return nil, err
+	}
+	return keyFromScalars(sk.x, sk.ys)
+}
+
+func decodePEM(data []byte, typ string) (*pem.Block, error) {
+	block, rest := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("%w: no PEM block", ErrMalformedInput)
+	}
+	if block.Type != typ {
+		return nil, fmt.Errorf("%w: PEM block is %q, want %q", ErrMalformedInput, block.Type, typ)
+	}
+	if len(strings.TrimSpace(string(rest))) != 0 {
+		return nil, fmt.Errorf("%w: trailing data after PEM block", ErrMalformedInput)
+	}
+	return block, nil
+}
@@ -0,0 +1,186 @@
+package AC
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"strings"
+	"testing"
+)
+
+const testMnemonic = "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about"
+
+func sameKeys(a, b *IssuerKey) bool {
+	if a.SK1.Cmp(b.SK1) != 0 || !a.PK1.Equal(b.PK1) || len(a.SK2) != len(b.SK2) || len(a.PK2) != len(b.PK2) {
+		return false
+	}
+	for i := range a.SK2 {
+		if a.SK2[i].Cmp(b.SK2[i]) != 0 || !a.PK2[i].Equal(b.PK2[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+func TestKeyGenFromSeed(t *testing.T) {
+	params := testParams(t)
+	seed := bytes.Repeat([]byte{7}, SeedSize)
+	a, err := KeyGenFromSeed(params, seed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := KeyGenFromSeed(params, append([]byte(nil), seed...))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !sameKeys(a, b) {
+		t.Error("one seed gives two keys")
+	}
+	other, err := KeyGenFromSeed(params, bytes.Repeat([]byte{8}, SeedSize))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if other.SK1.Cmp(a.SK1) == 0 || other.SK2[0].Cmp(a.SK2[0]) == 0 {
+		t.Error("two seeds give the same secrets")
+	}
+	if a.SK2[0].Cmp(a.SK2[1]) == 0 || a.SK1.Cmp(a.SK2[0]) == 0 {
+		t.Error("secrets of one key repeat")
+	}
+
+	// A key for more attributes extends the key for fewer.
+	more, err := Setup(4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, err := KeyGenFromSeed(more, seed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.SK2, c.PK2 = c.SK2[:2], c.PK2[:2]
+	if !sameKeys(a, c) {
+		t.Error("key for 4 attributes does not extend the key for 2")
+	}
+
+	if _, err := KeyGenFromSeed(params, seed[:SeedSize-1]); !errors.Is(err, ErrMalformedInput) {
+		t.Errorf("short seed: %v, want ErrMalformedInput", err)
+	}
+}
+
+func TestKeyGenFromMnemonic(t *testing.T) {
+	// The first test vector of BIP-39.
+	seed, err := SeedFromMnemonic(testMnemonic, "TREZOR")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "c55257c360c07c72029aebc1b53c05ed0362ada38ead3e3e9efa3708e53495531f09a6987599d18264c1e1c92f2cf141630c7a3c4ab7c81b2f001698e7463b04"
+	if hex.EncodeToString(seed) != want {
+		t.Errorf("seed %x, want %s", seed, want)
+	}
+
+	// The key derived from it, pinned so that a change of the derivation
+	// is noticed.
+	params := testParams(t)
+	key, err := KeyGenFromMnemonic(params, testMnemonic, "TREZOR")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := key.SK1.Text(16); got != "8f1a74526b513132dd4f9a6cb07fa9d0a1b4268092c21728e6a4f0c9e205e4e" {
+		t.Errorf("x = %s", got)
+	}
+	fp, err := key.Fingerprint()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fp != "1a008927d0e36bcab1aab9d640346bb4daedbf9256791a3c669bd4b31b883d65" {
+		t.Errorf("fingerprint %s", fp)
+	}
+
+	spaced, err := KeyGenFromMnemonic(params, "  "+strings.ReplaceAll(testMnemonic, " ", "\n\t ")+" ", "TREZOR")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !sameKeys(spaced, key) {
+		t.Error("whitespace between the words changes the key")
+	}
+	other, err := KeyGenFromMnemonic(params, testMnemonic, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sameKeys(other, key) {
+		t.Error("passphrase does not change the key")
+	}
+	short := strings.Join(strings.Fields(testMnemonic)[:11], " ")
+	if _, err := KeyGenFromMnemonic(params, short, ""); !errors.Is(err, ErrMalformedInput) {
+		t.Errorf("11 words: %v, want ErrMalformedInput", err)
+	}
+}
+
+func TestKeyPEM(t *testing.T) {
+	params := testParams(t)
+	key, err := KeyGen(params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fp, err := key.Fingerprint()
+	if err != nil {
+		t.Fatal(err)
+	}
+	pub, err := key.ExportPublicPEM()
+	if err != nil {
+		t.Fatal(err)
+	}
+	sec, err := key.ExportSecretPEM()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	gotPub, err := ImportPublicPEM(pub)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if gotFP, _ := gotPub.Fingerprint(); gotFP != fp || gotPub.SK1 != nil {
+		t.Error("public key changed in PEM")
+	}
+	if !bytes.Contains(pub, []byte(fp)) {
+		t.Error("public PEM has no fingerprint header")
+	}
+	gotSec, err := ImportSecretPEM(sec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !sameKeys(gotSec, key) {
+		t.Error("secret key changed in PEM")
+	}
+
+	block, _ := pem.Decode(pub)
+	block.Headers["Fingerprint"] = strings.Repeat("0", len(fp))
+	wrongFP := pem.EncodeToMemory(block)
+	block, _ = pem.Decode(pub)
+	block.Bytes[len(block.Bytes)-1] ^= 1
+	corrupt := pem.EncodeToMemory(block)
+	block, _ = pem.Decode(sec)
+	copy(block.Bytes[len(block.Bytes)-scalarSize:], make([]byte, scalarSize))
+	zeroSecret := pem.EncodeToMemory(block)
+
+	tests := []struct {
+		name   string
+		data   []byte
+		decode func([]byte) (*IssuerKey, error)
+	}{
+		{"empty", nil, ImportPublicPEM},
+		{"garbage", []byte("-----BEGIN nothing"), ImportSecretPEM},
+		{"secret as public", sec, ImportPublicPEM},
+		{"public as secret", pub, ImportSecretPEM},
+		{"trailing data", append(append([]byte(nil), pub...), "junk"...), ImportPublicPEM},
+		{"two blocks", append(append([]byte(nil), sec...), sec...), ImportSecretPEM},
+		{"fingerprint mismatch", wrongFP, ImportPublicPEM},
+		{"corrupted point", corrupt, ImportPublicPEM},
+		{"zero secret", zeroSecret, ImportSecretPEM},
+	}
+	for _, tt := range tests {
+		if _, err := tt.decode(tt.data); err == nil {
+			t.Errorf("%s: imported", tt.name)
+		}
+	}
+}
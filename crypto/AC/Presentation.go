@@ -0,0 +1,120 @@
+package AC
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"sync"
+	"time"
+)
+
+// Session is what a verifier binds a presentation to: a fresh nonce it
+// handed out and the context the presentation is for, e.g. OrderContext.
+// Both are hashed into the Fiat–Shamir challenge of π_v, so a presentation
+// seen by one seller, or on-chain, does not verify for another order. For
+// RegisterNymSet the context is NymContext.
+type Session struct {
+	Nonce   []byte
+	Context []byte
+}
+
+// NonceSize is the length of the nonces handed out by NewNonce.
+const NonceSize = 32
+
+// NewNonce draws a random verifier nonce.
+func NewNonce() ([]byte, error) {
+	nonce := make([]byte, NonceSize)
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrBadRandomness, err)
+	}
+	return nonce, nil
+}
+
+// OrderContext is the presentation context for an order with a seller.
+func OrderContext(seller string, orderID string) []byte {
+	return []byte("order|" + seller + "|" + orderID)
+}
+
+// bytes encodes the session unambiguously for the challenge hash.
+func (sess *Session) bytes() []byte {
+	buf := []byte("Obfushop/AC/session")
+	for _, field := range [][]byte{sess.Nonce, sess.Context} {
+		buf = binary.BigEndian.AppendUint32(buf, uint32(len(field)))
+		buf = append(buf, field...)
+	}
+	return buf
+}
+
+// ProveCredFor builds a presentation that is not tied to any public key:
+// U and S are freshly re-randomized and Kappa is blinded, so two
+// presentations of the same credential are unlinkable beyond what they
+// disclose. The proof only verifies for sess, see VerifyCredFor.
+func ProveCredFor(params *Params, issuerkey *IssuerKey, cred *Cred, m []*big.Int, disclose []bool, preds []*Predicate, rl *RevocationList, now *Validity, sess *Session) (*Proof, error) {
+	if sess == nil || len(sess.Nonce) == 0 {
+		return nil, fmt.Errorf("%w: presentation needs a verifier nonce", ErrMalformedInput)
+	}
+	return proveCred(params, big.NewInt(1), issuerkey, cred, m, disclose, preds, &presentOpts{rl: rl, now: now, bind: sess.bytes()})
+}
+
+// VerifyCredFor checks a presentation made by ProveCredFor for sess.
+func VerifyCredFor(params *Params, issuerkey *IssuerKey, proof *Proof, preds []*Predicate, rl *RevocationList, now *Validity, sess *Session) (bool, error) {
+	if sess == nil || len(sess.Nonce) == 0 {
+		return false, fmt.Errorf("%w: presentation needs a verifier nonce", ErrMalformedInput)
+	}
+	return verifyCred(params, params.G1, params.G2, issuerkey, proof, preds, &presentOpts{rl: rl, now: now, bind: sess.bytes()})
+}
+
+// NonceStore hands out nonces and accepts each of them once, within ttl.
+type NonceStore struct {
+	ttl    time.Duration
+	mu     sync.Mutex
+	issued map[string]time.Time
+}
+
+// NewNonceStore returns a store whose nonces expire after ttl.
+func NewNonceStore(ttl time.Duration) *NonceStore {
+	return &NonceStore{ttl: ttl, issued: make(map[string]time.Time)}
+}
+
+// Issue returns a fresh nonce to send to a prover.
+func (ns *NonceStore) Issue() ([]byte, error) {
+	nonce, err := NewNonce()
+	if err != nil {
+		return nil, err
+	}
+	ns.mu.Lock()
+	defer ns.mu.Unlock()
+	now := time.Now()
+	for key, at := range ns.issued {
+		if now.Sub(at) > ns.ttl {
+			delete(ns.issued, key)
+		}
+	}
+	ns.issued[hex.EncodeToString(nonce)] = now
+	return nonce, nil
+}
+
+// Consume reports whether nonce was issued, has not expired and has not
+// been used before. It can succeed only once per nonce.
+func (ns *NonceStore) Consume(nonce []byte) bool {
+	key := hex.EncodeToString(nonce)
+	ns.mu.Lock()
+	defer ns.mu.Unlock()
+	at, ok := ns.issued[key]
+	if !ok {
+		return false
+	}
+	delete(ns.issued, key)
+	return time.Since(at) <= ns.ttl
+}
+
+// VerifyFreshCred is VerifyCredFor for a nonce taken from ns. The nonce is
+// used up even if the proof is rejected, so every attempt needs a new one.
+func VerifyFreshCred(params *Params, issuerkey *IssuerKey, proof *Proof, preds []*Predicate, rl *RevocationList, now *Validity, ns *NonceStore, sess *Session) (bool, error) {
+	if sess == nil || !ns.Consume(sess.Nonce) {
+		return false, invalid("nonce unknown, expired or already used")
+	}
+	return VerifyCredFor(params, issuerkey, proof, preds, rl, now, sess)
+}
@@ -0,0 +1,91 @@
+package AC
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+)
+
+func TestVerifyFreshCred(t *testing.T) {
+	params := testParams(t)
+	key, err := KeyGen(params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := []*big.Int{big.NewInt(30), big.NewInt(7)}
+	cred := issue(t, params, key, m)
+	disclose := []bool{true, false}
+	ns := NewNonceStore(time.Minute)
+	present := func(sess *Session) *Proof {
+		t.Helper()
+		proof, err := ProveCredFor(params, key, cred, m, disclose, nil, nil, nil, sess)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return proof
+	}
+	fresh := func(context []byte) *Session {
+		t.Helper()
+		nonce, err := ns.Issue()
+		if err != nil {
+			t.Fatal(err)
+		}
+		return &Session{Nonce: nonce, Context: context}
+	}
+
+	sess := fresh(OrderContext("seller", "order-1"))
+	proof := present(sess)
+	if ok, err := VerifyFreshCred(params, key.Public(), proof, nil, nil, nil, ns, sess); !ok {
+		t.Fatalf("fresh presentation: %v", err)
+	}
+	if ok, err := VerifyFreshCred(params, key.Public(), proof, nil, nil, nil, ns, sess); ok || !errors.Is(err, ErrInvalidProof) {
+		t.Errorf("replayed presentation: %v, want ErrInvalidProof", err)
+	}
+
+	// A presentation for another order, seller or nonce does not verify,
+	// and the nonce is used up by the failed attempt.
+	sess = fresh(OrderContext("seller", "order-2"))
+	proof = present(sess)
+	for _, other := range []*Session{
+		{Nonce: sess.Nonce, Context: OrderContext("seller", "order-3")},
+		{Nonce: sess.Nonce, Context: OrderContext("other seller", "order-2")},
+		{Nonce: sess.Nonce},
+	} {
+		if ok, err := VerifyCredFor(params, key.Public(), proof, nil, nil, nil, other); ok || !errors.Is(err, ErrInvalidProof) {
+			t.Errorf("presentation for %q checked for %q: %v, want ErrInvalidProof", sess.Context, other.Context, err)
+		}
+	}
+	other := fresh(sess.Context)
+	if ok, err := VerifyFreshCred(params, key.Public(), proof, nil, nil, nil, ns, other); ok || !errors.Is(err, ErrInvalidProof) {
+		t.Errorf("presentation checked for another nonce: %v, want ErrInvalidProof", err)
+	}
+	if ok, err := VerifyFreshCred(params, key.Public(), present(other), nil, nil, nil, ns, other); ok || !errors.Is(err, ErrInvalidProof) {
+		t.Errorf("nonce used by a failed attempt: %v, want ErrInvalidProof", err)
+	}
+
+	// Nonces the store did not issue or that expired are refused.
+	nonce, err := NewNonce()
+	if err != nil {
+		t.Fatal(err)
+	}
+	sess = &Session{Nonce: nonce}
+	if ok, err := VerifyFreshCred(params, key.Public(), present(sess), nil, nil, nil, ns, sess); ok || !errors.Is(err, ErrInvalidProof) {
+		t.Errorf("unknown nonce: %v, want ErrInvalidProof", err)
+	}
+	short := NewNonceStore(time.Millisecond)
+	if sess.Nonce, err = short.Issue(); err != nil {
+		t.Fatal(err)
+	}
+	proof = present(sess)
+	time.Sleep(5 * time.Millisecond)
+	if ok, err := VerifyFreshCred(params, key.Public(), proof, nil, nil, nil, short, sess); ok || !errors.Is(err, ErrInvalidProof) {
+		t.Errorf("expired nonce: %v, want ErrInvalidProof", err)
+	}
+
+	for _, sess := range []*Session{nil, {Context: OrderContext("seller", "order-1")}} {
+		if _, err := ProveCredFor(params, key, cred, m, disclose, nil, nil, nil, sess); !errors.Is(err, ErrMalformedInput) {
+			t.Errorf("presentation without a nonce: %v, want ErrMalformedInput", err)
+		}
+	}
+}
@@ -3,16 +3,36 @@ package AC
 import (
 	"Obfushop/bn256"
 	//"Obfushop/crypto/RDKG"
-	"crypto/rand"
-	"crypto/sha256"
+	"fmt"
 	"math/big"
 )
 
 type PiS struct {
-	C  *big.Int // challenge
-	Rk *big.Int // responses for k
-	Rm *big.Int // responses for m
-	Ro *big.Int // response for r
+	C  *big.Int   // challenge
+	Rk []*big.Int // responses for k_i
+	Rm []*big.Int // responses for m_i
+	Ro *big.Int   // response for o
+}
+
+// PiV proves knowledge of the hidden attributes mᵢ and the blinding t
+// behind kappa = g1^t · ∏ Yᵢ^mᵢ, and that every AttrLink is formed from
+// the same hidden mⱼ.
+type PiV struct {
+	C  *big.Int   // challenge
+	Rm []*big.Int // responses for hidden m_i
+	Rt *big.Int   // response for t
+	Rr []*big.Int // responses for the randomness of each blinded link
+}
+
+// AttrLink ties a G1 element P to the hidden attribute at Index inside π_v.
+// A blinded link is a commitment P = g1^R · Base^m, an unblinded one is a
+// deterministic tag P = Base^m.
+type AttrLink struct {
+	Index   int       // position in the hidden attribute vector
+	Base    *bn256.G1 //
+	P       *bn256.G1 //
+	Blinded bool      //
+	R       *big.Int  // opening of a blinded link, prover only
 }
 
 type DL struct {
@@ -21,168 +41,333 @@ type DL struct {
 	RG *bn256.G1 // responses for m
 }
 
-func ToChallenge(elements []*bn256.G2) *big.Int {
-	hasher := sha256.New()
-	for _, e := range elements {
-		hasher.Write(e.Marshal())
-	}
-	return new(big.Int).SetBytes(hasher.Sum(nil))
+// piSTranscript starts the transcript of π_s, bound to the parameters.
+func piSTranscript(params *Params) *Transcript {
+	t := NewTranscript("Obfushop/AC/pi_s")
+	t.AppendParams(params)
+	return t
 }
 
-func ToChallengeMixed(g1s []*bn256.G1, g2s []*bn256.G2) *big.Int {
-	hasher := sha256.New()
-	for _, g := range g1s {
-		hasher.Write(g.Marshal())
+// piSStatement is the statement behind π_s: the prover knows o, k_i, m_i
+// with cm = g1^o · ∏ h_i^m_i, and ciphertext[i] = (g2^k_i, gamma^k_i · u^m_i)
+// for u = HashG2(cm). Attributes in public are disclosed.
+func piSStatement(params *Params, gamma *bn256.G2, ciphertext [][]*bn256.G2, cm *bn256.G1, u *bn256.G2, public []*big.Int) *Statement {
+	st := NewStatement("Obfushop/AC/pi_s")
+	n := len(ciphertext)
+	o := st.Witness("o")
+	k := make([]int, n)
+	m := make([]int, n)
+	for i := 0; i < n; i++ {
+		k[i] = st.Witness(fmt.Sprintf("k%d", i))
 	}
-	for _, g := range g2s {
-		hasher.Write(g.Marshal())
+	for i := 0; i < n; i++ {
+		m[i] = st.Witness(fmt.Sprintf("m%d", i))
 	}
-	return new(big.Int).SetBytes(hasher.Sum(nil))
+	terms := []G1Term{{params.G1, o}}
+	for i := 0; i < n; i++ {
+		terms = append(terms, G1Term{params.Hs[i], m[i]})
+	}
+	st.G1("cm", cm, terms...)
+	for i := 0; i < n; i++ {
+		st.G2("a", ciphertext[i][0], G2Term{params.G2, k[i]})
+		st.G2("b", ciphertext[i][1], G2Term{gamma, k[i]}, G2Term{u, m[i]})
+		if public != nil && public[i] != nil {
+			st.Disclose(m[i], public[i])
+		}
+	}
+	return st
 }
 
-func MakePiS(params *Params, gamma *bn256.G2, ciphertext []*bn256.G2, cm *bn256.G1,
-	k *big.Int, o *big.Int, m *big.Int) (*PiS, error) {
-
-	// 1. Generate random witnesses
-	wo, _ := rand.Int(rand.Reader, params.Order)
-	wk, _ := rand.Int(rand.Reader, params.Order)
-	wm, _ := rand.Int(rand.Reader, params.Order)
-
-	// 2. h = HashG1(cm)
-	u, _ := bn256.HashG2(string(cm.Marshal()))
-
-	// 3. Compute Aw = g1^wk[i]
-	Aw := new(bn256.G2).ScalarMult(params.G2, wk)
-
-	// 4. Compute Bw[i] = gamma^wk * h^wm
-	Bw := new(bn256.G2).Add(new(bn256.G2).ScalarMult(gamma, wk), new(bn256.G2).ScalarMult(u, wm))
-
-	// 5. Compute Cw = g1^wr * h_1^wm_1
-	Cw := new(bn256.G1).Add(new(bn256.G1).ScalarMult(params.G1, wo), new(bn256.G1).ScalarMult(params.H1, wm))
-
-	// 6. Compute challenge
-	toHashG1 := []*bn256.G1{cm, Cw}
-	toHashG2 := []*bn256.G2{u, Aw, Bw}
-	c := ToChallengeMixed(toHashG1, toHashG2)
+// MakePiS proves the request is well formed. Attributes marked in public
+// are revealed to the issuer: their witness is zero, so Rm[i] = -c·m_i.
+func MakePiS(params *Params, gamma *bn256.G2, ciphertext [][]*bn256.G2, cm *bn256.G1,
+	k []*big.Int, o *big.Int, m []*big.Int, public []bool) (*PiS, error) {
 
-	// 7. Responses
-	ro := new(big.Int).Sub(wo, new(big.Int).Mul(c, o))
-	ro.Mod(ro, params.Order)
-
-	rk := new(big.Int).Sub(wk, new(big.Int).Mul(c, k))
-	rk.Mod(rk, params.Order)
-
-	rm := new(big.Int).Sub(wm, new(big.Int).Mul(c, m))
-	rm.Mod(rm, params.Order)
-
-	return &PiS{C: c, Rk: rk, Rm: rm, Ro: ro}, nil
+	if len(k) != len(m) || len(ciphertext) != len(m) || len(m) > len(params.Hs) {
+		return nil, fmt.Errorf("%w: mismatched attributes and ciphertexts", ErrMalformedInput)
+	}
+	u, err := bn256.HashG2(string(cm.Marshal()))
+	if err != nil {
+		return nil, err
+	}
+	disclosed := make([]*big.Int, len(m))
+	for i := range m {
+		if public != nil && public[i] {
+			disclosed[i] = m[i]
+		}
+	}
+	witness := append(append([]*big.Int{o}, k...), m...)
+	proof, err := piSStatement(params, gamma, ciphertext, cm, u, disclosed).Prove(piSTranscript(params), witness)
+	if err != nil {
+		return nil, err
+	}
+	n := len(m)
+	return &PiS{C: proof.C, Ro: proof.R[0], Rk: proof.R[1 : 1+n], Rm: proof.R[1+n:]}, nil
 }
 
-func VerifyPiS(params *Params, gamma *bn256.G2, ciphertext []*bn256.G2, cm *bn256.G1, proof *PiS) (bool, error) {
+// VerifyPiS checks π_s. public holds the attribute values revealed to the
+// issuer (nil entries are hidden) and may itself be nil. A proof that does
+// not verify is reported as an error wrapping ErrInvalidProof.
+func VerifyPiS(params *Params, gamma *bn256.G2, ciphertext [][]*bn256.G2, cm *bn256.G1, proof *PiS, public []*big.Int) (bool, error) {
+	if err := checkG2("gamma", gamma); err != nil {
+		return false, err
+	}
+	if err := checkG1("cm", cm); err != nil {
+		return false, err
+	}
+	if proof == nil {
+		return false, invalid("π_s missing")
+	}
+	n := len(ciphertext)
+	if len(proof.Rk) != n || len(proof.Rm) != n || n > len(params.Hs) {
+		return false, invalid("π_s does not match the request")
+	}
+	if public != nil && len(public) != n {
+		return false, invalid("public attributes do not match the request")
+	}
+	for i := 0; i < n; i++ {
+		if len(ciphertext[i]) != 2 || ciphertext[i][0] == nil || ciphertext[i][1] == nil {
+			return false, fmt.Errorf("%w: ciphertext is not a pair", ErrMalformedInput)
+		}
+	}
+	u, err := bn256.HashG2(string(cm.Marshal()))
+	if err != nil {
+		return false, err
+	}
+	responses := append(append([]*big.Int{proof.Ro}, proof.Rk...), proof.Rm...)
+	return piSStatement(params, gamma, ciphertext, cm, u, public).Verify(piSTranscript(params), &SigmaProof{C: proof.C, R: responses})
+}
 
-	// 1. h = HashG1(cm)
-	u, _ := bn256.HashG2(string(cm.Marshal()))
+// piVChallenge is the challenge of π_v. The contract rebuilds the same
+// transcript in verifyPiVLinks, with ys the Y_i of the hidden attributes.
+func piVChallenge(params *Params, ys []*bn256.G1, kappa, Aw *bn256.G1, links []*AttrLink, Bw []*bn256.G1,
+	u, s *bn256.G2, bind []byte) *big.Int {
+	t := NewTranscript("Obfushop/AC/pi_v")
+	t.AppendG1("g1", params.G1)
+	for _, Y := range ys {
+		t.AppendG1("y", Y)
+	}
+	t.AppendG1("kappa", kappa)
+	t.AppendG1("A", Aw)
+	for k, link := range links {
+		t.AppendG1("P", link.P)
+		t.AppendG1("base", link.Base)
+		t.AppendG1("B", Bw[k])
+	}
+	t.AppendG2("u", u)
+	t.AppendG2("s", s)
+	t.AppendMessage("bind", bind)
+	return t.Challenge("c")
+}
 
-	// 2. Recompute Aw, Bw
-	a := ciphertext[0]
-	b := ciphertext[1]
+// MakePiV binds the proof to the randomized credential (u, s) so it cannot
+// be replayed against another presentation, and to bind if not empty.
+func MakePiV(params *Params, ys []*bn256.G1, kappa *bn256.G1, u *bn256.G2, s *bn256.G2,
+	m []*big.Int, t *big.Int, links []*AttrLink, bind []byte) (*PiV, error) {
+	if len(ys) != len(m) {
+		return nil, fmt.Errorf("%w: mismatched hidden attributes and bases", ErrMalformedInput)
+	}
 
-	Aw := new(bn256.G2).Add(new(bn256.G2).ScalarMult(a, proof.C), new(bn256.G2).ScalarMult(params.G2, proof.Rk))
+	// 1. Generate random witnesses
+	wt, err := randScalar(params.Order)
+	if err != nil {
+		return nil, err
+	}
+	wm, err := randScalars(params.Order, len(m))
+	if err != nil {
+		return nil, err
+	}
+	wr := make([]*big.Int, len(links))
+	for k, link := range links {
+		if link.Index < 0 || link.Index >= len(m) {
+			return nil, fmt.Errorf("%w: attribute link index out of range", ErrMalformedInput)
+		}
+		if link.Blinded {
+			if wr[k], err = randScalar(params.Order); err != nil {
+				return nil, err
+			}
+		}
+	}
 
-	part := new(bn256.G2).Add(new(bn256.G2).ScalarMult(gamma, proof.Rk), new(bn256.G2).ScalarMult(u, proof.Rm))
+	// 2. Compute Aw = g1^wt * ∏ Y_i^wm_i, Bw[k] = g1^wr[k] * base[k]^wm[idx[k]]
+	Aw := new(bn256.G1).ScalarMult(params.G1, wt)
+	for i := range m {
+		Aw.Add(Aw, new(bn256.G1).ScalarMult(ys[i], wm[i]))
+	}
+	Bw := make([]*bn256.G1, len(links))
+	for k, link := range links {
+		Bw[k] = new(bn256.G1).ScalarMult(link.Base, wm[link.Index])
+		if link.Blinded {
+			Bw[k].Add(Bw[k], new(bn256.G1).ScalarMult(params.G1, wr[k]))
+		}
+	}
 
-	Bw := new(bn256.G2).Add(new(bn256.G2).ScalarMult(b, proof.C), part)
+	// 3. Compute challenge
+	c := piVChallenge(params, ys, kappa, Aw, links, Bw, u, s, bind)
 
-	// 3. Recompute Cw
-	Cw := new(bn256.G1).Add(new(bn256.G1).ScalarMult(cm, proof.C), new(bn256.G1).ScalarMult(params.G1, proof.Ro))
-	Cw.Add(Cw, new(bn256.G1).ScalarMult(params.H1, proof.Rm))
+	// 4. Responses
+	rt := new(big.Int).Sub(wt, new(big.Int).Mul(c, t))
+	rt.Mod(rt, params.Order)
 
-	// 4. Recompute challenge
-	toHashG1 := []*bn256.G1{cm, Cw}
-	toHashG2 := []*bn256.G2{u, Aw, Bw}
-	cPrime := ToChallengeMixed(toHashG1, toHashG2)
+	rm := make([]*big.Int, len(m))
+	for i := range m {
+		rm[i] = new(big.Int).Sub(wm[i], new(big.Int).Mul(c, m[i]))
+		rm[i].Mod(rm[i], params.Order)
+	}
+	var rr []*big.Int
+	for k, link := range links {
+		if !link.Blinded {
+			continue
+		}
+		r := new(big.Int).Sub(wr[k], new(big.Int).Mul(c, link.R))
+		rr = append(rr, r.Mod(r, params.Order))
+	}
 
-	return cPrime.Cmp(proof.C) == 0, nil
+	return &PiV{C: c, Rm: rm, Rt: rt, Rr: rr}, nil
 }
 
-func DLProof(G *bn256.G1, xG *bn256.G1, x *big.Int) *DL {
-	//生成承诺
-	r, _ := rand.Int(rand.Reader, bn256.Order)
-	rG := new(bn256.G1).ScalarMult(G, r)
-
-	// 计算挑战
-	new_hash := sha256.New()
-	new_hash.Write(xG.Marshal())
-	new_hash.Write(rG.Marshal())
+// VerifyPiV checks π_v. A proof that does not verify is reported as an
+// error wrapping ErrInvalidProof.
+func VerifyPiV(params *Params, ys []*bn256.G1, kappa *bn256.G1, u *bn256.G2, s *bn256.G2,
+	links []*AttrLink, bind []byte, proof *PiV) (bool, error) {
+	if proof == nil || kappa == nil || u == nil || s == nil || len(proof.Rm) != len(ys) {
+		return false, invalid("π_v does not match the presentation")
+	}
+	if err := checkResponses(append(append([]*big.Int{proof.C, proof.Rt}, proof.Rm...), proof.Rr...)...); err != nil {
+		return false, err
+	}
 
-	cb := new_hash.Sum(nil)
-	c := new(big.Int).SetBytes(cb)
-	c.Mod(c, bn256.Order)
+	// Recompute Aw = kappa^c * g1^rt * ∏ Y_i^rm_i
+	Aw := new(bn256.G1).Add(new(bn256.G1).ScalarMult(kappa, proof.C), new(bn256.G1).ScalarMult(params.G1, proof.Rt))
+	for i := range ys {
+		Aw.Add(Aw, new(bn256.G1).ScalarMult(ys[i], proof.Rm[i]))
+	}
+	// Recompute Bw[k] = P[k]^c * g1^rr[k] * base[k]^rm[idx[k]]
+	Bw := make([]*bn256.G1, len(links))
+	j := 0
+	for k, link := range links {
+		if link.Index < 0 || link.Index >= len(ys) || link.P == nil || link.Base == nil {
+			return false, invalid("π_v link out of range")
+		}
+		Bw[k] = new(bn256.G1).Add(new(bn256.G1).ScalarMult(link.P, proof.C), new(bn256.G1).ScalarMult(link.Base, proof.Rm[link.Index]))
+		if link.Blinded {
+			if j >= len(proof.Rr) {
+				return false, invalid("π_v link responses missing")
+			}
+			Bw[k].Add(Bw[k], new(bn256.G1).ScalarMult(params.G1, proof.Rr[j]))
+			j++
+		}
+	}
+	if j != len(proof.Rr) {
+		return false, invalid("π_v has extra link responses")
+	}
 
-	// 生成相应
-	z := new(big.Int).Mul(c, x)
-	z.Sub(r, z)
-	z.Mod(z, bn256.Order)
+	cPrime := piVChallenge(params, ys, kappa, Aw, links, Bw, u, s, bind)
+	if cPrime.Cmp(proof.C) != 0 {
+		return false, invalid("π_v challenge mismatch")
+	}
+	return true, nil
+}
 
-	return &DL{C: c, Z: z, RG: rG}
+func dlStatement(G, xG *bn256.G1) *Statement {
+	st := NewStatement("Obfushop/AC/dl")
+	return st.G1("xG", xG, G1Term{G, st.Witness("x")})
 }
 
-// Verify verifies the DLEQ proof
-func VerifyDL(c, z *big.Int, G, xG, rG *bn256.G1) bool {
-	zG := new(bn256.G1).ScalarMult(G, z)
-	cxG := new(bn256.G1).ScalarMult(xG, c)
-	a := new(bn256.G1).Add(zG, cxG)
-	if !(rG.String() == a.String()) {
-		return false
+// DLProof proves knowledge of x with xG = G^x. RG is the commitment
+// G^z · xG^c, which the contract's DLVerify checks.
+func DLProof(G *bn256.G1, xG *bn256.G1, x *big.Int) (*DL, error) {
+	if err := checkScalar("x", x); err != nil {
+		return nil, err
 	}
-	return true
+	proof, A, err := dlStatement(G, xG).prove(NewTranscript("Obfushop/AC/dl"), []*big.Int{x})
+	if err != nil {
+		return nil, err
+	}
+	return &DL{C: proof.C, Z: proof.R[0], RG: A.g1[0]}, nil
 }
 
-// func DLEQProof(params *Params, _g1, _g2, _g3 *bn256.G1, _x *big.Int, _xG1, _xG2, _xG3 *bn256.G1) (*DLEQ, error) {
-
-// 	//生成承诺
-// 	k, err := rand.Int(rand.Reader, params.Order)
-// 	if err != nil {
-// 		return nil, err
-// 	}
-// 	kG1 := new(bn256.G1).ScalarMult(_g1, k)
-// 	kG2 := new(bn256.G1).ScalarMult(_g2, k)
-// 	kG3 := new(bn256.G1).ScalarMult(_g3, k)
-
-// 	// 计算挑战
-// 	new_hash := sha256.New()
-// 	new_hash.Write(_xG1.Marshal())
-// 	new_hash.Write(_xG2.Marshal())
-// 	new_hash.Write(_xG3.Marshal())
-// 	new_hash.Write(kG1.Marshal())
-// 	new_hash.Write(kG2.Marshal())
-// 	new_hash.Write(kG3.Marshal())
+// VerifyDL checks a DL proof.
+func VerifyDL(c, z *big.Int, G, xG, rG *bn256.G1) (bool, error) {
+	if err := checkG1("G", G); err != nil {
+		return false, err
+	}
+	if err := checkG1("xG", xG); err != nil {
+		return false, err
+	}
+	if rG == nil {
+		return false, invalid("DL commitment missing")
+	}
+	A, err := dlStatement(G, xG).verify(NewTranscript("Obfushop/AC/dl"), &SigmaProof{C: c, R: []*big.Int{z}})
+	if err != nil {
+		return false, err
+	}
+	if !rG.Equal(A.g1[0]) {
+		return false, invalid("DL commitment mismatch")
+	}
+	return true, nil
+}
 
-// 	cb := new_hash.Sum(nil)
-// 	challenge := new(big.Int).SetBytes(cb)
-// 	challenge.Mod(challenge, params.Order)
+// DLEQ proves that log_{G_i} P_i is the same for all i.
+type DLEQ struct {
+	C *big.Int // challenge
+	Z *big.Int // response for x
+}
 
-// 	// 生成相应
-// 	response := new(big.Int).Mul(challenge, _x)
-// 	response.Sub(k, response)
-// 	response.Mod(response, params.Order)
+func dleqStatement(bases, points []*bn256.G1) (*Statement, error) {
+	if len(bases) != len(points) || len(bases) == 0 {
+		return nil, fmt.Errorf("%w: DLEQ needs as many points as bases", ErrMalformedInput)
+	}
+	st := NewStatement("Obfushop/AC/dleq")
+	x := st.Witness("x")
+	for i := range bases {
+		if err := checkG1("DLEQ base", bases[i]); err != nil {
+			return nil, err
+		}
+		if err := checkG1("DLEQ point", points[i]); err != nil {
+			return nil, err
+		}
+		st.G1("P", points[i], G1Term{bases[i], x})
+	}
+	return st, nil
+}
 
-// 	return &DLEQ{C: challenge, Z: response, RG1: kG1, RG2: kG2, RG3: kG3}, nil
-// }
+// DLEQProof proves knowledge of x with points[i] = bases[i]^x for every i.
+func DLEQProof(bases, points []*bn256.G1, x *big.Int) (*DLEQ, error) {
+	if err := checkScalar("x", x); err != nil {
+		return nil, err
+	}
+	st, err := dleqStatement(bases, points)
+	if err != nil {
+		return nil, err
+	}
+	proof, err := st.Prove(NewTranscript("Obfushop/AC/dleq"), []*big.Int{x})
+	if err != nil {
+		return nil, err
+	}
+	return &DLEQ{C: proof.C, Z: proof.R[0]}, nil
+}
 
-// func VerifyDLEQ(params *Params, _g1, _g2, _g3 *bn256.G1, _xG1, _xG2, _xG3 *bn256.G1, Pi *DLEQ) bool {
+// VerifyDLEQ checks a DLEQProof for the same bases and points.
+func VerifyDLEQ(bases, points []*bn256.G1, proof *DLEQ) (bool, error) {
+	st, err := dleqStatement(bases, points)
+	if err != nil {
+		return false, err
+	}
+	if proof == nil {
+		return false, invalid("DLEQ proof missing")
+	}
+	return st.Verify(NewTranscript("Obfushop/AC/dleq"), &SigmaProof{C: proof.C, R: []*big.Int{proof.Z}})
+}
 
-// 	zG1 := new(bn256.G1).ScalarMult(_g1, Pi.Z)
-// 	zG2 := new(bn256.G1).ScalarMult(_g2, Pi.Z)
-// 	zG3 := new(bn256.G1).ScalarMult(_g3, Pi.Z)
-// 	cxG1 := new(bn256.G1).ScalarMult(_xG1, Pi.C)
-// 	cxG2 := new(bn256.G1).ScalarMult(_xG2, Pi.C)
-// 	cxG3 := new(bn256.G1).ScalarMult(_xG3, Pi.C)
-// 	a := new(bn256.G1).Add(zG1, cxG1)
-// 	b := new(bn256.G1).Add(zG2, cxG2)
-// 	c := new(bn256.G1).Add(zG3, cxG3)
-// 	if !(Pi.RG1.String() == a.String() && Pi.RG2.String() == b.String() && Pi.RG3.String() == c.String()) {
-// 		return false
-// 	}
-// 	return true
-// }
+// EqualCommitments is the statement that the Pedersen commitments
+// c1 = G1^r1 · h1^m and c2 = G1^r2 · h2^m open to the same m. Prove it
+// with the witness (m, r1, r2) on a transcript bound to the parameters
+// and whatever context the proof is for.
+func EqualCommitments(params *Params, c1, h1, c2, h2 *bn256.G1) *Statement {
+	st := NewStatement("Obfushop/AC/equal-commitments")
+	m, r1, r2 := st.Witness("m"), st.Witness("r1"), st.Witness("r2")
+	st.G1("c1", c1, G1Term{params.G1, r1}, G1Term{h1, m})
+	st.G1("c2", c2, G1Term{params.G1, r2}, G1Term{h2, m})
+	return st
+}
@@ -0,0 +1,63 @@
+package AC
+
+import (
+	"Obfushop/bn256"
+	"fmt"
+	"math/big"
+)
+
+// A credential can carry the user secret sk as a hidden attribute. For every
+// scope, e.g. a seller address, the holder then has exactly one pseudonym
+// nym = ScopeBase(scope)^sk: stable towards that seller, unlinkable across
+// sellers, and proven in π_v to come from the signed sk.
+
+// Pseudonym names the attribute holding sk and the scope to derive the nym for.
+type Pseudonym struct {
+	Index int    // attribute position of sk
+	Scope []byte // e.g. the seller address bytes
+}
+
+// ScopeBase hashes a scope to G1. The contract's ScopeBase computes the same
+// point for a seller address.
+func ScopeBase(scope []byte) *bn256.G1 {
+	base, _ := bn256.HashG1("Obfushop/AC/scope/" + string(scope))
+	return base
+}
+
+// Nym returns the pseudonym of sk in scope.
+func Nym(scope []byte, sk *big.Int) *bn256.G1 {
+	return new(bn256.G1).ScalarMult(ScopeBase(scope), sk)
+}
+
+// NymContext is the session context of a nym registered on-chain with
+// RegisterNymSet: the contract and seller addresses. The nonce is one the
+// holder draws with NewNonce; the contract accepts each nonce once.
+func NymContext(contract, seller []byte) []byte {
+	return append(append([]byte("nym|"), contract...), seller...)
+}
+
+// ProveNym builds an unlinkable presentation like ProveCredFor that also
+// shows proof.Nym for nym.Scope. The proof only verifies for sess.
+func ProveNym(params *Params, issuerkey *IssuerKey, cred *Cred, m []*big.Int, disclose []bool, preds []*Predicate, rl *RevocationList, now *Validity, nym *Pseudonym, sess *Session) (*Proof, error) {
+	if nym == nil {
+		return nil, fmt.Errorf("%w: no pseudonym scope given", ErrMalformedInput)
+	}
+	if sess == nil || len(sess.Nonce) == 0 {
+		return nil, fmt.Errorf("%w: presentation needs a verifier nonce", ErrMalformedInput)
+	}
+	opts := &presentOpts{rl: rl, now: now, nym: nym, bind: sess.bytes()}
+	return proveCred(params, big.NewInt(1), issuerkey, cred, m, disclose, preds, opts)
+}
+
+// VerifyNym checks a presentation made by ProveNym for sess. On success
+// proof.Nym is the holder's identity within nym.Scope.
+func VerifyNym(params *Params, issuerkey *IssuerKey, proof *Proof, preds []*Predicate, rl *RevocationList, now *Validity, nym *Pseudonym, sess *Session) (bool, error) {
+	if nym == nil {
+		return false, fmt.Errorf("%w: no pseudonym scope given", ErrMalformedInput)
+	}
+	if sess == nil || len(sess.Nonce) == 0 {
+		return false, fmt.Errorf("%w: presentation needs a verifier nonce", ErrMalformedInput)
+	}
+	opts := &presentOpts{rl: rl, now: now, nym: nym, bind: sess.bytes()}
+	return verifyCred(params, params.G1, params.G2, issuerkey, proof, preds, opts)
+}
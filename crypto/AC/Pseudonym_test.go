@@ -0,0 +1,88 @@
+package AC
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+)
+
+func TestPseudonym(t *testing.T) {
+	params := testParams(t)
+	key, err := KeyGen(params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sk := big.NewInt(42)
+	m := []*big.Int{big.NewInt(30), sk}
+	cred := issue(t, params, key, m)
+	disclose := []bool{true, false}
+	sellerA, sellerB := &Pseudonym{Index: 1, Scope: []byte("seller A")}, &Pseudonym{Index: 1, Scope: []byte("seller B")}
+	session := func() *Session {
+		t.Helper()
+		nonce, err := NewNonce()
+		if err != nil {
+			t.Fatal(err)
+		}
+		return &Session{Nonce: nonce, Context: []byte("nym test")}
+	}
+	present := func(nym *Pseudonym, sess *Session) *Proof {
+		t.Helper()
+		proof, err := ProveNym(params, key, cred, m, disclose, nil, nil, nil, nym, sess)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if ok, err := VerifyNym(params, key.Public(), proof, nil, nil, nil, nym, sess); !ok {
+			t.Fatalf("presentation for %q: %v", nym.Scope, err)
+		}
+		return proof
+	}
+
+	// Two presentations to one seller show the same nym, the holder's nym
+	// there; presentations to two sellers show unrelated ones.
+	sess1, sess2 := session(), session()
+	a1, a2 := present(sellerA, sess1), present(sellerA, sess2)
+	if !a1.Nym.Equal(a2.Nym) || !a1.Nym.Equal(Nym(sellerA.Scope, sk)) {
+		t.Error("presentations to one seller show different nyms")
+	}
+	if a1.Kappa.Equal(a2.Kappa) || a1.U.Equal(a2.U) {
+		t.Error("presentations to one seller share more than the nym")
+	}
+	b := present(sellerB, session())
+	if b.Nym.Equal(a1.Nym) {
+		t.Error("presentations to two sellers show the same nym")
+	}
+	pk1, _ := userKeys(params, sk)
+	if a1.Nym.Equal(pk1) || b.Nym.Equal(pk1) {
+		t.Error("nym is the holder's public key")
+	}
+
+	// The proof only holds for the scope it was made for and the nym of the
+	// signed secret.
+	if ok, err := VerifyNym(params, key.Public(), a1, nil, nil, nil, sellerB, sess1); ok || !errors.Is(err, ErrInvalidProof) {
+		t.Errorf("nym of seller A checked for seller B: %v, want ErrInvalidProof", err)
+	}
+	swapped := *a1
+	swapped.Nym = Nym(sellerA.Scope, big.NewInt(43))
+	if ok, err := VerifyNym(params, key.Public(), &swapped, nil, nil, nil, sellerA, sess1); ok || !errors.Is(err, ErrInvalidProof) {
+		t.Errorf("nym of another secret: %v, want ErrInvalidProof", err)
+	}
+	swapped.Nym = b.Nym
+	if ok, err := VerifyNym(params, key.Public(), &swapped, nil, nil, nil, sellerA, sess1); ok || !errors.Is(err, ErrInvalidProof) {
+		t.Errorf("nym of seller B in a presentation to seller A: %v, want ErrInvalidProof", err)
+	}
+	other, err := KeyGen(params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ok, err := VerifyNym(params, other.Public(), a1, nil, nil, nil, sellerA, sess1); ok || !errors.Is(err, ErrInvalidProof) {
+		t.Errorf("nym checked under another issuer key: %v, want ErrInvalidProof", err)
+	}
+
+	// The secret must be a hidden attribute.
+	if _, err := ProveNym(params, key, cred, m, []bool{false, true}, nil, nil, nil, sellerA, session()); !errors.Is(err, ErrMalformedInput) {
+		t.Errorf("disclosed nym secret: %v, want ErrMalformedInput", err)
+	}
+	if _, err := ProveNym(params, key, cred, m, disclose, nil, nil, nil, sellerA, &Session{}); !errors.Is(err, ErrMalformedInput) {
+		t.Errorf("nym without a nonce: %v, want ErrMalformedInput", err)
+	}
+}
@@ -0,0 +1,218 @@
+package AC
+
+import (
+	"Obfushop/bn256"
+	"fmt"
+	"math/big"
+	"sync"
+)
+
+// RangeBits is the bit length of the value a RangeProof shows to be
+// non-negative, so a predicate holds for |m - Bound| < 2^RangeBits.
+const RangeBits = 32
+
+var (
+	rangeBaseOnce sync.Once
+	rangeBase     *bn256.G1
+)
+
+// RangeBase returns the base h of the attribute commitments g1^r · h^m.
+// It is hashed to G1 so that nobody knows log_g1(h).
+func RangeBase() *bn256.G1 {
+	rangeBaseOnce.Do(func() {
+		rangeBase, _ = bn256.HashG1("Obfushop/AC/range-h")
+	})
+	return new(bn256.G1).Set(rangeBase)
+}
+
+// Predicate is a public comparison between a numeric attribute and a bound,
+// e.g. {Index: 1, Op: ">=", Bound: 18} for "age >= 18".
+type Predicate struct {
+	Index int      // attribute position in the credential
+	Op    string   // one of ">=", ">", "<=", "<"
+	Bound *big.Int // public threshold
+}
+
+// RangeProof shows that the attribute committed in Commit satisfies a
+// Predicate, by proving that the shifted value v is a sum of RangeBits bits.
+type RangeProof struct {
+	Commit *bn256.G1   // g1^r · h^m, linked to kappa by π_v
+	Bits   []*bn256.G1 // B_k = g1^s_k · h^b_k, with ∏ B_k^(2^k) = g1^r' · h^v
+	C0     []*big.Int  // OR-proof challenges for b_k = 0
+	C1     []*big.Int  // OR-proof challenges for b_k = 1
+	Z0     []*big.Int  // OR-proof responses for b_k = 0
+	Z1     []*big.Int  // OR-proof responses for b_k = 1
+}
+
+func (p *Predicate) String() string {
+	return fmt.Sprintf("m[%d] %s %v", p.Index, p.Op, p.Bound)
+}
+
+// Equal reports whether p and q state the same comparison.
+func (p *Predicate) Equal(q *Predicate) bool {
+	return p != nil && q != nil && p.Index == q.Index && p.Op == q.Op &&
+		p.Bound != nil && q.Bound != nil && p.Bound.Cmp(q.Bound) == 0
+}
+
+// shift returns (sign, offset) such that the predicate holds iff
+// v = sign·m + offset lies in [0, 2^RangeBits).
+func (p *Predicate) shift() (int, *big.Int, error) {
+	if p.Bound == nil {
+		return 0, nil, fmt.Errorf("%w: predicate without bound", ErrMalformedInput)
+	}
+	switch p.Op {
+	case ">=":
+		return 1, new(big.Int).Neg(p.Bound), nil
+	case ">":
+		return 1, new(big.Int).Sub(new(big.Int).Neg(p.Bound), big.NewInt(1)), nil
+	case "<=":
+		return -1, new(big.Int).Set(p.Bound), nil
+	case "<":
+		return -1, new(big.Int).Sub(p.Bound, big.NewInt(1)), nil
+	}
+	return 0, nil, fmt.Errorf("%w: unknown predicate operator %q", ErrMalformedInput, p.Op)
+}
+
+// Holds evaluates the predicate on a plain attribute value.
+func (p *Predicate) Holds(m *big.Int) bool {
+	sign, offset, err := p.shift()
+	if err != nil || m == nil {
+		return false
+	}
+	v := new(big.Int).Mul(big.NewInt(int64(sign)), m)
+	v.Add(v, offset)
+	return v.Sign() >= 0 && v.BitLen() <= RangeBits
+}
+
+// shiftedCommit maps the commitment to m onto the commitment to v.
+func (p *Predicate) shiftedCommit(commit *bn256.G1) (*bn256.G1, error) {
+	sign, offset, err := p.shift()
+	if err != nil {
+		return nil, err
+	}
+	V := new(bn256.G1).Set(commit)
+	if sign < 0 {
+		V.Neg(V)
+	}
+	off := new(big.Int).Mod(offset, bn256.Order)
+	return V.Add(V, new(bn256.G1).ScalarMult(RangeBase(), off)), nil
+}
+
+// proveRange builds the bit decomposition of v for commit = g1^r · h^m.
+// u is the randomized credential the proof is bound to.
+func proveRange(params *Params, pred *Predicate, m *big.Int, r *big.Int, commit *bn256.G1, u *bn256.G2) (*RangeProof, error) {
+	if !pred.Holds(m) {
+		return nil, fmt.Errorf("%w: attribute does not satisfy predicate %s", ErrMalformedInput, pred.String())
+	}
+	sign, offset, _ := pred.shift()
+	v := new(big.Int).Mul(big.NewInt(int64(sign)), m)
+	v.Add(v, offset)
+	rv := new(big.Int).Mul(big.NewInt(int64(sign)), r)
+	rv.Mod(rv, params.Order)
+
+	h := RangeBase()
+	V, err := pred.shiftedCommit(commit)
+	if err != nil {
+		return nil, err
+	}
+
+	// s_0 is fixed so that Σ 2^k·s_k = r_v and ∏ B_k^(2^k) = V exactly.
+	s := make([]*big.Int, RangeBits)
+	acc := new(big.Int)
+	for k := 1; k < RangeBits; k++ {
+		if s[k], err = randScalar(params.Order); err != nil {
+			return nil, err
+		}
+		acc.Add(acc, new(big.Int).Lsh(s[k], uint(k)))
+	}
+	s[0] = new(big.Int).Sub(rv, acc)
+	s[0].Mod(s[0], params.Order)
+
+	proof := &RangeProof{
+		Commit: commit,
+		Bits:   make([]*bn256.G1, RangeBits),
+		C0:     make([]*big.Int, RangeBits),
+		C1:     make([]*big.Int, RangeBits),
+		Z0:     make([]*big.Int, RangeBits),
+		Z1:     make([]*big.Int, RangeBits),
+	}
+	for k := 0; k < RangeBits; k++ {
+		b := v.Bit(k)
+		B := new(bn256.G1).ScalarMult(params.G1, s[k])
+		if b == 1 {
+			B.Add(B, h)
+		}
+		proof.Bits[k] = B
+
+		// Y0 = B, Y1 = B / h; the prover knows log_g1 of Y_b.
+		or, err := ProveOr(rangeTranscript(V, u, k), bitStatements(params, B, h), int(b), []*big.Int{s[k]})
+		if err != nil {
+			return nil, err
+		}
+		proof.C0[k], proof.C1[k] = or.C[0], or.C[1]
+		proof.Z0[k], proof.Z1[k] = or.R[0][0], or.R[1][0]
+	}
+	return proof, nil
+}
+
+// rangeTranscript starts the transcript of bit k, bound to the shifted
+// commitment and the randomized credential.
+func rangeTranscript(V *bn256.G1, u *bn256.G2, k int) *Transcript {
+	t := NewTranscript("Obfushop/AC/range")
+	t.AppendG1("V", V)
+	t.AppendG2("u", u)
+	t.AppendUint32("bit", uint32(k))
+	return t
+}
+
+// bitStatements are the branches of the bit proof for B = g1^s · h^b:
+// B = g1^s for b = 0 and B / h = g1^s for b = 1.
+func bitStatements(params *Params, B, h *bn256.G1) []*Statement {
+	zero := NewStatement("bit0")
+	zero.G1("B", B, G1Term{params.G1, zero.Witness("s")})
+	one := NewStatement("bit1")
+	one.G1("B/h", new(bn256.G1).Add(B, new(bn256.G1).Neg(h)), G1Term{params.G1, one.Witness("s")})
+	return []*Statement{zero, one}
+}
+
+// verifyRange checks a RangeProof for pred against the randomized credential
+// u and returns an error wrapping ErrInvalidProof if it does not hold.
+func verifyRange(params *Params, pred *Predicate, proof *RangeProof, u *bn256.G2) error {
+	if proof == nil || len(proof.Bits) != RangeBits ||
+		len(proof.C0) != RangeBits || len(proof.C1) != RangeBits ||
+		len(proof.Z0) != RangeBits || len(proof.Z1) != RangeBits {
+		return invalid("malformed range proof")
+	}
+	if err := checkG1("range commitment", proof.Commit); err != nil {
+		return err
+	}
+	V, err := pred.shiftedCommit(proof.Commit)
+	if err != nil {
+		return err
+	}
+
+	h := RangeBase()
+	sum := new(bn256.G1).ScalarBaseMult(big.NewInt(0))
+	for k := 0; k < RangeBits; k++ {
+		B := proof.Bits[k]
+		if B == nil {
+			return invalid("range proof bit commitment missing")
+		}
+		if err := checkResponses(proof.C0[k], proof.C1[k], proof.Z0[k], proof.Z1[k]); err != nil {
+			return err
+		}
+		sum.Add(sum, new(bn256.G1).ScalarMult(B, new(big.Int).Lsh(big.NewInt(1), uint(k))))
+
+		or := &OrProof{
+			C: []*big.Int{proof.C0[k], proof.C1[k]},
+			R: [][]*big.Int{{proof.Z0[k]}, {proof.Z1[k]}},
+		}
+		if _, err := VerifyOr(rangeTranscript(V, u, k), bitStatements(params, B, h), or); err != nil {
+			return invalid(fmt.Sprintf("range proof bit %d: %v", k, err))
+		}
+	}
+	if !sum.Equal(V) {
+		return invalid("range proof bits do not open the commitment")
+	}
+	return nil
+}
@@ -0,0 +1,194 @@
+package AC
+
+import (
+	"Obfushop/bn256"
+	"crypto/rand"
+	"errors"
+	"math/big"
+	"testing"
+)
+
+// rangeMax is the largest shifted value a RangeProof can show, 2^RangeBits - 1.
+var rangeMax = new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), RangeBits), big.NewInt(1))
+
+func testParams(t *testing.T) *Params {
+	t.Helper()
+	params, err := Setup(2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return params
+}
+
+func randomG2(t *testing.T) *bn256.G2 {
+	t.Helper()
+	_, u, err := bn256.RandomG2(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return u
+}
+
+// commitAttr returns g1^r · h^m for a fresh r.
+func commitAttr(t *testing.T, params *Params, m *big.Int) (*bn256.G1, *big.Int) {
+	t.Helper()
+	r, err := randScalar(params.Order)
+	if err != nil {
+		t.Fatal(err)
+	}
+	commit := new(bn256.G1).ScalarMult(params.G1, r)
+	commit.Add(commit, new(bn256.G1).ScalarMult(RangeBase(), new(big.Int).Mod(m, params.Order)))
+	return commit, r
+}
+
+func proveAttr(t *testing.T, params *Params, pred *Predicate, m *big.Int, u *bn256.G2) *RangeProof {
+	t.Helper()
+	commit, r := commitAttr(t, params, m)
+	proof, err := proveRange(params, pred, m, r, commit, u)
+	if err != nil {
+		t.Fatalf("%s for m = %v: %v", pred, m, err)
+	}
+	return proof
+}
+
+func TestPredicateHolds(t *testing.T) {
+	bound := big.NewInt(18)
+	tests := []struct {
+		op   string
+		m    *big.Int
+		want bool
+	}{
+		{">=", big.NewInt(18), true},
+		{">=", big.NewInt(17), false},
+		{">=", new(big.Int).Add(bound, rangeMax), true},
+		{">=", new(big.Int).Add(bound, new(big.Int).Add(rangeMax, big.NewInt(1))), false},
+		{">", big.NewInt(19), true},
+		{">", big.NewInt(18), false},
+		{"<=", big.NewInt(18), true},
+		{"<=", big.NewInt(19), false},
+		{"<=", new(big.Int).Sub(bound, rangeMax), true},
+		{"<=", new(big.Int).Sub(bound, new(big.Int).Add(rangeMax, big.NewInt(1))), false},
+		{"<", big.NewInt(17), true},
+		{"<", big.NewInt(18), false},
+		{"==", big.NewInt(18), false},
+	}
+	for _, tt := range tests {
+		p := &Predicate{Index: 0, Op: tt.op, Bound: bound}
+		if got := p.Holds(tt.m); got != tt.want {
+			t.Errorf("%s holds for m = %v: %v, want %v", p, tt.m, got, tt.want)
+		}
+	}
+	if (&Predicate{Op: ">="}).Holds(big.NewInt(1)) {
+		t.Error("predicate without bound holds")
+	}
+}
+
+func TestRangeProofEnds(t *testing.T) {
+	params := testParams(t)
+	u := randomG2(t)
+	bound := big.NewInt(1000)
+	one := big.NewInt(1)
+	tests := []struct {
+		op       string
+		low, top *big.Int // smallest and largest shifted value
+	}{
+		{">=", bound, new(big.Int).Add(bound, rangeMax)},
+		{">", new(big.Int).Add(bound, one), new(big.Int).Add(bound, new(big.Int).Add(rangeMax, one))},
+		{"<=", bound, new(big.Int).Sub(bound, rangeMax)},
+		{"<", new(big.Int).Sub(bound, one), new(big.Int).Sub(bound, new(big.Int).Add(rangeMax, one))},
+	}
+	for _, tt := range tests {
+		pred := &Predicate{Index: 0, Op: tt.op, Bound: bound}
+		for _, m := range []*big.Int{tt.low, tt.top} {
+			proof := proveAttr(t, params, pred, m, u)
+			if err := verifyRange(params, pred, proof, u); err != nil {
+				t.Errorf("%s, m = %v: %v", pred, m, err)
+			}
+		}
+	}
+}
+
+func TestRangeProofJustOutside(t *testing.T) {
+	params := testParams(t)
+	u := randomG2(t)
+	bound := big.NewInt(1000)
+	tests := []struct {
+		op      string
+		inside  *big.Int // the closest value that satisfies op bound
+		outside *big.Int // the closest value that does not
+		tighter *big.Int // bound for which inside is just outside
+	}{
+		{">=", big.NewInt(1000), big.NewInt(999), big.NewInt(1001)},
+		{">", big.NewInt(1001), big.NewInt(1000), big.NewInt(1001)},
+		{"<=", big.NewInt(1000), big.NewInt(1001), big.NewInt(999)},
+		{"<", big.NewInt(999), big.NewInt(1000), big.NewInt(999)},
+	}
+	for _, tt := range tests {
+		pred := &Predicate{Index: 0, Op: tt.op, Bound: bound}
+		commit, r := commitAttr(t, params, tt.outside)
+		if _, err := proveRange(params, pred, tt.outside, r, commit, u); !errors.Is(err, ErrMalformedInput) {
+			t.Errorf("%s: proving m = %v gave %v, want ErrMalformedInput", pred, tt.outside, err)
+		}
+
+		// A proof for the closest value inside must not pass for a bound
+		// one step tighter, where that value is outside.
+		proof := proveAttr(t, params, pred, tt.inside, u)
+		tighter := &Predicate{Index: 0, Op: tt.op, Bound: tt.tighter}
+		if tighter.Holds(tt.inside) {
+			t.Fatalf("%s holds for m = %v", tighter, tt.inside)
+		}
+		if err := verifyRange(params, tighter, proof, u); !errors.Is(err, ErrInvalidProof) {
+			t.Errorf("%s: proof for m = %v gave %v, want ErrInvalidProof", tighter, tt.inside, err)
+		}
+	}
+}
+
+func TestRangeProofReplay(t *testing.T) {
+	params := testParams(t)
+	u := randomG2(t)
+	pred := &Predicate{Index: 0, Op: ">=", Bound: big.NewInt(18)}
+	m := big.NewInt(42)
+	proof := proveAttr(t, params, pred, m, u)
+
+	if err := verifyRange(params, pred, proof, randomG2(t)); !errors.Is(err, ErrInvalidProof) {
+		t.Errorf("proof replayed against another u: %v, want ErrInvalidProof", err)
+	}
+
+	other, _ := commitAttr(t, params, m)
+	replayed := *proof
+	replayed.Commit = other
+	if err := verifyRange(params, pred, &replayed, u); !errors.Is(err, ErrInvalidProof) {
+		t.Errorf("proof replayed against another commitment: %v, want ErrInvalidProof", err)
+	}
+}
+
+func TestRangeProofTampered(t *testing.T) {
+	params := testParams(t)
+	u := randomG2(t)
+	pred := &Predicate{Index: 0, Op: "<", Bound: big.NewInt(100)}
+	proof := proveAttr(t, params, pred, big.NewInt(7), u)
+	if err := verifyRange(params, pred, proof, u); err != nil {
+		t.Fatal(err)
+	}
+
+	tamper := []struct {
+		name string
+		edit func(p *RangeProof)
+	}{
+		{"bit commitment", func(p *RangeProof) { p.Bits[3] = new(bn256.G1).Add(p.Bits[3], params.G1) }},
+		{"flipped bit", func(p *RangeProof) { p.Bits[0] = new(bn256.G1).Add(p.Bits[0], RangeBase()) }},
+		{"challenge", func(p *RangeProof) { p.C0[5] = new(big.Int).Add(p.C0[5], big.NewInt(1)) }},
+		{"response", func(p *RangeProof) { p.Z1[RangeBits-1] = new(big.Int).Add(p.Z1[RangeBits-1], big.NewInt(1)) }},
+		{"missing bit", func(p *RangeProof) { p.Bits = p.Bits[1:] }},
+	}
+	for _, tt := range tamper {
+		p := *proof
+		p.Bits = append([]*bn256.G1(nil), proof.Bits...)
+		p.C0 = append([]*big.Int(nil), proof.C0...)
+		p.Z1 = append([]*big.Int(nil), proof.Z1...)
+		tt.edit(&p)
+		if err := verifyRange(params, pred, &p, u); !errors.Is(err, ErrInvalidProof) {
+			t.Errorf("%s: %v, want ErrInvalidProof", tt.name, err)
+		}
+	}
+}
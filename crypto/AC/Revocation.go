@@ -0,0 +1,99 @@
+package AC
+
+import (
+	"Obfushop/bn256"
+	"math/big"
+	"strconv"
+	"sync"
+)
+
+// Revocation is verifier-local: every revocable credential carries a secret
+// handle as its last attribute, known to the issuer from PrepareRevocableBlindSign.
+// A presentation reveals the tag base(epoch)^handle together with a proof that
+// the tag is built from the signed handle. Tags of one credential in different
+// epochs are unlinkable, and anyone holding the revoked handles can compute
+// the tags to reject for the current epoch.
+
+// RevocationBase returns the tag base for an epoch, hashed to G1 so that no
+// discrete log between epochs is known.
+func RevocationBase(epoch uint64) *bn256.G1 {
+	base, _ := bn256.HashG1("Obfushop/AC/revocation/" + strconv.FormatUint(epoch, 10))
+	return base
+}
+
+// RevocationTag returns the tag a credential with the given handle shows in epoch.
+func RevocationTag(epoch uint64, handle *big.Int) *bn256.G1 {
+	return new(bn256.G1).ScalarMult(RevocationBase(epoch), handle)
+}
+
+// NewRevocationHandle draws a fresh handle for PrepareRevocableBlindSign.
+func NewRevocationHandle(params *Params) (*big.Int, error) {
+	return randScalar(params.Order)
+}
+
+// RevocationList is the issuer's list of revoked handles for one epoch.
+// Revoke, Tags and IsRevoked may be called concurrently; Epoch and Handles
+// are not to be changed directly while they are.
+type RevocationList struct {
+	Epoch   uint64
+	Handles []*big.Int
+
+	mu        sync.Mutex
+	tags      map[string]bool // tags of the first tagsCount handles in tagsEpoch
+	tagsEpoch uint64
+	tagsCount int
+}
+
+// NewRevocationList returns an empty list for epoch.
+func NewRevocationList(epoch uint64) *RevocationList {
+	return &RevocationList{Epoch: epoch}
+}
+
+// Revoke adds a handle to the list.
+func (rl *RevocationList) Revoke(handle *big.Int) {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+	rl.Handles = append(rl.Handles, handle)
+}
+
+// Tags returns the tags of the revoked handles in rl.Epoch, in the order they
+// were revoked. This is what RevokeTags publishes on-chain.
+func (rl *RevocationList) Tags() []*bn256.G1 {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+	return rl.computeTags()
+}
+
+func (rl *RevocationList) computeTags() []*bn256.G1 {
+	tags := make([]*bn256.G1, len(rl.Handles))
+	for i, handle := range rl.Handles {
+		tags[i] = RevocationTag(rl.Epoch, handle)
+	}
+	return tags
+}
+
+// IsRevoked reports whether a presented tag belongs to a revoked handle.
+// The tags are computed once and again only after the list changes.
+func (rl *RevocationList) IsRevoked(tag *bn256.G1) bool {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+	if rl.tags == nil || rl.tagsEpoch != rl.Epoch || rl.tagsCount != len(rl.Handles) {
+		rl.tags = make(map[string]bool, len(rl.Handles))
+		for _, t := range rl.computeTags() {
+			rl.tags[string(t.Marshal())] = true
+		}
+		rl.tagsEpoch, rl.tagsCount = rl.Epoch, len(rl.Handles)
+	}
+	return rl.tags[string(tag.Marshal())]
+}
+
+// FindHandle returns the handle among handles whose tag in epoch is tag, so the
+// issuer can tell which credential a misbehaving presentation came from.
+func FindHandle(epoch uint64, tag *bn256.G1, handles []*big.Int) (*big.Int, bool) {
+	for _, handle := range handles {
+		if RevocationTag(epoch, handle).Equal(tag) {
+			return handle, true
+		}
+	}
+	return nil, false
+}
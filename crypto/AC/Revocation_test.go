@@ -0,0 +1,116 @@
+package AC
+
+import (
+	"errors"
+	"math/big"
+	"sync"
+	"testing"
+)
+
+// Run with -race: IsRevoked builds its tag set while other goroutines
+// revoke and check.
+func TestRevocationListConcurrent(t *testing.T) {
+	rl := NewRevocationList(7)
+	handles := make([]*big.Int, 8)
+	for i := range handles {
+		handles[i] = big.NewInt(int64(1000 + i))
+	}
+	rl.Revoke(handles[0])
+
+	var wg sync.WaitGroup
+	for i := 1; i < len(handles); i++ {
+		wg.Add(2)
+		go func(h *big.Int) {
+			defer wg.Done()
+			rl.Revoke(h)
+		}(handles[i])
+		go func() {
+			defer wg.Done()
+			if !rl.IsRevoked(RevocationTag(7, handles[0])) {
+				t.Error("revoked handle not found")
+			}
+		}()
+	}
+	wg.Wait()
+
+	for _, h := range handles {
+		if !rl.IsRevoked(RevocationTag(7, h)) {
+			t.Errorf("handle %v revoked but not found", h)
+		}
+	}
+	if rl.IsRevoked(RevocationTag(8, handles[0])) {
+		t.Error("tag of another epoch is revoked")
+	}
+	if rl.IsRevoked(RevocationTag(7, big.NewInt(1))) {
+		t.Error("handle never revoked is revoked")
+	}
+	if n := len(rl.Tags()); n != len(handles) {
+		t.Errorf("%d tags, want %d", n, len(handles))
+	}
+}
+
+func TestVerifyCredRevocation(t *testing.T) {
+	params, err := Setup(2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	key, err := KeyGen(params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	handle, err := NewRevocationHandle(params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	d, req, err := PrepareRevocableBlindSign(params, []*big.Int{big.NewInt(30)}, handle)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sig, err := BlindSign(params, key, req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cred, err := ObtainCred(sig, d)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := []*big.Int{big.NewInt(30), handle}
+	sk := big.NewInt(42)
+	pk1, pk2 := userKeys(params, sk)
+	present := func(rl *RevocationList) *Proof {
+		t.Helper()
+		proof, err := ProveCred(params, sk, key, cred, m, []bool{true, false}, nil, rl, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return proof
+	}
+
+	rl7 := NewRevocationList(7)
+	rl7.Revoke(big.NewInt(1))
+	proof7 := present(rl7)
+	if ok, err := VerifyCred(params, pk1, pk2, key.Public(), proof7, nil, rl7, nil); !ok {
+		t.Fatalf("before revocation: %v", err)
+	}
+	rl7.Revoke(handle)
+	if ok, err := VerifyCred(params, pk1, pk2, key.Public(), proof7, nil, rl7, nil); ok || !errors.Is(err, ErrInvalidProof) {
+		t.Errorf("revoked handle: %v, want ErrInvalidProof", err)
+	}
+	if ok, err := VerifyCred(params, pk1, pk2, key.Public(), present(rl7), nil, rl7, nil); ok || !errors.Is(err, ErrInvalidProof) {
+		t.Errorf("new presentation of a revoked handle: %v, want ErrInvalidProof", err)
+	}
+
+	// In the next epoch the handle is only revoked if the issuer lists it
+	// again, and presentations of epoch 7 no longer verify.
+	rl8 := NewRevocationList(8)
+	if ok, err := VerifyCred(params, pk1, pk2, key.Public(), present(rl8), nil, rl8, nil); !ok {
+		t.Errorf("epoch 8 without the handle: %v", err)
+	}
+	if ok, err := VerifyCred(params, pk1, pk2, key.Public(), proof7, nil, rl8, nil); ok || !errors.Is(err, ErrInvalidProof) {
+		t.Errorf("epoch 7 presentation in epoch 8: %v, want ErrInvalidProof", err)
+	}
+	rl8.Revoke(handle)
+	if ok, err := VerifyCred(params, pk1, pk2, key.Public(), present(rl8), nil, rl8, nil); ok || !errors.Is(err, ErrInvalidProof) {
+		t.Errorf("handle revoked again in epoch 8: %v, want ErrInvalidProof", err)
+	}
+}
@@ -0,0 +1,407 @@
+package AC
+
+import (
+	"Obfushop/bn256"
+	"fmt"
+	"math/big"
+)
+
+// Sigma protocols for linear relations. A Statement lists secret scalars
+// (witnesses) and equations P = Σ base_k·w_k in G1 or G2 over them; a
+// SigmaProof shows knowledge of witnesses satisfying every equation at
+// once. The prover commits A = Σ base_k·ρ_k, appends the statement and the
+// commitments to a Transcript and takes the challenge c from it; the
+// responses are r = ρ - c·w mod order. The verifier recomputes
+// A = c·P + Σ base_k·r_k and the transcript. Whatever else the proof should
+// be bound to (parameters, a nonce) is appended to the transcript before.
+//
+// Witnesses are identified by name, so And of two statements that use the
+// same name proves the values are equal. A witness the verifier already
+// knows can be disclosed: its nonce is zero and its response must be -c·w.
+// Or proves one of several statements without telling which.
+
+// G1Term is Base·w for the witness at index Witness.
+type G1Term struct {
+	Base    *bn256.G1
+	Witness int
+}
+
+// G2Term is Base·w for the witness at index Witness.
+type G2Term struct {
+	Base    *bn256.G2
+	Witness int
+}
+
+type equation struct {
+	label string
+	p1    *bn256.G1 // left-hand side in G1, or
+	p2    *bn256.G2 // in G2
+	t1    []G1Term
+	t2    []G2Term
+}
+
+// Statement is a conjunction of linear relations over named witnesses.
+type Statement struct {
+	label     string
+	witnesses []string
+	disclosed map[int]*big.Int
+	eqs       []*equation
+}
+
+// SigmaProof is a non-interactive proof for a Statement: the challenge and
+// one response per witness.
+type SigmaProof struct {
+	C *big.Int
+	R []*big.Int
+}
+
+// OrProof proves one of several statements: a challenge and responses per
+// branch, the challenges summing to the hash of all branches.
+type OrProof struct {
+	C []*big.Int
+	R [][]*big.Int
+}
+
+// NewStatement returns an empty statement. The label separates the
+// challenges of different protocols.
+func NewStatement(label string) *Statement {
+	return &Statement{label: label, disclosed: make(map[int]*big.Int)}
+}
+
+// Witness returns the index of the witness called name, adding it if new.
+func (st *Statement) Witness(name string) int {
+	for i, w := range st.witnesses {
+		if w == name {
+			return i
+		}
+	}
+	st.witnesses = append(st.witnesses, name)
+	return len(st.witnesses) - 1
+}
+
+// Witnesses returns the number of witnesses.
+func (st *Statement) Witnesses() int {
+	return len(st.witnesses)
+}
+
+// G1 adds the relation P = Σ terms in G1.
+func (st *Statement) G1(label string, P *bn256.G1, terms ...G1Term) *Statement {
+	st.eqs = append(st.eqs, &equation{label: label, p1: P, t1: terms})
+	return st
+}
+
+// G2 adds the relation P = Σ terms in G2.
+func (st *Statement) G2(label string, P *bn256.G2, terms ...G2Term) *Statement {
+	st.eqs = append(st.eqs, &equation{label: label, p2: P, t2: terms})
+	return st
+}
+
+// Disclose makes witness w public with the given value.
+func (st *Statement) Disclose(w int, value *big.Int) *Statement {
+	st.disclosed[w] = value
+	return st
+}
+
+// And combines statements into one with the given label. Witnesses of the
+// same name are the same witness; the others keep their order.
+func And(label string, sts ...*Statement) *Statement {
+	and := NewStatement(label)
+	for _, st := range sts {
+		index := make([]int, len(st.witnesses))
+		for i, name := range st.witnesses {
+			index[i] = and.Witness(name)
+		}
+		for w, v := range st.disclosed {
+			and.disclosed[index[w]] = v
+		}
+		for _, eq := range st.eqs {
+			e := &equation{label: st.label + "/" + eq.label, p1: eq.p1, p2: eq.p2}
+			for _, t := range eq.t1 {
+				e.t1 = append(e.t1, G1Term{t.Base, index[t.Witness]})
+			}
+			for _, t := range eq.t2 {
+				e.t2 = append(e.t2, G2Term{t.Base, index[t.Witness]})
+			}
+			and.eqs = append(and.eqs, e)
+		}
+	}
+	return and
+}
+
+// check rejects statements with missing points or bad witness indexes.
+func (st *Statement) check() error {
+	if len(st.eqs) == 0 {
+		return fmt.Errorf("%w: statement %q has no relations", ErrMalformedInput, st.label)
+	}
+	for _, eq := range st.eqs {
+		if (eq.p1 == nil) == (eq.p2 == nil) {
+			return fmt.Errorf("%w: relation %q: left-hand side missing", ErrMalformedInput, eq.label)
+		}
+		for _, t := range eq.t1 {
+			if t.Base == nil || t.Witness < 0 || t.Witness >= len(st.witnesses) {
+				return fmt.Errorf("%w: relation %q: bad term", ErrMalformedInput, eq.label)
+			}
+		}
+		for _, t := range eq.t2 {
+			if t.Base == nil || t.Witness < 0 || t.Witness >= len(st.witnesses) {
+				return fmt.Errorf("%w: relation %q: bad term", ErrMalformedInput, eq.label)
+			}
+		}
+	}
+	return nil
+}
+
+// commitments are A_j in G1 or G2, one per equation.
+type commitments struct {
+	g1 []*bn256.G1
+	g2 []*bn256.G2
+}
+
+// commit computes A_j = c·P_j + Σ base·r. With c nil it is the prover's
+// Σ base·ρ.
+func (st *Statement) commit(c *big.Int, r []*big.Int) *commitments {
+	A := &commitments{g1: make([]*bn256.G1, len(st.eqs)), g2: make([]*bn256.G2, len(st.eqs))}
+	for j, eq := range st.eqs {
+		if eq.p1 != nil {
+			a := new(bn256.G1).ScalarBaseMult(big.NewInt(0))
+			if c != nil {
+				a.ScalarMult(eq.p1, c)
+			}
+			for _, t := range eq.t1 {
+				a.Add(a, new(bn256.G1).ScalarMult(t.Base, r[t.Witness]))
+			}
+			A.g1[j] = a
+		} else {
+			a := new(bn256.G2).ScalarBaseMult(big.NewInt(0))
+			if c != nil {
+				a.ScalarMult(eq.p2, c)
+			}
+			for _, t := range eq.t2 {
+				a.Add(a, new(bn256.G2).ScalarMult(t.Base, r[t.Witness]))
+			}
+			A.g2[j] = a
+		}
+	}
+	return A
+}
+
+// absorb appends the statement and its commitments to t.
+func (st *Statement) absorb(t *Transcript, A *commitments) {
+	t.AppendMessage("statement", []byte(st.label))
+	for w, name := range st.witnesses {
+		t.AppendMessage("witness", []byte(name))
+		if v, ok := st.disclosed[w]; ok {
+			t.AppendScalar("disclosed", v)
+		}
+	}
+	for j, eq := range st.eqs {
+		t.AppendMessage("relation", []byte(eq.label))
+		if eq.p1 != nil {
+			t.AppendG1("lhs", eq.p1)
+			for _, term := range eq.t1 {
+				t.AppendG1("base", term.Base)
+				t.AppendUint32("term", uint32(term.Witness))
+			}
+			t.AppendG1("commitment", A.g1[j])
+		} else {
+			t.AppendG2("lhs", eq.p2)
+			for _, term := range eq.t2 {
+				t.AppendG2("base", term.Base)
+				t.AppendUint32("term", uint32(term.Witness))
+			}
+			t.AppendG2("commitment", A.g2[j])
+		}
+	}
+}
+
+// responses returns ρ - c·w mod order.
+func responses(c *big.Int, nonces, witness []*big.Int) []*big.Int {
+	r := make([]*big.Int, len(witness))
+	for i := range witness {
+		r[i] = new(big.Int).Sub(nonces[i], new(big.Int).Mul(c, witness[i]))
+		r[i].Mod(r[i], bn256.Order)
+	}
+	return r
+}
+
+// nonces draws the prover's nonces, zero for disclosed witnesses.
+func (st *Statement) nonces() ([]*big.Int, error) {
+	rho, err := randScalars(bn256.Order, len(st.witnesses))
+	if err != nil {
+		return nil, err
+	}
+	for w := range st.disclosed {
+		rho[w] = big.NewInt(0)
+	}
+	return rho, nil
+}
+
+// checkWitness rejects witness vectors of the wrong length and disclosed
+// values that do not match.
+func (st *Statement) checkWitness(witness []*big.Int) error {
+	if len(witness) != len(st.witnesses) {
+		return fmt.Errorf("%w: statement %q has %d witnesses, got %d", ErrMalformedInput, st.label, len(st.witnesses), len(witness))
+	}
+	for i, w := range witness {
+		if w == nil {
+			return fmt.Errorf("%w: witness %q missing", ErrMalformedInput, st.witnesses[i])
+		}
+	}
+	for w, v := range st.disclosed {
+		d := new(big.Int).Sub(witness[w], v)
+		if d.Mod(d, bn256.Order).Sign() != 0 {
+			return fmt.Errorf("%w: disclosed witness %q does not match", ErrMalformedInput, st.witnesses[w])
+		}
+	}
+	return nil
+}
+
+// checkDisclosed checks r_w = -c·v for every disclosed witness.
+func (st *Statement) checkDisclosed(c *big.Int, r []*big.Int) error {
+	for w, v := range st.disclosed {
+		want := new(big.Int).Neg(new(big.Int).Mul(c, v))
+		if want.Mod(want, bn256.Order).Cmp(r[w]) != 0 {
+			return invalid(fmt.Sprintf("disclosed witness %q does not match", st.witnesses[w]))
+		}
+	}
+	return nil
+}
+
+// Prove proves knowledge of witness, indexed like the statement's
+// witnesses, on transcript t. It does not check the relations; a false
+// statement just gives a proof that does not verify.
+func (st *Statement) Prove(t *Transcript, witness []*big.Int) (*SigmaProof, error) {
+	proof, _, err := st.prove(t, witness)
+	return proof, err
+}
+
+func (st *Statement) prove(t *Transcript, witness []*big.Int) (*SigmaProof, *commitments, error) {
+	if err := st.check(); err != nil {
+		return nil, nil, err
+	}
+	if err := st.checkWitness(witness); err != nil {
+		return nil, nil, err
+	}
+	rho, err := st.nonces()
+	if err != nil {
+		return nil, nil, err
+	}
+	A := st.commit(nil, rho)
+	st.absorb(t, A)
+	c := t.Challenge("c")
+	return &SigmaProof{C: c, R: responses(c, rho, witness)}, A, nil
+}
+
+// Verify checks a proof made by Prove on a transcript with the same
+// contents as t. A proof that does not verify is reported as an error
+// wrapping ErrInvalidProof.
+func (st *Statement) Verify(t *Transcript, proof *SigmaProof) (bool, error) {
+	_, err := st.verify(t, proof)
+	return err == nil, err
+}
+
+func (st *Statement) verify(t *Transcript, proof *SigmaProof) (*commitments, error) {
+	if err := st.check(); err != nil {
+		return nil, err
+	}
+	if proof == nil || len(proof.R) != len(st.witnesses) {
+		return nil, invalid(fmt.Sprintf("%s: wrong number of responses", st.label))
+	}
+	if err := checkResponses(append([]*big.Int{proof.C}, proof.R...)...); err != nil {
+		return nil, err
+	}
+	if err := st.checkDisclosed(proof.C, proof.R); err != nil {
+		return nil, err
+	}
+	A := st.commit(proof.C, proof.R)
+	st.absorb(t, A)
+	if t.Challenge("c").Cmp(proof.C) != 0 {
+		return nil, invalid(fmt.Sprintf("%s: challenge mismatch", st.label))
+	}
+	return A, nil
+}
+
+// ProveOr proves on transcript t that the prover knows witness for
+// branches[which], without revealing which branch it is. The other
+// branches are simulated.
+func ProveOr(t *Transcript, branches []*Statement, which int, witness []*big.Int) (*OrProof, error) {
+	if which < 0 || which >= len(branches) {
+		return nil, fmt.Errorf("%w: no such branch", ErrMalformedInput)
+	}
+	for _, st := range branches {
+		if err := st.check(); err != nil {
+			return nil, err
+		}
+	}
+	if err := branches[which].checkWitness(witness); err != nil {
+		return nil, err
+	}
+	proof := &OrProof{C: make([]*big.Int, len(branches)), R: make([][]*big.Int, len(branches))}
+	As := make([]*commitments, len(branches))
+	sum := big.NewInt(0)
+	for i, st := range branches {
+		if i == which {
+			continue
+		}
+		c, err := randScalar(bn256.Order)
+		if err != nil {
+			return nil, err
+		}
+		r, err := randScalars(bn256.Order, len(st.witnesses))
+		if err != nil {
+			return nil, err
+		}
+		for w, v := range st.disclosed {
+			r[w] = new(big.Int).Neg(new(big.Int).Mul(c, v))
+			r[w].Mod(r[w], bn256.Order)
+		}
+		proof.C[i], proof.R[i] = c, r
+		As[i] = st.commit(c, r)
+		sum.Add(sum, c)
+	}
+	rho, err := branches[which].nonces()
+	if err != nil {
+		return nil, err
+	}
+	As[which] = branches[which].commit(nil, rho)
+	for i, st := range branches {
+		st.absorb(t, As[i])
+	}
+	c := t.Challenge("c")
+	proof.C[which] = c.Sub(c, sum).Mod(c, bn256.Order)
+	proof.R[which] = responses(proof.C[which], rho, witness)
+	return proof, nil
+}
+
+// VerifyOr checks a proof made by ProveOr with the same branches on a
+// transcript with the same contents as t.
+func VerifyOr(t *Transcript, branches []*Statement, proof *OrProof) (bool, error) {
+	if proof == nil || len(proof.C) != len(branches) || len(proof.R) != len(branches) {
+		return false, invalid("OR proof: wrong number of branches")
+	}
+	As := make([]*commitments, len(branches))
+	sum := big.NewInt(0)
+	for i, st := range branches {
+		if err := st.check(); err != nil {
+			return false, err
+		}
+		if len(proof.R[i]) != len(st.witnesses) {
+			return false, invalid(fmt.Sprintf("%s: wrong number of responses", st.label))
+		}
+		if err := checkResponses(append([]*big.Int{proof.C[i]}, proof.R[i]...)...); err != nil {
+			return false, err
+		}
+		if err := st.checkDisclosed(proof.C[i], proof.R[i]); err != nil {
+			return false, err
+		}
+		As[i] = st.commit(proof.C[i], proof.R[i])
+		sum.Add(sum, proof.C[i])
+	}
+	for i, st := range branches {
+		st.absorb(t, As[i])
+	}
+	if t.Challenge("c").Cmp(sum.Mod(sum, bn256.Order)) != 0 {
+		return false, invalid("OR proof: challenge mismatch")
+	}
+	return true, nil
+}
@@ -0,0 +1,207 @@
+package AC
+
+import (
+	"Obfushop/bn256"
+	"errors"
+	"math/big"
+	"testing"
+)
+
+func mustScalars(t *testing.T, n int) []*big.Int {
+	t.Helper()
+	ks, err := randScalars(bn256.Order, n)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return ks
+}
+
+// pedersenStatement is P = g^a · h^b in G1 and Q = g2^a in G2, with a shared.
+func pedersenStatement(a, b *big.Int) *Statement {
+	g := new(bn256.G1).ScalarBaseMult(big.NewInt(1))
+	h := RangeBase()
+	g2 := new(bn256.G2).ScalarBaseMult(big.NewInt(1))
+	P := new(bn256.G1).Add(new(bn256.G1).ScalarMult(g, a), new(bn256.G1).ScalarMult(h, b))
+	Q := new(bn256.G2).ScalarMult(g2, a)
+
+	st := NewStatement("test/pedersen")
+	wa, wb := st.Witness("a"), st.Witness("b")
+	st.G1("P", P, G1Term{g, wa}, G1Term{h, wb})
+	return st.G2("Q", Q, G2Term{g2, wa})
+}
+
+func copyProof(p *SigmaProof) *SigmaProof {
+	return &SigmaProof{C: new(big.Int).Set(p.C), R: append([]*big.Int(nil), p.R...)}
+}
+
+func TestSigmaCompleteness(t *testing.T) {
+	w := mustScalars(t, 2)
+	st := pedersenStatement(w[0], w[1])
+	proof, err := st.Prove(NewTranscript("test"), w)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ok, err := st.Verify(NewTranscript("test"), proof); !ok {
+		t.Fatalf("honest proof rejected: %v", err)
+	}
+
+	// And of two statements sharing "a" proves the same a in both.
+	a, b, c := w[0], w[1], mustScalars(t, 1)[0]
+	g := new(bn256.G1).ScalarBaseMult(big.NewInt(1))
+	other := NewStatement("test/other")
+	other.G1("R", new(bn256.G1).ScalarMult(g, new(big.Int).Add(a, c)), G1Term{g, other.Witness("a")}, G1Term{g, other.Witness("c")})
+	and := And("test/and", pedersenStatement(a, b), other)
+	if and.Witnesses() != 3 {
+		t.Fatalf("And has %d witnesses, want 3", and.Witnesses())
+	}
+	proof, err = and.Prove(NewTranscript("test"), []*big.Int{a, b, c})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ok, err := and.Verify(NewTranscript("test"), proof); !ok {
+		t.Fatalf("And proof rejected: %v", err)
+	}
+
+	// A disclosed witness verifies only with its disclosed value.
+	st = pedersenStatement(a, b).Disclose(1, b)
+	proof, err = st.Prove(NewTranscript("test"), []*big.Int{a, b})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ok, err := st.Verify(NewTranscript("test"), proof); !ok {
+		t.Fatalf("proof with a disclosed witness rejected: %v", err)
+	}
+	wrong := pedersenStatement(a, b).Disclose(1, new(big.Int).Add(b, big.NewInt(1)))
+	if ok, _ := wrong.Verify(NewTranscript("test"), proof); ok {
+		t.Error("proof verifies with a different disclosed value")
+	}
+	if _, err := wrong.Prove(NewTranscript("test"), []*big.Int{a, b}); !errors.Is(err, ErrMalformedInput) {
+		t.Errorf("proving with a mismatched disclosed value: %v, want ErrMalformedInput", err)
+	}
+}
+
+func TestSigmaSoundness(t *testing.T) {
+	w := mustScalars(t, 2)
+	st := pedersenStatement(w[0], w[1])
+
+	wrongWitness := []*big.Int{new(big.Int).Add(w[0], big.NewInt(1)), w[1]}
+	proof, err := st.Prove(NewTranscript("test"), wrongWitness)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := st.Verify(NewTranscript("test"), proof); !errors.Is(err, ErrInvalidProof) {
+		t.Errorf("proof with a wrong witness: %v, want ErrInvalidProof", err)
+	}
+
+	proof, err = st.Prove(NewTranscript("test"), w)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tamper := []struct {
+		name string
+		edit func(p *SigmaProof)
+	}{
+		{"response", func(p *SigmaProof) { p.R[1] = new(big.Int).Add(p.R[1], big.NewInt(1)) }},
+		{"challenge", func(p *SigmaProof) { p.C.Add(p.C, big.NewInt(1)) }},
+		{"response out of range", func(p *SigmaProof) { p.R[0] = new(big.Int).Add(p.R[0], bn256.Order) }},
+		{"missing response", func(p *SigmaProof) { p.R = p.R[:1] }},
+	}
+	for _, tt := range tamper {
+		p := copyProof(proof)
+		tt.edit(p)
+		if _, err := st.Verify(NewTranscript("test"), p); !errors.Is(err, ErrInvalidProof) {
+			t.Errorf("%s: %v, want ErrInvalidProof", tt.name, err)
+		}
+	}
+
+	if _, err := st.Verify(NewTranscript("other"), proof); !errors.Is(err, ErrInvalidProof) {
+		t.Errorf("proof on another transcript: %v, want ErrInvalidProof", err)
+	}
+	if _, err := st.Prove(NewTranscript("test"), w[:1]); !errors.Is(err, ErrMalformedInput) {
+		t.Errorf("proving with too few witnesses: %v, want ErrMalformedInput", err)
+	}
+}
+
+// orBranches are Y0 = g^x0 and Y1 = g^x1; only one x is needed.
+func orBranches(x0, x1 *big.Int) []*Statement {
+	g := new(bn256.G1).ScalarBaseMult(big.NewInt(1))
+	var branches []*Statement
+	for _, x := range []*big.Int{x0, x1} {
+		st := NewStatement("test/or")
+		st.G1("Y", new(bn256.G1).ScalarMult(g, x), G1Term{g, st.Witness("x")})
+		branches = append(branches, st)
+	}
+	return branches
+}
+
+func copyOr(p *OrProof) *OrProof {
+	q := &OrProof{}
+	for i := range p.C {
+		q.C = append(q.C, new(big.Int).Set(p.C[i]))
+		q.R = append(q.R, append([]*big.Int(nil), p.R[i]...))
+	}
+	return q
+}
+
+func TestOrProof(t *testing.T) {
+	x := mustScalars(t, 2)
+	branches := orBranches(x[0], x[1])
+
+	for which := range branches {
+		proof, err := ProveOr(NewTranscript("test"), branches, which, []*big.Int{x[which]})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if ok, err := VerifyOr(NewTranscript("test"), branches, proof); !ok {
+			t.Fatalf("branch %d: honest proof rejected: %v", which, err)
+		}
+
+		// The branch challenges sum to the challenge of the transcript.
+		tr := NewTranscript("test")
+		sum := new(big.Int)
+		for i, st := range branches {
+			st.absorb(tr, st.commit(proof.C[i], proof.R[i]))
+			sum.Add(sum, proof.C[i])
+		}
+		if tr.Challenge("c").Cmp(sum.Mod(sum, bn256.Order)) != 0 {
+			t.Errorf("branch %d: challenges do not sum to the transcript challenge", which)
+		}
+	}
+
+	// Knowing neither witness gives a proof that does not verify.
+	proof, err := ProveOr(NewTranscript("test"), branches, 0, []*big.Int{new(big.Int).Add(x[0], big.NewInt(1))})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := VerifyOr(NewTranscript("test"), branches, proof); !errors.Is(err, ErrInvalidProof) {
+		t.Errorf("OR proof with a wrong witness: %v, want ErrInvalidProof", err)
+	}
+
+	proof, err = ProveOr(NewTranscript("test"), branches, 1, []*big.Int{x[1]})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tamper := []struct {
+		name string
+		edit func(p *OrProof)
+	}{
+		{"one challenge", func(p *OrProof) { p.C[0].Add(p.C[0], big.NewInt(1)) }},
+		{"challenges shifted, same sum", func(p *OrProof) {
+			p.C[0].Add(p.C[0], big.NewInt(1))
+			p.C[1].Sub(p.C[1], big.NewInt(1))
+		}},
+		{"response", func(p *OrProof) { p.R[1][0] = new(big.Int).Add(p.R[1][0], big.NewInt(1)) }},
+		{"missing branch", func(p *OrProof) { p.C, p.R = p.C[:1], p.R[:1] }},
+	}
+	for _, tt := range tamper {
+		p := copyOr(proof)
+		tt.edit(p)
+		if _, err := VerifyOr(NewTranscript("test"), branches, p); !errors.Is(err, ErrInvalidProof) {
+			t.Errorf("%s: %v, want ErrInvalidProof", tt.name, err)
+		}
+	}
+
+	if _, err := ProveOr(NewTranscript("test"), branches, 2, []*big.Int{x[0]}); !errors.Is(err, ErrMalformedInput) {
+		t.Errorf("proving a branch that does not exist: %v, want ErrMalformedInput", err)
+	}
+}
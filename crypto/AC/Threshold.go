@@ -0,0 +1,223 @@
+package AC
+
+import (
+	"Obfushop/bn256"
+	"Obfushop/crypto/OABE"
+	"fmt"
+	"math/big"
+)
+
+// AuthorityKey is one issuing authority's share of a t-of-n issuer key.
+// Key holds the Shamir shares of x and y_i and the matching public shares,
+// so BlindSign can be called with it unchanged to get a partial signature.
+type AuthorityKey struct {
+	Index     *big.Int // Shamir x-coordinate of the share
+	Threshold int      // number of authorities needed to issue, t
+	Key       *IssuerKey
+}
+
+// Public returns the authority key without its secret shares, which is what
+// the party aggregating partial signatures needs.
+func (authority *AuthorityKey) Public() *AuthorityKey {
+	return &AuthorityKey{Index: authority.Index, Threshold: authority.Threshold, Key: authority.Key.Public()}
+}
+
+// TTPKeyGen lets a trusted dealer split a fresh issuer key into n authority
+// keys, any t of which can issue credentials. It returns the public part of
+// the aggregated key, which is what UploadACsParams publishes.
+func TTPKeyGen(params *Params, t, n int) (*IssuerKey, []*AuthorityKey, error) {
+	if t < 1 || t > n {
+		return nil, nil, fmt.Errorf("%w: threshold t must satisfy 1 <= t <= n", ErrMalformedInput)
+	}
+	master, err := KeyGen(params)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	xs, xShares, err := OABE.GenerateShares(master.SK1, t, n, params.Order, 1)
+	if err != nil {
+		return nil, nil, err
+	}
+	q := len(master.SK2)
+	yShares := make([][]*big.Int, q)
+	for j := 0; j < q; j++ {
+		_, yShares[j], err = OABE.GenerateShares(master.SK2[j], t, n, params.Order, 1)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	authorities := make([]*AuthorityKey, n)
+	for i := 0; i < n; i++ {
+		ys := make([]*big.Int, q)
+		Ys := make([]*bn256.G1, q)
+		for j := 0; j < q; j++ {
+			ys[j] = yShares[j][i]
+			Ys[j] = new(bn256.G1).ScalarBaseMult(ys[j])
+		}
+		authorities[i] = &AuthorityKey{
+			Index:     xs[i],
+			Threshold: t,
+			Key: &IssuerKey{
+				SK1: xShares[i],
+				SK2: ys,
+				PK1: new(bn256.G1).ScalarBaseMult(xShares[i]),
+				PK2: Ys,
+			},
+		}
+	}
+
+	return &IssuerKey{PK1: master.PK1, PK2: master.PK2}, authorities, nil
+}
+
+// checkIndexes rejects missing, zero and repeated authority indexes, for
+// which the Lagrange coefficients are undefined.
+func checkIndexes(indexes []*big.Int) error {
+	seen := make(map[string]bool)
+	for i, x := range indexes {
+		if x == nil || x.Sign() == 0 {
+			return fmt.Errorf("%w: authority index %d is missing or zero", ErrMalformedInput, i)
+		}
+		if seen[x.String()] {
+			return fmt.Errorf("%w: authority index %v given twice", ErrMalformedInput, x)
+		}
+		seen[x.String()] = true
+	}
+	return nil
+}
+
+// lagrangeAtZero returns λ_i for interpolating at 0 from the given indexes.
+func lagrangeAtZero(params *Params, indexes []*big.Int) ([]*big.Int, error) {
+	if err := checkIndexes(indexes); err != nil {
+		return nil, err
+	}
+	coeffs := OABE.LagrangeCoefficients(indexes, params.Order)
+	lambda := make([]*big.Int, len(indexes))
+	for i, x := range indexes {
+		lambda[i] = coeffs[x.String()]
+	}
+	return lambda, nil
+}
+
+// AggregateIssuerKey recombines the public key from t authorities' public
+// shares, e.g. to check it against the key uploaded on-chain.
+func AggregateIssuerKey(params *Params, keys []*IssuerKey, indexes []*big.Int) (*IssuerKey, error) {
+	if len(keys) == 0 || len(keys) != len(indexes) {
+		return nil, fmt.Errorf("%w: need one index per authority key", ErrMalformedInput)
+	}
+	lambda, err := lagrangeAtZero(params, indexes)
+	if err != nil {
+		return nil, err
+	}
+	q := len(keys[0].PK2)
+	pk1 := new(bn256.G1).ScalarBaseMult(big.NewInt(0))
+	pk2 := make([]*bn256.G1, q)
+	for j := range pk2 {
+		pk2[j] = new(bn256.G1).ScalarBaseMult(big.NewInt(0))
+	}
+	for i, key := range keys {
+		if len(key.PK2) != q {
+			return nil, fmt.Errorf("%w: authority keys cover different attribute counts", ErrMalformedInput)
+		}
+		pk1.Add(pk1, new(bn256.G1).ScalarMult(key.PK1, lambda[i]))
+		for j := 0; j < q; j++ {
+			pk2[j].Add(pk2[j], new(bn256.G1).ScalarMult(key.PK2[j], lambda[i]))
+		}
+	}
+	return &IssuerKey{PK1: pk1, PK2: pk2}, nil
+}
+
+// AggregateBlindSign combines partial blind signatures on req, each
+// produced by BlindSign under an AuthorityKey, into one BlindSignature under
+// the aggregated key. authorities[i] is the public authority key that made
+// sigs[i]; at least t of them are needed, all from different authorities.
+// Every share is checked against its authority's key, so one bad authority
+// cannot spoil the credential unnoticed. The error names the first share
+// that does not verify. The result is unblinded with ObtainCred as usual.
+func AggregateBlindSign(params *Params, req *Req, sigs []*BlindSignature, authorities []*AuthorityKey) (*BlindSignature, error) {
+	if len(sigs) == 0 || len(sigs) != len(authorities) {
+		return nil, fmt.Errorf("%w: need one authority key per partial signature", ErrMalformedInput)
+	}
+	if req == nil {
+		return nil, fmt.Errorf("%w: missing request", ErrMalformedInput)
+	}
+	if err := checkReq(req); err != nil {
+		return nil, err
+	}
+	indexes := make([]*big.Int, len(authorities))
+	for i, authority := range authorities {
+		if authority == nil || authority.Key == nil {
+			return nil, fmt.Errorf("%w: authority key %d missing", ErrMalformedInput, i)
+		}
+		if authority.Threshold < 1 || authority.Threshold != authorities[0].Threshold {
+			return nil, fmt.Errorf("%w: authority keys disagree on the threshold", ErrMalformedInput)
+		}
+		indexes[i] = authority.Index
+	}
+	if t := authorities[0].Threshold; len(sigs) < t {
+		return nil, fmt.Errorf("%w: %d partial signatures for a threshold of %d", ErrMalformedInput, len(sigs), t)
+	}
+	if err := checkIndexes(indexes); err != nil {
+		return nil, err
+	}
+	lambda, err := lagrangeAtZero(params, indexes)
+	if err != nil {
+		return nil, err
+	}
+	u, err := bn256.HashG2(string(req.Cm.Marshal()))
+	if err != nil {
+		return nil, err
+	}
+
+	_C := make([]*bn256.G2, 2)
+	_C[0] = new(bn256.G2).ScalarBaseMult(big.NewInt(0))
+	_C[1] = new(bn256.G2).ScalarBaseMult(big.NewInt(0))
+	for i, sig := range sigs {
+		if err := checkPartialSign(params, req, u, authorities[i].Key, sig); err != nil {
+			return nil, fmt.Errorf("partial signature %d: %w", i, err)
+		}
+		_C[0].Add(_C[0], new(bn256.G2).ScalarMult(sig.C[0], lambda[i]))
+		_C[1].Add(_C[1], new(bn256.G2).ScalarMult(sig.C[1], lambda[i]))
+	}
+
+	return &BlindSignature{
+		U: u,
+		C: _C,
+	}, nil
+}
+
+// checkPartialSign checks that sig is what BlindSign returns for req under
+// key, u = HashG2(cm), from the public key alone:
+//
+//	e(g1, ã) = ∏ e(Yᵢ, aᵢ)
+//	e(g1, b̃) = e(X, u) · ∏ e(Yᵢ, bᵢ)
+func checkPartialSign(params *Params, req *Req, u *bn256.G2, key *IssuerKey, sig *BlindSignature) error {
+	if sig == nil || len(sig.C) != 2 || sig.C[0] == nil || sig.C[1] == nil {
+		return fmt.Errorf("%w: blind signature", ErrMalformedInput)
+	}
+	if err := checkG2("u", sig.U); err != nil {
+		return err
+	}
+	if !sig.U.Equal(u) {
+		return invalid("not on the request")
+	}
+	if err := checkIssuerKey(key); err != nil {
+		return err
+	}
+	if len(req.C) != len(key.PK2) {
+		return fmt.Errorf("%w: %d attributes for an issuer key over %d", ErrMalformedInput, len(req.C), len(key.PK2))
+	}
+	a := []*bn256.G1{params.G1}
+	b := []*bn256.G1{params.G1, new(bn256.G1).Neg(key.PK1)}
+	as := []*bn256.G2{sig.C[0]}
+	bs := []*bn256.G2{sig.C[1], u}
+	for i, c := range req.C {
+		Y := new(bn256.G1).Neg(key.PK2[i])
+		a, as = append(a, Y), append(as, c[0])
+		b, bs = append(b, Y), append(bs, c[1])
+	}
+	if !bn256.PairingCheck(a, as) || !bn256.PairingCheck(b, bs) {
+		return invalid("does not verify under the authority key")
+	}
+	return nil
+}
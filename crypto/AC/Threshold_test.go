@@ -0,0 +1,166 @@
+package AC
+
+import (
+	"Obfushop/bn256"
+	"errors"
+	"math/big"
+	"strings"
+	"testing"
+)
+
+func TestAggregateBlindSign(t *testing.T) {
+	params := testParams(t)
+	master, authorities, err := TTPKeyGen(params, 2, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := []*big.Int{big.NewInt(7), big.NewInt(25)}
+	d, req, err := PrepareBlindSign(params, m)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Authorities 0 and 2 sign.
+	var sigs []*BlindSignature
+	var signers []*AuthorityKey
+	for _, a := range []*AuthorityKey{authorities[0], authorities[2]} {
+		sig, err := BlindSign(params, a.Key, req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		sigs = append(sigs, sig)
+		signers = append(signers, a.Public())
+	}
+	sig, err := AggregateBlindSign(params, req, sigs, signers)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cred, err := ObtainCred(sig, d)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sk := big.NewInt(42)
+	proof, err := ProveCred(params, sk, master, cred, m, []bool{true, false}, nil, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pk1 := new(bn256.G1).ScalarMult(params.G1, sk)
+	pk2 := new(bn256.G2).ScalarMult(params.G2, sk)
+	if ok, err := VerifyCred(params, pk1, pk2, master, proof, nil, nil, nil); !ok {
+		t.Fatalf("aggregated credential does not verify: %v", err)
+	}
+
+	// A share from another authority, another request or with a changed
+	// component is rejected by index.
+	_, other, err := PrepareBlindSign(params, m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wrongReq, err := BlindSign(params, authorities[1].Key, other)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wrongKey, err := BlindSign(params, authorities[1].Key, req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	changed := &BlindSignature{U: sigs[1].U, C: []*bn256.G2{sigs[1].C[0], new(bn256.G2).Add(sigs[1].C[1], params.G2)}}
+	bad := []struct {
+		name string
+		sig  *BlindSignature
+		want error
+	}{
+		{"other authority", wrongKey, ErrInvalidProof},
+		{"other request", wrongReq, ErrInvalidProof},
+		{"changed b", changed, ErrInvalidProof},
+		{"nil", nil, ErrMalformedInput},
+		{"no components", &BlindSignature{U: sigs[1].U}, ErrMalformedInput},
+	}
+	for _, tt := range bad {
+		_, err := AggregateBlindSign(params, req, []*BlindSignature{sigs[0], tt.sig}, signers)
+		if !errors.Is(err, tt.want) || !strings.Contains(err.Error(), "partial signature 1") {
+			t.Errorf("%s: %v, want %v naming partial signature 1", tt.name, err, tt.want)
+		}
+	}
+
+	for _, sigs := range [][]*BlindSignature{nil, {}, sigs[:1]} {
+		if _, err := AggregateBlindSign(params, req, sigs, signers); !errors.Is(err, ErrMalformedInput) {
+			t.Errorf("%d partial signatures for 2 keys: %v, want ErrMalformedInput", len(sigs), err)
+		}
+	}
+	if _, err := AggregateBlindSign(params, nil, sigs, signers); !errors.Is(err, ErrMalformedInput) {
+		t.Errorf("no request: %v, want ErrMalformedInput", err)
+	}
+}
+
+func TestAggregateBlindSignThreshold(t *testing.T) {
+	params := testParams(t)
+	master, authorities, err := TTPKeyGen(params, 2, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, a := range authorities {
+		if a.Threshold != 2 || a.Public().Threshold != 2 || a.Public().Key.SK1 != nil {
+			t.Fatalf("authority key %v: threshold %d", a.Index, a.Threshold)
+		}
+	}
+	m := []*big.Int{big.NewInt(7), big.NewInt(25)}
+	d, req, err := PrepareBlindSign(params, m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sigs := make([]*BlindSignature, len(authorities))
+	signers := make([]*AuthorityKey, len(authorities))
+	for i, a := range authorities {
+		if sigs[i], err = BlindSign(params, a.Key, req); err != nil {
+			t.Fatal(err)
+		}
+		signers[i] = a.Public()
+	}
+
+	// More than t shares interpolate to the same signature.
+	sig, err := AggregateBlindSign(params, req, sigs, signers)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cred, err := ObtainCred(sig, d)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sk := big.NewInt(42)
+	pk1, pk2 := userKeys(params, sk)
+	proof, err := ProveCred(params, sk, master, cred, m, []bool{true, false}, nil, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ok, err := VerifyCred(params, pk1, pk2, master, proof, nil, nil, nil); !ok {
+		t.Fatalf("credential from all 3 authorities: %v", err)
+	}
+
+	// One authority alone, or one authority counted twice, is below the
+	// threshold.
+	if _, err := AggregateBlindSign(params, req, sigs[:1], signers[:1]); !errors.Is(err, ErrMalformedInput) || !strings.Contains(err.Error(), "threshold of 2") {
+		t.Errorf("1 of 2 partial signatures: %v, want ErrMalformedInput naming the threshold", err)
+	}
+	twice := []*AuthorityKey{signers[0], signers[0]}
+	if _, err := AggregateBlindSign(params, req, []*BlindSignature{sigs[0], sigs[0]}, twice); !errors.Is(err, ErrMalformedInput) || !strings.Contains(err.Error(), "given twice") {
+		t.Errorf("one authority twice: %v, want ErrMalformedInput for the repeated index", err)
+	}
+	sameIndex := &AuthorityKey{Index: signers[0].Index, Threshold: 2, Key: signers[1].Key}
+	if _, err := AggregateBlindSign(params, req, sigs[:2], []*AuthorityKey{signers[0], sameIndex}); !errors.Is(err, ErrMalformedInput) || !strings.Contains(err.Error(), "given twice") {
+		t.Errorf("two authorities with one index: %v, want ErrMalformedInput for the repeated index", err)
+	}
+
+	lower := *signers[1]
+	lower.Threshold = 1
+	for name, keys := range map[string][]*AuthorityKey{
+		"thresholds differ": {signers[0], &lower},
+		"no threshold":      {{Index: signers[0].Index, Key: signers[0].Key}, {Index: signers[1].Index, Key: signers[1].Key}},
+		"missing key":       {signers[0], nil},
+		"zero index":        {signers[0], {Index: big.NewInt(0), Threshold: 2, Key: signers[1].Key}},
+	} {
+		if _, err := AggregateBlindSign(params, req, sigs[:2], keys); !errors.Is(err, ErrMalformedInput) {
+			t.Errorf("%s: %v, want ErrMalformedInput", name, err)
+		}
+	}
+}
@@ -0,0 +1,91 @@
+package AC
+
+import (
+	"Obfushop/bn256"
+	"crypto/sha256"
+	"encoding/binary"
+	"hash"
+	"math/big"
+)
+
+// Transcript derives Fiat–Shamir challenges in the style of Merlin: prover
+// and verifier append the same labeled messages and each challenge is a
+// hash of everything appended before it. Every proof in this package runs
+// on a transcript that starts with its protocol label, so challenges of
+// different protocols never collide.
+//
+// The encoding is simple enough for the contract to rebuild with
+// abi.encodePacked (see tFrame and tChallenge in BC_SID.sol). A transcript
+// is the concatenation of frames
+//
+//	uint32 len(label) || label || uint32 len(data) || data
+//
+// with lengths big-endian, starting with the frame ("Obfushop/AC/transcript/v1",
+// protocol). G1 points are appended as their 64-byte Marshal output, G2
+// points as 128 bytes (x.x, x.y, y.x, y.y, as in a contract G2Point) and
+// scalars as 32 bytes. Challenge(label) appends the frame (label, "") and
+// returns sha256 of the whole transcript reduced mod bn256.Order; the
+// challenge is then appended as a scalar under the same label so later
+// challenges depend on it.
+type Transcript struct {
+	h hash.Hash
+}
+
+const transcriptDomain = "Obfushop/AC/transcript/v1"
+
+// NewTranscript starts a transcript for protocol.
+func NewTranscript(protocol string) *Transcript {
+	t := &Transcript{h: sha256.New()}
+	t.AppendMessage(transcriptDomain, []byte(protocol))
+	return t
+}
+
+// AppendMessage appends data under label.
+func (t *Transcript) AppendMessage(label string, data []byte) {
+	var n [4]byte
+	binary.BigEndian.PutUint32(n[:], uint32(len(label)))
+	t.h.Write(n[:])
+	t.h.Write([]byte(label))
+	binary.BigEndian.PutUint32(n[:], uint32(len(data)))
+	t.h.Write(n[:])
+	t.h.Write(data)
+}
+
+// AppendG1 appends a G1 point.
+func (t *Transcript) AppendG1(label string, p *bn256.G1) {
+	t.AppendMessage(label, p.Marshal())
+}
+
+// AppendG2 appends a G2 point.
+func (t *Transcript) AppendG2(label string, p *bn256.G2) {
+	t.AppendMessage(label, p.Marshal())
+}
+
+// AppendScalar appends k as 32 bytes. k must be non-negative and fit in
+// 256 bits, as attributes, secrets and challenges do.
+func (t *Transcript) AppendScalar(label string, k *big.Int) {
+	t.AppendMessage(label, k.FillBytes(make([]byte, scalarSize)))
+}
+
+// AppendUint32 appends a counter or an index as 4 bytes.
+func (t *Transcript) AppendUint32(label string, n uint32) {
+	t.AppendMessage(label, binary.BigEndian.AppendUint32(nil, n))
+}
+
+// AppendParams binds the public parameters: g1, h_1..h_q and g2.
+func (t *Transcript) AppendParams(params *Params) {
+	t.AppendG1("g1", params.G1)
+	for _, h := range params.Hs {
+		t.AppendG1("h", h)
+	}
+	t.AppendG2("g2", params.G2)
+}
+
+// Challenge returns the challenge for label, a scalar mod bn256.Order.
+func (t *Transcript) Challenge(label string) *big.Int {
+	t.AppendMessage(label, nil)
+	c := new(big.Int).SetBytes(t.h.Sum(nil))
+	c.Mod(c, bn256.Order)
+	t.AppendScalar(label, c)
+	return c
+}
@@ -0,0 +1,286 @@
+package AC
+
+import (
+	"Obfushop/bn256"
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"flag"
+	"hash"
+	"math/big"
+	"os"
+	"testing"
+)
+
+// The golden vectors in testdata/transcript_vectors.json pin the transcript
+// encoding and the challenges of the proofs the contract checks. The
+// contract runs the same vectors through DLVerify and VerifyKeyBinding, and
+// fresh presentations through VerifyPiV, RegisterSIDSet and RegisterNymSet,
+// see compile/contract, so a change on either side fails a test. Regenerate
+// them with go test -run Golden -update only for a deliberate change of
+// the encoding, together with BC_SID.sol.
+
+var update = flag.Bool("update", false, "rewrite testdata/transcript_vectors.json")
+
+const vectorsFile = "testdata/transcript_vectors.json"
+
+// TranscriptVectors is the layout of the vectors file. Points are hex
+// Marshal output, scalars 32 bytes of hex.
+type TranscriptVectors struct {
+	Transcript struct {
+		Bytes      string   `json:"bytes"`
+		Challenges []string `json:"challenges"`
+	} `json:"transcript"`
+	DL struct {
+		G1 string `json:"g1"`
+		Y1 string `json:"y1"`
+		A1 string `json:"a1"`
+		C  string `json:"c"`
+		Z  string `json:"z"`
+	} `json:"dl"`
+	KeyBinding struct {
+		PK1 string `json:"pk1"`
+		U   string `json:"u"`
+		R   string `json:"r"`
+		A1  string `json:"a1"`
+		A2  string `json:"a2"`
+		C   string `json:"c"`
+		Z   string `json:"z"`
+	} `json:"keyBinding"`
+}
+
+// recorder is a sha256 that keeps a copy of what it hashes.
+type recorder struct {
+	hash.Hash
+	buf bytes.Buffer
+}
+
+func (r *recorder) Write(p []byte) (int, error) {
+	r.buf.Write(p)
+	return r.Hash.Write(p)
+}
+
+func scalarHex(k *big.Int) string { return hex.EncodeToString(k.FillBytes(make([]byte, scalarSize))) }
+
+// solFrame and solChallenge transcribe tFrame and tChallenge of BC_SID.sol.
+func solFrame(t []byte, label string, data []byte) []byte {
+	t = binary.BigEndian.AppendUint32(t, uint32(len(label)))
+	t = append(t, label...)
+	t = binary.BigEndian.AppendUint32(t, uint32(len(data)))
+	return append(t, data...)
+}
+
+func solChallenge(t []byte, label string) *big.Int {
+	sum := sha256.Sum256(solFrame(t, label, nil))
+	return new(big.Int).Mod(new(big.Int).SetBytes(sum[:]), bn256.Order)
+}
+
+func solTranscript(protocol string) []byte {
+	return solFrame(nil, "Obfushop/AC/transcript/v1", []byte(protocol))
+}
+
+// solDL rebuilds the challenge of DLVerify.
+func solDL(g1, y1, a1 *bn256.G1) *big.Int {
+	t := solTranscript("Obfushop/AC/dl")
+	t = solFrame(t, "statement", []byte("Obfushop/AC/dl"))
+	t = solFrame(t, "witness", []byte("x"))
+	t = solFrame(t, "relation", []byte("xG"))
+	t = solFrame(t, "lhs", y1.Marshal())
+	t = solFrame(t, "base", g1.Marshal())
+	t = solFrame(t, "term", []byte{0, 0, 0, 0})
+	t = solFrame(t, "commitment", a1.Marshal())
+	return solChallenge(t, "c")
+}
+
+// solKeyBinding rebuilds the challenge of VerifyKeyBinding.
+func solKeyBinding(g1, pk1, a1 *bn256.G1, u, r, a2 *bn256.G2) *big.Int {
+	t := solTranscript("Obfushop/AC/key-binding")
+	t = solFrame(t, "statement", []byte("Obfushop/AC/key-binding"))
+	t = solFrame(t, "witness", []byte("sk"))
+	t = solFrame(t, "relation", []byte("pk"))
+	t = solFrame(t, "lhs", pk1.Marshal())
+	t = solFrame(t, "base", g1.Marshal())
+	t = solFrame(t, "term", []byte{0, 0, 0, 0})
+	t = solFrame(t, "commitment", a1.Marshal())
+	t = solFrame(t, "relation", []byte("U"))
+	t = solFrame(t, "lhs", u.Marshal())
+	t = solFrame(t, "base", r.Marshal())
+	t = solFrame(t, "term", []byte{0, 0, 0, 0})
+	t = solFrame(t, "commitment", a2.Marshal())
+	return solChallenge(t, "c")
+}
+
+// fixedProof runs the prover of st with the nonces rho instead of random
+// ones.
+func fixedProof(st *Statement, protocol string, witness, rho []*big.Int) (*SigmaProof, *commitments) {
+	A := st.commit(nil, rho)
+	t := NewTranscript(protocol)
+	st.absorb(t, A)
+	c := t.Challenge("c")
+	return &SigmaProof{C: c, R: responses(c, rho, witness)}, A
+}
+
+// goldenVectors computes the vectors from fixed inputs.
+func goldenVectors(t *testing.T) *TranscriptVectors {
+	t.Helper()
+	v := new(TranscriptVectors)
+	g1 := new(bn256.G1).ScalarBaseMult(big.NewInt(1))
+	g2 := new(bn256.G2).ScalarBaseMult(big.NewInt(1))
+
+	rec := &recorder{Hash: sha256.New()}
+	tr := &Transcript{h: rec}
+	tr.AppendMessage(transcriptDomain, []byte("Obfushop/AC/test"))
+	tr.AppendMessage("msg", []byte("hello"))
+	tr.AppendG1("p", g1)
+	tr.AppendG2("q", g2)
+	tr.AppendScalar("k", big.NewInt(42))
+	tr.AppendUint32("n", 7)
+	c1 := tr.Challenge("c")
+	c2 := tr.Challenge("d")
+	v.Transcript.Bytes = hex.EncodeToString(rec.buf.Bytes())
+	v.Transcript.Challenges = []string{scalarHex(c1), scalarHex(c2)}
+
+	x, rho := big.NewInt(5), big.NewInt(7)
+	y1 := new(bn256.G1).ScalarMult(g1, x)
+	dl, A := fixedProof(dlStatement(g1, y1), "Obfushop/AC/dl", []*big.Int{x}, []*big.Int{rho})
+	v.DL.G1 = hex.EncodeToString(g1.Marshal())
+	v.DL.Y1 = hex.EncodeToString(y1.Marshal())
+	v.DL.A1 = hex.EncodeToString(A.g1[0].Marshal())
+	v.DL.C, v.DL.Z = scalarHex(dl.C), scalarHex(dl.R[0])
+
+	params, err := Setup(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sk := big.NewInt(5)
+	R := new(bn256.G2).ScalarBaseMult(big.NewInt(11))
+	U := new(bn256.G2).ScalarMult(R, sk)
+	pk1 := new(bn256.G1).ScalarMult(params.G1, sk)
+	kb, A := fixedProof(keyBindingStatement(params, pk1, R, U), "Obfushop/AC/key-binding", []*big.Int{sk}, []*big.Int{rho})
+	v.KeyBinding.PK1 = hex.EncodeToString(pk1.Marshal())
+	v.KeyBinding.U = hex.EncodeToString(U.Marshal())
+	v.KeyBinding.R = hex.EncodeToString(R.Marshal())
+	v.KeyBinding.A1 = hex.EncodeToString(A.g1[0].Marshal())
+	v.KeyBinding.A2 = hex.EncodeToString(A.g2[1].Marshal())
+	v.KeyBinding.C, v.KeyBinding.Z = scalarHex(kb.C), scalarHex(kb.R[0])
+	return v
+}
+
+func TestTranscriptGolden(t *testing.T) {
+	got := goldenVectors(t)
+	if *update {
+		data, err := json.MarshalIndent(got, "", "  ")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(vectorsFile, append(data, '\n'), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	data, err := os.ReadFile(vectorsFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := new(TranscriptVectors)
+	if err := json.Unmarshal(data, want); err != nil {
+		t.Fatal(err)
+	}
+
+	if got.Transcript.Bytes != want.Transcript.Bytes {
+		t.Errorf("transcript bytes changed:\n got %s\nwant %s", got.Transcript.Bytes, want.Transcript.Bytes)
+	}
+	for i := range want.Transcript.Challenges {
+		if i >= len(got.Transcript.Challenges) || got.Transcript.Challenges[i] != want.Transcript.Challenges[i] {
+			t.Errorf("transcript challenge %d changed", i)
+		}
+	}
+	if got.DL != want.DL {
+		t.Errorf("DL vector changed:\n got %+v\nwant %+v", got.DL, want.DL)
+	}
+	if got.KeyBinding != want.KeyBinding {
+		t.Errorf("key binding vector changed:\n got %+v\nwant %+v", got.KeyBinding, want.KeyBinding)
+	}
+}
+
+func mustG1(t *testing.T, s string) *bn256.G1 {
+	t.Helper()
+	b, _ := hex.DecodeString(s)
+	p, err := decodeG1(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return p
+}
+
+func mustG2(t *testing.T, s string) *bn256.G2 {
+	t.Helper()
+	b, _ := hex.DecodeString(s)
+	p, err := decodeG2(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return p
+}
+
+func mustScalar(s string) *big.Int {
+	b, _ := hex.DecodeString(s)
+	return new(big.Int).SetBytes(b)
+}
+
+// TestTranscriptSolidity checks the vectors against the public verifiers and
+// against the transcripts of BC_SID.sol as transcribed above.
+func TestTranscriptSolidity(t *testing.T) {
+	data, err := os.ReadFile(vectorsFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	v := new(TranscriptVectors)
+	if err := json.Unmarshal(data, v); err != nil {
+		t.Fatal(err)
+	}
+
+	// the frames of goldenVectors, challenges appended as in Challenge
+	tr := solTranscript("Obfushop/AC/test")
+	tr = solFrame(tr, "msg", []byte("hello"))
+	tr = solFrame(tr, "p", new(bn256.G1).ScalarBaseMult(big.NewInt(1)).Marshal())
+	tr = solFrame(tr, "q", new(bn256.G2).ScalarBaseMult(big.NewInt(1)).Marshal())
+	tr = solFrame(tr, "k", big.NewInt(42).FillBytes(make([]byte, scalarSize)))
+	tr = solFrame(tr, "n", []byte{0, 0, 0, 7})
+	var challenges []string
+	for _, label := range []string{"c", "d"} {
+		c := solChallenge(tr, label)
+		tr = solFrame(solFrame(tr, label, nil), label, c.FillBytes(make([]byte, scalarSize)))
+		challenges = append(challenges, scalarHex(c))
+	}
+	if hex.EncodeToString(tr) != v.Transcript.Bytes {
+		t.Error("transcript bytes differ from the frames of BC_SID.sol")
+	}
+	if len(v.Transcript.Challenges) != 2 || challenges[0] != v.Transcript.Challenges[0] || challenges[1] != v.Transcript.Challenges[1] {
+		t.Error("transcript challenges differ from tChallenge")
+	}
+
+	g1, y1, a1 := mustG1(t, v.DL.G1), mustG1(t, v.DL.Y1), mustG1(t, v.DL.A1)
+	c, z := mustScalar(v.DL.C), mustScalar(v.DL.Z)
+	if ok, err := VerifyDL(c, z, g1, y1, a1); !ok {
+		t.Errorf("VerifyDL rejects the DL vector: %v", err)
+	}
+	if solDL(g1, y1, a1).Cmp(c) != 0 {
+		t.Error("DLVerify would compute a different challenge")
+	}
+
+	params, err := Setup(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	kb := v.KeyBinding
+	pk1, U := mustG1(t, kb.PK1), mustG2(t, kb.U)
+	binding := &KeyBinding{R: mustG2(t, kb.R), C: mustScalar(kb.C), Z: mustScalar(kb.Z), A1: mustG1(t, kb.A1), A2: mustG2(t, kb.A2)}
+	if ok, err := VerifyKeyBinding(params, pk1, U, binding); !ok {
+		t.Errorf("VerifyKeyBinding rejects the key binding vector: %v", err)
+	}
+	if solKeyBinding(params.G1, pk1, binding.A1, U, binding.R, binding.A2).Cmp(binding.C) != 0 {
+		t.Error("the contract's VerifyKeyBinding would compute a different challenge")
+	}
+}
@@ -13,16 +13,36 @@ type Params struct {
 	GT *bn256.GT
 }
 
+// AttributeKey is the key of a user for a set of attributes, one part per
+// attribute in the order they were given to KeyGen.
 type AttributeKey struct {
-	D        *bn256.G1
-	KeyValue map[string]map[*bn256.G1]*bn256.G2
+	D     *bn256.G1
+	Parts []*KeyPart
 }
 
+// KeyPart is the component of an attribute key for one attribute.
+type KeyPart struct {
+	Attribute string
+	D1        *bn256.G1 // PKu^r · H(attribute)^rj
+	D2        *bn256.G2 // g2^rj
+}
+
+// Ciphertext is an encryption under an access policy. Xs[id] is the
+// x-coordinate of the share of the policy node with that ID, and Parts has
+// one part per attribute leaf in depth-first order, see PolicyNode.Number.
 type Ciphertext struct {
-	Policy    *PolicyNode
-	C         *bn256.GT
-	CC        *bn256.G2
-	NodeValue map[string]map[*big.Int]map[*bn256.G1]*bn256.G2
+	Policy *PolicyNode
+	C      *bn256.GT
+	CC     *bn256.G2
+	Xs     []*big.Int
+	Parts  []*CiphertextPart
+}
+
+// CiphertextPart is the component of a ciphertext for one attribute leaf.
+type CiphertextPart struct {
+	Attribute string
+	C1        *bn256.G1 // H(attribute)^share
+	C2        *bn256.G2 // g2^share
 }
 
 func Setup() (*big.Int, *Params) {
@@ -42,38 +62,32 @@ func Setup() (*big.Int, *Params) {
 func KeyGen(PKu *bn256.G1, MSK *big.Int, PK *Params, Su []string) *AttributeKey {
 	r, _ := rand.Int(rand.Reader, bn256.Order)
 	rx := make([]*big.Int, len(Su))
-	keyValue := make(map[string]map[*bn256.G1]*bn256.G2)
+	parts := make([]*KeyPart, len(Su))
 
 	for i := 0; i < len(Su); i++ {
 		rx[i], _ = rand.Int(rand.Reader, bn256.Order)
 		dx := new(bn256.G1).Add(new(bn256.G1).ScalarMult(PKu, r), new(bn256.G1).ScalarMult(Convert.StringToG1(Su[i]), rx[i]))
 		_dx := new(bn256.G2).ScalarMult(PK.G2, rx[i])
-
-		// 确保 keyValue[Su[i]] 已经初始化
-		if _, exists := keyValue[Su[i]]; !exists {
-			keyValue[Su[i]] = make(map[*bn256.G1]*bn256.G2)
-		}
-
-		// 现在可以安全地赋值
-		keyValue[Su[i]][dx] = _dx
+		parts[i] = &KeyPart{Attribute: Su[i], D1: dx, D2: _dx}
 	}
 	d := new(bn256.G1).ScalarMult(PKu, new(big.Int).Add(MSK, r))
 
 	return &AttributeKey{
-		D:        d,
-		KeyValue: keyValue,
+		D:     d,
+		Parts: parts,
 	}
 }
 
-func Encrypt(m *bn256.GT, tau string, PK *Params) (*Ciphertext, xsMapType, *bn256.GT, *big.Int) {
-	s, _ := rand.Int(rand.Reader, bn256.Order)
-	c := new(bn256.GT).Add(m, new(bn256.GT).ScalarMult(PK.GT, s))
-	_c := new(bn256.G2).ScalarMult(PK.G2, s)
+// Encrypt encrypts m under the policy tau, see ParsePolicy for its syntax.
+func Encrypt(m *bn256.GT, tau string, PK *Params) (*Ciphertext, *bn256.GT, *big.Int, error) {
 	// 解析策略表达式为树
 	policy, err := ParsePolicy(tau)
 	if err != nil {
-		panic("ParsePolicy error: " + err.Error())
+		return nil, nil, nil, err
 	}
+	s, _ := rand.Int(rand.Reader, bn256.Order)
+	c := new(bn256.GT).Add(m, new(bn256.GT).ScalarMult(PK.GT, s))
+	_c := new(bn256.G2).ScalarMult(PK.G2, s)
 	// 打印策略树结构
 	//PrintPolicyTree(policy, 0)
 
@@ -81,95 +95,136 @@ func Encrypt(m *bn256.GT, tau string, PK *Params) (*Ciphertext, xsMapType, *bn25
 	//fmt.Printf("策略中包含属性:%v\n", CountAttributes(policy))
 
 	// 执行秘密分享
-	shares, xsMap, err := ComputeShares(s, policy, FieldOrder)
+	shares, xs, err := ComputeShares(s, policy, FieldOrder)
 	if err != nil {
-		panic("ComputeShares error: " + err.Error())
+		return nil, nil, nil, err
 	}
 
-	nodeValue := make(map[string]map[*big.Int]map[*bn256.G1]*bn256.G2)
-	// 打印所有属性份额
+	// 份额按策略树深度优先顺序排列，与叶子一一对应
+	parts := make([]*CiphertextPart, len(shares))
 	for i := 0; i < len(shares); i++ {
 		s := shares[i] // 通过索引访问元素
 		//fmt.Printf("%s: X=%v, S=%v\n", s.Attribute, s.X, s.Share)
 		cy := new(bn256.G2).ScalarMult(PK.G2, s.Share)
 		_cy := new(bn256.G1).ScalarMult(Convert.StringToG1(s.Attribute), s.Share)
-		// 初始化嵌套的 map
-		if _, exists := nodeValue[s.Attribute]; !exists {
-			nodeValue[s.Attribute] = make(map[*big.Int]map[*bn256.G1]*bn256.G2)
-		}
-		if _, exists := nodeValue[s.Attribute][s.X]; !exists {
-			nodeValue[s.Attribute][s.X] = make(map[*bn256.G1]*bn256.G2)
-		}
-		// 现在可以安全地赋值
-		nodeValue[s.Attribute][s.X][_cy] = cy
+		parts[i] = &CiphertextPart{Attribute: s.Attribute, C1: _cy, C2: cy}
 	}
 
 	return &Ciphertext{
-		Policy:    policy,
-		C:         c,
-		CC:        _c,
-		NodeValue: nodeValue,
-	}, xsMap, new(bn256.GT).ScalarMult(PK.GT, s), s
+		Policy: policy,
+		C:      c,
+		CC:     _c,
+		Xs:     xs,
+		Parts:  parts,
+	}, new(bn256.GT).ScalarMult(PK.GT, s), s, nil
 }
 
-func ODecrypt(attributeSet map[string]bool, CT *Ciphertext, SK *AttributeKey, xsMap xsMapType, PK *Params) *bn256.GT {
-	// ======== 切换不同属性集，验证左右子树恢复 ========
-	// 左子树满足策略：A + C
-	//attrs := map[string]bool{"Age>18": true, "Man": true}
-	// 右子树满足策略：D + E + G
-	//attrs := map[string]bool{"D": true, "F": true, "G": true}
-
-	attributePolicy := CountAttributes(CT.Policy)
-	//attrs := map[string]bool{}
-	//提取所给属性集中满足策略树的属性
-	// for i := 0; i < len(attributeSet); i++ {
-	// 	if Contains(attributePolicy, attributeSet[i]) != "" {
-	// 		attrs[attributeSet[i]] = true
-	// 	}
-	// }
-	// fmt.Printf("满足策略的属性有：:%v\n", attrs)
-
-	// 构造 attrX 映射
-	attrX := make(map[string]*big.Int)
-	for attr, s := range CT.NodeValue {
-		if attributeSet[attr] {
-			for x, _ := range s {
-				attrX[attr] = x
+// ODecrypt is the outsourced part of decryption: it turns CT into the
+// intermediate result that Decrypt finishes with the secret of the user.
+func ODecrypt(CT *Ciphertext, SK *AttributeKey) *bn256.GT {
+	temp := recoverShares(CT, SK)
+	recovered := new(bn256.GT).Add(bn256.Pair(SK.D, CT.CC), new(bn256.GT).Neg(temp))
+	return recovered
+}
 
-			}
+// ODecryptPruned computes the same intermediate result as ODecrypt, but
+// only from a minimal set of leaves, see PolicyNode.MinimalSet, and with
+// all its pairings folded into one multi-pairing:
+//
+//	IR = e(D, CC) · ∏ e(D1^-c, C2) · e(C1^c, D2)
+//
+// where c is the Lagrange coefficient of a leaf. For l leaves that is 2l+1
+// Miller loops and a single final exponentiation, where ODecrypt computes
+// two full pairings for every key part and matching leaf. It fails with an
+// *UnsatisfiedError if SK does not satisfy the policy of CT.
+func ODecryptPruned(CT *Ciphertext, SK *AttributeKey) (*bn256.GT, error) {
+	keyParts := make(map[string]*KeyPart, len(SK.Parts))
+	attrs := make([]string, 0, len(SK.Parts))
+	for _, part := range SK.Parts {
+		if keyParts[part.Attribute] == nil {
+			keyParts[part.Attribute] = part
+			attrs = append(attrs, part.Attribute)
 		}
 	}
-	//fmt.Printf("OABE attrX:%v\n", attrX)
+	s, err := CT.Policy.MinimalSet(attrs)
+	if err != nil {
+		return nil, err
+	}
+	coeffs, err := GetCoefficientsPruned(CT.Policy, s.Leaves, CT.Xs, FieldOrder)
+	if err != nil {
+		return nil, err
+	}
 
-	// 计算拉格朗日系数
-	coeffs := GetCoefficientsNoPrune(CT.Policy, attributeSet, attrX, xsMap, FieldOrder)
+	// CT.Parts[k] belongs to the k-th leaf
+	leaves := CT.leaves()
+	if len(leaves) != len(CT.Parts) {
+		return nil, malformed("%d ciphertext parts for %d leaves", len(CT.Parts), len(leaves))
+	}
+	partOf := make(map[int]*CiphertextPart, len(leaves))
+	for k, leaf := range leaves {
+		partOf[leaf.ID] = CT.Parts[k]
+	}
+
+	as := []*bn256.G1{SK.D}
+	bs := []*bn256.G2{CT.CC}
+	for _, leaf := range s.Leaves {
+		c := coeffs[leaf.ID]
+		keyPart, part := keyParts[leaf.Attribute], partOf[leaf.ID]
+		as = append(as,
+			new(bn256.G1).Neg(new(bn256.G1).ScalarMult(keyPart.D1, c)),
+			new(bn256.G1).ScalarMult(part.C1, c))
+		bs = append(bs, part.C2, keyPart.D2)
+	}
+	return bn256.MultiPair(as, bs), nil
+}
+
+// recoverShares pairs the key parts with the ciphertext parts of the same
+// attribute and combines the results with the Lagrange coefficients for the
+// attributes of SK.
+func recoverShares(CT *Ciphertext, SK *AttributeKey) *bn256.GT {
+	attributeSet := make(map[string]bool, len(SK.Parts))
+	for _, keyPart := range SK.Parts {
+		attributeSet[keyPart.Attribute] = true
+	}
 
-	// for attr, coeff := range coeffs {
-	// 	fmt.Printf("%s -> %v\n", attr, coeff)
-	// }
+	// 计算拉格朗日系数
+	coeffs := GetCoefficientsNoPrune(CT.Policy, attributeSet, CT.Xs, FieldOrder)
 
 	// 根据属性份额和系数恢复秘密
+	leaves := CT.leaves()
 	var usedShares []DecShare
-	for attr, keyValue := range SK.KeyValue {
-		var usedshare DecShare
-		if Contains(attributePolicy, attr) != "" {
-			usedshare.Attribute = attr
-			for x, cyValue := range CT.NodeValue[attr] {
-				usedshare.X = x
-				for _cy, cy := range cyValue {
-					for dx, _dx := range keyValue {
-						left := bn256.Pair(dx, cy)
-						right := bn256.Pair(_cy, _dx)
-						usedshare.Share = new(bn256.GT).Add(left, new(bn256.GT).Neg(right))
-						usedShares = append(usedShares, usedshare)
-					}
-				}
+	for _, keyPart := range SK.Parts {
+		for k, part := range CT.Parts {
+			if part.Attribute != keyPart.Attribute || k >= len(leaves) {
+				continue
 			}
+			left := bn256.Pair(keyPart.D1, part.C2)
+			right := bn256.Pair(part.C1, keyPart.D2)
+			usedShares = append(usedShares, DecShare{
+				Attribute: part.Attribute,
+				Node:      leaves[k].ID,
+				X:         nodeX(CT.Xs, leaves[k]),
+				Share:     new(bn256.GT).Add(left, new(bn256.GT).Neg(right)),
+			})
 		}
 	}
-	temp := RecoverSecret(usedShares, coeffs, FieldOrder)
-	recovered := new(bn256.GT).Add(bn256.Pair(SK.D, CT.CC), new(bn256.GT).Neg(temp))
-	return recovered
+	return RecoverSecret(usedShares, coeffs, FieldOrder)
+}
+
+// leaves returns the attribute leaves of CT.Policy, matching CT.Parts.
+func (CT *Ciphertext) leaves() []*PolicyNode {
+	var leaves []*PolicyNode
+	var walk func(node *PolicyNode)
+	walk = func(node *PolicyNode) {
+		if node.Type == ATTR {
+			leaves = append(leaves, node)
+		}
+		for _, child := range node.Children {
+			walk(child)
+		}
+	}
+	walk(CT.Policy)
+	return leaves
 }
 
 func Decrypt(IR *bn256.GT, SKu *big.Int, CT *Ciphertext) *bn256.GT {
@@ -179,40 +234,9 @@ func Decrypt(IR *bn256.GT, SKu *big.Int, CT *Ciphertext) *bn256.GT {
 	return m
 }
 
-func BSWDecrypt(attributeSet map[string]bool, CT *Ciphertext, SK *AttributeKey, xsMap xsMapType, PK *Params) *bn256.GT {
-
-	attributePolicy := CountAttributes(CT.Policy)
-	// 构造 attrX 映射
-	attrX := make(map[string]*big.Int)
-	for attr, s := range CT.NodeValue {
-		if attributeSet[attr] {
-			for x, _ := range s {
-				attrX[attr] = x
-			}
-		}
-	}
-	// 计算拉格朗日系数
-	coeffs := GetCoefficientsNoPrune(CT.Policy, attributeSet, attrX, xsMap, FieldOrder)
-	// 根据属性份额和系数恢复秘密
-	var usedShares []DecShare
-	for attr, keyValue := range SK.KeyValue {
-		var usedshare DecShare
-		if Contains(attributePolicy, attr) != "" {
-			usedshare.Attribute = attr
-			for x, cyValue := range CT.NodeValue[attr] {
-				usedshare.X = x
-				for _cy, cy := range cyValue {
-					for dx, _dx := range keyValue {
-						left := bn256.Pair(dx, cy)
-						right := bn256.Pair(_cy, _dx)
-						usedshare.Share = new(bn256.GT).Add(left, new(bn256.GT).Neg(right))
-						usedShares = append(usedShares, usedshare)
-					}
-				}
-			}
-		}
-	}
-	temp := RecoverSecret(usedShares, coeffs, FieldOrder)
+// BSWDecrypt decrypts CT without outsourcing, with a key made for PKu = g1.
+func BSWDecrypt(CT *Ciphertext, SK *AttributeKey) *bn256.GT {
+	temp := recoverShares(CT, SK)
 	recovered := new(bn256.GT).Add(bn256.Pair(SK.D, CT.CC), new(bn256.GT).Neg(temp))
 	m := new(bn256.GT).Add(CT.C, new(bn256.GT).Neg(recovered))
 	return m
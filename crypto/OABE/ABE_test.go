@@ -0,0 +1,176 @@
+package OABE
+
+import (
+	"crypto/rand"
+	"errors"
+	"math/big"
+	"reflect"
+	"testing"
+
+	bn256 "Obfushop/bn256"
+)
+
+// decryptCases are policies with keys that satisfy them in more than one
+// way, so that pruning has something to skip.
+var decryptCases = []struct {
+	name   string
+	policy string
+	attrs  []string
+}{
+	{"drone", "Owner OR (Community_A AND Hovering_drone)", []string{"Owner", "Community_A", "Hovering_drone"}},
+	{"threshold", "2 of (A AND B AND C, D, E AND F, G)", []string{"A", "B", "C", "D", "E", "F", "G"}},
+	{"repeated", "(A AND B) OR (A AND C) OR (B AND C)", []string{"A", "B", "C"}},
+	{"comparison", "floor >= 3 AND floor <= 12", NumericAttributes("floor", 7)},
+}
+
+// decryptSetup encrypts a random message under policy for a key on attrs.
+// It returns the secret of the user and the message as well.
+func decryptSetup(tb testing.TB, policy string, attrs []string) (*Ciphertext, *AttributeKey, *big.Int, *bn256.GT) {
+	tb.Helper()
+	MSK, PK := Setup()
+	sku, pku, err := bn256.RandomG1(rand.Reader)
+	if err != nil {
+		tb.Fatal(err)
+	}
+	_, m, err := bn256.RandomG1(rand.Reader)
+	if err != nil {
+		tb.Fatal(err)
+	}
+	msg := bn256.Pair(m, PK.G2)
+	CT, _, _, err := Encrypt(msg, policy, PK)
+	if err != nil {
+		tb.Fatal(err)
+	}
+	return CT, KeyGen(pku, MSK, PK, attrs), sku, msg
+}
+
+func TestODecryptPruned(t *testing.T) {
+	for _, tc := range decryptCases {
+		CT, SK, sku, msg := decryptSetup(t, tc.policy, tc.attrs)
+		pruned, err := ODecryptPruned(CT, SK)
+		if err != nil {
+			t.Fatalf("%s: %v", tc.name, err)
+		}
+		full := ODecrypt(CT, SK)
+		if !pruned.Equal(full) {
+			t.Errorf("%s: ODecryptPruned and ODecrypt differ", tc.name)
+		}
+		if !Decrypt(pruned, sku, CT).Equal(msg) {
+			t.Errorf("%s: ODecryptPruned does not recover the message", tc.name)
+		}
+		if !Decrypt(full, sku, CT).Equal(msg) {
+			t.Errorf("%s: ODecrypt does not recover the message", tc.name)
+		}
+	}
+
+	CT, SK, _, _ := decryptSetup(t, "2 of (A AND B AND C, D, E AND F, G)", []string{"A", "B", "D", "E"})
+	_, err := ODecryptPruned(CT, SK)
+	var ue *UnsatisfiedError
+	if !errors.As(err, &ue) {
+		t.Fatalf("ODecryptPruned with an unsatisfying key: %v, want an *UnsatisfiedError", err)
+	}
+	if got := ue.Missing(); !reflect.DeepEqual(got, []string{"C", "F", "G"}) {
+		t.Errorf("missing %v, want [C F G]", got)
+	}
+}
+
+// depthFirst lists the nodes of a policy without renumbering them.
+func depthFirst(node *PolicyNode) []*PolicyNode {
+	nodes := []*PolicyNode{node}
+	for _, child := range node.Children {
+		nodes = append(nodes, depthFirst(child)...)
+	}
+	return nodes
+}
+
+// TestDecryptDecoded decrypts ciphertexts and keys that went through both
+// wire encodings, which have to keep the node IDs and x-coordinates.
+func TestDecryptDecoded(t *testing.T) {
+	for _, tc := range decryptCases {
+		CT, SK, sku, msg := decryptSetup(t, tc.policy, tc.attrs)
+		ctBin, err := CT.MarshalBinary()
+		if err != nil {
+			t.Fatal(err)
+		}
+		keyBin, err := SK.MarshalBinary()
+		if err != nil {
+			t.Fatal(err)
+		}
+		ctJSON, err := CT.MarshalJSON()
+		if err != nil {
+			t.Fatal(err)
+		}
+		keyJSON, err := SK.MarshalJSON()
+		if err != nil {
+			t.Fatal(err)
+		}
+		fromBin, fromJSON := new(Ciphertext), new(Ciphertext)
+		keyFromBin, keyFromJSON := new(AttributeKey), new(AttributeKey)
+		for _, err := range []error{
+			fromBin.UnmarshalBinary(ctBin), fromJSON.UnmarshalJSON(ctJSON),
+			keyFromBin.UnmarshalBinary(keyBin), keyFromJSON.UnmarshalJSON(keyJSON),
+		} {
+			if err != nil {
+				t.Fatalf("%s: %v", tc.name, err)
+			}
+		}
+
+		for _, dec := range []struct {
+			name string
+			CT   *Ciphertext
+			SK   *AttributeKey
+		}{{"binary", fromBin, keyFromBin}, {"JSON", fromJSON, keyFromJSON}} {
+			want, got := depthFirst(CT.Policy), depthFirst(dec.CT.Policy)
+			if len(got) != len(want) {
+				t.Fatalf("%s, %s: %d policy nodes, want %d", tc.name, dec.name, len(got), len(want))
+			}
+			for i := range want {
+				if got[i].ID != want[i].ID || got[i].Attribute != want[i].Attribute {
+					t.Errorf("%s, %s: node %d is %d %q, want %d %q", tc.name, dec.name, i, got[i].ID, got[i].Attribute, want[i].ID, want[i].Attribute)
+				}
+				if dec.CT.Xs[i].Cmp(CT.Xs[i]) != 0 {
+					t.Errorf("%s, %s: x-coordinate %d changed", tc.name, dec.name, i)
+				}
+			}
+			full := ODecrypt(dec.CT, dec.SK)
+			if !Decrypt(full, sku, dec.CT).Equal(msg) {
+				t.Errorf("%s, %s: ODecrypt does not recover the message", tc.name, dec.name)
+			}
+			pruned, err := ODecryptPruned(dec.CT, dec.SK)
+			if err != nil {
+				t.Fatalf("%s, %s: %v", tc.name, dec.name, err)
+			}
+			if !Decrypt(pruned, sku, dec.CT).Equal(msg) {
+				t.Errorf("%s, %s: ODecryptPruned does not recover the message", tc.name, dec.name)
+			}
+		}
+	}
+}
+
+func BenchmarkODecrypt(b *testing.B) {
+	for _, tc := range decryptCases {
+		b.Run(tc.name, func(b *testing.B) {
+			CT, SK, _, _ := decryptSetup(b, tc.policy, tc.attrs)
+			b.ResetTimer()
+
+			for i := 0; i < b.N; i++ {
+				ODecrypt(CT, SK)
+			}
+		})
+	}
+}
+
+func BenchmarkODecryptPruned(b *testing.B) {
+	for _, tc := range decryptCases {
+		b.Run(tc.name, func(b *testing.B) {
+			CT, SK, _, _ := decryptSetup(b, tc.policy, tc.attrs)
+			b.ResetTimer()
+
+			for i := 0; i < b.N; i++ {
+				if _, err := ODecryptPruned(CT, SK); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
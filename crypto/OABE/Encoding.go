@@ -0,0 +1,741 @@
+package OABE
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+
+	bn256 "Obfushop/bn256"
+)
+
+// Wire format. Ciphertexts and attribute keys have a canonical binary and
+// JSON encoding, in the style of the AC package, so a ciphertext can be
+// stored on-chain or handed to the outsourcing server and a key can be sent
+// to the drone that holds it.
+//
+// Binary: one version byte, one type byte, then the fields in declaration
+// order. Points are bn256 Marshal output (64 bytes in G1, 128 in G2, 384 in
+// GT), scalars are 32 bytes big-endian, strings carry a 1-byte length and
+// lists a 4-byte count. The policy of a ciphertext is the list of its nodes
+// by ID, i.e. in depth-first order: an attribute leaf is the byte 0 and the attribute, a
+// threshold gate the byte 1, its threshold and its number of children as 4
+// bytes each, and either is followed by the x-coordinate of the share of the
+// node. The parts of a ciphertext follow the attribute leaves of its policy
+// and do not repeat the attribute.
+//
+// JSON: an object with "version" and "type" and one key per field, points
+// and scalars as fixed-width hex strings, policy nodes as {"attribute": a,
+// "x": x} or {"threshold": k, "children": n, "x": x}.
+//
+// Decoding rejects other versions, trailing bytes, unknown JSON keys, points
+// that are not on the curve or not in GT, identity points, malformed
+// policies and x-coordinates other than those Encrypt derives from the
+// shape of the policy. The encoding carries no integrity check: a changed
+// attribute or threshold still decodes.
+
+// WireVersion is the version of the encoding written by this package.
+const WireVersion = 1
+
+// ErrMalformedInput is returned for encodings that cannot be decoded.
+var ErrMalformedInput = errors.New("OABE: malformed input")
+
+const (
+	scalarSize     = 32
+	g1Size         = 64
+	g2Size         = 128
+	gtSize         = 384
+	maxListSize    = 1 << 16
+	maxPolicyDepth = 64
+)
+
+const (
+	wireCiphertext byte = iota + 1
+	wireAttributeKey
+)
+
+var wireNames = map[byte]string{
+	wireCiphertext:   "ciphertext",
+	wireAttributeKey: "attributekey",
+}
+
+func malformed(format string, args ...any) error {
+	return fmt.Errorf("%w: "+format, append([]any{ErrMalformedInput}, args...)...)
+}
+
+// wireNode is a policy node in the flat form used on the wire, with the
+// x-coordinate of its share in a ciphertext.
+type wireNode struct {
+	Attribute string
+	Threshold int
+	Children  int
+	X         *big.Int
+}
+
+// flattenPolicy lists the nodes of root in depth-first order.
+func flattenPolicy(root *PolicyNode) ([]wireNode, error) {
+	var nodes []wireNode
+	var walk func(node *PolicyNode, depth int) error
+	walk = func(node *PolicyNode, depth int) error {
+		switch {
+		case node == nil:
+			return malformed("policy node missing")
+		case depth > maxPolicyDepth || len(nodes) >= maxListSize:
+			return malformed("policy too large")
+		case node.ID != len(nodes):
+			return malformed("policy is not numbered")
+		case node.Type == ATTR:
+			if node.Attribute == "" || len(node.Attribute) > 255 || len(node.Children) != 0 {
+				return malformed("bad attribute leaf %q", node.Attribute)
+			}
+			nodes = append(nodes, wireNode{Attribute: node.Attribute})
+			return nil
+		case node.Type == THRESHOLD:
+			if node.Threshold < 1 || node.Threshold > len(node.Children) {
+				return malformed("bad threshold %d-of-%d", node.Threshold, len(node.Children))
+			}
+			nodes = append(nodes, wireNode{Threshold: node.Threshold, Children: len(node.Children)})
+			for _, child := range node.Children {
+				if err := walk(child, depth+1); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+		return malformed("unknown policy node type %d", node.Type)
+	}
+	if err := walk(root, 0); err != nil {
+		return nil, err
+	}
+	return nodes, nil
+}
+
+// buildPolicy rebuilds the tree listed by flattenPolicy.
+func buildPolicy(nodes []wireNode) (*PolicyNode, error) {
+	next := 0
+	var build func(depth int) (*PolicyNode, error)
+	build = func(depth int) (*PolicyNode, error) {
+		if next >= len(nodes) {
+			return nil, malformed("policy truncated")
+		}
+		if depth > maxPolicyDepth {
+			return nil, malformed("policy too large")
+		}
+		n := nodes[next]
+		next++
+		if n.Threshold == 0 && n.Children == 0 {
+			if n.Attribute == "" {
+				return nil, malformed("empty attribute")
+			}
+			return &PolicyNode{Type: ATTR, Attribute: n.Attribute}, nil
+		}
+		if n.Attribute != "" || n.Threshold < 1 || n.Threshold > n.Children {
+			return nil, malformed("bad threshold gate")
+		}
+		if n.Children > len(nodes)-next {
+			return nil, malformed("policy truncated")
+		}
+		node := &PolicyNode{Type: THRESHOLD, Threshold: n.Threshold, Children: make([]*PolicyNode, n.Children)}
+		for i := range node.Children {
+			child, err := build(depth + 1)
+			if err != nil {
+				return nil, err
+			}
+			node.Children[i] = child
+		}
+		return node, nil
+	}
+	root, err := build(0)
+	if err != nil {
+		return nil, err
+	}
+	if next != len(nodes) {
+		return nil, malformed("policy has extra nodes")
+	}
+	root.Number()
+	return root, nil
+}
+
+// flattenCiphertext lists the policy nodes of CT with their x-coordinates
+// and checks that the parts of CT follow the leaves.
+func flattenCiphertext(CT *Ciphertext) ([]wireNode, error) {
+	nodes, err := flattenPolicy(CT.Policy)
+	if err != nil {
+		return nil, err
+	}
+	if len(CT.Xs) != len(nodes) {
+		return nil, malformed("ciphertext has %d x-coordinates for %d policy nodes", len(CT.Xs), len(nodes))
+	}
+	for i := range nodes {
+		nodes[i].X = CT.Xs[i]
+	}
+	return nodes, checkParts(CT, nodes)
+}
+
+// checkParts checks that the parts of CT follow the leaves of its policy.
+func checkParts(CT *Ciphertext, nodes []wireNode) error {
+	k := 0
+	for _, n := range nodes {
+		if n.Threshold != 0 {
+			continue
+		}
+		if k >= len(CT.Parts) || CT.Parts[k] == nil {
+			return malformed("ciphertext part %d missing", k)
+		}
+		if CT.Parts[k].Attribute != n.Attribute {
+			return malformed("ciphertext part %d is for %q, not %q", k, CT.Parts[k].Attribute, n.Attribute)
+		}
+		k++
+	}
+	if k != len(CT.Parts) {
+		return malformed("ciphertext has %d parts for %d attribute leaves", len(CT.Parts), k)
+	}
+	return nil
+}
+
+func encodeG1(name string, p *bn256.G1) ([]byte, error) {
+	if p == nil || p.IsIdentity() {
+		return nil, malformed("%s missing", name)
+	}
+	return p.Marshal(), nil
+}
+
+func encodeG2(name string, p *bn256.G2) ([]byte, error) {
+	if p == nil || p.IsIdentity() {
+		return nil, malformed("%s missing", name)
+	}
+	return p.Marshal(), nil
+}
+
+func encodeGT(name string, e *bn256.GT) ([]byte, error) {
+	if e == nil {
+		return nil, malformed("%s missing", name)
+	}
+	return e.Marshal(), nil
+}
+
+func encodeScalar(name string, k *big.Int) ([]byte, error) {
+	if k == nil || k.Sign() <= 0 || k.Cmp(bn256.Order) >= 0 {
+		return nil, malformed("%s is not a non-zero scalar", name)
+	}
+	return k.FillBytes(make([]byte, scalarSize)), nil
+}
+
+func decodeG1(name string, b []byte) (*bn256.G1, error) {
+	p := new(bn256.G1)
+	if _, err := p.Unmarshal(b); err != nil {
+		return nil, malformed("%s: %v", name, err)
+	}
+	if p.IsIdentity() {
+		return nil, malformed("%s is the identity", name)
+	}
+	return p, nil
+}
+
+func decodeG2(name string, b []byte) (*bn256.G2, error) {
+	p := new(bn256.G2)
+	if _, err := p.Unmarshal(b); err != nil {
+		return nil, malformed("%s: %v", name, err)
+	}
+	if p.IsIdentity() {
+		return nil, malformed("%s is the identity", name)
+	}
+	return p, nil
+}
+
+func decodeGT(name string, b []byte) (*bn256.GT, error) {
+	e := new(bn256.GT)
+	if _, err := e.Unmarshal(b); err != nil {
+		return nil, malformed("%s: %v", name, err)
+	}
+	if !new(bn256.GT).ScalarMult(e, bn256.Order).IsIdentity() {
+		return nil, malformed("%s is not in GT", name)
+	}
+	return e, nil
+}
+
+func decodeScalar(name string, b []byte) (*big.Int, error) {
+	k := new(big.Int).SetBytes(b)
+	if k.Sign() == 0 || k.Cmp(bn256.Order) >= 0 {
+		return nil, malformed("%s is not a non-zero scalar", name)
+	}
+	return k, nil
+}
+
+// reader consumes binary input. The first error sticks.
+type reader struct {
+	buf []byte
+	err error
+}
+
+func (r *reader) take(n int) []byte {
+	if r.err != nil {
+		return nil
+	}
+	if len(r.buf) < n {
+		r.err = malformed("truncated data")
+		return nil
+	}
+	b := r.buf[:n]
+	r.buf = r.buf[n:]
+	return b
+}
+
+// count reads a list length; each element takes at least min bytes.
+func (r *reader) count(min int) int {
+	b := r.take(4)
+	if b == nil {
+		return 0
+	}
+	n := binary.BigEndian.Uint32(b)
+	if n > maxListSize || int(n)*min > len(r.buf) {
+		r.err = malformed("bad list length")
+		return 0
+	}
+	return int(n)
+}
+
+func (r *reader) str() string {
+	n := r.take(1)
+	if n == nil {
+		return ""
+	}
+	return string(r.take(int(n[0])))
+}
+
+// field decodes a fixed-size value with decode unless an error occurred.
+func field[T any](r *reader, name string, size int, decode func(string, []byte) (T, error)) T {
+	var zero T
+	b := r.take(size)
+	if b == nil {
+		return zero
+	}
+	v, err := decode(name, b)
+	if err != nil {
+		r.err = err
+		return zero
+	}
+	return v
+}
+
+func appendStr(buf []byte, s string) []byte {
+	buf = append(buf, byte(len(s)))
+	return append(buf, s...)
+}
+
+func appendPolicy(buf []byte, nodes []wireNode) ([]byte, error) {
+	buf = binary.BigEndian.AppendUint32(buf, uint32(len(nodes)))
+	for _, n := range nodes {
+		if n.Threshold == 0 {
+			buf = appendStr(append(buf, 0), n.Attribute)
+		} else {
+			buf = binary.BigEndian.AppendUint32(append(buf, 1), uint32(n.Threshold))
+			buf = binary.BigEndian.AppendUint32(buf, uint32(n.Children))
+		}
+		x, err := encodeScalar("x", n.X)
+		if err != nil {
+			return nil, err
+		}
+		buf = append(buf, x...)
+	}
+	return buf, nil
+}
+
+func (r *reader) policy() []wireNode {
+	nodes := make([]wireNode, r.count(2+scalarSize))
+	for i := range nodes {
+		t := r.take(1)
+		switch {
+		case t == nil:
+			return nil
+		case t[0] == 0:
+			nodes[i].Attribute = r.str()
+		case t[0] == 1:
+			if b := r.take(8); b != nil {
+				nodes[i].Threshold = int(binary.BigEndian.Uint32(b[:4]))
+				nodes[i].Children = int(binary.BigEndian.Uint32(b[4:]))
+			}
+		default:
+			r.err = malformed("bad policy node type")
+		}
+		nodes[i].X = field(r, "x", scalarSize, decodeScalar)
+		if r.err != nil {
+			return nil
+		}
+	}
+	return nodes
+}
+
+func checkHeader(data []byte, tag byte) ([]byte, error) {
+	if len(data) < 2 || data[0] != WireVersion {
+		return nil, malformed("unsupported wire version")
+	}
+	if data[1] != tag {
+		return nil, malformed("not a %s", wireNames[tag])
+	}
+	return data[2:], nil
+}
+
+// finish reports the first error of r, or trailing data.
+func (r *reader) finish() error {
+	if r.err == nil && len(r.buf) != 0 {
+		r.err = malformed("trailing data")
+	}
+	return r.err
+}
+
+// MarshalBinary implements the wire format described at the top of this file.
+func (CT *Ciphertext) MarshalBinary() ([]byte, error) {
+	nodes, err := flattenCiphertext(CT)
+	if err != nil {
+		return nil, err
+	}
+	buf, err := appendPolicy([]byte{WireVersion, wireCiphertext}, nodes)
+	if err != nil {
+		return nil, err
+	}
+	c, err := encodeGT("c", CT.C)
+	if err != nil {
+		return nil, err
+	}
+	cc, err := encodeG2("cc", CT.CC)
+	if err != nil {
+		return nil, err
+	}
+	buf = append(append(buf, c...), cc...)
+	buf = binary.BigEndian.AppendUint32(buf, uint32(len(CT.Parts)))
+	for _, part := range CT.Parts {
+		c1, err := encodeG1("c1", part.C1)
+		if err != nil {
+			return nil, err
+		}
+		c2, err := encodeG2("c2", part.C2)
+		if err != nil {
+			return nil, err
+		}
+		buf = append(append(buf, c1...), c2...)
+	}
+	return buf, nil
+}
+
+// UnmarshalBinary implements the wire format described at the top of this file.
+func (CT *Ciphertext) UnmarshalBinary(data []byte) error {
+	data, err := checkHeader(data, wireCiphertext)
+	if err != nil {
+		return err
+	}
+	r := &reader{buf: data}
+	nodes := r.policy()
+	out := &Ciphertext{
+		C:  field(r, "c", gtSize, decodeGT),
+		CC: field(r, "cc", g2Size, decodeG2),
+	}
+	out.Parts = make([]*CiphertextPart, r.count(g1Size+g2Size))
+	for i := range out.Parts {
+		out.Parts[i] = &CiphertextPart{
+			C1: field(r, "c1", g1Size, decodeG1),
+			C2: field(r, "c2", g2Size, decodeG2),
+		}
+	}
+	if err := r.finish(); err != nil {
+		return err
+	}
+	if err := setPolicy(out, nodes); err != nil {
+		return err
+	}
+	*CT = *out
+	return nil
+}
+
+// setPolicy builds the policy of a decoded ciphertext, checks that its
+// nodes carry the x-coordinates Encrypt gives them and names its parts
+// after the leaves.
+func setPolicy(out *Ciphertext, nodes []wireNode) error {
+	policy, err := buildPolicy(nodes)
+	if err != nil {
+		return err
+	}
+	out.Xs = shareXs(policy, FieldOrder)
+	leaves := 0
+	for i, n := range nodes {
+		if n.X.Cmp(out.Xs[i]) != 0 {
+			return malformed("x-coordinate of policy node %d does not match the policy", i)
+		}
+		if n.Threshold == 0 {
+			if leaves < len(out.Parts) {
+				out.Parts[leaves].Attribute = n.Attribute
+			}
+			leaves++
+		}
+	}
+	if leaves != len(out.Parts) {
+		return malformed("ciphertext has %d parts for %d attribute leaves", len(out.Parts), leaves)
+	}
+	out.Policy = policy
+	return nil
+}
+
+// MarshalBinary implements the wire format described at the top of this file.
+func (key *AttributeKey) MarshalBinary() ([]byte, error) {
+	d, err := encodeG1("d", key.D)
+	if err != nil {
+		return nil, err
+	}
+	buf := append([]byte{WireVersion, wireAttributeKey}, d...)
+	buf = binary.BigEndian.AppendUint32(buf, uint32(len(key.Parts)))
+	for _, part := range key.Parts {
+		if part == nil || len(part.Attribute) > 255 {
+			return nil, malformed("bad key part")
+		}
+		d1, err := encodeG1("d1", part.D1)
+		if err != nil {
+			return nil, err
+		}
+		d2, err := encodeG2("d2", part.D2)
+		if err != nil {
+			return nil, err
+		}
+		buf = append(append(appendStr(buf, part.Attribute), d1...), d2...)
+	}
+	return buf, nil
+}
+
+// UnmarshalBinary implements the wire format described at the top of this file.
+func (key *AttributeKey) UnmarshalBinary(data []byte) error {
+	data, err := checkHeader(data, wireAttributeKey)
+	if err != nil {
+		return err
+	}
+	r := &reader{buf: data}
+	out := &AttributeKey{D: field(r, "d", g1Size, decodeG1)}
+	out.Parts = make([]*KeyPart, r.count(1+g1Size+g2Size))
+	for i := range out.Parts {
+		out.Parts[i] = &KeyPart{
+			Attribute: r.str(),
+			D1:        field(r, "d1", g1Size, decodeG1),
+			D2:        field(r, "d2", g2Size, decodeG2),
+		}
+	}
+	if err := r.finish(); err != nil {
+		return err
+	}
+	*key = *out
+	return nil
+}
+
+// JSON forms. Fields are listed in the order of the binary encoding.
+
+type ciphertextJSON struct {
+	Version int                  `json:"version"`
+	Type    string               `json:"type"`
+	Policy  []policyNodeJSON     `json:"policy"`
+	C       string               `json:"c"`
+	CC      string               `json:"cc"`
+	Parts   []ciphertextPartJSON `json:"parts"`
+}
+
+type policyNodeJSON struct {
+	Attribute string `json:"attribute,omitempty"`
+	Threshold int    `json:"threshold,omitempty"`
+	Children  int    `json:"children,omitempty"`
+	X         string `json:"x"`
+}
+
+type ciphertextPartJSON struct {
+	C1 string `json:"c1"`
+	C2 string `json:"c2"`
+}
+
+type attributeKeyJSON struct {
+	Version int           `json:"version"`
+	Type    string        `json:"type"`
+	D       string        `json:"d"`
+	Parts   []keyPartJSON `json:"parts"`
+}
+
+type keyPartJSON struct {
+	Attribute string `json:"attribute"`
+	D1        string `json:"d1"`
+	D2        string `json:"d2"`
+}
+
+// unhex decodes a fixed-width lower-case hex field.
+func unhex(name, s string, size int) ([]byte, error) {
+	b, err := hex.DecodeString(s)
+	if err != nil || len(b) != size || hex.EncodeToString(b) != s {
+		return nil, malformed("field %q: want %d bytes of lower-case hex", name, size)
+	}
+	return b, nil
+}
+
+// jsonField decodes a hex field with decode unless an error occurred.
+func jsonField[T any](err *error, name, s string, size int, decode func(string, []byte) (T, error)) T {
+	var zero T
+	if *err != nil {
+		return zero
+	}
+	b, e := unhex(name, s, size)
+	if e != nil {
+		*err = e
+		return zero
+	}
+	v, e := decode(name, b)
+	if e != nil {
+		*err = e
+		return zero
+	}
+	return v
+}
+
+// decodeJSON reads data into v, rejecting unknown keys, and checks the
+// version and type.
+func decodeJSON(data []byte, v any, version *int, typ *string, tag byte) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(v); err != nil {
+		return malformed("%v", err)
+	}
+	if dec.More() {
+		return malformed("trailing data")
+	}
+	if *version != WireVersion {
+		return malformed("unsupported wire version")
+	}
+	if *typ != wireNames[tag] {
+		return malformed("not a %s", wireNames[tag])
+	}
+	return nil
+}
+
+// MarshalJSON implements the wire format described at the top of this file.
+func (CT *Ciphertext) MarshalJSON() ([]byte, error) {
+	// The binary encoding does all the checks.
+	if _, err := CT.MarshalBinary(); err != nil {
+		return nil, err
+	}
+	nodes, _ := flattenCiphertext(CT)
+	out := ciphertextJSON{
+		Version: WireVersion,
+		Type:    wireNames[wireCiphertext],
+		Policy:  make([]policyNodeJSON, len(nodes)),
+		C:       hex.EncodeToString(CT.C.Marshal()),
+		CC:      hex.EncodeToString(CT.CC.Marshal()),
+		Parts:   make([]ciphertextPartJSON, len(CT.Parts)),
+	}
+	for i, n := range nodes {
+		out.Policy[i] = policyNodeJSON{
+			Attribute: n.Attribute,
+			Threshold: n.Threshold,
+			Children:  n.Children,
+			X:         hex.EncodeToString(n.X.FillBytes(make([]byte, scalarSize))),
+		}
+	}
+	for i, part := range CT.Parts {
+		out.Parts[i] = ciphertextPartJSON{
+			C1: hex.EncodeToString(part.C1.Marshal()),
+			C2: hex.EncodeToString(part.C2.Marshal()),
+		}
+	}
+	return json.Marshal(&out)
+}
+
+// UnmarshalJSON implements the wire format described at the top of this file.
+func (CT *Ciphertext) UnmarshalJSON(data []byte) error {
+	var in ciphertextJSON
+	if err := decodeJSON(data, &in, &in.Version, &in.Type, wireCiphertext); err != nil {
+		return err
+	}
+	if len(in.Policy) > maxListSize || len(in.Parts) > maxListSize {
+		return malformed("list too long")
+	}
+	var err error
+	out := &Ciphertext{
+		C:     jsonField(&err, "c", in.C, gtSize, decodeGT),
+		CC:    jsonField(&err, "cc", in.CC, g2Size, decodeG2),
+		Parts: make([]*CiphertextPart, len(in.Parts)),
+	}
+	for i, part := range in.Parts {
+		out.Parts[i] = &CiphertextPart{
+			C1: jsonField(&err, "c1", part.C1, g1Size, decodeG1),
+			C2: jsonField(&err, "c2", part.C2, g2Size, decodeG2),
+		}
+	}
+	if err != nil {
+		return err
+	}
+	nodes := make([]wireNode, len(in.Policy))
+	for i, n := range in.Policy {
+		if len(n.Attribute) > 255 {
+			return malformed("attribute too long")
+		}
+		nodes[i] = wireNode{
+			Attribute: n.Attribute,
+			Threshold: n.Threshold,
+			Children:  n.Children,
+			X:         jsonField(&err, "x", n.X, scalarSize, decodeScalar),
+		}
+	}
+	if err != nil {
+		return err
+	}
+	if err := setPolicy(out, nodes); err != nil {
+		return err
+	}
+	*CT = *out
+	return nil
+}
+
+// MarshalJSON implements the wire format described at the top of this file.
+func (key *AttributeKey) MarshalJSON() ([]byte, error) {
+	if _, err := key.MarshalBinary(); err != nil {
+		return nil, err
+	}
+	out := attributeKeyJSON{
+		Version: WireVersion,
+		Type:    wireNames[wireAttributeKey],
+		D:       hex.EncodeToString(key.D.Marshal()),
+		Parts:   make([]keyPartJSON, len(key.Parts)),
+	}
+	for i, part := range key.Parts {
+		out.Parts[i] = keyPartJSON{
+			Attribute: part.Attribute,
+			D1:        hex.EncodeToString(part.D1.Marshal()),
+			D2:        hex.EncodeToString(part.D2.Marshal()),
+		}
+	}
+	return json.Marshal(&out)
+}
+
+// UnmarshalJSON implements the wire format described at the top of this file.
+func (key *AttributeKey) UnmarshalJSON(data []byte) error {
+	var in attributeKeyJSON
+	if err := decodeJSON(data, &in, &in.Version, &in.Type, wireAttributeKey); err != nil {
+		return err
+	}
+	if len(in.Parts) > maxListSize {
+		return malformed("list too long")
+	}
+	var err error
+	out := &AttributeKey{
+		D:     jsonField(&err, "d", in.D, g1Size, decodeG1),
+		Parts: make([]*KeyPart, len(in.Parts)),
+	}
+	for i, part := range in.Parts {
+		if len(part.Attribute) > 255 {
+			return malformed("attribute too long")
+		}
+		out.Parts[i] = &KeyPart{
+			Attribute: part.Attribute,
+			D1:        jsonField(&err, "d1", part.D1, g1Size, decodeG1),
+			D2:        jsonField(&err, "d2", part.D2, g2Size, decodeG2),
+		}
+	}
+	if err != nil {
+		return err
+	}
+	*key = *out
+	return nil
+}
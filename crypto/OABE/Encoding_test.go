@@ -0,0 +1,283 @@
+package OABE
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"strings"
+	"testing"
+)
+
+type wireValue interface {
+	encoding.BinaryMarshaler
+	encoding.BinaryUnmarshaler
+	json.Marshaler
+	json.Unmarshaler
+}
+
+// sameCiphertext reports whether a and b are equal apart from the
+// attributes and thresholds of their policies.
+func sameCiphertext(a, b *Ciphertext) bool {
+	if !a.C.Equal(b.C) || !a.CC.Equal(b.CC) || len(a.Xs) != len(b.Xs) || len(a.Parts) != len(b.Parts) {
+		return false
+	}
+	for i := range a.Xs {
+		if a.Xs[i].Cmp(b.Xs[i]) != 0 {
+			return false
+		}
+	}
+	for i, part := range a.Parts {
+		if !part.C1.Equal(b.Parts[i].C1) || !part.C2.Equal(b.Parts[i].C2) {
+			return false
+		}
+	}
+	return true
+}
+
+// sameKey reports whether a and b are equal apart from their attributes.
+func sameKey(a, b *AttributeKey) bool {
+	if !a.D.Equal(b.D) || len(a.Parts) != len(b.Parts) {
+		return false
+	}
+	for i, part := range a.Parts {
+		if !part.D1.Equal(b.Parts[i].D1) || !part.D2.Equal(b.Parts[i].D2) {
+			return false
+		}
+	}
+	return true
+}
+
+func TestWireRoundTrip(t *testing.T) {
+	for _, tc := range decryptCases {
+		CT, SK, _, _ := decryptSetup(t, tc.policy, tc.attrs)
+		cases := []struct {
+			name  string
+			v     wireValue
+			fresh func() wireValue
+			same  func(got wireValue) bool
+		}{
+			{"ciphertext", CT, func() wireValue { return new(Ciphertext) }, func(got wireValue) bool {
+				ct := got.(*Ciphertext)
+				return sameCiphertext(ct, CT) && ct.Policy.String() == CT.Policy.String()
+			}},
+			{"attribute key", SK, func() wireValue { return new(AttributeKey) }, func(got wireValue) bool {
+				key := got.(*AttributeKey)
+				for i, part := range key.Parts {
+					if part.Attribute != SK.Parts[i].Attribute {
+						return false
+					}
+				}
+				return sameKey(key, SK)
+			}},
+		}
+		for _, tt := range cases {
+			data, err := tt.v.MarshalBinary()
+			if err != nil {
+				t.Fatalf("%s %s: %v", tc.name, tt.name, err)
+			}
+			got := tt.fresh()
+			if err := got.UnmarshalBinary(data); err != nil {
+				t.Fatalf("%s %s: decoding binary: %v", tc.name, tt.name, err)
+			}
+			if !tt.same(got) {
+				t.Errorf("%s %s: binary decoding differs", tc.name, tt.name)
+			}
+			if again, err := got.MarshalBinary(); err != nil || !bytes.Equal(again, data) {
+				t.Errorf("%s %s: binary encoding does not round-trip", tc.name, tt.name)
+			}
+
+			js, err := tt.v.MarshalJSON()
+			if err != nil {
+				t.Fatalf("%s %s: %v", tc.name, tt.name, err)
+			}
+			got = tt.fresh()
+			if err := got.UnmarshalJSON(js); err != nil {
+				t.Fatalf("%s %s: decoding JSON: %v", tc.name, tt.name, err)
+			}
+			if !tt.same(got) {
+				t.Errorf("%s %s: JSON decoding differs", tc.name, tt.name)
+			}
+			if again, err := got.MarshalJSON(); err != nil || !bytes.Equal(again, js) {
+				t.Errorf("%s %s: JSON encoding does not round-trip", tc.name, tt.name)
+			}
+		}
+	}
+}
+
+func TestWireRejects(t *testing.T) {
+	CT, SK, _, _ := decryptSetup(t, "Owner OR (Community_A AND Hovering_drone)", []string{"Owner", "Community_A"})
+	ctBin, err := CT.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyBin, err := SK.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	modify := func(data []byte, edit func(b []byte) []byte) []byte {
+		return edit(append([]byte(nil), data...))
+	}
+	// The key encoding ends with the d2 of its last part, and the
+	// ciphertext with the c2 of its last part; cc and c precede the parts,
+	// and the x of the last policy node precedes c.
+	parts := 4 + 2*(g1Size+g2Size)
+	ccAt := len(ctBin) - parts - g2Size
+	cAt := ccAt - gtSize
+	xAt := cAt - scalarSize
+	lastD1 := len(keyBin) - g2Size - g1Size
+
+	tests := []struct {
+		name string
+		v    wireValue
+		data []byte
+	}{
+		{"empty", new(Ciphertext), nil},
+		{"version only", new(Ciphertext), ctBin[:1]},
+		{"other version", new(Ciphertext), modify(ctBin, func(b []byte) []byte { b[0] = WireVersion + 1; return b })},
+		{"key as ciphertext", new(Ciphertext), keyBin},
+		{"ciphertext as key", new(AttributeKey), ctBin},
+		{"unknown type", new(AttributeKey), modify(keyBin, func(b []byte) []byte { b[1] = 9; return b })},
+		{"truncated ciphertext", new(Ciphertext), ctBin[:len(ctBin)-1]},
+		{"truncated key", new(AttributeKey), keyBin[:len(keyBin)-1]},
+		{"trailing ciphertext bytes", new(Ciphertext), append(append([]byte(nil), ctBin...), 0)},
+		{"trailing key bytes", new(AttributeKey), append(append([]byte(nil), keyBin...), 0)},
+		{"huge policy", new(Ciphertext), modify(ctBin, func(b []byte) []byte { copy(b[2:], []byte{0xff, 0xff, 0xff, 0xff}); return b })},
+		{"extra part", new(Ciphertext), modify(ctBin, func(b []byte) []byte { b[ccAt+g2Size+3]++; return b })},
+		{"missing part", new(Ciphertext), modify(ctBin, func(b []byte) []byte {
+			b[ccAt+g2Size+3]--
+			return b[:len(b)-g1Size-g2Size]
+		})},
+		{"huge key", new(AttributeKey), modify(keyBin, func(b []byte) []byte { b[2+g1Size] = 0xff; return b })},
+		{"bad policy node", new(Ciphertext), modify(ctBin, func(b []byte) []byte { b[6] = 2; return b })},
+		{"threshold above children", new(Ciphertext), modify(ctBin, func(b []byte) []byte { b[10] = 3; return b })},
+		{"other x", new(Ciphertext), modify(ctBin, func(b []byte) []byte { b[cAt-1] ^= 1; return b })},
+		{"zero x", new(Ciphertext), modify(ctBin, func(b []byte) []byte { copy(b[xAt:cAt], make([]byte, scalarSize)); return b })},
+		{"c not in GT", new(Ciphertext), modify(ctBin, func(b []byte) []byte { b[cAt+gtSize-1] ^= 1; return b })},
+		{"c zero", new(Ciphertext), modify(ctBin, func(b []byte) []byte { copy(b[cAt:ccAt], make([]byte, gtSize)); return b })},
+		{"cc off the curve", new(Ciphertext), modify(ctBin, func(b []byte) []byte { b[ccAt+g2Size-1] ^= 1; return b })},
+		{"cc identity", new(Ciphertext), modify(ctBin, func(b []byte) []byte { copy(b[ccAt:], make([]byte, g2Size)); return b })},
+		{"c2 off the curve", new(Ciphertext), modify(ctBin, func(b []byte) []byte { b[len(b)-1] ^= 1; return b })},
+		{"d1 off the curve", new(AttributeKey), modify(keyBin, func(b []byte) []byte { b[lastD1+g1Size-1] ^= 1; return b })},
+		{"d1 identity", new(AttributeKey), modify(keyBin, func(b []byte) []byte { copy(b[lastD1:], make([]byte, g1Size)); return b })},
+		{"d identity", new(AttributeKey), modify(keyBin, func(b []byte) []byte { copy(b[2:], make([]byte, g1Size)); return b })},
+		{"d2 off the curve", new(AttributeKey), modify(keyBin, func(b []byte) []byte { b[len(b)-1] ^= 1; return b })},
+	}
+	for _, tt := range tests {
+		if err := tt.v.UnmarshalBinary(tt.data); !errors.Is(err, ErrMalformedInput) {
+			t.Errorf("binary, %s: %v, want ErrMalformedInput", tt.name, err)
+		}
+	}
+
+	ctJSON, err := CT.MarshalJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyJSON, err := SK.MarshalJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	edit := func(data []byte, edit func(m map[string]any)) []byte {
+		var fields map[string]any
+		if err := json.Unmarshal(data, &fields); err != nil {
+			t.Fatal(err)
+		}
+		edit(fields)
+		out, err := json.Marshal(fields)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return out
+	}
+	policy := func(m map[string]any) []any { return m["policy"].([]any) }
+	jsonTests := []struct {
+		name string
+		v    wireValue
+		data []byte
+	}{
+		{"unknown key", new(Ciphertext), edit(ctJSON, func(m map[string]any) { m["extra"] = 1 })},
+		{"other version", new(Ciphertext), edit(ctJSON, func(m map[string]any) { m["version"] = WireVersion + 1 })},
+		{"key as ciphertext", new(Ciphertext), keyJSON},
+		{"trailing data", new(AttributeKey), append(append([]byte(nil), keyJSON...), []byte(" {}")...)},
+		{"upper-case hex", new(Ciphertext), edit(ctJSON, func(m map[string]any) { m["cc"] = strings.ToUpper(m["cc"].(string)) })},
+		{"short point", new(AttributeKey), edit(keyJSON, func(m map[string]any) { m["d"] = m["d"].(string)[2:] })},
+		{"identity", new(AttributeKey), edit(keyJSON, func(m map[string]any) { m["d"] = strings.Repeat("00", g1Size) })},
+		{"other x", new(Ciphertext), edit(ctJSON, func(m map[string]any) {
+			policy(m)[1].(map[string]any)["x"] = strings.Repeat("00", scalarSize-1) + "07"
+		})},
+		{"extra node", new(Ciphertext), edit(ctJSON, func(m map[string]any) { m["policy"] = append(policy(m), policy(m)[1]) })},
+		{"empty attribute", new(Ciphertext), edit(ctJSON, func(m map[string]any) { policy(m)[1].(map[string]any)["attribute"] = "" })},
+		{"missing part", new(Ciphertext), edit(ctJSON, func(m map[string]any) { m["parts"] = m["parts"].([]any)[1:] })},
+		{"long attribute", new(AttributeKey), edit(keyJSON, func(m map[string]any) {
+			m["parts"].([]any)[0].(map[string]any)["attribute"] = strings.Repeat("A", 256)
+		})},
+	}
+	for _, tt := range jsonTests {
+		if err := tt.v.UnmarshalJSON(tt.data); !errors.Is(err, ErrMalformedInput) {
+			t.Errorf("JSON, %s: %v, want ErrMalformedInput", tt.name, err)
+		}
+	}
+}
+
+// TestWireBitFlips flips one bit in every byte of both encodings. Only
+// flips in the attributes and thresholds of a policy, which the encoding
+// does not protect, may still decode.
+func TestWireBitFlips(t *testing.T) {
+	CT, SK, _, _ := decryptSetup(t, "2 of (A AND B, C, D)", []string{"A", "B", "C"})
+	ctBin, err := CT.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyBin, err := SK.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := range ctBin {
+		b := append([]byte(nil), ctBin...)
+		b[i] ^= 1 << (i % 8)
+		got := new(Ciphertext)
+		if got.UnmarshalBinary(b) == nil && (!sameCiphertext(got, CT) || got.Policy.String() == CT.Policy.String()) {
+			t.Errorf("ciphertext with byte %d changed decodes", i)
+		}
+	}
+	for i := range keyBin {
+		b := append([]byte(nil), keyBin...)
+		b[i] ^= 1 << (i % 8)
+		got := new(AttributeKey)
+		if got.UnmarshalBinary(b) == nil && !sameKey(got, SK) {
+			t.Errorf("attribute key with byte %d changed decodes", i)
+		}
+	}
+	for n := range ctBin {
+		if new(Ciphertext).UnmarshalBinary(ctBin[:n]) == nil {
+			t.Errorf("ciphertext truncated to %d bytes decodes", n)
+		}
+	}
+	for n := range keyBin {
+		if new(AttributeKey).UnmarshalBinary(keyBin[:n]) == nil {
+			t.Errorf("attribute key truncated to %d bytes decodes", n)
+		}
+	}
+}
+
+// TestShareXs checks that the x-coordinates the decoder expects are those
+// of ComputeShares.
+func TestShareXs(t *testing.T) {
+	for _, policy := range []string{"A", "A OR (B AND C)", "2 of (A AND B AND C, D, E AND F, G)", "x < 1000 OR 2 of (x > 7, y >= 1, z <= 65534)"} {
+		node := mustParse(t, policy)
+		_, want, err := ComputeShares(big.NewInt(5), node, FieldOrder)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got := shareXs(node, FieldOrder)
+		if len(got) != len(want) {
+			t.Fatalf("%s: %d x-coordinates, want %d", policy, len(got), len(want))
+		}
+		for i := range want {
+			if got[i].Cmp(want[i]) != 0 {
+				t.Errorf("%s: x-coordinate %d is %v, want %v", policy, i, got[i], want[i])
+			}
+		}
+	}
+}
@@ -0,0 +1,176 @@
+package OABE
+
+import (
+	"errors"
+	"sort"
+	"strings"
+)
+
+// Normal forms. Policies are monotone, so every policy has a DNF, an OR of
+// ANDs of attributes, and a CNF, an AND of ORs. Both are computed as lists
+// of clauses: the DNF clauses of a k-of-n gate are the unions of the
+// clauses of k of its children, and since the dual of a k-of-n gate is an
+// (n-k+1)-of-n gate, the CNF clauses of a policy are the DNF clauses of its
+// dual. Clauses are kept minimal: repeated attributes, repeated clauses and
+// clauses that contain another clause are dropped.
+
+// ErrPolicyTooLarge is returned when a normal form exceeds its limit.
+var ErrPolicyTooLarge = errors.New("OABE: normal form too large")
+
+// DNF returns the policy in disjunctive normal form: an OR of ANDs of
+// attributes, clauses ordered by size and then by attributes. It fails
+// with ErrPolicyTooLarge if an intermediate result has more than limit
+// clauses.
+func (node *PolicyNode) DNF(limit int) (*PolicyNode, error) {
+	clauses, err := normalClauses(node, false, limit)
+	if err != nil {
+		return nil, err
+	}
+	return clausePolicy(clauses, true), nil
+}
+
+// CNF returns the policy in conjunctive normal form: an AND of ORs of
+// attributes, ordered as in DNF.
+func (node *PolicyNode) CNF(limit int) (*PolicyNode, error) {
+	clauses, err := normalClauses(node, true, limit)
+	if err != nil {
+		return nil, err
+	}
+	return clausePolicy(clauses, false), nil
+}
+
+// clause is a sorted set of attributes.
+type clause []string
+
+func (c clause) key() string { return strings.Join(c, "\x00") }
+
+// contains reports whether c contains every attribute of d.
+func (c clause) contains(d clause) bool {
+	i := 0
+	for _, a := range c {
+		if i < len(d) && a == d[i] {
+			i++
+		}
+	}
+	return i == len(d)
+}
+
+func union(c, d clause) clause {
+	out := make(clause, 0, len(c)+len(d))
+	i, j := 0, 0
+	for i < len(c) || j < len(d) {
+		switch {
+		case j == len(d) || i < len(c) && c[i] < d[j]:
+			out = append(out, c[i])
+			i++
+		case i == len(c) || d[j] < c[i]:
+			out = append(out, d[j])
+			j++
+		default:
+			out = append(out, c[i])
+			i, j = i+1, j+1
+		}
+	}
+	return out
+}
+
+// minimize sorts clauses and drops duplicates and clauses that contain
+// another one.
+func minimize(clauses []clause) []clause {
+	sort.Slice(clauses, func(i, j int) bool {
+		if len(clauses[i]) != len(clauses[j]) {
+			return len(clauses[i]) < len(clauses[j])
+		}
+		return clauses[i].key() < clauses[j].key()
+	})
+	var out []clause
+	for _, c := range clauses {
+		redundant := false
+		for _, d := range out {
+			if c.contains(d) {
+				redundant = true
+				break
+			}
+		}
+		if !redundant {
+			out = append(out, c)
+		}
+	}
+	return out
+}
+
+// product returns the pairwise unions of the clauses of a and b.
+func product(a, b []clause, limit int) ([]clause, error) {
+	if len(a)*len(b) > limit {
+		return nil, ErrPolicyTooLarge
+	}
+	out := make([]clause, 0, len(a)*len(b))
+	for _, c := range a {
+		for _, d := range b {
+			out = append(out, union(c, d))
+		}
+	}
+	return minimize(out), nil
+}
+
+// normalClauses returns the DNF clauses of node, or of its dual.
+func normalClauses(node *PolicyNode, dual bool, limit int) ([]clause, error) {
+	if node.Type == ATTR {
+		return []clause{{node.Attribute}}, nil
+	}
+	n := len(node.Children)
+	k := node.Threshold
+	if dual {
+		k = n - k + 1
+	}
+	// chosen[j] holds the clauses for j of the children seen so far
+	chosen := make([][]clause, k+1)
+	chosen[0] = []clause{{}}
+	for i, child := range node.Children {
+		clauses, err := normalClauses(child, dual, limit)
+		if err != nil {
+			return nil, err
+		}
+		// fewer than k-(n-i-1) chosen children can no longer reach k
+		for j := k; j >= 1 && j >= k-(n-i-1); j-- {
+			if chosen[j-1] == nil {
+				continue
+			}
+			more, err := product(chosen[j-1], clauses, limit)
+			if err != nil {
+				return nil, err
+			}
+			chosen[j] = minimize(append(chosen[j], more...))
+			if len(chosen[j]) > limit {
+				return nil, ErrPolicyTooLarge
+			}
+		}
+	}
+	return chosen[k], nil
+}
+
+// clausePolicy builds an OR of ANDs (dnf) or an AND of ORs from clauses,
+// collapsing gates with a single child.
+func clausePolicy(clauses []clause, dnf bool) *PolicyNode {
+	gate := func(children []*PolicyNode, and bool) *PolicyNode {
+		if len(children) == 1 {
+			return children[0]
+		}
+		threshold := 1
+		if and {
+			threshold = len(children)
+		}
+		return &PolicyNode{Type: THRESHOLD, Threshold: threshold, Children: children}
+	}
+	outer := make([]*PolicyNode, len(clauses))
+	for i, c := range clauses {
+		inner := make([]*PolicyNode, len(c))
+		for j, attr := range c {
+			inner[j] = leaf(attr)
+		}
+		outer[i] = gate(inner, dnf)
+	}
+	root := gate(outer, !dnf)
+	root.Number()
+	return root
+}
@@ -0,0 +1,489 @@
+package OABE
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Policy language. A policy is a monotone boolean formula over attributes:
+//
+//	policy     = or
+//	or         = and { "OR" and }
+//	and        = term { "AND" term }
+//	term       = "(" policy ")" | threshold | comparison | attribute
+//	threshold  = number "of" "(" policy { "," policy } ")"
+//	comparison = attribute ( "<" | "<=" | ">" | ">=" | "==" ) number
+//	attribute  = word | quoted
+//
+// AND binds tighter than OR and both are case-insensitive; "k of (...)" is
+// satisfied by any k of its arguments. A word is a run of characters other
+// than space, parentheses, commas, quotes and comparison operators, e.g.
+// Community_A or drone-2; a quoted attribute is a Go string literal and may
+// hold anything, e.g. "Hovering drone". A word that is a keyword or a number
+// must be quoted to be used as an attribute. The form t-of-(k, a, b, ...) of
+// earlier versions is still accepted.
+//
+// There is no NOT. A comparison on a numeric attribute compiles into a
+// formula over its bits, see NumericAttributes, so keys and ciphertexts only
+// ever deal with plain attributes.
+//
+// String prints a policy in canonical form, which parses back to the same
+// tree: chains of AND and OR print without parentheses, nested gates with
+// them, and other thresholds as "k of (...)".
+
+// PolicyError reports an error in a policy at byte offset Pos.
+type PolicyError struct {
+	Policy string
+	Pos    int
+	Msg    string
+}
+
+func (e *PolicyError) Error() string {
+	return fmt.Sprintf("OABE: policy column %d: %s", utf8.RuneCountInString(e.Policy[:e.Pos])+1, e.Msg)
+}
+
+// NumericBits is the width of numeric attributes: comparisons accept bounds
+// from 0 to 2^NumericBits-1.
+const NumericBits = 16
+
+// NumericAttributes returns the attributes a key needs to satisfy
+// comparisons on the numeric attribute name with the given value, one per
+// bit.
+func NumericAttributes(name string, value uint16) []string {
+	attrs := make([]string, NumericBits)
+	for i := range attrs {
+		attrs[i] = bitAttribute(name, i, uint(value>>i)&1)
+	}
+	return attrs
+}
+
+func bitAttribute(name string, i int, bit uint) string {
+	return fmt.Sprintf("%s#%d=%d", name, i, bit)
+}
+
+type tokenKind int
+
+const (
+	tokEOF tokenKind = iota
+	tokWord
+	tokQuoted
+	tokLParen
+	tokRParen
+	tokComma
+	tokCompare
+)
+
+type token struct {
+	kind tokenKind
+	text string // the word, the unquoted string or the operator
+	pos  int
+}
+
+func isWordByte(c byte) bool {
+	switch c {
+	case ' ', '\t', '\n', '\r', '(', ')', ',', '"', '<', '>', '=':
+		return false
+	}
+	return true
+}
+
+// lexPolicy splits policy into tokens.
+func lexPolicy(policy string) ([]token, error) {
+	var toks []token
+	for i := 0; i < len(policy); {
+		c := policy[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case c == '(':
+			toks = append(toks, token{tokLParen, "(", i})
+			i++
+		case c == ')':
+			toks = append(toks, token{tokRParen, ")", i})
+			i++
+		case c == ',':
+			toks = append(toks, token{tokComma, ",", i})
+			i++
+		case c == '<' || c == '>' || c == '=':
+			op := policy[i : i+1]
+			if i+1 < len(policy) && policy[i+1] == '=' {
+				op = policy[i : i+2]
+			}
+			if op == "=" {
+				return nil, &PolicyError{policy, i, `unexpected "=", comparisons use "=="`}
+			}
+			toks = append(toks, token{tokCompare, op, i})
+			i += len(op)
+		case c == '"':
+			j := i + 1
+			for j < len(policy) && policy[j] != '"' {
+				if policy[j] == '\\' {
+					j++
+				}
+				j++
+			}
+			if j >= len(policy) {
+				return nil, &PolicyError{policy, i, "unterminated quoted attribute"}
+			}
+			s, err := strconv.Unquote(policy[i : j+1])
+			if err != nil {
+				return nil, &PolicyError{policy, i, "bad quoted attribute"}
+			}
+			toks = append(toks, token{tokQuoted, s, i})
+			i = j + 1
+		default:
+			j := i
+			for j < len(policy) && isWordByte(policy[j]) {
+				j++
+			}
+			toks = append(toks, token{tokWord, policy[i:j], i})
+			i = j
+		}
+	}
+	return append(toks, token{tokEOF, "", len(policy)}), nil
+}
+
+type policyParser struct {
+	policy string
+	toks   []token
+	next   int
+}
+
+func (p *policyParser) peek() token { return p.toks[p.next] }
+
+func (p *policyParser) advance() token {
+	t := p.toks[p.next]
+	if t.kind != tokEOF {
+		p.next++
+	}
+	return t
+}
+
+func (p *policyParser) errorf(t token, format string, args ...any) error {
+	return &PolicyError{p.policy, t.pos, fmt.Sprintf(format, args...)}
+}
+
+// describe names a token for error messages.
+func describe(t token) string {
+	switch t.kind {
+	case tokEOF:
+		return "end of policy"
+	case tokQuoted:
+		return strconv.Quote(t.text)
+	}
+	return fmt.Sprintf("%q", t.text)
+}
+
+func isKeyword(t token, kw string) bool {
+	return t.kind == tokWord && strings.EqualFold(t.text, kw)
+}
+
+func isNumber(s string) bool {
+	if s == "" {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+func (p *policyParser) expect(kind tokenKind, what string) (token, error) {
+	t := p.advance()
+	if t.kind != kind {
+		return t, p.errorf(t, "expected %s, found %s", what, describe(t))
+	}
+	return t, nil
+}
+
+// chain parses operands separated by the keyword op into one gate; a
+// single operand is returned as is.
+func (p *policyParser) chain(op string, operand func() (*PolicyNode, error)) (*PolicyNode, error) {
+	first, err := operand()
+	if err != nil {
+		return nil, err
+	}
+	children := []*PolicyNode{first}
+	for isKeyword(p.peek(), op) {
+		p.advance()
+		child, err := operand()
+		if err != nil {
+			return nil, err
+		}
+		children = append(children, child)
+	}
+	if len(children) == 1 {
+		return first, nil
+	}
+	threshold := 1
+	if op == "AND" {
+		threshold = len(children)
+	}
+	return &PolicyNode{Type: THRESHOLD, Threshold: threshold, Children: children}, nil
+}
+
+func (p *policyParser) or() (*PolicyNode, error) {
+	return p.chain("OR", p.and)
+}
+
+func (p *policyParser) and() (*PolicyNode, error) {
+	return p.chain("AND", p.term)
+}
+
+func (p *policyParser) term() (*PolicyNode, error) {
+	t := p.advance()
+	switch {
+	case t.kind == tokLParen:
+		node, err := p.or()
+		if err != nil {
+			return nil, err
+		}
+		if _, err := p.expect(tokRParen, `")"`); err != nil {
+			return nil, err
+		}
+		return node, nil
+	case t.kind == tokWord && isNumber(t.text) && isKeyword(p.peek(), "of"):
+		p.advance()
+		if _, err := p.expect(tokLParen, `"(" after "of"`); err != nil {
+			return nil, err
+		}
+		return p.threshold(t)
+	case t.kind == tokWord && strings.EqualFold(t.text, "t-of-") && p.peek().kind == tokLParen:
+		p.advance()
+		k, err := p.expect(tokWord, "a threshold")
+		if err == nil && !isNumber(k.text) {
+			err = p.errorf(k, "expected a threshold, found %s", describe(k))
+		}
+		if err == nil {
+			_, err = p.expect(tokComma, `","`)
+		}
+		if err != nil {
+			return nil, err
+		}
+		return p.threshold(k)
+	case t.kind == tokQuoted || t.kind == tokWord && !isKeyword(t, "AND") && !isKeyword(t, "OR") && !isKeyword(t, "of") && !isNumber(t.text):
+		if t.text == "" {
+			return nil, p.errorf(t, "empty attribute")
+		}
+		if len(t.text) > 255 {
+			return nil, p.errorf(t, "attribute longer than 255 bytes")
+		}
+		if p.peek().kind == tokCompare {
+			return p.comparison(t)
+		}
+		return &PolicyNode{Type: ATTR, Attribute: t.text}, nil
+	}
+	return nil, p.errorf(t, "expected an attribute, found %s", describe(t))
+}
+
+// threshold parses the arguments of a threshold gate with threshold k, up
+// to the closing parenthesis.
+func (p *policyParser) threshold(k token) (*PolicyNode, error) {
+	var children []*PolicyNode
+	for {
+		child, err := p.or()
+		if err != nil {
+			return nil, err
+		}
+		children = append(children, child)
+		t := p.advance()
+		if t.kind == tokRParen {
+			break
+		}
+		if t.kind != tokComma {
+			return nil, p.errorf(t, `expected "," or ")", found %s`, describe(t))
+		}
+	}
+	n, err := strconv.Atoi(k.text)
+	if err != nil || n < 1 || n > len(children) {
+		return nil, p.errorf(k, "threshold %s of %d arguments", k.text, len(children))
+	}
+	return &PolicyNode{Type: THRESHOLD, Threshold: n, Children: children}, nil
+}
+
+// comparison compiles attribute op bound into a formula over the bits of
+// attribute.
+func (p *policyParser) comparison(attr token) (*PolicyNode, error) {
+	op := p.advance()
+	t, err := p.expect(tokWord, "a number")
+	if err == nil && !isNumber(t.text) {
+		err = p.errorf(t, "expected a number, found %s", describe(t))
+	}
+	if err != nil {
+		return nil, err
+	}
+	const max = 1<<NumericBits - 1
+	bound, err := strconv.ParseUint(t.text, 10, 64)
+	if err != nil || bound > max {
+		return nil, p.errorf(t, "bound %s is larger than %d", t.text, max)
+	}
+	name := attr.text
+	var node *PolicyNode
+	switch {
+	case op.text == "==":
+		for i := NumericBits - 1; i >= 0; i-- {
+			node = and(leaf(bitAttribute(name, i, uint(bound>>i)&1)), node, true)
+		}
+		return node, nil
+	case op.text == ">" && bound == max, op.text == "<" && bound == 0:
+		return nil, p.errorf(op, "%s %s %d is never satisfied", name, op.text, bound)
+	case op.text == ">=" && bound == 0, op.text == "<=" && bound == max:
+		// true for every value: ask for the attribute only
+		return or(leaf(bitAttribute(name, 0, 0)), leaf(bitAttribute(name, 0, 1))), nil
+	case op.text == ">=":
+		return greater(name, bound-1), nil
+	case op.text == ">":
+		return greater(name, bound), nil
+	case op.text == "<=":
+		return less(name, bound+1), nil
+	}
+	return less(name, bound), nil
+}
+
+// greater returns the formula for name > c. From the lowest bit up, x > c
+// on bits 0..i holds if bit i of x is 1 and, where bit i of c is 1, x > c
+// holds on the bits below as well. Each key has exactly one of the
+// attributes bit i = 0 and bit i = 1, so the formula needs no negation.
+func greater(name string, c uint64) *PolicyNode {
+	var node *PolicyNode // false
+	for i := 0; i < NumericBits; i++ {
+		bit := leaf(bitAttribute(name, i, 1))
+		if c>>i&1 == 0 {
+			node = or(bit, node)
+		} else {
+			node = and(bit, node, false)
+		}
+	}
+	return node
+}
+
+// less returns the formula for name < c, built like greater.
+func less(name string, c uint64) *PolicyNode {
+	var node *PolicyNode // false
+	for i := 0; i < NumericBits; i++ {
+		bit := leaf(bitAttribute(name, i, 0))
+		if c>>i&1 == 1 {
+			node = or(bit, node)
+		} else {
+			node = and(bit, node, false)
+		}
+	}
+	return node
+}
+
+func leaf(attr string) *PolicyNode {
+	return &PolicyNode{Type: ATTR, Attribute: attr}
+}
+
+// or returns a OR b, merging b into the gate if it is an OR. nil is false.
+func or(a, b *PolicyNode) *PolicyNode {
+	if b == nil {
+		return a
+	}
+	if b.Type == THRESHOLD && b.Threshold == 1 {
+		return &PolicyNode{Type: THRESHOLD, Threshold: 1, Children: append([]*PolicyNode{a}, b.Children...)}
+	}
+	return &PolicyNode{Type: THRESHOLD, Threshold: 1, Children: []*PolicyNode{a, b}}
+}
+
+// and returns a AND b, merging b into the gate if it is an AND. nil is
+// false, or true if nilTrue is set.
+func and(a, b *PolicyNode, nilTrue bool) *PolicyNode {
+	if b == nil {
+		if nilTrue {
+			return a
+		}
+		return nil
+	}
+	if b.Type == THRESHOLD && b.Threshold == len(b.Children) {
+		children := append([]*PolicyNode{a}, b.Children...)
+		return &PolicyNode{Type: THRESHOLD, Threshold: len(children), Children: children}
+	}
+	return &PolicyNode{Type: THRESHOLD, Threshold: 2, Children: []*PolicyNode{a, b}}
+}
+
+// ParsePolicy parses a policy in the language described above and numbers
+// its nodes. Errors are *PolicyError.
+func ParsePolicy(policy string) (*PolicyNode, error) {
+	toks, err := lexPolicy(policy)
+	if err != nil {
+		return nil, err
+	}
+	p := &policyParser{policy: policy, toks: toks}
+	if p.peek().kind == tokEOF {
+		return nil, p.errorf(p.peek(), "empty policy")
+	}
+	root, err := p.or()
+	if err != nil {
+		return nil, err
+	}
+	if t := p.peek(); t.kind != tokEOF {
+		return nil, p.errorf(t, "expected AND, OR or end of policy, found %s", describe(t))
+	}
+	root.Number()
+	return root, nil
+}
+
+// String returns the canonical form of the policy rooted at node.
+func (node *PolicyNode) String() string {
+	var b strings.Builder
+	node.format(&b, false)
+	return b.String()
+}
+
+// format writes node, in parentheses if it is a gate and nested is set.
+func (node *PolicyNode) format(b *strings.Builder, nested bool) {
+	if node.Type == ATTR {
+		b.WriteString(quoteAttribute(node.Attribute))
+		return
+	}
+	n := len(node.Children)
+	op := ""
+	switch {
+	case n >= 2 && node.Threshold == n:
+		op = " AND "
+	case n >= 2 && node.Threshold == 1:
+		op = " OR "
+	}
+	if op == "" {
+		fmt.Fprintf(b, "%d of (", node.Threshold)
+		for i, child := range node.Children {
+			if i > 0 {
+				b.WriteString(", ")
+			}
+			child.format(b, false)
+		}
+		b.WriteString(")")
+		return
+	}
+	if nested {
+		b.WriteString("(")
+	}
+	for i, child := range node.Children {
+		if i > 0 {
+			b.WriteString(op)
+		}
+		child.format(b, true)
+	}
+	if nested {
+		b.WriteString(")")
+	}
+}
+
+// quoteAttribute returns attr as a word if it reads back as one and as a
+// quoted string otherwise.
+func quoteAttribute(attr string) string {
+	word := attr != "" && !isNumber(attr) && !strings.EqualFold(attr, "AND") && !strings.EqualFold(attr, "OR") &&
+		!strings.EqualFold(attr, "of") && !strings.EqualFold(attr, "t-of-")
+	for i := 0; word && i < len(attr); i++ {
+		word = isWordByte(attr[i]) && attr[i] >= 0x20 && attr[i] < 0x7f
+	}
+	if word {
+		return attr
+	}
+	return strconv.Quote(attr)
+}
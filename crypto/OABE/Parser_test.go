@@ -0,0 +1,233 @@
+package OABE
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestParsePolicyString(t *testing.T) {
+	tests := []struct {
+		policy string
+		want   string // canonical form
+	}{
+		{"A", "A"},
+		{"((A))", "A"},
+		{"A and B or C", "(A AND B) OR C"},
+		{"A AND (B OR C)", "A AND (B OR C)"},
+		{"A AND (B AND C)", "A AND (B AND C)"},
+		{"Owner OR (Community_A AND Hovering_drone)", "Owner OR (Community_A AND Hovering_drone)"},
+		{"2 of (A, B AND C, D)", "2 of (A, B AND C, D)"},
+		{"2 OF (A,B,C)", "2 of (A, B, C)"},
+		{"1 of (A)", "1 of (A)"},
+		{"t-of-(2, A, B, C)", "2 of (A, B, C)"},
+		{"3 of (A, B, C)", "A AND B AND C"},
+		{"1 of (A, B)", "A OR B"},
+		{`"Hovering drone" OR drone-2`, `"Hovering drone" OR drone-2`},
+		{`"AND" AND "of" AND "7" AND "t-of-"`, `"AND" AND "of" AND "7" AND "t-of-"`},
+		{`"a\"b" OR "tab\there"`, `"a\"b" OR "tab\there"`},
+		{"floor == 5", ""},
+		{"floor >= 3 AND floor <= 12", ""},
+		{"x < 1000 OR 2 of (x > 7, y >= 1, z <= 65534)", ""},
+	}
+	for _, tt := range tests {
+		node, err := ParsePolicy(tt.policy)
+		if err != nil {
+			t.Errorf("%s: %v", tt.policy, err)
+			continue
+		}
+		got := node.String()
+		if tt.want != "" && got != tt.want {
+			t.Errorf("%s prints as %s, want %s", tt.policy, got, tt.want)
+		}
+		again, err := ParsePolicy(got)
+		if err != nil {
+			t.Errorf("%s: canonical form %s: %v", tt.policy, got, err)
+			continue
+		}
+		if again.String() != got {
+			t.Errorf("%s: canonical form %s prints as %s", tt.policy, got, again.String())
+		}
+		if len(again.Number()) != len(node.Number()) {
+			t.Errorf("%s: canonical form %s has another shape", tt.policy, got)
+		}
+	}
+}
+
+func TestParsePolicyErrors(t *testing.T) {
+	tests := []struct {
+		policy string
+		want   string
+	}{
+		{"", `OABE: policy column 1: empty policy`},
+		{"A AND", `OABE: policy column 6: expected an attribute, found end of policy`},
+		{"A OR OR B", `OABE: policy column 6: expected an attribute, found "OR"`},
+		{"A B", `OABE: policy column 3: expected AND, OR or end of policy, found "B"`},
+		{"(A", `OABE: policy column 3: expected ")", found end of policy`},
+		{"A)", `OABE: policy column 2: expected AND, OR or end of policy, found ")"`},
+		{"3 of (A,B)", `OABE: policy column 1: threshold 3 of 2 arguments`},
+		{"0 of (A,B)", `OABE: policy column 1: threshold 0 of 2 arguments`},
+		{"2 of A, B", `OABE: policy column 6: expected "(" after "of", found "A"`},
+		{"2 of (A B)", `OABE: policy column 9: expected "," or ")", found "B"`},
+		{"t-of-(x, A)", `OABE: policy column 7: expected a threshold, found "x"`},
+		{`A AND "B`, `OABE: policy column 7: unterminated quoted attribute`},
+		{`"B\"`, `OABE: policy column 1: unterminated quoted attribute`},
+		{`"\q"`, `OABE: policy column 1: bad quoted attribute`},
+		{`A OR ""`, `OABE: policy column 6: empty attribute`},
+		{"x = 3", `OABE: policy column 3: unexpected "=", comparisons use "=="`},
+		{"x >= y", `OABE: policy column 6: expected a number, found "y"`},
+		{"x >= 65536", `OABE: policy column 6: bound 65536 is larger than 65535`},
+		{"x > 65535", `OABE: policy column 3: x > 65535 is never satisfied`},
+		{"x < 0", `OABE: policy column 3: x < 0 is never satisfied`},
+		{"7", `OABE: policy column 1: expected an attribute, found "7"`},
+		{"é AND", `OABE: policy column 6: expected an attribute, found end of policy`},
+	}
+	for _, tt := range tests {
+		_, err := ParsePolicy(tt.policy)
+		var pe *PolicyError
+		if !errors.As(err, &pe) {
+			t.Errorf("%q: %v, want a *PolicyError", tt.policy, err)
+			continue
+		}
+		if err.Error() != tt.want {
+			t.Errorf("%q: %v, want %s", tt.policy, err, tt.want)
+		}
+	}
+}
+
+func TestComparisonBoundaries(t *testing.T) {
+	const max = 1<<NumericBits - 1
+	holds := map[string]func(v, c int) bool{
+		"<":  func(v, c int) bool { return v < c },
+		"<=": func(v, c int) bool { return v <= c },
+		">":  func(v, c int) bool { return v > c },
+		">=": func(v, c int) bool { return v >= c },
+		"==": func(v, c int) bool { return v == c },
+	}
+	for _, bound := range []int{0, 1, 2, 7, 8, 255, 256, 1000, 32768, max - 1, max} {
+		for op, want := range holds {
+			node, err := ParsePolicy(fmt.Sprintf("x %s %d", op, bound))
+			if op == ">" && bound == max || op == "<" && bound == 0 {
+				if err == nil {
+					t.Errorf("x %s %d parses", op, bound)
+				}
+				continue
+			}
+			if err != nil {
+				t.Fatalf("x %s %d: %v", op, bound, err)
+			}
+			for _, v := range []int{0, bound - 1, bound, bound + 1, max} {
+				if v < 0 || v > max {
+					continue
+				}
+				if got := node.Satisfies(NumericAttributes("x", uint16(v))); got != want(v, bound) {
+					t.Errorf("x %s %d for x = %d: %v, want %v", op, bound, v, got, want(v, bound))
+				}
+			}
+		}
+	}
+
+	// Every value against one bound.
+	for op, want := range holds {
+		node, err := ParsePolicy("x " + op + " 1000")
+		if err != nil {
+			t.Fatal(err)
+		}
+		for v := 0; v <= max; v++ {
+			if node.Satisfies(NumericAttributes("x", uint16(v))) != want(v, 1000) {
+				t.Fatalf("x %s 1000 for x = %d: wrong", op, v)
+			}
+		}
+	}
+}
+
+func TestNormalForms(t *testing.T) {
+	tests := []struct {
+		policy   string
+		dnf, cnf string
+	}{
+		{"A", "A", "A"},
+		{"A AND B", "A AND B", "A AND B"},
+		{"A OR B", "A OR B", "A OR B"},
+		{"(A OR B) AND C", "(A AND C) OR (B AND C)", "C AND (A OR B)"},
+		{"(A AND B) OR C", "C OR (A AND B)", "(A OR C) AND (B OR C)"},
+		{"2 of (A, B, C)", "(A AND B) OR (A AND C) OR (B AND C)", "(A OR B) AND (A OR C) AND (B OR C)"},
+		{"(A AND B) OR A", "A", "A"},
+		{"(A OR B) AND (B OR A) AND A", "A", "A"},
+		{"(B AND A) OR (A AND B AND C)", "A AND B", "A AND B"},
+	}
+	for _, tt := range tests {
+		node, err := ParsePolicy(tt.policy)
+		if err != nil {
+			t.Fatal(err)
+		}
+		dnf, err := node.DNF(100)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.policy, err)
+		}
+		cnf, err := node.CNF(100)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.policy, err)
+		}
+		if dnf.String() != tt.dnf {
+			t.Errorf("DNF of %s: %s, want %s", tt.policy, dnf, tt.dnf)
+		}
+		if cnf.String() != tt.cnf {
+			t.Errorf("CNF of %s: %s, want %s", tt.policy, cnf, tt.cnf)
+		}
+
+		// Both forms are satisfied by the same subsets of A, B and C.
+		for mask := 0; mask < 8; mask++ {
+			var attrs []string
+			for i, attr := range []string{"A", "B", "C"} {
+				if mask>>i&1 == 1 {
+					attrs = append(attrs, attr)
+				}
+			}
+			want := node.Satisfies(attrs)
+			if dnf.Satisfies(attrs) != want || cnf.Satisfies(attrs) != want {
+				t.Errorf("%s: normal forms disagree on %v", tt.policy, attrs)
+			}
+		}
+	}
+}
+
+func TestNormalFormLimit(t *testing.T) {
+	// The DNF of ten ORs of two has 2^10 clauses; its CNF has ten.
+	policy := "(A0 OR B0)"
+	for i := 1; i < 10; i++ {
+		policy += fmt.Sprintf(" AND (A%d OR B%d)", i, i)
+	}
+	node, err := ParsePolicy(policy)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := node.DNF(1000); !errors.Is(err, ErrPolicyTooLarge) {
+		t.Errorf("DNF with limit 1000: %v, want ErrPolicyTooLarge", err)
+	}
+	dnf, err := node.DNF(1024)
+	if err != nil {
+		t.Fatalf("DNF with limit 1024: %v", err)
+	}
+	if n := len(dnf.Children); n != 1024 {
+		t.Errorf("DNF has %d clauses, want 1024", n)
+	}
+	cnf, err := node.CNF(10)
+	if err != nil {
+		t.Fatalf("CNF with limit 10: %v", err)
+	}
+	if n := len(cnf.Children); n != 10 {
+		t.Errorf("CNF has %d clauses, want 10", n)
+	}
+
+	two, err := ParsePolicy("2 of (A, B, C)")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := two.DNF(2); !errors.Is(err, ErrPolicyTooLarge) {
+		t.Errorf("DNF of 2 of (A, B, C) with limit 2: %v, want ErrPolicyTooLarge", err)
+	}
+	if _, err := two.CNF(2); !errors.Is(err, ErrPolicyTooLarge) {
+		t.Errorf("CNF of 2 of (A, B, C) with limit 2: %v, want ErrPolicyTooLarge", err)
+	}
+}
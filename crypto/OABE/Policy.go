@@ -5,8 +5,6 @@ import (
 	"errors"
 	"fmt"
 	"math/big"
-	"regexp"
-	"strconv"
 	"strings"
 
 	bn256 "Obfushop/bn256"
@@ -26,22 +24,42 @@ type PolicyNode struct {
 	Threshold int
 	Attribute string
 	Children  []*PolicyNode
+	ID        int // position in depth-first order, see Number
+}
+
+// Number sets the ID of every node of the policy rooted at node to its
+// position in depth-first order and returns the nodes in that order. IDs
+// depend only on the shape of the policy, so they survive encoding. ParsePolicy
+// and the decoders number their policies; trees built by hand must be
+// numbered before use.
+func (node *PolicyNode) Number() []*PolicyNode {
+	var nodes []*PolicyNode
+	var walk func(n *PolicyNode)
+	walk = func(n *PolicyNode) {
+		n.ID = len(nodes)
+		nodes = append(nodes, n)
+		for _, child := range n.Children {
+			walk(child)
+		}
+	}
+	walk(node)
+	return nodes
 }
 
 type AttributeShare struct {
 	Attribute string
+	Node      int // ID of the leaf
 	Share     *big.Int
 	X         *big.Int
 }
 
 type DecShare struct {
 	Attribute string
+	Node      int // ID of the leaf
 	Share     *bn256.GT
 	X         *big.Int
 }
 
-type xsMapType map[*PolicyNode][]*big.Int
-
 // EvaluatePolynomial evaluates a polynomial at x modulo p
 func EvaluatePolynomial(coeffs []*big.Int, x *big.Int, p *big.Int) *big.Int {
 	result := big.NewInt(0)
@@ -143,12 +161,39 @@ func RecoverSecretAt(xs []*big.Int, ys []*big.Int, xRecover *big.Int, p *big.Int
 	return secret
 }
 
-func ComputeShares(secret *big.Int, node *PolicyNode, p *big.Int) ([]AttributeShare, xsMapType, error) {
+// ComputeShares shares secret along the policy rooted at node. It returns
+// the shares of the attribute leaves in depth-first order and the
+// x-coordinates of the shares of all nodes, indexed by node ID.
+func ComputeShares(secret *big.Int, node *PolicyNode, p *big.Int) ([]AttributeShare, []*big.Int, error) {
 	var result []AttributeShare
-	xsMap := make(xsMapType)
+	xs := make([]*big.Int, len(node.Number()))
+	xs[node.ID] = big.NewInt(1)
 	xGen := NewXGenerator()
-	err := computeSharesHelperWithX(secret, node, &result, p, big.NewInt(1), xsMap, xGen)
-	return result, xsMap, err
+	err := computeSharesHelperWithX(secret, node, &result, p, xs[node.ID], xs, xGen)
+	return result, xs, err
+}
+
+// shareXs returns the x-coordinates ComputeShares gives the nodes of the
+// numbered policy rooted at node, indexed by node ID. They depend only on
+// the shape of the policy.
+func shareXs(node *PolicyNode, p *big.Int) []*big.Int {
+	xs := make([]*big.Int, len(node.Number()))
+	xs[node.ID] = big.NewInt(1)
+	xGen := NewXGenerator()
+	var walk func(node *PolicyNode)
+	walk = func(node *PolicyNode) {
+		if node.Type == ATTR {
+			return
+		}
+		startX := xGen.Next()
+		for i, child := range node.Children {
+			x := new(big.Int).Mul(xs[node.ID], big.NewInt(int64(startX+i)))
+			xs[child.ID] = x.Mod(x, p)
+			walk(child)
+		}
+	}
+	walk(node)
+	return xs
 }
 
 // XGenerator 用于生成唯一 startX
@@ -165,13 +210,14 @@ func (gen *XGenerator) Next() int {
 	return gen.counter
 }
 
-func computeSharesHelperWithX(secret *big.Int, node *PolicyNode, result *[]AttributeShare, p *big.Int, x *big.Int, xsMap xsMapType, xGen *XGenerator) error {
+func computeSharesHelperWithX(secret *big.Int, node *PolicyNode, result *[]AttributeShare, p *big.Int, x *big.Int, xs []*big.Int, xGen *XGenerator) error {
 	if node == nil {
 		return nil
 	}
 	if node.Type == ATTR {
 		*result = append(*result, AttributeShare{
 			Attribute: node.Attribute,
+			Node:      node.ID,
 			X:         new(big.Int).Set(x),
 			Share:     new(big.Int).Set(secret),
 		})
@@ -182,17 +228,16 @@ func computeSharesHelperWithX(secret *big.Int, node *PolicyNode, result *[]Attri
 	k := node.Threshold
 
 	startX := xGen.Next() // 每一层使用独立 startX 起点
-	xs, ys, err := GenerateShares(secret, k, n, p, startX)
+	childXs, ys, err := GenerateShares(secret, k, n, p, startX)
 	if err != nil {
 		return err
 	}
 
-	xsMap[node] = make([]*big.Int, n)
 	for i := 0; i < n; i++ {
-		newX := new(big.Int).Mul(x, xs[i])
+		newX := new(big.Int).Mul(x, childXs[i])
 		newX.Mod(newX, p)
-		xsMap[node][i] = newX
-		err := computeSharesHelperWithX(ys[i], node.Children[i], result, p, newX, xsMap, xGen)
+		xs[node.Children[i].ID] = newX
+		err := computeSharesHelperWithX(ys[i], node.Children[i], result, p, newX, xs, xGen)
 		if err != nil {
 			return err
 		}
@@ -200,101 +245,6 @@ func computeSharesHelperWithX(secret *big.Int, node *PolicyNode, result *[]Attri
 	return nil
 }
 
-func ParsePolicy(policy string) (*PolicyNode, error) {
-	converted, err := ConvertPolicyExpr(policy)
-	if err != nil {
-		return nil, err
-	}
-	return parseThresholdExpr(converted)
-}
-
-func parseThresholdExpr(s string) (*PolicyNode, error) {
-	s = strings.TrimSpace(s)
-	if len(s) == 0 {
-		return nil, errors.New("empty policy expression")
-	}
-	for strings.HasPrefix(s, "(") && strings.HasSuffix(s, ")") {
-		inner := s[1 : len(s)-1]
-		if balancedBrackets(inner) {
-			s = inner
-		} else {
-			break
-		}
-	}
-
-	if strings.HasPrefix(s, "t-of-(") {
-		re := regexp.MustCompile(`t-of-\((\d+),(.+)\)$`)
-
-		matches := re.FindStringSubmatch(s)
-		if len(matches) != 3 {
-			return nil, fmt.Errorf("invalid threshold format: %s", s)
-		}
-		k, err := strconv.Atoi(matches[1])
-		if err != nil {
-			return nil, fmt.Errorf("invalid threshold value: %s", matches[1])
-		}
-		rest := matches[2]
-		parts, err := splitTopLevel(rest)
-		if err != nil {
-			return nil, err
-		}
-		if k > len(parts) {
-			return nil, fmt.Errorf("threshold k (%d) > number of children (%d)", k, len(parts))
-		}
-		children := []*PolicyNode{}
-		for _, part := range parts {
-			child, err := parseThresholdExpr(part)
-			if err != nil {
-				return nil, err
-			}
-			children = append(children, child)
-		}
-		return &PolicyNode{
-			Type:      THRESHOLD,
-			Threshold: k,
-			Children:  children,
-		}, nil
-	}
-
-	return &PolicyNode{Type: ATTR, Attribute: s}, nil
-}
-
-func balancedBrackets(s string) bool {
-	count := 0
-	for _, ch := range s {
-		if ch == '(' {
-			count++
-		} else if ch == ')' {
-			count--
-			if count < 0 {
-				return false
-			}
-		}
-	}
-	return count == 0
-}
-
-func splitTopLevel(s string) ([]string, error) {
-	depth := 0
-	parts := []string{}
-	start := 0
-	for i := 0; i < len(s); i++ {
-		switch s[i] {
-		case '(':
-			depth++
-		case ')':
-			depth--
-		case ',':
-			if depth == 0 {
-				parts = append(parts, s[start:i])
-				start = i + 1
-			}
-		}
-	}
-	parts = append(parts, s[start:])
-	return parts, nil
-}
-
 func PrintPolicyTree(node *PolicyNode, level int) {
 	if node == nil {
 		return
@@ -311,113 +261,6 @@ func PrintPolicyTree(node *PolicyNode, level int) {
 	}
 }
 
-func ConvertPolicyExpr(s string) (string, error) {
-	s = strings.ReplaceAll(s, " ", "")
-	s = strings.ReplaceAll(s, "2-of-", "t-of-")
-	return parseExpr(s)
-}
-
-func parseExpr(s string) (string, error) {
-	if len(s) >= 2 && s[0] == '(' && s[len(s)-1] == ')' && isSurroundedByParentheses(s) {
-		s = s[1 : len(s)-1]
-	}
-
-	if strings.HasPrefix(s, "t-of-") {
-		re := regexp.MustCompile(`t-of-\((\d+),(.+)\)`)
-		matches := re.FindStringSubmatch(s)
-		if len(matches) != 3 {
-			return "", fmt.Errorf("invalid threshold format: %s", s)
-		}
-		k := matches[1]
-		rest := matches[2]
-		parts, err := splitTopLevel(rest)
-		if err != nil {
-			return "", err
-		}
-		newParts := []string{}
-		for _, p := range parts {
-			conv, err := parseExpr(p)
-			if err != nil {
-				return "", err
-			}
-			newParts = append(newParts, conv)
-		}
-		return fmt.Sprintf("t-of-(%s,%s)", k, strings.Join(newParts, ",")), nil
-	}
-
-	pos, op := findTopLevelOperator(s)
-	if pos == -1 {
-		return s, nil
-	}
-
-	left := s[:pos]
-	right := s[pos+len(op):]
-
-	leftParts, err := splitTopLevel(left)
-	if err != nil {
-		return "", err
-	}
-	rightParts, err := splitTopLevel(right)
-	if err != nil {
-		return "", err
-	}
-
-	children := append(leftParts, rightParts...)
-
-	threshold := "1"
-	if op == "AND" {
-		threshold = fmt.Sprintf("%d", len(children))
-	}
-
-	newChildren := []string{}
-	for _, c := range children {
-		cc, err := parseExpr(c)
-		if err != nil {
-			return "", err
-		}
-		newChildren = append(newChildren, cc)
-	}
-
-	return fmt.Sprintf("t-of-(%s,%s)", threshold, strings.Join(newChildren, ",")), nil
-}
-
-func isSurroundedByParentheses(s string) bool {
-	count := 0
-	for i := 0; i < len(s); i++ {
-		if s[i] == '(' {
-			count++
-		} else if s[i] == ')' {
-			count--
-			if count == 0 && i != len(s)-1 {
-				return false
-			}
-		}
-	}
-	return count == 0
-}
-
-func findTopLevelOperator(s string) (pos int, op string) {
-	depth := 0
-	for i := 0; i <= len(s)-3; i++ {
-		switch s[i] {
-		case '(':
-			depth++
-		case ')':
-			depth--
-		default:
-			if depth == 0 {
-				if len(s[i:]) >= 3 && s[i:i+3] == "AND" {
-					return i, "AND"
-				}
-				if len(s[i:]) >= 2 && s[i:i+2] == "OR" {
-					return i, "OR"
-				}
-			}
-		}
-	}
-	return -1, ""
-}
-
 // 计算属性对应的 X (横坐标) 映射，方便后续拉格朗日插值使用
 func BuildAttrXMap(shares []AttributeShare) map[string]*big.Int {
 	attrX := make(map[string]*big.Int)
@@ -445,14 +288,15 @@ func subtreeSatisfiable(node *PolicyNode, attrs map[string]bool) bool {
 }
 
 // 修复后的：GetCoefficientsNoPrune（带子树可满足性判断）
-func GetCoefficientsNoPrune(root *PolicyNode, attrs map[string]bool, attrX map[string]*big.Int, xsMap xsMapType, p *big.Int) map[string]*big.Int {
-	coeffs := make(map[string]*big.Int)
+// xs 为 ComputeShares 返回的各节点横坐标，系数按叶子节点 ID 返回
+func GetCoefficientsNoPrune(root *PolicyNode, attrs map[string]bool, xs []*big.Int, p *big.Int) map[int]*big.Int {
+	coeffs := make(map[int]*big.Int)
 
 	var helper func(node *PolicyNode, coeff *big.Int)
 	helper = func(node *PolicyNode, coeff *big.Int) {
 		if node.Type == ATTR {
 			if attrs[node.Attribute] {
-				coeffs[node.Attribute] = new(big.Int).Set(coeff)
+				coeffs[node.ID] = new(big.Int).Set(coeff)
 			}
 			return
 		}
@@ -463,7 +307,7 @@ func GetCoefficientsNoPrune(root *PolicyNode, attrs map[string]bool, attrX map[s
 		// 只选择满足子树策略的子节点
 		for i, child := range node.Children {
 			if subtreeSatisfiable(child, attrs) {
-				xi := xsMap[node][i]
+				xi := nodeX(xs, child)
 				if xi != nil {
 					xList = append(xList, xi)
 					idxs = append(idxs, i)
@@ -478,7 +322,7 @@ func GetCoefficientsNoPrune(root *PolicyNode, attrs map[string]bool, attrX map[s
 
 		lag := LagrangeCoefficients(xList, p)
 		for _, i := range idxs {
-			xi := xsMap[node][i]
+			xi := nodeX(xs, node.Children[i])
 			lc := lag[xi.String()]
 			childCoeff := new(big.Int).Mul(coeff, lc)
 			childCoeff.Mod(childCoeff, p)
@@ -490,6 +334,72 @@ func GetCoefficientsNoPrune(root *PolicyNode, attrs map[string]bool, attrX map[s
 	return coeffs
 }
 
+// GetCoefficientsPruned 计算剪枝恢复的拉格朗日系数：leaves 为满足策略的叶子集合
+// (见 PolicyNode.MinimalSet)，每个门只用含有选中叶子的子节点插值，系数按叶子 ID 返回
+func GetCoefficientsPruned(root *PolicyNode, leaves []*PolicyNode, xs []*big.Int, p *big.Int) (map[int]*big.Int, error) {
+	// used[id] 表示该子树含有选中的叶子
+	used := make(map[int]bool)
+	for _, leaf := range leaves {
+		used[leaf.ID] = true
+	}
+	var mark func(node *PolicyNode) bool
+	mark = func(node *PolicyNode) bool {
+		for _, child := range node.Children {
+			if mark(child) {
+				used[node.ID] = true
+			}
+		}
+		return used[node.ID]
+	}
+	mark(root)
+
+	coeffs := make(map[int]*big.Int)
+	var helper func(node *PolicyNode, coeff *big.Int) error
+	helper = func(node *PolicyNode, coeff *big.Int) error {
+		if node.Type == ATTR {
+			coeffs[node.ID] = coeff
+			return nil
+		}
+		var chosen []*PolicyNode
+		var xList []*big.Int
+		for _, child := range node.Children {
+			if !used[child.ID] {
+				continue
+			}
+			xi := nodeX(xs, child)
+			if xi == nil {
+				return fmt.Errorf("OABE: no x-coordinate for policy node %d", child.ID)
+			}
+			chosen = append(chosen, child)
+			xList = append(xList, xi)
+		}
+		if len(chosen) != node.Threshold {
+			return fmt.Errorf("OABE: %d children selected for a %d-of-%d gate", len(chosen), node.Threshold, len(node.Children))
+		}
+		lag := LagrangeCoefficients(xList, p)
+		for i, child := range chosen {
+			childCoeff := new(big.Int).Mul(coeff, lag[xList[i].String()])
+			childCoeff.Mod(childCoeff, p)
+			if err := helper(child, childCoeff); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	if err := helper(root, big.NewInt(1)); err != nil {
+		return nil, err
+	}
+	return coeffs, nil
+}
+
+// nodeX 返回节点份额的横坐标，xs 不完整时返回 nil
+func nodeX(xs []*big.Int, node *PolicyNode) *big.Int {
+	if node.ID < 0 || node.ID >= len(xs) {
+		return nil
+	}
+	return xs[node.ID]
+}
+
 func findFirstAttrX(node *PolicyNode, attrX map[string]*big.Int) *big.Int {
 	if node.Type == ATTR {
 		return attrX[node.Attribute]
@@ -528,10 +438,10 @@ func getAnyAttr(m map[string]*big.Int) string {
 }
 
 // RecoverSecretDirectly 根据满足的属性份额和拉格朗日系数恢复秘密
-func RecoverSecretDirectly(shares []AttributeShare, coeffs map[string]*big.Int, p *big.Int) *big.Int {
+func RecoverSecretDirectly(shares []AttributeShare, coeffs map[int]*big.Int, p *big.Int) *big.Int {
 	secret := big.NewInt(0)
 	for _, share := range shares {
-		coeff, ok := coeffs[share.Attribute]
+		coeff, ok := coeffs[share.Node]
 		if !ok {
 			continue
 		}
@@ -545,13 +455,13 @@ func RecoverSecretDirectly(shares []AttributeShare, coeffs map[string]*big.Int,
 
 // RecoverSecretDirectly 根据满足的属性份额和拉格朗日系数恢复秘密
 // RecoverSecretDirectly 计算通过 GT 群元素恢复的秘密
-func RecoverSecret(shares []DecShare, coeffs map[string]*big.Int, p *big.Int) *bn256.GT {
+func RecoverSecret(shares []DecShare, coeffs map[int]*big.Int, p *big.Int) *bn256.GT {
 	secret := new(bn256.GT).ScalarBaseMult(big.NewInt(int64(0)))
 	for _, share := range shares {
-		coeff, _ := coeffs[share.Attribute]
-		// if !ok {
-		// 	continue
-		// }
+		coeff, ok := coeffs[share.Node]
+		if !ok {
+			continue
+		}
 		//t := new(big.Int).Mul(coeff, share.Share)
 		//t.Mod(t, p)
 		t := new(bn256.GT).ScalarMult(share.Share, coeff)
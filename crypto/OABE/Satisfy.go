@@ -0,0 +1,206 @@
+package OABE
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Satisfiability. Decryption costs two pairings per attribute leaf it uses,
+// so before any pairing is computed a client can check that its attributes
+// satisfy a policy, find the fewest leaves that do and send the server only
+// the key parts for their attributes, see AttributeKey.Select.
+
+// ErrUnsatisfied is wrapped by the errors of attribute sets that do not
+// satisfy a policy.
+var ErrUnsatisfied = errors.New("OABE: attributes do not satisfy the policy")
+
+// UnsatisfiedError explains why an attribute set does not satisfy the
+// policy node Node: Have of its children are satisfied where it needs Need,
+// and Children explains the unsatisfied ones. For a leaf Need is 1, Have 0
+// and the attribute is missing.
+type UnsatisfiedError struct {
+	Node     *PolicyNode
+	Have     int
+	Need     int
+	Children []*UnsatisfiedError
+}
+
+func (e *UnsatisfiedError) Error() string {
+	var b strings.Builder
+	b.WriteString(ErrUnsatisfied.Error())
+	b.WriteString(": ")
+	e.explain(&b)
+	return b.String()
+}
+
+func (e *UnsatisfiedError) Unwrap() error { return ErrUnsatisfied }
+
+func (e *UnsatisfiedError) explain(b *strings.Builder) {
+	if e.Node.Type == ATTR {
+		fmt.Fprintf(b, "missing %s", quoteAttribute(e.Node.Attribute))
+		return
+	}
+	fmt.Fprintf(b, "%d of %d satisfied in %s", e.Have, e.Need, e.Node)
+	for i, child := range e.Children {
+		if i == 0 {
+			b.WriteString(" (")
+		} else {
+			b.WriteString("; ")
+		}
+		child.explain(b)
+	}
+	if len(e.Children) > 0 {
+		b.WriteString(")")
+	}
+}
+
+// Missing returns the attributes of the leaves in e, sorted and without
+// repetitions.
+func (e *UnsatisfiedError) Missing() []string {
+	seen := make(map[string]bool)
+	var walk func(e *UnsatisfiedError)
+	walk = func(e *UnsatisfiedError) {
+		if e.Node.Type == ATTR {
+			seen[e.Node.Attribute] = true
+		}
+		for _, child := range e.Children {
+			walk(child)
+		}
+	}
+	walk(e)
+	missing := make([]string, 0, len(seen))
+	for attr := range seen {
+		missing = append(missing, attr)
+	}
+	sort.Strings(missing)
+	return missing
+}
+
+func attributeSet(attrs []string) map[string]bool {
+	set := make(map[string]bool, len(attrs))
+	for _, attr := range attrs {
+		set[attr] = true
+	}
+	return set
+}
+
+// Satisfies reports whether attrs satisfy the policy rooted at node.
+func (node *PolicyNode) Satisfies(attrs []string) bool {
+	return subtreeSatisfiable(node, attributeSet(attrs))
+}
+
+// Explain returns nil if attrs satisfy the policy rooted at node and an
+// *UnsatisfiedError otherwise.
+func (node *PolicyNode) Explain(attrs []string) error {
+	if e := explain(node, attributeSet(attrs)); e != nil {
+		return e
+	}
+	return nil
+}
+
+func explain(node *PolicyNode, attrs map[string]bool) *UnsatisfiedError {
+	if node.Type == ATTR {
+		if attrs[node.Attribute] {
+			return nil
+		}
+		return &UnsatisfiedError{Node: node, Need: 1}
+	}
+	e := &UnsatisfiedError{Node: node, Need: node.Threshold}
+	for _, child := range node.Children {
+		if ce := explain(child, attrs); ce != nil {
+			e.Children = append(e.Children, ce)
+		} else {
+			e.Have++
+		}
+	}
+	if e.Have >= e.Need {
+		return nil
+	}
+	return e
+}
+
+// Selection is a set of attribute leaves that satisfies a policy.
+type Selection struct {
+	Leaves     []*PolicyNode // in depth-first order
+	Attributes []string      // of Leaves, sorted and without repetitions
+}
+
+// Pairings returns the number of pairings needed to recover the shares of
+// the selected leaves.
+func (s *Selection) Pairings() int { return 2 * len(s.Leaves) }
+
+// MinimalSet returns the smallest set of leaves of the policy rooted at node
+// whose attributes are in attrs and that satisfies it, i.e. the one that
+// needs the fewest pairings. Every gate takes its k cheapest satisfied
+// children, the leftmost ones on ties. If attrs do not satisfy the policy,
+// the error is an *UnsatisfiedError.
+func (node *PolicyNode) MinimalSet(attrs []string) (*Selection, error) {
+	set := attributeSet(attrs)
+	leaves, ok := minimalLeaves(node, set)
+	if !ok {
+		return nil, explain(node, set)
+	}
+	sort.Slice(leaves, func(i, j int) bool { return leaves[i].ID < leaves[j].ID })
+	seen := make(map[string]bool, len(leaves))
+	s := &Selection{Leaves: leaves}
+	for _, leaf := range leaves {
+		if !seen[leaf.Attribute] {
+			seen[leaf.Attribute] = true
+			s.Attributes = append(s.Attributes, leaf.Attribute)
+		}
+	}
+	sort.Strings(s.Attributes)
+	return s, nil
+}
+
+// minimalLeaves returns the fewest leaves that satisfy node. Leaves are
+// never shared between subtrees, so the cheapest choice for a gate is its k
+// cheapest children.
+func minimalLeaves(node *PolicyNode, attrs map[string]bool) ([]*PolicyNode, bool) {
+	if node.Type == ATTR {
+		if !attrs[node.Attribute] {
+			return nil, false
+		}
+		return []*PolicyNode{node}, true
+	}
+	var options [][]*PolicyNode
+	for _, child := range node.Children {
+		if leaves, ok := minimalLeaves(child, attrs); ok {
+			options = append(options, leaves)
+		}
+	}
+	if len(options) < node.Threshold {
+		return nil, false
+	}
+	sort.SliceStable(options, func(i, j int) bool { return len(options[i]) < len(options[j]) })
+	var leaves []*PolicyNode
+	for _, option := range options[:node.Threshold] {
+		leaves = append(leaves, option...)
+	}
+	return leaves, true
+}
+
+// Select returns a key with only the parts of SK that a minimal set of
+// leaves of policy needs, for a client that hands its key to an untrusted
+// decryption server. It fails as MinimalSet does.
+func (SK *AttributeKey) Select(policy *PolicyNode) (*AttributeKey, error) {
+	attrs := make([]string, len(SK.Parts))
+	for i, part := range SK.Parts {
+		attrs[i] = part.Attribute
+	}
+	s, err := policy.MinimalSet(attrs)
+	if err != nil {
+		return nil, err
+	}
+	needed := attributeSet(s.Attributes)
+	out := &AttributeKey{D: SK.D}
+	for _, part := range SK.Parts {
+		if needed[part.Attribute] {
+			out.Parts = append(out.Parts, part)
+			delete(needed, part.Attribute)
+		}
+	}
+	return out, nil
+}
@@ -0,0 +1,165 @@
+package OABE
+
+import (
+	"crypto/rand"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	bn256 "Obfushop/bn256"
+)
+
+func mustParse(t testing.TB, policy string) *PolicyNode {
+	t.Helper()
+	node, err := ParsePolicy(policy)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return node
+}
+
+func TestMinimalSet(t *testing.T) {
+	tests := []struct {
+		policy string
+		attrs  string
+		leaves []string // attributes of the selected leaves, in order
+		want   []string // Selection.Attributes
+	}{
+		{"A", "A", []string{"A"}, []string{"A"}},
+		{"A OR (B AND C)", "A B C", []string{"A"}, []string{"A"}},
+		{"A OR (B AND C)", "B C", []string{"B", "C"}, []string{"B", "C"}},
+		{"(B AND C) OR A", "A B C", []string{"A"}, []string{"A"}},
+		{"2 of (A AND B AND C, D, E AND F, G)", "A B C D E F G", []string{"D", "G"}, []string{"D", "G"}},
+		{"2 of (A AND B AND C, D, E AND F, G)", "A B C D E F", []string{"D", "E", "F"}, []string{"D", "E", "F"}},
+		{"2 of (A AND B AND C, D, E AND F, G)", "A B C E F", []string{"A", "B", "C", "E", "F"}, []string{"A", "B", "C", "E", "F"}},
+		{"2 of (A AND B AND C, D, E AND F, G)", "A B C", nil, nil},
+		{"3 of (A, B AND C, D, E)", "A B C D E", []string{"A", "D", "E"}, []string{"A", "D", "E"}},
+		{"3 of (A, B AND C, D, E)", "A B C D", []string{"A", "B", "C", "D"}, []string{"A", "B", "C", "D"}},
+		// repeated attributes are separate leaves, so they count twice
+		{"(A AND B) OR (A AND C) OR (B AND C)", "A B C", []string{"A", "B"}, []string{"A", "B"}},
+		{"(A AND B) OR (A AND C) OR (B AND C)", "A C", []string{"A", "C"}, []string{"A", "C"}},
+		{"A AND (A OR B)", "A B", []string{"A", "A"}, []string{"A"}},
+		{"2 of (A, A, B)", "A", []string{"A", "A"}, []string{"A"}},
+	}
+	for _, tt := range tests {
+		node := mustParse(t, tt.policy)
+		attrs := strings.Fields(tt.attrs)
+		s, err := node.MinimalSet(attrs)
+		if tt.leaves == nil {
+			if err == nil {
+				t.Errorf("%s with %v: selected %d leaves, want an error", tt.policy, attrs, len(s.Leaves))
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s with %v: %v", tt.policy, attrs, err)
+			continue
+		}
+		var got []string
+		for i, leaf := range s.Leaves {
+			got = append(got, leaf.Attribute)
+			if i > 0 && leaf.ID <= s.Leaves[i-1].ID {
+				t.Errorf("%s with %v: leaves out of order", tt.policy, attrs)
+			}
+		}
+		if !reflect.DeepEqual(got, tt.leaves) {
+			t.Errorf("%s with %v: leaves %v, want %v", tt.policy, attrs, got, tt.leaves)
+		}
+		if !reflect.DeepEqual(s.Attributes, tt.want) {
+			t.Errorf("%s with %v: attributes %v, want %v", tt.policy, attrs, s.Attributes, tt.want)
+		}
+		if s.Pairings() != 2*len(tt.leaves) {
+			t.Errorf("%s with %v: %d pairings, want %d", tt.policy, attrs, s.Pairings(), 2*len(tt.leaves))
+		}
+		if !node.Satisfies(s.Attributes) {
+			t.Errorf("%s: selected attributes %v do not satisfy it", tt.policy, s.Attributes)
+		}
+	}
+}
+
+func TestUnsatisfiedError(t *testing.T) {
+	tests := []struct {
+		policy  string
+		attrs   string
+		text    string
+		missing []string
+	}{
+		{"A", "", "missing A", []string{"A"}},
+		{"Owner OR (Community_A AND Hovering_drone)", "Community_A",
+			"0 of 1 satisfied in Owner OR (Community_A AND Hovering_drone) (missing Owner; 1 of 2 satisfied in Community_A AND Hovering_drone (missing Hovering_drone))",
+			[]string{"Hovering_drone", "Owner"}},
+		{`"x y" AND A`, "B", `0 of 2 satisfied in "x y" AND A (missing "x y"; missing A)`, []string{"A", "x y"}},
+		{"(A AND B) OR (A AND C)", "",
+			"0 of 1 satisfied in (A AND B) OR (A AND C) (0 of 2 satisfied in A AND B (missing A; missing B); 0 of 2 satisfied in A AND C (missing A; missing C))",
+			[]string{"A", "B", "C"}},
+		{"2 of (A, B, C)", "B", "1 of 2 satisfied in 2 of (A, B, C) (missing A; missing C)", []string{"A", "C"}},
+	}
+	for _, tt := range tests {
+		node := mustParse(t, tt.policy)
+		attrs := strings.Fields(tt.attrs)
+		if node.Satisfies(attrs) {
+			t.Fatalf("%s is satisfied by %v", tt.policy, attrs)
+		}
+		for _, err := range []error{node.Explain(attrs), func() error { _, err := node.MinimalSet(attrs); return err }()} {
+			var ue *UnsatisfiedError
+			if !errors.As(err, &ue) || !errors.Is(err, ErrUnsatisfied) {
+				t.Errorf("%s with %v: %v, want an *UnsatisfiedError", tt.policy, attrs, err)
+				continue
+			}
+			if want := ErrUnsatisfied.Error() + ": " + tt.text; err.Error() != want {
+				t.Errorf("%s with %v:\n got %s\nwant %s", tt.policy, attrs, err, want)
+			}
+			if got := ue.Missing(); !reflect.DeepEqual(got, tt.missing) {
+				t.Errorf("%s with %v: missing %v, want %v", tt.policy, attrs, got, tt.missing)
+			}
+		}
+	}
+
+	node := mustParse(t, "A OR B")
+	if err := node.Explain([]string{"B"}); err != nil {
+		t.Errorf("Explain of a satisfied policy: %v", err)
+	}
+}
+
+func TestSelect(t *testing.T) {
+	MSK, PK := Setup()
+	_, pku, err := bn256.RandomG1(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	SK := KeyGen(pku, MSK, PK, []string{"A", "B", "C", "D", "E", "F", "G"})
+
+	tests := []struct {
+		policy string
+		want   []string
+	}{
+		{"2 of (A AND B AND C, D, E AND F, G)", []string{"D", "G"}},
+		{"(A AND B) OR (A AND C) OR (B AND C)", []string{"A", "B"}},
+		{"G AND (G OR A)", []string{"G"}},
+		{"A AND H OR C", []string{"C"}},
+	}
+	for _, tt := range tests {
+		selected, err := SK.Select(mustParse(t, tt.policy))
+		if err != nil {
+			t.Errorf("%s: %v", tt.policy, err)
+			continue
+		}
+		if selected.D != SK.D {
+			t.Errorf("%s: selected key has another D", tt.policy)
+		}
+		var got []string
+		for _, part := range selected.Parts {
+			got = append(got, part.Attribute)
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: selected parts %v, want %v", tt.policy, got, tt.want)
+		}
+	}
+
+	_, err = SK.Select(mustParse(t, "A AND H"))
+	var ue *UnsatisfiedError
+	if !errors.As(err, &ue) || !reflect.DeepEqual(ue.Missing(), []string{"H"}) {
+		t.Errorf("selecting for a policy the key does not satisfy: %v", err)
+	}
+}
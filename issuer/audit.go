@@ -0,0 +1,49 @@
+package issuer
+
+import (
+	"encoding/json"
+	"io"
+	"math/big"
+	"sync"
+	"time"
+)
+
+// AuditEntry records one decision of the issuer. The revocation handle is
+// not logged, only whether there was one.
+type AuditEntry struct {
+	Time       time.Time `json:"time"`
+	Remote     string    `json:"remote"`
+	Request    string    `json:"request,omitempty"` // request ID, empty if it did not decode
+	Attributes int       `json:"attributes,omitempty"`
+	Epoch      *big.Int  `json:"epoch,omitempty"`
+	Revocable  bool      `json:"revocable,omitempty"`
+	Outcome    string    `json:"outcome"` // "issued" or "rejected"
+	Reason     string    `json:"reason,omitempty"`
+}
+
+// AuditLog stores audit entries. The server does not hand out a signature
+// unless its entry was recorded.
+type AuditLog interface {
+	Record(entry AuditEntry) error
+}
+
+type jsonLog struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+// NewAuditLog returns an AuditLog writing one JSON object per line to w.
+func NewAuditLog(w io.Writer) AuditLog {
+	return &jsonLog{w: w}
+}
+
+func (l *jsonLog) Record(entry AuditEntry) error {
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	_, err = l.w.Write(append(line, '\n'))
+	return err
+}
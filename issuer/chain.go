@@ -0,0 +1,230 @@
+package issuer
+
+import (
+	"Obfushop/bn256"
+	"Obfushop/compile/contract"
+	"Obfushop/crypto/AC"
+	"Obfushop/crypto/Convert"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// On-chain key history. The contract keeps every issuer key it was given
+// as a numbered version: version 1 comes with UploadACsParams, later ones
+// with RegisterIssuer and RotateIssuerKey. A superseded key stays usable
+// for RegisterSIDSet and CheckClaim until its grace period ends.
+//
+// Issuers are known by an ID in an AC.IssuerRegistry and by a bytes32 on
+// chain, see ChainID. The issuer that uploaded the parameters is RootIssuer.
+
+// RootIssuer is the registry ID of the issuer that deployed the contract
+// and called UploadACsParams.
+const RootIssuer = "root"
+
+// ChainID returns the on-chain ID of issuer id: zero for RootIssuer and
+// sha256(id) otherwise.
+func ChainID(id string) [32]byte {
+	if id == RootIssuer {
+		return [32]byte{}
+	}
+	return sha256.Sum256([]byte(id))
+}
+
+// RegisterIssuer registers issuer id on-chain with its first key, in use
+// from epoch on; addr may rotate it later. It must be sent by the root
+// issuer. It returns the key version, see RotateKey.
+func RegisterIssuer(ctx context.Context, backend bind.DeployBackend, c *contract.Contract, auth *bind.TransactOpts,
+	id string, addr common.Address, key *AC.IssuerKey, epoch uint64) (uint64, error) {
+	if id == RootIssuer {
+		return 0, errors.New("issuer: the root issuer is registered by UploadACsParams")
+	}
+	fingerprint, err := key.Fingerprint()
+	if err != nil {
+		return 0, err
+	}
+	X, Ys := chainKey(key)
+	tx, err := c.RegisterIssuer(auth, ChainID(id), addr, X, Ys, new(big.Int).SetUint64(epoch))
+	if err != nil {
+		return 0, fmt.Errorf("issuer: register %q: %w", id, err)
+	}
+	return awaitKey(ctx, backend, c, tx, fingerprint)
+}
+
+// RotateKey makes key the next issuer key version on-chain, in use from
+// epoch on, and leaves the previous version valid for grace. It waits for
+// the transaction to be mined and checks that the contract reports the
+// fingerprint of key for the new version, which it returns.
+func RotateKey(ctx context.Context, backend bind.DeployBackend, c *contract.Contract, auth *bind.TransactOpts,
+	key *AC.IssuerKey, epoch uint64, grace time.Duration) (uint64, error) {
+	fingerprint, err := key.Fingerprint()
+	if err != nil {
+		return 0, err
+	}
+	if grace < 0 {
+		return 0, errors.New("issuer: negative grace period")
+	}
+	X, Ys := chainKey(key)
+	tx, err := c.RotateIssuerKey(auth, X, Ys, new(big.Int).SetUint64(epoch), big.NewInt(int64(grace/time.Second)))
+	if err != nil {
+		return 0, fmt.Errorf("issuer: rotate key: %w", err)
+	}
+	return awaitKey(ctx, backend, c, tx, fingerprint)
+}
+
+func chainKey(key *AC.IssuerKey) (contract.BCSIDG1Point, []contract.BCSIDG1Point) {
+	Ys := make([]contract.BCSIDG1Point, len(key.PK2))
+	for i, Y := range key.PK2 {
+		Ys[i] = Convert.G1ToG1Point(Y)
+	}
+	return Convert.G1ToG1Point(key.PK1), Ys
+}
+
+// await waits for tx to be mined and checks that it succeeded.
+func await(ctx context.Context, backend bind.DeployBackend, tx *types.Transaction) (*types.Receipt, error) {
+	receipt, err := bind.WaitMined(ctx, backend, tx)
+	if err != nil {
+		return nil, fmt.Errorf("issuer: %w", err)
+	}
+	if receipt.Status != types.ReceiptStatusSuccessful {
+		return nil, fmt.Errorf("issuer: transaction %s reverted", tx.Hash().Hex())
+	}
+	return receipt, nil
+}
+
+// awaitKey waits for a transaction that adds a key version and checks that
+// the contract reports fingerprint for it.
+func awaitKey(ctx context.Context, backend bind.DeployBackend, c *contract.Contract, tx *types.Transaction, fingerprint string) (uint64, error) {
+	receipt, err := await(ctx, backend, tx)
+	if err != nil {
+		return 0, err
+	}
+	var version *big.Int
+	for _, l := range receipt.Logs {
+		if ev, err := c.ParseIssuerKeyRotated(*l); err == nil {
+			version = ev.Version
+		}
+	}
+	if version == nil || !version.IsUint64() {
+		return 0, errors.New("issuer: no IssuerKeyRotated event")
+	}
+	onchain, err := c.IssuerKeyFingerprint(&bind.CallOpts{Context: ctx}, version)
+	if err != nil {
+		return 0, err
+	}
+	if hex.EncodeToString(onchain[:]) != fingerprint {
+		return 0, fmt.Errorf("issuer: key version %d on-chain has fingerprint %x, want %s", version, onchain, fingerprint)
+	}
+	return version.Uint64(), nil
+}
+
+// StartRevocationEpoch publishes the revocation tag base of epoch. From
+// then on identities hold their claims only after registering again with a
+// tag of epoch, and RevokeTags takes the tags of epoch. It must be sent by
+// the root issuer.
+func StartRevocationEpoch(ctx context.Context, backend bind.DeployBackend, c *contract.Contract, auth *bind.TransactOpts, epoch uint64) error {
+	tx, err := c.UploadRevocationBase(auth, new(big.Int).SetUint64(epoch), Convert.G1ToG1Point(AC.RevocationBase(epoch)))
+	if err != nil {
+		return fmt.Errorf("issuer: start revocation epoch %d: %w", epoch, err)
+	}
+	_, err = await(ctx, backend, tx)
+	return err
+}
+
+// RevokeTags publishes the tags of the handles in rl, so that CheckClaim
+// fails for the identities registered with them. rl.Epoch must be the
+// revocation epoch on-chain: tags of any other epoch match no identity.
+// It must be sent by the root issuer.
+func RevokeTags(ctx context.Context, backend bind.DeployBackend, c *contract.Contract, auth *bind.TransactOpts, rl *AC.RevocationList) error {
+	epoch, err := c.GetRevocationEpoch(&bind.CallOpts{Context: ctx})
+	if err != nil {
+		return err
+	}
+	if !epoch.IsUint64() || epoch.Uint64() != rl.Epoch {
+		return fmt.Errorf("issuer: revocation list is for epoch %d, the contract is in epoch %s", rl.Epoch, epoch)
+	}
+	tags := rl.Tags()
+	points := make([]contract.BCSIDG1Point, len(tags))
+	for i, tag := range tags {
+		points[i] = Convert.G1ToG1Point(tag)
+	}
+	tx, err := c.RevokeTags(auth, points)
+	if err != nil {
+		return fmt.Errorf("issuer: revoke tags: %w", err)
+	}
+	_, err = await(ctx, backend, tx)
+	return err
+}
+
+// ChainKey is a version of the issuer key as recorded on-chain.
+type ChainKey struct {
+	Version uint64
+	Key     *AC.IssuerKey // public part only
+	Epoch   uint64        // epoch the key was registered for
+	Expires time.Time     // end of the grace period, zero while the key is current
+}
+
+// FetchKey reads key version from the contract, e.g. for a verifier that
+// holds a presentation made under an older key.
+func FetchKey(opts *bind.CallOpts, c *contract.Contract, version uint64) (*ChainKey, error) {
+	X, Ys, epoch, expires, err := c.GetIssuerKey(opts, new(big.Int).SetUint64(version))
+	if err != nil {
+		return nil, err
+	}
+	key := &AC.IssuerKey{PK2: make([]*bn256.G1, len(Ys))}
+	if key.PK1, err = chainG1(X); err != nil {
+		return nil, err
+	}
+	for i, Y := range Ys {
+		if key.PK2[i], err = chainG1(Y); err != nil {
+			return nil, err
+		}
+	}
+	ck := &ChainKey{Version: version, Key: key, Epoch: epoch.Uint64()}
+	if expires.Sign() != 0 {
+		ck.Expires = time.Unix(expires.Int64(), 0).UTC()
+	}
+	return ck, nil
+}
+
+// FetchRegistry builds a registry of the current on-chain keys of the
+// issuers ids, for VerifyAggregate.
+func FetchRegistry(opts *bind.CallOpts, c *contract.Contract, ids []string) (*AC.IssuerRegistry, error) {
+	reg := AC.NewIssuerRegistry()
+	for _, id := range ids {
+		version, err := c.IssuerKeyVersion(opts, ChainID(id))
+		if err != nil {
+			return nil, err
+		}
+		if version.Sign() == 0 {
+			return nil, fmt.Errorf("%w: %q is not registered on-chain", AC.ErrUnknownIssuer, id)
+		}
+		ck, err := FetchKey(opts, c, version.Uint64())
+		if err != nil {
+			return nil, err
+		}
+		if err := reg.Register(id, ck.Key); err != nil {
+			return nil, err
+		}
+	}
+	return reg, nil
+}
+
+func chainG1(p contract.BCSIDG1Point) (*bn256.G1, error) {
+	b := make([]byte, 64)
+	p.X.FillBytes(b[:32])
+	p.Y.FillBytes(b[32:])
+	g := new(bn256.G1)
+	if _, err := g.Unmarshal(b); err != nil {
+		return nil, fmt.Errorf("issuer: bad key point on-chain: %w", err)
+	}
+	return g, nil
+}
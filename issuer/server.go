@@ -0,0 +1,307 @@
+// Package issuer serves blind credential issuance over HTTP/JSON.
+//
+//	GET  /v1/params          AC.Params
+//	GET  /v1/issuerkey       public part of the AC.IssuerKey
+//	POST /v1/requests        AC.Req from PrepareBlindSign → Issued
+//	GET  /v1/requests/{id}   Issued for an earlier request
+//
+// All bodies use the AC JSON wire format. Every request passes the vetting
+// hooks before it is signed or its signature is returned again, and every
+// decision is written to the audit log. Answered requests are kept only for
+// a while, see Server.Retain.
+// Issued carries the on-chain version of the key that signed, which the
+// holder passes to RegisterSIDSet; see RotateKey for the key history.
+package issuer
+
+import (
+	"Obfushop/crypto/AC"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// MaxRequestSize bounds the body of a submitted request.
+const MaxRequestSize = 1 << 20
+
+// Defaults for how many answered requests are kept, and for how long, so
+// that resubmitting a request or fetching it by ID returns the same
+// signature; see Server.Retain.
+const (
+	DefaultMaxIssued = 10000
+	DefaultIssuedTTL = 24 * time.Hour
+)
+
+// Issued is the response to a submitted request.
+type Issued struct {
+	ID         string             `json:"id"` // hex sha256 of the binary request
+	Signature  *AC.BlindSignature `json:"signature"`
+	KeyVersion uint64             `json:"keyVersion,omitempty"`
+}
+
+// Server is an http.Handler for the issuer endpoints.
+type Server struct {
+	params  *AC.Params
+	audit   AuditLog
+	vetters []Vetter
+	onIssue func(id string, req *AC.Req) error
+	mux     *http.ServeMux
+
+	mu        sync.Mutex
+	key       *AC.IssuerKey
+	version   uint64
+	issued    map[string]*issuedEntry
+	order     []string // IDs in issued, oldest first
+	maxIssued int
+	issuedTTL time.Duration
+	now       func() time.Time
+}
+
+// issuedEntry is an answered request, kept with the request so that the
+// vetters can run again before the signature is handed out a second time.
+type issuedEntry struct {
+	issued *Issued
+	req    *AC.Req
+	at     time.Time
+}
+
+// NewServer returns a server signing with key. Requests are accepted only if
+// every vetter accepts them; audit may be nil to disable the audit log. Use
+// SetKey to record the on-chain version of key.
+func NewServer(params *AC.Params, key *AC.IssuerKey, audit AuditLog, vetters ...Vetter) *Server {
+	srv := &Server{
+		params:  params,
+		key:     key,
+		audit:   audit,
+		vetters: vetters,
+		mux:     http.NewServeMux(),
+
+		issued:    make(map[string]*issuedEntry),
+		maxIssued: DefaultMaxIssued,
+		issuedTTL: DefaultIssuedTTL,
+		now:       time.Now,
+	}
+	srv.mux.HandleFunc("GET /v1/params", srv.getParams)
+	srv.mux.HandleFunc("GET /v1/issuerkey", srv.getIssuerKey)
+	srv.mux.HandleFunc("POST /v1/requests", srv.postRequest)
+	srv.mux.HandleFunc("GET /v1/requests/{id}", srv.getRequest)
+	return srv
+}
+
+// OnIssue sets a hook that runs after a request is signed and before the
+// signature is returned, e.g. to store the revocation handle. If it fails
+// the signature is withheld.
+func (srv *Server) OnIssue(fn func(id string, req *AC.Req) error) {
+	srv.onIssue = fn
+}
+
+// SetKey switches to signing with key, on-chain version version (0 if the
+// key is not on-chain). Requests answered earlier keep their signatures.
+func (srv *Server) SetKey(key *AC.IssuerKey, version uint64) {
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+	srv.key = key
+	srv.version = version
+}
+
+// Retain keeps at most max answered requests, each for at most ttl. Older
+// ones are forgotten: fetching them by ID fails and resubmitting them signs
+// them again.
+func (srv *Server) Retain(max int, ttl time.Duration) {
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+	srv.maxIssued = max
+	srv.issuedTTL = ttl
+	srv.expire()
+}
+
+// lookup returns the answered request id, if it is still kept.
+func (srv *Server) lookup(id string) (*issuedEntry, bool) {
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+	srv.expire()
+	e, ok := srv.issued[id]
+	return e, ok
+}
+
+// store keeps an answered request, forgetting the oldest ones beyond the
+// limits set with Retain.
+func (srv *Server) store(id string, e *issuedEntry) {
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+	if _, ok := srv.issued[id]; !ok {
+		srv.order = append(srv.order, id)
+	}
+	srv.issued[id] = e
+	srv.expire()
+}
+
+// expire drops entries older than issuedTTL and the oldest entries beyond
+// maxIssued. srv.mu must be held.
+func (srv *Server) expire() {
+	cutoff := srv.now().Add(-srv.issuedTTL)
+	for len(srv.order) > 0 {
+		id := srv.order[0]
+		if len(srv.order) <= srv.maxIssued && !srv.issued[id].at.Before(cutoff) {
+			break
+		}
+		delete(srv.issued, id)
+		srv.order = srv.order[1:]
+	}
+}
+
+func (srv *Server) currentKey() (*AC.IssuerKey, uint64) {
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+	return srv.key, srv.version
+}
+
+func (srv *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	srv.mux.ServeHTTP(w, r)
+}
+
+func (srv *Server) getParams(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, srv.params)
+}
+
+func (srv *Server) getIssuerKey(w http.ResponseWriter, r *http.Request) {
+	key, _ := srv.currentKey()
+	writeJSON(w, http.StatusOK, key.Public())
+}
+
+func (srv *Server) getRequest(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	e, ok := srv.lookup(id)
+	if !ok {
+		writeError(w, http.StatusNotFound, errors.New("unknown request"))
+		return
+	}
+	// the signature is handed out again, so the request is vetted again
+	if err := srv.vet(r, e.req); err != nil {
+		entry := AuditEntry{Time: srv.now().UTC(), Remote: r.RemoteAddr, Request: id}
+		srv.reject(w, &entry, http.StatusForbidden, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, e.issued)
+}
+
+// vet runs the vetters on req.
+func (srv *Server) vet(r *http.Request, req *AC.Req) error {
+	for _, vet := range srv.vetters {
+		if err := vet(r, req); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (srv *Server) postRequest(w http.ResponseWriter, r *http.Request) {
+	entry := AuditEntry{Time: srv.now().UTC(), Remote: r.RemoteAddr}
+
+	req := new(AC.Req)
+	dec := json.NewDecoder(http.MaxBytesReader(w, r.Body, MaxRequestSize))
+	if err := dec.Decode(req); err != nil {
+		srv.reject(w, &entry, http.StatusBadRequest, err)
+		return
+	}
+	bin, err := req.MarshalBinary()
+	if err != nil {
+		srv.reject(w, &entry, http.StatusBadRequest, err)
+		return
+	}
+	sum := sha256.Sum256(bin)
+	id := hex.EncodeToString(sum[:])
+	entry.Request = id
+	entry.Attributes = len(req.C)
+	entry.Epoch = req.Epoch
+	entry.Revocable = req.Handle != nil
+
+	// vetted before the cache is consulted: a request answered earlier may
+	// no longer be acceptable, e.g. once its epoch has passed
+	if err := srv.vet(r, req); err != nil {
+		srv.reject(w, &entry, http.StatusForbidden, err)
+		return
+	}
+	if e, ok := srv.lookup(id); ok {
+		writeJSON(w, http.StatusOK, e.issued)
+		return
+	}
+
+	key, version := srv.currentKey()
+	sig, err := AC.BlindSign(srv.params, key, req)
+	if err != nil {
+		srv.reject(w, &entry, statusOf(err), err)
+		return
+	}
+
+	if srv.onIssue != nil {
+		if err := srv.onIssue(id, req); err != nil {
+			srv.reject(w, &entry, http.StatusInternalServerError, err)
+			return
+		}
+	}
+	// nothing is handed out that the audit log does not know about
+	entry.Outcome = "issued"
+	if err := srv.record(entry); err != nil {
+		writeError(w, http.StatusInternalServerError, errors.New("audit log unavailable"))
+		return
+	}
+	issued := &Issued{ID: id, Signature: sig, KeyVersion: version}
+	srv.store(id, &issuedEntry{issued: issued, req: req, at: srv.now()})
+	w.Header().Set("Location", "/v1/requests/"+id)
+	writeJSON(w, http.StatusCreated, issued)
+}
+
+// reject logs and answers a request that is not signed.
+func (srv *Server) reject(w http.ResponseWriter, entry *AuditEntry, status int, err error) {
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		status = http.StatusRequestEntityTooLarge
+	}
+	entry.Outcome = "rejected"
+	entry.Reason = err.Error()
+	if err := srv.record(*entry); err != nil {
+		writeError(w, http.StatusInternalServerError, errors.New("audit log unavailable"))
+		return
+	}
+	writeError(w, status, err)
+}
+
+func (srv *Server) record(entry AuditEntry) error {
+	if srv.audit == nil {
+		return nil
+	}
+	return srv.audit.Record(entry)
+}
+
+// statusOf maps AC errors to HTTP statuses.
+func statusOf(err error) int {
+	switch {
+	case errors.Is(err, AC.ErrInvalidProof):
+		return http.StatusUnprocessableEntity
+	case errors.Is(err, AC.ErrMalformedInput), errors.Is(err, AC.ErrIdentityPoint), errors.Is(err, AC.ErrScalarOutOfRange):
+		return http.StatusBadRequest
+	}
+	return http.StatusInternalServerError
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	body, err := json.Marshal(v)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	w.Write(append(body, '\n'))
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	body, _ := json.Marshal(map[string]string{"error": err.Error()})
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	w.Write(append(body, '\n'))
+}
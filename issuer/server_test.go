@@ -0,0 +1,352 @@
+package issuer
+
+import (
+	"Obfushop/bn256"
+	"Obfushop/crypto/AC"
+	"bytes"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+const testEpoch = 2026
+
+func newTestServer(t *testing.T) (*httptest.Server, *bytes.Buffer, *[]*big.Int) {
+	params, err := AC.Setup(4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	key, err := AC.KeyGen(params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	audit := new(bytes.Buffer)
+	srv := NewServer(params, key, NewAuditLog(audit),
+		RequireAttributes(4),
+		RequireEpoch(func() uint64 { return testEpoch }),
+		RequireRevocable(),
+	)
+	handles := new([]*big.Int)
+	srv.OnIssue(func(id string, req *AC.Req) error {
+		*handles = append(*handles, req.Handle)
+		return nil
+	})
+	ts := httptest.NewServer(srv)
+	t.Cleanup(ts.Close)
+	return ts, audit, handles
+}
+
+func getJSON(t *testing.T, url string, v any) {
+	resp, err := http.Get(url)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("GET %s: %s", url, resp.Status)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func post(t *testing.T, url string, v any) *http.Response {
+	body, err := json.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := http.Post(url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return resp
+}
+
+func TestIssue(t *testing.T) {
+	ts, audit, handles := newTestServer(t)
+
+	// the holder only knows the server
+	params := new(AC.Params)
+	getJSON(t, ts.URL+"/v1/params", params)
+	key := new(AC.IssuerKey)
+	getJSON(t, ts.URL+"/v1/issuerkey", key)
+	if key.SK1 != nil || key.SK2 != nil {
+		t.Fatal("issuer key endpoint leaks secrets")
+	}
+
+	m := []*big.Int{big.NewInt(30), big.NewInt(7)}
+	handle, err := AC.NewRevocationHandle(params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	d, req, err := AC.PrepareEpochBlindSign(params, m, testEpoch, handle)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp := post(t, ts.URL+"/v1/requests", req)
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusCreated {
+		t.Fatalf("POST: %s", resp.Status)
+	}
+	issued := new(Issued)
+	if err := json.NewDecoder(resp.Body).Decode(issued); err != nil {
+		t.Fatal(err)
+	}
+	if len(*handles) != 1 || (*handles)[0].Cmp(handle) != 0 {
+		t.Fatal("OnIssue did not see the handle")
+	}
+
+	again := new(Issued)
+	getJSON(t, ts.URL+resp.Header.Get("Location"), again)
+	if again.ID != issued.ID || !again.Signature.U.Equal(issued.Signature.U) {
+		t.Fatal("stored signature differs")
+	}
+
+	cred, err := AC.ObtainCred(issued.Signature, d)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m = append(m, req.Epoch, req.Handle)
+	sk := big.NewInt(42)
+	proof, err := AC.ProveCred(params, sk, key, cred, m, []bool{true, false, true, false}, nil, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ok, err := AC.VerifyCred(params, new(bn256.G1).ScalarBaseMult(sk), new(bn256.G2).ScalarBaseMult(sk), key, proof, nil, nil, nil); !ok {
+		t.Fatalf("credential from the server does not verify: %v", err)
+	}
+	if !strings.Contains(audit.String(), `"outcome":"issued"`) {
+		t.Fatalf("audit log: %s", audit)
+	}
+}
+
+func TestReject(t *testing.T) {
+	ts, audit, handles := newTestServer(t)
+	params := new(AC.Params)
+	getJSON(t, ts.URL+"/v1/params", params)
+	handle, err := AC.NewRevocationHandle(params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := []*big.Int{big.NewInt(30), big.NewInt(7)}
+
+	_, stale, err := AC.PrepareEpochBlindSign(params, m, testEpoch-1, handle)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, plain, err := AC.PrepareBlindSign(params, append(m, big.NewInt(testEpoch), handle))
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, good, err := AC.PrepareEpochBlindSign(params, m, testEpoch, handle)
+	if err != nil {
+		t.Fatal(err)
+	}
+	forged := *good
+	forged.Epoch = new(big.Int).Set(stale.Epoch)
+	forged.Epoch.Add(forged.Epoch, big.NewInt(1)) // right epoch, but not the one π_s was made for
+	forged.PiS = stale.PiS
+
+	for _, tc := range []struct {
+		name   string
+		body   any
+		status int
+	}{
+		{"stale epoch", stale, http.StatusForbidden},
+		{"hidden epoch", plain, http.StatusForbidden},
+		{"forged proof", &forged, http.StatusUnprocessableEntity},
+		{"garbage", map[string]string{"cm": "00"}, http.StatusBadRequest},
+	} {
+		resp := post(t, ts.URL+"/v1/requests", tc.body)
+		resp.Body.Close()
+		if resp.StatusCode != tc.status {
+			t.Errorf("%s: got %s, want %d", tc.name, resp.Status, tc.status)
+		}
+	}
+	if len(*handles) != 0 {
+		t.Fatal("rejected requests reached OnIssue")
+	}
+	if n := strings.Count(audit.String(), `"outcome":"rejected"`); n != 4 {
+		t.Fatalf("audit log has %d rejections:\n%s", n, audit)
+	}
+
+	resp, err := http.Get(ts.URL + "/v1/requests/00")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusNotFound {
+		t.Fatalf("unknown request: %s", resp.Status)
+	}
+}
+
+func TestSetKey(t *testing.T) {
+	params, err := AC.Setup(2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	oldKey, err := AC.KeyGen(params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	newKey, err := AC.KeyGen(params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := NewServer(params, oldKey, nil)
+	srv.SetKey(oldKey, 1)
+	ts := httptest.NewServer(srv)
+	defer ts.Close()
+
+	m := []*big.Int{big.NewInt(30), big.NewInt(7)}
+	issue := func() (*Issued, *big.Int) {
+		d, req, err := AC.PrepareBlindSign(params, m)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp := post(t, ts.URL+"/v1/requests", req)
+		defer resp.Body.Close()
+		issued := new(Issued)
+		if err := json.NewDecoder(resp.Body).Decode(issued); err != nil {
+			t.Fatal(err)
+		}
+		return issued, d
+	}
+	before, _ := issue()
+	srv.SetKey(newKey, 2)
+	after, d := issue()
+	if before.KeyVersion != 1 || after.KeyVersion != 2 {
+		t.Fatalf("key versions %d, %d", before.KeyVersion, after.KeyVersion)
+	}
+	key := new(AC.IssuerKey)
+	getJSON(t, ts.URL+"/v1/issuerkey", key)
+	if !key.PK1.Equal(newKey.PK1) {
+		t.Fatal("issuer key endpoint still serves the old key")
+	}
+
+	cred, err := AC.ObtainCred(after.Signature, d)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sk := big.NewInt(42)
+	proof, err := AC.ProveCred(params, sk, newKey, cred, m, []bool{true, true}, nil, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ok, err := AC.VerifyCred(params, new(bn256.G1).ScalarBaseMult(sk), new(bn256.G2).ScalarBaseMult(sk), newKey, proof, nil, nil, nil); !ok {
+		t.Fatalf("credential under the new key does not verify: %v", err)
+	}
+	if ok, _ := AC.VerifyCred(params, new(bn256.G1).ScalarBaseMult(sk), new(bn256.G2).ScalarBaseMult(sk), oldKey, proof, nil, nil, nil); ok {
+		t.Fatal("credential verifies under the old key")
+	}
+}
+
+func TestCachedRequestIsVetted(t *testing.T) {
+	params, err := AC.Setup(2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	key, err := AC.KeyGen(params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	epoch := uint64(testEpoch)
+	ts := httptest.NewServer(NewServer(params, key, nil, RequireEpoch(func() uint64 { return epoch })))
+	defer ts.Close()
+
+	_, req, err := AC.PrepareEpochBlindSign(params, []*big.Int{big.NewInt(30)}, testEpoch, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	status := func(resp *http.Response) int {
+		resp.Body.Close()
+		return resp.StatusCode
+	}
+	resp := post(t, ts.URL+"/v1/requests", req)
+	location := resp.Header.Get("Location")
+	if s := status(resp); s != http.StatusCreated {
+		t.Fatalf("POST: %d", s)
+	}
+	if s := status(post(t, ts.URL+"/v1/requests", req)); s != http.StatusOK {
+		t.Fatalf("POST again: %d", s)
+	}
+
+	// once the epoch is over neither path hands the signature out again
+	epoch++
+	if s := status(post(t, ts.URL+"/v1/requests", req)); s != http.StatusForbidden {
+		t.Errorf("POST in the next epoch: %d, want %d", s, http.StatusForbidden)
+	}
+	resp, err = http.Get(ts.URL + location)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s := status(resp); s != http.StatusForbidden {
+		t.Errorf("GET in the next epoch: %d, want %d", s, http.StatusForbidden)
+	}
+}
+
+func TestRetain(t *testing.T) {
+	params, err := AC.Setup(2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	key, err := AC.KeyGen(params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := NewServer(params, key, nil)
+	now := time.Unix(1000, 0)
+	srv.now = func() time.Time { return now }
+	srv.Retain(2, time.Hour)
+	ts := httptest.NewServer(srv)
+	defer ts.Close()
+
+	var reqs []*AC.Req
+	var locations []string
+	for i := 0; i < 3; i++ {
+		_, req, err := AC.PrepareBlindSign(params, []*big.Int{big.NewInt(30), big.NewInt(int64(i))})
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp := post(t, ts.URL+"/v1/requests", req)
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusCreated {
+			t.Fatalf("POST %d: %s", i, resp.Status)
+		}
+		reqs = append(reqs, req)
+		locations = append(locations, resp.Header.Get("Location"))
+		now = now.Add(time.Minute)
+	}
+	get := func(location string) int {
+		resp, err := http.Get(ts.URL + location)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		return resp.StatusCode
+	}
+	for i, want := range []int{http.StatusNotFound, http.StatusOK, http.StatusOK} {
+		if s := get(locations[i]); s != want {
+			t.Errorf("GET %d of 3 with room for 2: %d, want %d", i, s, want)
+		}
+	}
+
+	now = now.Add(time.Hour)
+	for i := range locations {
+		if s := get(locations[i]); s != http.StatusNotFound {
+			t.Errorf("GET %d after an hour: %d, want %d", i, s, http.StatusNotFound)
+		}
+	}
+	resp := post(t, ts.URL+"/v1/requests", reqs[0])
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusCreated {
+		t.Errorf("POST of a forgotten request: %s, want it signed again", resp.Status)
+	}
+	if n := len(srv.issued); n != 1 || len(srv.order) != 1 {
+		t.Errorf("%d requests kept, %d in order, want 1", n, len(srv.order))
+	}
+}
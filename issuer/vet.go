@@ -0,0 +1,47 @@
+package issuer
+
+import (
+	"Obfushop/crypto/AC"
+	"fmt"
+	"math/big"
+	"net/http"
+)
+
+// Vetter decides whether a request may be signed. It sees the HTTP request,
+// e.g. for authentication headers, and the attribute values revealed to the
+// issuer. Vetters run before π_s is checked, so they must not keep state;
+// bookkeeping for issued credentials belongs in Server.OnIssue.
+type Vetter func(r *http.Request, req *AC.Req) error
+
+// RequireAttributes accepts only requests over exactly n attributes,
+// including the epoch and the revocation handle.
+func RequireAttributes(n int) Vetter {
+	return func(r *http.Request, req *AC.Req) error {
+		if len(req.C) != n {
+			return fmt.Errorf("want %d attributes, got %d", n, len(req.C))
+		}
+		return nil
+	}
+}
+
+// RequireEpoch accepts only requests for the validity epoch returned by now,
+// see AC.PrepareEpochBlindSign.
+func RequireEpoch(now func() uint64) Vetter {
+	return func(r *http.Request, req *AC.Req) error {
+		epoch := new(big.Int).SetUint64(now())
+		if req.Epoch == nil || req.Epoch.Cmp(epoch) != 0 {
+			return fmt.Errorf("credentials are issued for epoch %v only", epoch)
+		}
+		return nil
+	}
+}
+
+// RequireRevocable accepts only requests carrying a revocation handle.
+func RequireRevocable() Vetter {
+	return func(r *http.Request, req *AC.Req) error {
+		if req.Handle == nil {
+			return fmt.Errorf("request has no revocation handle")
+		}
+		return nil
+	}
+}
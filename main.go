@@ -12,6 +12,7 @@ import (
 	"context"
 	"crypto/rand"
 	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"log"
 	"math/big"
@@ -94,70 +95,132 @@ func main() {
 	}
 	//=============================Setup Phase==============================//
 	//1.Shopping-chain setup
-	paramters := AC.Setup()           //Generate ACs parameters
-	issuerkey := AC.KeyGen(paramters) //Generate issuer's key pair
+	paramters, err := AC.Setup(attributeNum + 3) //Generate ACs parameters, followed by the buyer secret, the validity epoch and the revocation handle
+	if err != nil {
+		log.Fatalf("AC setup failed: %v", err)
+	}
+	issuerkey, err := AC.KeyGen(paramters) //Generate issuer's key pair
+	if err != nil {
+		log.Fatalf("Issuer key generation failed: %v", err)
+	}
+	issuerPKY := make([]contract.BCSIDG1Point, attributeNum+3)
+	for i := 0; i < attributeNum+3; i++ {
+		issuerPKY[i] = Convert.G1ToG1Point(issuerkey.PK2[i])
+	}
 	auth0 := utils.Transact(client, privatekey, big.NewInt(0))
-	tx0, _ := Contract.UploadACsParams(auth0, Convert.G1ToG1Point(paramters.G1), Convert.G2ToG2Point(paramters.G2), Convert.G1ToG1Point(issuerkey.PK1), Convert.G1ToG1Point(issuerkey.PK2))
+	tx0, _ := Contract.UploadACsParams(auth0, Convert.G1ToG1Point(paramters.G1), Convert.G2ToG2Point(paramters.G2), Convert.G1ToG1Point(issuerkey.PK1), issuerPKY)
 	receipt0, err := bind.WaitMined(context.Background(), client, tx0)
 	if err != nil {
 		log.Fatalf("Tx receipt failed: %v", err)
 	}
 	fmt.Printf("UploadIssuerKey Gas used: %d\n", receipt0.GasUsed)
+	fingerprint, err := issuerkey.Fingerprint()
+	if err != nil {
+		log.Fatalf("Issuer key fingerprint failed: %v", err)
+	}
+	keyVersion, _ := Contract.CurrentKeyVersion(&bind.CallOpts{}) //credentials below are issued under this key version
+	onchain, _ := Contract.IssuerKeyFingerprint(&bind.CallOpts{}, keyVersion)
+	fmt.Printf("Issuer key fingerprint %s, on-chain key matches: %v\n", fingerprint, hex.EncodeToString(onchain[:]) == fingerprint)
+
+	//2.Issuer opens the yearly validity epoch and the revocation epoch
+	epoch, err := AC.EpochAt(time.Now(), 365*24*time.Hour)
+	if err != nil {
+		log.Fatalf("Validity epoch failed: %v", err)
+	}
+	validity := &AC.Validity{Index: attributeNum + 1, Epoch: epoch}
+	auth02 := utils.Transact(client, privatekey, big.NewInt(0))
+	tx02, _ := Contract.SetValidityEpoch(auth02, big.NewInt(int64(validity.Index)), new(big.Int).SetUint64(validity.Epoch))
+	_, err = bind.WaitMined(context.Background(), client, tx02)
+	if err != nil {
+		log.Fatalf("Tx receipt failed: %v", err)
+	}
+	auth03 := utils.Transact(client, privatekey, big.NewInt(0))
+	tx03, _ := Contract.SetNymIndex(auth03, big.NewInt(int64(attributeNum)))
+	_, err = bind.WaitMined(context.Background(), client, tx03)
+	if err != nil {
+		log.Fatalf("Tx receipt failed: %v", err)
+	}
+	revocationList := AC.NewRevocationList(1)
+	auth01 := utils.Transact(client, privatekey, big.NewInt(0))
+	tx01, _ := Contract.UploadRevocationBase(auth01, new(big.Int).SetUint64(revocationList.Epoch), Convert.G1ToG1Point(AC.RevocationBase(revocationList.Epoch)))
+	_, err = bind.WaitMined(context.Background(), client, tx01)
+	if err != nil {
+		log.Fatalf("Tx receipt failed: %v", err)
+	}
 
 	//==================================Register=====================================//
 	var attributeACsSet = []string{"Age>18", "B", "C", "D", "E", "F", "G", "H", "I", "J"}
 
-	//1.User obtains his credential
+	//1.User obtains one credential over all his attributes and his secret skB
+	skB, _ := rand.Int(rand.Reader, bn256.Order)
 	hashSet := make([][32]byte, attributeNum)
 	mSet := make([]*big.Int, attributeNum)
-	dSet := make([]*big.Int, attributeNum)
-	reqSet := make([]*AC.Req, attributeNum)
-	signatureSet := make([]*AC.BlindSignature, attributeNum)
-	credSet := make([]*AC.Cred, attributeNum)
 	for i := 0; i < attributeNum; i++ {
 		hashSet[i] = sha256.Sum256([]byte(attributeACsSet[i]))
 		mSet[i] = new(big.Int).SetBytes(hashSet[i][:])
-		dSet[i], reqSet[i] = AC.PrepareBlindSign(paramters, mSet[i])
-		signatureSet[i] = AC.BlindSign(paramters, issuerkey, reqSet[i])
-		credSet[i] = AC.ObtainCred(signatureSet[i], dSet[i])
 	}
+	mSet = append(mSet, skB)
+	handle, err := AC.NewRevocationHandle(paramters)
+	if err != nil {
+		log.Fatalf("Revocation handle failed: %v", err)
+	}
+	d, req, err := AC.PrepareEpochBlindSign(paramters, mSet, validity.Epoch, handle)
+	if err != nil {
+		log.Fatalf("Credential request failed: %v", err)
+	}
+	signature, err := AC.BlindSign(paramters, issuerkey, req)
+	if err != nil {
+		log.Fatalf("Blind signing failed: %v", err)
+	}
+	cred, err := AC.ObtainCred(signature, d)
+	if err != nil {
+		log.Fatalf("Obtaining credential failed: %v", err)
+	}
+	mSet = append(mSet, req.Epoch, req.Handle)
 
-	ProofAttrSet := make([]string, attributeNum)
+	//Only "Age>18" and the current epoch are disclosed, the remaining attributes, skB and the revocation handle stay hidden
+	disclose := make([]bool, attributeNum+3)
+	disclose[0] = true
+	disclose[validity.Index] = true
+	ProofAttrSet := make([]string, attributeNum+3)
 	for i := 0; i < attributeNum; i++ {
-		ProofAttrSet[i] = attributeACsSet[i]
+		if disclose[i] {
+			ProofAttrSet[i] = attributeACsSet[i]
+		}
 	}
 
-	//Algorithm1:Generate shopping identity
-	var skB *big.Int
+	//Algorithm1:Generate shopping identity, a pseudonym pkB = H(seller)^skB towards the seller
+	sellerAddr := utils.Transact(client, privatekeySeller, big.NewInt(0)).From
+	nym := &AC.Pseudonym{Index: attributeNum, Scope: sellerAddr.Bytes()}
+	//The contract accepts each nonce once and checks the presentation was made for it and this seller
+	nonce, err := AC.NewNonce()
+	if err != nil {
+		log.Fatalf("Nonce failed: %v", err)
+	}
+	session := &AC.Session{Nonce: nonce, Context: AC.NymContext(address.Bytes(), sellerAddr.Bytes())}
 	var pkB *bn256.G1
-	var pi_pkB *AC.DL
-	Proofs := make([]*AC.Proof, attributeNum)
+	var Proof *AC.Proof
 
 	start := time.Now() // 记录开始时间
 	for i := 0; i < iterations; i++ {
-		skB, _ = rand.Int(rand.Reader, bn256.Order)
-		pkB = new(bn256.G1).ScalarBaseMult(skB)
-		pi_pkB = AC.DLProof(new(bn256.G1).ScalarBaseMult(big.NewInt(int64(1))), pkB, skB)
-		for i := 0; i < attributeNum; i++ {
-			Proofs[i], _ = AC.ProveCred(paramters, skB, issuerkey, credSet[i], mSet[i])
+		Proof, err = AC.ProveNym(paramters, issuerkey, cred, mSet, disclose, nil, revocationList, validity, nym, session)
+		if err != nil {
+			log.Fatalf("Shopping identity failed: %v", err)
 		}
+		pkB = Proof.Nym
 	}
 	elapsed := time.Since(start)
 	fmt.Printf("Time cost of Algorithm1: : %.6f ms\n", elapsed.Seconds()*1000/float64(iterations))
 
 	//Verify shopping identity
-	ProofsUSet := make([]contract.BCSIDG2Point, attributeNum)
-	ProofsSSet := make([]contract.BCSIDG2Point, attributeNum)
-	ProofsValueSet := make([]*big.Int, attributeNum)
-	for i := 0; i < attributeNum; i++ {
-		ProofsUSet[i] = Convert.G2ToG2Point(Proofs[i].U)
-		ProofsSSet[i] = Convert.G2ToG2Point(Proofs[i].S)
-		ProofsValueSet[i] = Proofs[i].Value
-	}
-
 	auth11 := utils.Transact(client, privatekeyBuyer, big.NewInt(0))
-	tx11, _ := Contract.RegisterSIDSet(auth11, Convert.G1ToG1Point(pkB), Convert.G1ToG1Point(pi_pkB.RG), pi_pkB.C, pi_pkB.Z,
-		ProofsUSet, ProofsSSet, ProofAttrSet)
+	tx11, err := Contract.RegisterNymSet(auth11, sellerAddr, Convert.G1ToG1Point(pkB),
+		Convert.G2ToG2Point(Proof.U), Convert.G2ToG2Point(Proof.S),
+		Convert.G1ToG1Point(Proof.Kappa), Proof.PiV.C, Proof.PiV.Rm, Proof.PiV.Rt, ProofAttrSet, disclose,
+		Convert.G1ToG1Point(Proof.Tag), new(big.Int).SetUint64(validity.Epoch), keyVersion, [32]byte(nonce))
+	if err != nil {
+		log.Fatalf("Registering shopping identity failed: %v", err)
+	}
 	receipt11, err := bind.WaitMined(context.Background(), client, tx11)
 	if err != nil {
 		log.Fatalf("Tx receipt failed: %v", err)
@@ -169,9 +232,50 @@ func main() {
 	pkB2 := new(bn256.G2).ScalarBaseMult(skB)
 	fmt.Printf("Size of G1Point: %.6f KB\n", float64(sizeOfG1Point(Convert.G1ToG1Point(pkB)))/1024)
 	fmt.Printf("Size of G2Point: %.6f KB\n", float64(sizeOfG2Point(Convert.G2ToG2Point(pkB2)))/1024)
-	fmt.Printf("Size of uint256: %.6f KB\n", float64(sizeOfBigInt(pi_pkB.C))/1024)
+	fmt.Printf("Size of uint256: %.6f KB\n", float64(sizeOfBigInt(Proof.PiV.C))/1024)
 	fmt.Printf("Size of string: %.6f KB\n", float64(sizeOfString(attributeACsSet[0]))/1024)
 
+	//A bank issues a KYC credential to the same skB; the buyer shows it together with "Age>18" in one aggregated presentation
+	kycParams, err := AC.Setup(1)
+	if err != nil {
+		log.Fatalf("AC setup failed: %v", err)
+	}
+	bankKey, err := AC.KeyGen(kycParams)
+	if err != nil {
+		log.Fatalf("Bank key generation failed: %v", err)
+	}
+	registry := AC.NewIssuerRegistry()
+	if err := registry.Register("root", issuerkey); err != nil {
+		log.Fatalf("Issuer registry failed: %v", err)
+	}
+	if err := registry.Register("bank", bankKey); err != nil {
+		log.Fatalf("Issuer registry failed: %v", err)
+	}
+	kycHash := sha256.Sum256([]byte("KYC-verified"))
+	kycSet := []*big.Int{new(big.Int).SetBytes(kycHash[:])}
+	dKYC, reqKYC, err := AC.PrepareBlindSign(kycParams, kycSet)
+	if err != nil {
+		log.Fatalf("Credential request failed: %v", err)
+	}
+	sigKYC, err := AC.BlindSign(kycParams, bankKey, reqKYC)
+	if err != nil {
+		log.Fatalf("Blind signing failed: %v", err)
+	}
+	credKYC, err := AC.ObtainCred(sigKYC, dKYC)
+	if err != nil {
+		log.Fatalf("Obtaining credential failed: %v", err)
+	}
+	parts := []*AC.AggregatePart{{Issuer: "root", Revocation: revocationList, Validity: validity}, {Issuer: "bank"}}
+	aggProof, err := AC.ProveAggregate(paramters, registry, skB, []*AC.Holding{
+		{AggregatePart: *parts[0], Cred: cred, Attrs: mSet, Disclose: disclose},
+		{AggregatePart: *parts[1], Cred: credKYC, Attrs: kycSet, Disclose: []bool{true}},
+	}, nil)
+	if err != nil {
+		log.Fatalf("Aggregated presentation failed: %v", err)
+	}
+	aggOK, err := AC.VerifyAggregate(paramters, registry, new(bn256.G1).ScalarBaseMult(skB), aggProof, parts, nil)
+	fmt.Printf("Aggregated presentation from %v verifies: %v %v\n", aggProof.Issuers, aggOK, err)
+
 	//====================================Shopping=====================================//
 	attribute := "Age>18"
 	//1.Merchant sets productID and its price.
@@ -183,7 +287,6 @@ func main() {
 		log.Fatalf("Tx receipt failed: %v", err)
 	}
 	fmt.Printf("Merchant set the  product Gas used: %d\n", receipt2.GasUsed)
-	sellerAddr := auth2.From
 
 	//2. Buyer Obtain product price;
 	price, err := Contract.GetProduct(&bind.CallOpts{}, sellerAddr, productID)
@@ -239,7 +342,16 @@ func main() {
 	//加密派件地址
 	//Algorithm 3
 	tau := "(Owner  OR (Community_A AND Hovering_drone))"
-	ABECT, xsMap, _, _ := OABE.Encrypt(keyAES, tau, PK)
+	ABECT, _, _, err := OABE.Encrypt(keyAES, tau, PK)
+	if err != nil {
+		log.Fatalf("加密失败: %v", err)
+	}
+	// 密文以二进制形式交给物流方和无人机
+	ABECTBytes, err := ABECT.MarshalBinary()
+	if err != nil {
+		log.Fatalf("密文编码失败: %v", err)
+	}
+	fmt.Printf("OABE密文长度: %d bytes\n", len(ABECTBytes))
 	cipherAddr, err := AES.EncryptAndEncode(DelivAddr, keyAES.Marshal())
 	if err != nil {
 		log.Fatalf("加密失败: %v", err)
@@ -248,7 +360,7 @@ func main() {
 
 	//2.Logistics company generate a logistics order
 	N, _ := rand.Int(rand.Reader, bn256.Order)
-	code := Convert.StringToBigInt(order[0].OrderID + "||" + string(new(bn256.G1).ScalarBaseMult(N).Marshal()))
+	code := Convert.StringToBigInt(order[0].OrderID + "||" + string(new(bn256.G1).ScalarMult(AC.ScopeBase(sellerAddr.Bytes()), N).Marshal()))
 	SN := new(bn256.G1).ScalarMult(pkB, N)
 	auth5 := utils.Transact(client, privatekeyLogistics, big.NewInt(0)) // ⬅️ 发送 totalPrice wei
 	tx5, _ := Contract.CreateLogisticsOrder(auth5, sellerAddr, buyerAddr, order[0].OrderID, code, Convert.G1ToG1Point(SN))
@@ -277,8 +389,20 @@ func main() {
 
 	//5.Drone decrypts the intermediate result to obtain delivery address
 	//Algorithm 4
-	IR := OABE.ODecrypt(Su, ABECT, SK, xsMap, PK) //外包解密
-	_keyAES := OABE.Decrypt(IR, sku, ABECT)       //无人机解密
+	droneCT := new(OABE.Ciphertext)
+	if err := droneCT.UnmarshalBinary(ABECTBytes); err != nil {
+		log.Fatalf("密文解码失败: %v", err)
+	}
+	// 只把满足策略所需的属性密钥分量交给外包服务器
+	serverSK, err := SK.Select(droneCT.Policy)
+	if err != nil {
+		log.Fatalf("属性不满足访问策略: %v", err)
+	}
+	IR, err := OABE.ODecryptPruned(droneCT, serverSK) //外包解密
+	if err != nil {
+		log.Fatalf("外包解密失败: %v", err)
+	}
+	_keyAES := OABE.Decrypt(IR, sku, droneCT) //无人机解密
 	_DelivAddr, err := AES.DecodeAndDecrypt(cipherAddr, _keyAES.Marshal())
 	if err != nil {
 		fmt.Println("解密失败:", err)
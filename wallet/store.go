@@ -0,0 +1,188 @@
+package wallet
+
+import (
+	"Obfushop/crypto/AES"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"golang.org/x/crypto/scrypt"
+)
+
+// A wallet file is a JSON envelope holding the scrypt parameters and the
+// AES-GCM encryption (crypto/AES) of the JSON contents. Every save draws a
+// fresh salt and nonce.
+
+// FileVersion is the version of the envelope written by Save.
+const FileVersion = 1
+
+// Default scrypt cost, about 100ms and 32MB per unlock.
+const (
+	ScryptN = 1 << 15
+	ScryptR = 8
+	ScryptP = 1
+)
+
+const saltSize = 16
+
+var (
+	// ErrBadPassword is returned when the file does not decrypt.
+	ErrBadPassword = errors.New("wallet: wrong password or corrupted file")
+	// ErrNotFound is returned for unknown entry names.
+	ErrNotFound = errors.New("wallet: no such entry")
+	// ErrExists is returned when an entry name is already taken.
+	ErrExists = errors.New("wallet: entry exists")
+)
+
+type kdfParams struct {
+	Name string `json:"name"`
+	Salt []byte `json:"salt"`
+	N    int    `json:"n"`
+	R    int    `json:"r"`
+	P    int    `json:"p"`
+}
+
+type envelope struct {
+	Version int       `json:"version"`
+	KDF     kdfParams `json:"kdf"`
+	Data    string    `json:"data"` // AES.EncryptAndEncode output
+}
+
+func (kdf *kdfParams) key(password []byte) ([]byte, error) {
+	if kdf.Name != "scrypt" || len(kdf.Salt) < saltSize {
+		return nil, fmt.Errorf("wallet: unsupported key derivation %q", kdf.Name)
+	}
+	// refuse parameters that would take forever rather than trying them
+	if kdf.N < 2 || kdf.N > 1<<22 || kdf.R < 1 || kdf.R > 32 || kdf.P < 1 || kdf.P > 16 {
+		return nil, errors.New("wallet: unreasonable scrypt parameters")
+	}
+	return scrypt.Key(password, kdf.Salt, kdf.N, kdf.R, kdf.P, 32)
+}
+
+// seal encrypts v under password.
+func seal(v any, password []byte) ([]byte, error) {
+	if len(password) == 0 {
+		return nil, errors.New("wallet: empty password")
+	}
+	plain, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	kdf := kdfParams{Name: "scrypt", Salt: make([]byte, saltSize), N: ScryptN, R: ScryptR, P: ScryptP}
+	if _, err := rand.Read(kdf.Salt); err != nil {
+		return nil, err
+	}
+	key, err := kdf.key(password)
+	if err != nil {
+		return nil, err
+	}
+	data, err := AES.EncryptAndEncode(plain, key)
+	if err != nil {
+		return nil, err
+	}
+	return json.MarshalIndent(&envelope{Version: FileVersion, KDF: kdf, Data: data}, "", "  ")
+}
+
+// open decrypts the output of seal into v.
+func open(sealed []byte, password []byte, v any) error {
+	var env envelope
+	if err := json.Unmarshal(sealed, &env); err != nil {
+		return fmt.Errorf("wallet: not a wallet file: %w", err)
+	}
+	if env.Version != FileVersion {
+		return fmt.Errorf("wallet: unsupported file version %d", env.Version)
+	}
+	key, err := env.KDF.key(password)
+	if err != nil {
+		return err
+	}
+	plain, err := AES.DecodeAndDecrypt(env.Data, key)
+	if err != nil {
+		return ErrBadPassword
+	}
+	return json.Unmarshal(plain, v)
+}
+
+// Create makes a new empty wallet at path. It fails if the file exists.
+func Create(path string, password []byte) (*Wallet, error) {
+	w := &Wallet{path: path, password: append([]byte(nil), password...), contents: newContents()}
+	sealed, err := seal(w.contents, w.password)
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := f.Write(sealed); err != nil {
+		f.Close()
+		return nil, err
+	}
+	return w, f.Close()
+}
+
+// Open unlocks the wallet at path.
+func Open(path string, password []byte) (*Wallet, error) {
+	sealed, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	w := &Wallet{path: path, password: append([]byte(nil), password...), contents: newContents()}
+	if err := open(sealed, password, w.contents); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+// Save writes the wallet back to its file, replacing it atomically.
+func (w *Wallet) Save() error {
+	if w.password == nil {
+		return errors.New("wallet: closed")
+	}
+	sealed, err := seal(w.contents, w.password)
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(w.path), filepath.Base(w.path)+".tmp*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(sealed); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Chmod(0600); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), w.path)
+}
+
+// Rotate re-encrypts the wallet under a new password and saves it.
+func (w *Wallet) Rotate(password []byte) error {
+	old := w.password
+	w.password = append([]byte(nil), password...)
+	if err := w.Save(); err != nil {
+		w.password = old
+		return err
+	}
+	clear(old)
+	return nil
+}
+
+// Close forgets the password. The wallet cannot be saved afterwards.
+func (w *Wallet) Close() {
+	clear(w.password)
+	w.password = nil
+}
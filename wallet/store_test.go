@@ -0,0 +1,205 @@
+package wallet
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestSealOpen(t *testing.T) {
+	in := map[string]string{"skB": "42"}
+	sealed, err := seal(in, []byte("pw"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var out map[string]string
+	if err := open(sealed, []byte("pw"), &out); err != nil {
+		t.Fatal(err)
+	}
+	if out["skB"] != "42" || len(out) != 1 {
+		t.Errorf("opened %v, want %v", out, in)
+	}
+	if bytes.Contains(sealed, []byte("skB")) {
+		t.Error("sealed file contains the plaintext")
+	}
+	again, err := seal(in, []byte("pw"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Equal(again, sealed) {
+		t.Error("two seals of the same contents are equal")
+	}
+
+	if err := open(sealed, []byte("wrong"), &out); !errors.Is(err, ErrBadPassword) {
+		t.Errorf("wrong password: %v, want ErrBadPassword", err)
+	}
+	if _, err := seal(in, nil); err == nil {
+		t.Error("sealed with an empty password")
+	}
+	corrupt := bytes.Replace(sealed, []byte(`"data": "`), []byte(`"data": "A`), 1)
+	if err := open(corrupt, []byte("pw"), &out); !errors.Is(err, ErrBadPassword) {
+		t.Errorf("corrupted data: %v, want ErrBadPassword", err)
+	}
+	for name, data := range map[string][]byte{
+		"not JSON":     []byte("wallet"),
+		"version":      bytes.Replace(sealed, []byte(`"version": 1`), []byte(`"version": 2`), 1),
+		"kdf":          bytes.Replace(sealed, []byte(`"scrypt"`), []byte(`"pbkdf2"`), 1),
+		"scrypt costs": bytes.Replace(sealed, []byte(`"n": 32768`), []byte(`"n": 1073741824`), 1),
+	} {
+		if bytes.Equal(data, sealed) {
+			t.Fatalf("%s: edit did not apply", name)
+		}
+		if err := open(data, []byte("pw"), &out); err == nil || errors.Is(err, ErrBadPassword) {
+			t.Errorf("%s: %v, want a format error", name, err)
+		}
+	}
+}
+
+func TestCreateOpen(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "w")
+	w, err := Create(path, []byte("pw"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	id, err := w.NewIdentity("alice")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Save(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Create(path, []byte("pw")); !errors.Is(err, os.ErrExist) {
+		t.Errorf("creating over a wallet: %v, want os.ErrExist", err)
+	}
+	if _, err := Open(path, []byte("wrong")); !errors.Is(err, ErrBadPassword) {
+		t.Errorf("wrong password: %v, want ErrBadPassword", err)
+	}
+	w, err = Open(path, []byte("pw"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := w.Identity("alice")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.SK.Cmp(id.SK) != 0 {
+		t.Error("identity changed across Save and Open")
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != 0600 {
+		t.Errorf("wallet file mode %v, want 0600", info.Mode().Perm())
+	}
+}
+
+func TestSaveAtomic(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "w")
+	w, err := Create(path, []byte("pw"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	before, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	old, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer old.Close()
+	oldInfo, err := old.Stat()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Save writes a temporary file and renames it over the wallet, so a
+	// reader holding the old file still sees it whole.
+	if _, err := w.NewIdentity("alice"); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Save(); err != nil {
+		t.Fatal(err)
+	}
+	newInfo, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if os.SameFile(oldInfo, newInfo) {
+		t.Error("Save wrote the wallet in place")
+	}
+	var buf bytes.Buffer
+	if _, err := buf.ReadFrom(old); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(buf.Bytes(), before) {
+		t.Error("old wallet file changed under a reader")
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 {
+		t.Errorf("Save left %d files behind", len(entries)-1)
+	}
+
+	// A failed save leaves the wallet as it was.
+	after, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w.Close()
+	if err := w.Save(); err == nil {
+		t.Error("saved a closed wallet")
+	}
+	if now, err := os.ReadFile(path); err != nil || !bytes.Equal(now, after) {
+		t.Error("a failed save changed the wallet file")
+	}
+}
+
+func TestRotate(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "sub")
+	if err := os.Mkdir(dir, 0700); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(dir, "w")
+	w, err := Create(path, []byte("old"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := w.NewIdentity("alice"); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Rotate([]byte("new")); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Open(path, []byte("old")); !errors.Is(err, ErrBadPassword) {
+		t.Errorf("old password after Rotate: %v, want ErrBadPassword", err)
+	}
+	w2, err := Open(path, []byte("new"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := w2.Identity("alice"); err != nil {
+		t.Error(err)
+	}
+
+	if err := w.Rotate(nil); err == nil {
+		t.Error("rotated to an empty password")
+	}
+
+	// If the new file cannot be written the wallet keeps its password.
+	if err := os.RemoveAll(dir); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Rotate([]byte("newer")); err == nil {
+		t.Fatal("Rotate succeeded without a directory to write to")
+	}
+	if !bytes.Equal(w.password, []byte("new")) {
+		t.Error("failed Rotate changed the password")
+	}
+}
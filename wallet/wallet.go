@@ -0,0 +1,310 @@
+// Package wallet keeps a buyer's secrets in a password-encrypted file: the
+// shopping identity skB with its credential, OABE attribute keys and the
+// secrets of open orders. Losing skB means losing the pickup code, so the
+// wallet is the one place these live between runs.
+//
+// Changes are made in memory and written by Save.
+package wallet
+
+import (
+	"Obfushop/bn256"
+	"Obfushop/crypto/AC"
+	"Obfushop/crypto/OABE"
+	"crypto/rand"
+	"fmt"
+	"math/big"
+	"sort"
+	"time"
+)
+
+// Entry kinds.
+const (
+	KindIdentity     = "identity"
+	KindAttributeKey = "attribute-key"
+	KindOrder        = "order"
+)
+
+// Wallet is an unlocked wallet file.
+type Wallet struct {
+	path     string
+	password []byte
+	contents *contents
+}
+
+type contents struct {
+	Identities    map[string]*Identity     `json:"identities"`
+	AttributeKeys map[string]*AttributeKey `json:"attributeKeys"`
+	Orders        map[string]*Order        `json:"orders"`
+}
+
+func newContents() *contents {
+	return &contents{
+		Identities:    make(map[string]*Identity),
+		AttributeKeys: make(map[string]*AttributeKey),
+		Orders:        make(map[string]*Order),
+	}
+}
+
+// Identity is a shopping identity: the secret skB and the credential that
+// carries it as a hidden attribute.
+type Identity struct {
+	SK      *big.Int   `json:"sk"`              // skB
+	Attrs   []*big.Int `json:"attrs,omitempty"` // signed attribute vector, for AC.ProveCred
+	Cred    *AC.Cred   `json:"cred,omitempty"`
+	Created time.Time  `json:"created"`
+
+	KeyVersion uint64 `json:"keyVersion,omitempty"` // on-chain issuer key of Cred, for RegisterSIDSet
+
+	// Credentials from further issuers by issuer ID, e.g. a bank's KYC
+	// credential, presented together with Cred by AC.ProveAggregate.
+	Held map[string]*Held `json:"held,omitempty"`
+}
+
+// Held is a credential from another issuer for the same skB.
+type Held struct {
+	Attrs      []*big.Int `json:"attrs"`
+	Cred       *AC.Cred   `json:"cred"`
+	KeyVersion uint64     `json:"keyVersion,omitempty"`
+}
+
+// Nym is the pseudonym pkB of the identity towards scope, see AC.Nym.
+func (id *Identity) Nym(scope []byte) *bn256.G1 {
+	return AC.Nym(scope, id.SK)
+}
+
+// PickupCode recovers N·H(seller) from the SN the logistics company put on
+// chain for this identity, as read by GetSN.
+func (id *Identity) PickupCode(SN *bn256.G1) *bn256.G1 {
+	inv := new(big.Int).ModInverse(id.SK, bn256.Order)
+	return new(bn256.G1).ScalarMult(SN, inv)
+}
+
+// AttributeKey is an OABE attribute key with the secret sku that finishes
+// decryption, see OABE.Decrypt.
+type AttributeKey struct {
+	SK         *big.Int           `json:"sk"`
+	Attributes []string           `json:"attributes"`
+	Key        *OABE.AttributeKey `json:"key"` // in the OABE wire format
+	Created    time.Time          `json:"created"`
+}
+
+// Order holds the secrets of one order, e.g. the AES key of the encrypted
+// delivery address.
+type Order struct {
+	Identity string            `json:"identity"` // name of the identity that placed it
+	Seller   string            `json:"seller,omitempty"`
+	Secrets  map[string][]byte `json:"secrets,omitempty"`
+	Created  time.Time         `json:"created"`
+}
+
+// Item names one entry of the wallet.
+type Item struct {
+	Kind    string
+	Name    string
+	Created time.Time
+}
+
+// List returns all entries, sorted by kind and name.
+func (w *Wallet) List() []Item {
+	var items []Item
+	for name, id := range w.contents.Identities {
+		items = append(items, Item{KindIdentity, name, id.Created})
+	}
+	for name, key := range w.contents.AttributeKeys {
+		items = append(items, Item{KindAttributeKey, name, key.Created})
+	}
+	for name, order := range w.contents.Orders {
+		items = append(items, Item{KindOrder, name, order.Created})
+	}
+	sort.Slice(items, func(i, j int) bool {
+		if items[i].Kind != items[j].Kind {
+			return items[i].Kind < items[j].Kind
+		}
+		return items[i].Name < items[j].Name
+	})
+	return items
+}
+
+// NewIdentity draws a fresh skB and stores it under name.
+func (w *Wallet) NewIdentity(name string) (*Identity, error) {
+	sk, err := rand.Int(rand.Reader, new(big.Int).Sub(bn256.Order, big.NewInt(1)))
+	if err != nil {
+		return nil, err
+	}
+	id := &Identity{SK: sk.Add(sk, big.NewInt(1)), Created: time.Now().UTC()}
+	return id, w.PutIdentity(name, id)
+}
+
+// PutIdentity stores an existing identity under a new name.
+func (w *Wallet) PutIdentity(name string, id *Identity) error {
+	if id == nil || id.SK == nil || id.SK.Sign() <= 0 || id.SK.Cmp(bn256.Order) >= 0 {
+		return fmt.Errorf("wallet: identity %q has no valid secret", name)
+	}
+	return put(w.contents.Identities, name, id)
+}
+
+// Identity returns the identity stored under name.
+func (w *Wallet) Identity(name string) (*Identity, error) {
+	return get(w.contents.Identities, name)
+}
+
+// SetCredential stores the credential obtained for an identity together with
+// the attribute vector it signs and the on-chain version of the issuer key
+// that signed it.
+func (w *Wallet) SetCredential(name string, attrs []*big.Int, cred *AC.Cred, keyVersion uint64) error {
+	id, err := w.Identity(name)
+	if err != nil {
+		return err
+	}
+	id.Attrs = append([]*big.Int(nil), attrs...)
+	id.Cred = cred
+	id.KeyVersion = keyVersion
+	return nil
+}
+
+// AddCredential stores a credential from issuer for an identity, replacing
+// an earlier one from the same issuer.
+func (w *Wallet) AddCredential(name, issuer string, attrs []*big.Int, cred *AC.Cred, keyVersion uint64) error {
+	id, err := w.Identity(name)
+	if err != nil {
+		return err
+	}
+	if issuer == "" || cred == nil {
+		return fmt.Errorf("wallet: credential for %q needs an issuer and a credential", name)
+	}
+	if id.Held == nil {
+		id.Held = make(map[string]*Held)
+	}
+	id.Held[issuer] = &Held{Attrs: append([]*big.Int(nil), attrs...), Cred: cred, KeyVersion: keyVersion}
+	return nil
+}
+
+// PutAttributeKey stores an OABE attribute key under name.
+func (w *Wallet) PutAttributeKey(name string, key *AttributeKey) error {
+	if key == nil || key.SK == nil || key.Key == nil || key.Key.D == nil {
+		return fmt.Errorf("wallet: attribute key %q is incomplete", name)
+	}
+	if key.Created.IsZero() {
+		key.Created = time.Now().UTC()
+	}
+	return put(w.contents.AttributeKeys, name, key)
+}
+
+// AttributeKey returns the attribute key stored under name.
+func (w *Wallet) AttributeKey(name string) (*AttributeKey, error) {
+	return get(w.contents.AttributeKeys, name)
+}
+
+// PutOrder stores the secrets of an order under its order ID.
+func (w *Wallet) PutOrder(orderID string, order *Order) error {
+	if order == nil {
+		return fmt.Errorf("wallet: order %q is empty", orderID)
+	}
+	if _, ok := w.contents.Identities[order.Identity]; order.Identity != "" && !ok {
+		return fmt.Errorf("%w: identity %q", ErrNotFound, order.Identity)
+	}
+	if order.Created.IsZero() {
+		order.Created = time.Now().UTC()
+	}
+	return put(w.contents.Orders, orderID, order)
+}
+
+// Order returns the order stored under orderID.
+func (w *Wallet) Order(orderID string) (*Order, error) {
+	return get(w.contents.Orders, orderID)
+}
+
+// Remove deletes an entry.
+func (w *Wallet) Remove(kind, name string) error {
+	switch kind {
+	case KindIdentity:
+		return remove(w.contents.Identities, name)
+	case KindAttributeKey:
+		return remove(w.contents.AttributeKeys, name)
+	case KindOrder:
+		return remove(w.contents.Orders, name)
+	}
+	return fmt.Errorf("wallet: unknown kind %q", kind)
+}
+
+func put[T any](m map[string]T, name string, v T) error {
+	if name == "" {
+		return fmt.Errorf("wallet: empty name")
+	}
+	if _, ok := m[name]; ok {
+		return fmt.Errorf("%w: %q", ErrExists, name)
+	}
+	m[name] = v
+	return nil
+}
+
+func get[T any](m map[string]*T, name string) (*T, error) {
+	v, ok := m[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrNotFound, name)
+	}
+	return v, nil
+}
+
+func remove[T any](m map[string]T, name string) error {
+	if _, ok := m[name]; !ok {
+		return fmt.Errorf("%w: %q", ErrNotFound, name)
+	}
+	delete(m, name)
+	return nil
+}
+
+// bundle is one exported entry.
+type bundle struct {
+	Kind         string        `json:"kind"`
+	Name         string        `json:"name"`
+	Identity     *Identity     `json:"identity,omitempty"`
+	AttributeKey *AttributeKey `json:"attributeKey,omitempty"`
+	Order        *Order        `json:"order,omitempty"`
+}
+
+// Export encrypts one entry under its own password, e.g. to move an identity
+// to another device. Import reads it back.
+func (w *Wallet) Export(kind, name string, password []byte) ([]byte, error) {
+	b := &bundle{Kind: kind, Name: name}
+	var err error
+	switch kind {
+	case KindIdentity:
+		b.Identity, err = w.Identity(name)
+	case KindAttributeKey:
+		b.AttributeKey, err = w.AttributeKey(name)
+	case KindOrder:
+		b.Order, err = w.Order(name)
+	default:
+		err = fmt.Errorf("wallet: unknown kind %q", kind)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return seal(b, password)
+}
+
+// Import adds an entry made by Export. It does not replace existing entries.
+func (w *Wallet) Import(data []byte, password []byte) (Item, error) {
+	var b bundle
+	if err := open(data, password, &b); err != nil {
+		return Item{}, err
+	}
+	item := Item{Kind: b.Kind, Name: b.Name}
+	var err error
+	switch {
+	case b.Kind == KindIdentity && b.Identity != nil:
+		item.Created = b.Identity.Created
+		err = w.PutIdentity(b.Name, b.Identity)
+	case b.Kind == KindAttributeKey && b.AttributeKey != nil:
+		item.Created = b.AttributeKey.Created
+		err = w.PutAttributeKey(b.Name, b.AttributeKey)
+	case b.Kind == KindOrder && b.Order != nil:
+		item.Created = b.Order.Created
+		// the identity may not have been imported yet
+		err = put(w.contents.Orders, b.Name, b.Order)
+	default:
+		err = fmt.Errorf("wallet: malformed export of kind %q", b.Kind)
+	}
+	return item, err
+}
@@ -0,0 +1,165 @@
+package wallet
+
+import (
+	"Obfushop/bn256"
+	"Obfushop/crypto/OABE"
+	"bytes"
+	"crypto/rand"
+	"errors"
+	"path/filepath"
+	"testing"
+)
+
+func newWallet(t *testing.T) *Wallet {
+	t.Helper()
+	w, err := Create(filepath.Join(t.TempDir(), "w"), []byte("pw"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return w
+}
+
+func newAttributeKey(t *testing.T) *AttributeKey {
+	t.Helper()
+	MSK, PK := OABE.Setup()
+	sk, pku, err := bn256.RandomG1(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	attrs := []string{"Owner", "Community_A"}
+	return &AttributeKey{SK: sk, Attributes: attrs, Key: OABE.KeyGen(pku, MSK, PK, attrs)}
+}
+
+func sameAttributeKey(a, b *AttributeKey) bool {
+	if a.SK.Cmp(b.SK) != 0 || len(a.Attributes) != len(b.Attributes) || !a.Created.Equal(b.Created) ||
+		!bytes.Equal(a.Key.D.Marshal(), b.Key.D.Marshal()) || len(a.Key.Parts) != len(b.Key.Parts) {
+		return false
+	}
+	for i, part := range a.Key.Parts {
+		other := b.Key.Parts[i]
+		if a.Attributes[i] != b.Attributes[i] || part.Attribute != other.Attribute ||
+			!bytes.Equal(part.D1.Marshal(), other.D1.Marshal()) || !bytes.Equal(part.D2.Marshal(), other.D2.Marshal()) {
+			return false
+		}
+	}
+	return true
+}
+
+func TestExportImport(t *testing.T) {
+	w := newWallet(t)
+	id, err := w.NewIdentity("alice")
+	if err != nil {
+		t.Fatal(err)
+	}
+	key := newAttributeKey(t)
+	if err := w.PutAttributeKey("drone", key); err != nil {
+		t.Fatal(err)
+	}
+	order := &Order{Identity: "alice", Seller: "0x01", Secrets: map[string][]byte{"address": {1, 2, 3}}}
+	if err := w.PutOrder("order-1", order); err != nil {
+		t.Fatal(err)
+	}
+
+	// Entries move to another wallet one at a time, an order before its
+	// identity.
+	other := newWallet(t)
+	for _, item := range []Item{{Kind: KindOrder, Name: "order-1"}, {Kind: KindIdentity, Name: "alice"}, {Kind: KindAttributeKey, Name: "drone"}} {
+		data, err := w.Export(item.Kind, item.Name, []byte("export"))
+		if err != nil {
+			t.Fatalf("exporting %s %s: %v", item.Kind, item.Name, err)
+		}
+		if _, err := other.Import(data, []byte("wrong")); !errors.Is(err, ErrBadPassword) {
+			t.Errorf("importing %s %s with a wrong password: %v, want ErrBadPassword", item.Kind, item.Name, err)
+		}
+		got, err := other.Import(data, []byte("export"))
+		if err != nil {
+			t.Fatalf("importing %s %s: %v", item.Kind, item.Name, err)
+		}
+		if got.Kind != item.Kind || got.Name != item.Name || got.Created.IsZero() {
+			t.Errorf("imported %+v, want %s %s", got, item.Kind, item.Name)
+		}
+		if _, err := other.Import(data, []byte("export")); !errors.Is(err, ErrExists) {
+			t.Errorf("importing %s %s twice: %v, want ErrExists", item.Kind, item.Name, err)
+		}
+	}
+
+	gotID, err := other.Identity("alice")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if gotID.SK.Cmp(id.SK) != 0 || !gotID.Created.Equal(id.Created) {
+		t.Error("identity changed in export")
+	}
+	gotKey, err := other.AttributeKey("drone")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !sameAttributeKey(gotKey, key) {
+		t.Error("attribute key changed in export")
+	}
+	gotOrder, err := other.Order("order-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if gotOrder.Identity != "alice" || gotOrder.Seller != "0x01" || !bytes.Equal(gotOrder.Secrets["address"], []byte{1, 2, 3}) {
+		t.Errorf("order changed in export: %+v", gotOrder)
+	}
+
+	if _, err := w.Export(KindIdentity, "bob", []byte("export")); !errors.Is(err, ErrNotFound) {
+		t.Errorf("exporting a missing identity: %v, want ErrNotFound", err)
+	}
+	if _, err := w.Export("cookie", "alice", []byte("export")); err == nil {
+		t.Error("exported an unknown kind")
+	}
+	if _, err := w.Export(KindIdentity, "alice", nil); err == nil {
+		t.Error("exported under an empty password")
+	}
+	junk, err := seal(&bundle{Kind: KindIdentity, Name: "carol"}, []byte("export"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := other.Import(junk, []byte("export")); err == nil {
+		t.Error("imported an identity bundle without an identity")
+	}
+}
+
+func TestSaveOpenEntries(t *testing.T) {
+	w := newWallet(t)
+	if _, err := w.NewIdentity("alice"); err != nil {
+		t.Fatal(err)
+	}
+	key := newAttributeKey(t)
+	if err := w.PutAttributeKey("drone", key); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Save(); err != nil {
+		t.Fatal(err)
+	}
+	again, err := Open(w.path, []byte("pw"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := again.AttributeKey("drone")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !sameAttributeKey(got, key) {
+		t.Error("attribute key changed across Save and Open")
+	}
+	items := again.List()
+	if len(items) != 2 || items[0].Kind != KindAttributeKey || items[1].Name != "alice" {
+		t.Errorf("List: %+v", items)
+	}
+	if err := again.Remove(KindIdentity, "alice"); err != nil {
+		t.Error(err)
+	}
+	if err := again.Remove(KindIdentity, "alice"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("removing twice: %v, want ErrNotFound", err)
+	}
+	if err := again.PutAttributeKey("drone", key); !errors.Is(err, ErrExists) {
+		t.Errorf("reusing a name: %v, want ErrExists", err)
+	}
+	if err := again.PutOrder("order", &Order{Identity: "alice"}); !errors.Is(err, ErrNotFound) {
+		t.Errorf("order of a removed identity: %v, want ErrNotFound", err)
+	}
+}